@@ -15,6 +15,11 @@ type Config struct {
 	Server             ServerConfig
 	JWT                JWTConfig
 	Storage            StorageConfig
+	Lifecycle          LifecycleConfig
+	Payment            PaymentConfig
+	Reports            ReportsConfig
+	Maintenance        MaintenanceConfig
+	Campaigns          CampaignsConfig
 	Env                string
 	CORSAllowedOrigins []string
 }
@@ -46,6 +51,42 @@ type StorageConfig struct {
 	MaxUploadBytes int64
 }
 
+// LifecycleConfig controls configuration expiry, price locks and the background job that enforces them.
+type LifecycleConfig struct {
+	DraftExpiryDays   int
+	PriceLockDays     int
+	ExpiryWarningDays int
+	JobsEnabled       bool
+	JobInterval       time.Duration
+}
+
+// PaymentConfig selects the online payment gateway and how its webhooks are verified.
+type PaymentConfig struct {
+	Gateway       string
+	WebhookSecret string
+	Currency      string
+	// FakeAutoConfirm makes the fake gateway settle payments without waiting for a webhook.
+	FakeAutoConfirm bool
+}
+
+// ReportsConfig controls how often the analytics materialized views are refreshed.
+type ReportsConfig struct {
+	RefreshInterval time.Duration
+}
+
+// MaintenanceConfig controls when service reminders are raised and how often the job looks for them.
+type MaintenanceConfig struct {
+	// A reminder is raised this many days or kilometres before the service is due.
+	LeadDays         int
+	LeadKm           int
+	ReminderInterval time.Duration
+}
+
+// CampaignsConfig controls how often service campaigns are matched against newly registered cars.
+type CampaignsConfig struct {
+	MatchInterval time.Duration
+}
+
 func Load() (*Config, error) {
 	_ = godotenv.Load()
 
@@ -72,6 +113,30 @@ func Load() (*Config, error) {
 			RootPath:       getEnv("DOCUMENT_STORAGE_ROOT", "./data/documents"),
 			MaxUploadBytes: getEnvAsInt64("DOCUMENT_MAX_UPLOAD_BYTES", 15<<20),
 		},
+		Lifecycle: LifecycleConfig{
+			DraftExpiryDays:   getEnvAsInt("CONFIG_DRAFT_EXPIRY_DAYS", 30),
+			PriceLockDays:     getEnvAsInt("CONFIG_PRICE_LOCK_DAYS", 7),
+			ExpiryWarningDays: getEnvAsInt("CONFIG_EXPIRY_WARNING_DAYS", 2),
+			JobsEnabled:       getEnv("JOBS_ENABLED", "true") != "false",
+			JobInterval:       time.Duration(getEnvAsInt("JOBS_INTERVAL_MINUTES", 15)) * time.Minute,
+		},
+		Payment: PaymentConfig{
+			Gateway:         getEnv("PAYMENT_GATEWAY", "fake"),
+			WebhookSecret:   getEnv("PAYMENT_WEBHOOK_SECRET", "change-me-in-production"),
+			Currency:        getEnv("PAYMENT_CURRENCY", "RUB"),
+			FakeAutoConfirm: getEnv("PAYMENT_FAKE_AUTO_CONFIRM", "") == "true",
+		},
+		Reports: ReportsConfig{
+			RefreshInterval: time.Duration(getEnvAsInt("REPORTS_REFRESH_MINUTES", 60)) * time.Minute,
+		},
+		Maintenance: MaintenanceConfig{
+			LeadDays:         getEnvAsInt("MAINTENANCE_REMINDER_LEAD_DAYS", 30),
+			LeadKm:           getEnvAsInt("MAINTENANCE_REMINDER_LEAD_KM", 1000),
+			ReminderInterval: time.Duration(getEnvAsInt("MAINTENANCE_REMINDER_INTERVAL_MINUTES", 360)) * time.Minute,
+		},
+		Campaigns: CampaignsConfig{
+			MatchInterval: time.Duration(getEnvAsInt("CAMPAIGN_MATCH_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
 		Env: getEnv("ENV", "development"),
 		CORSAllowedOrigins: parseCSVOrigins(getEnv("CORS_ALLOWED_ORIGINS", "")),
 	}
@@ -104,9 +169,42 @@ func (c *Config) validate() error {
 			return fmt.Errorf("DB_SSLMODE must not be disable in production")
 		}
 	}
+	if c.Payment.Gateway != "fake" {
+		return fmt.Errorf("PAYMENT_GATEWAY %q is not supported", c.Payment.Gateway)
+	}
+	if c.Payment.WebhookSecret == "change-me-in-production" && c.Env == "production" {
+		return fmt.Errorf("PAYMENT_WEBHOOK_SECRET must be changed in production")
+	}
 	if c.Storage.MaxUploadBytes < 1<<20 {
 		c.Storage.MaxUploadBytes = 15 << 20
 	}
+	if c.Lifecycle.DraftExpiryDays < 1 {
+		c.Lifecycle.DraftExpiryDays = 30
+	}
+	if c.Lifecycle.PriceLockDays < 1 {
+		c.Lifecycle.PriceLockDays = 7
+	}
+	if c.Lifecycle.ExpiryWarningDays < 0 {
+		c.Lifecycle.ExpiryWarningDays = 0
+	}
+	if c.Lifecycle.JobInterval < time.Minute {
+		c.Lifecycle.JobInterval = 15 * time.Minute
+	}
+	if c.Reports.RefreshInterval < time.Minute {
+		c.Reports.RefreshInterval = time.Hour
+	}
+	if c.Maintenance.LeadDays < 0 {
+		c.Maintenance.LeadDays = 0
+	}
+	if c.Maintenance.LeadKm < 0 {
+		c.Maintenance.LeadKm = 0
+	}
+	if c.Maintenance.ReminderInterval < time.Minute {
+		c.Maintenance.ReminderInterval = 6 * time.Hour
+	}
+	if c.Campaigns.MatchInterval < time.Minute {
+		c.Campaigns.MatchInterval = time.Hour
+	}
 	if c.Server.MaxJSONBodyBytes < 4096 {
 		c.Server.MaxJSONBodyBytes = 1 << 20
 	}
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 // TestConfig returns settings suitable for unit and integration tests.
 func TestConfig() *Config {
@@ -27,6 +30,29 @@ func TestConfig() *Config {
 			RootPath:       envOr("DOCUMENT_STORAGE_ROOT", "./testdata/documents"),
 			MaxUploadBytes: 15 << 20,
 		},
+		Lifecycle: LifecycleConfig{
+			DraftExpiryDays:   30,
+			PriceLockDays:     7,
+			ExpiryWarningDays: 2,
+			JobsEnabled:       false,
+			JobInterval:       15 * time.Minute,
+		},
+		Payment: PaymentConfig{
+			Gateway:       "fake",
+			WebhookSecret: "carkeeper-test-payment-secret",
+			Currency:      "RUB",
+		},
+		Reports: ReportsConfig{
+			RefreshInterval: time.Hour,
+		},
+		Maintenance: MaintenanceConfig{
+			LeadDays:         30,
+			LeadKm:           1000,
+			ReminderInterval: 6 * time.Hour,
+		},
+		Campaigns: CampaignsConfig{
+			MatchInterval: time.Hour,
+		},
 		Env: "test",
 	}
 }
@@ -43,8 +43,70 @@ func NewRouter(handlers *handler.Handler, cfg *config.Config, db *database.DB) *
 			r.Use(authMiddleware.AuthMiddleware(handlers.Services().Auth))
 			r.Get("/roles", handlers.AdminListRoleDefinitions)
 			r.Get("/orders", handlers.AdminListAllOrders)
+			r.Get("/orders/export", handlers.AdminExportOrders)
 			r.Get("/appointments", handlers.AdminListAllAppointments)
+			r.Post("/appointments", handlers.AdminCreateAppointment)
+			r.Get("/appointments/export", handlers.AdminExportAppointments)
+			r.Post("/orders/{id}/claim", handlers.AdminClaimOrder)
+			r.Put("/orders/{id}/manager", handlers.AdminAssignOrderManager)
+			r.Post("/orders/{id}/handover", handlers.AdminHandOverOrder)
+			r.Get("/deliveries", handlers.AdminDeliveryCalendar)
+			r.Get("/reports/orders", handlers.AdminOrdersReport)
+			r.Get("/reports/funnel", handlers.AdminFunnelReport)
+			r.Get("/reports/service", handlers.AdminServiceReport)
+			r.Post("/reports/refresh", handlers.AdminRefreshReports)
+			r.Post("/appointments/{id}/claim", handlers.AdminClaimAppointment)
+			r.Put("/appointments/{id}/manager", handlers.AdminAssignAppointmentManager)
+			r.Post("/appointments/{id}/check-in", handlers.AdminCheckInAppointment)
+			r.Put("/appointments/{id}/work-order", handlers.AdminUpdateWorkOrder)
+			r.Post("/appointments/{id}/complete", handlers.AdminCompleteAppointment)
+			r.Post("/appointments/{id}/no-show", handlers.AdminMarkAppointmentNoShow)
+			r.Post("/orders/{id}/invoices", handlers.AdminCreateInvoice)
+			r.Post("/invoices/{id}/cancel", handlers.AdminCancelInvoice)
+			r.Post("/invoices/{id}/payments", handlers.AdminRecordPayment)
+			r.Post("/payments/{id}/refunds", handlers.AdminRefundPayment)
+			r.Post("/documents/generate", handlers.AdminGenerateDocument)
+			r.Get("/my-queue", handlers.AdminMyQueue)
+			r.Get("/workload", handlers.AdminWorkload)
+			r.Route("/assignment-rules", func(r chi.Router) {
+				r.Get("/", handlers.AdminListAssignmentRules)
+				r.Put("/{entity}", handlers.AdminUpdateAssignmentRule)
+				r.Post("/{entity}/run", handlers.AdminRunAutoAssign)
+			})
 			r.Patch("/branches/{id}", handlers.AdminUpdateBranch)
+			r.Route("/branches/{id}/schedule", func(r chi.Router) {
+				r.Get("/", handlers.AdminGetBranchSchedule)
+				r.Put("/weekly", handlers.AdminReplaceBranchWeekly)
+				r.Put("/exceptions", handlers.AdminUpsertBranchException)
+				r.Delete("/exceptions/{exceptionID}", handlers.AdminDeleteBranchException)
+			})
+			r.Get("/branches/{id}/resources", handlers.AdminListBranchResources)
+			r.Post("/branches/{id}/resources", handlers.AdminCreateBranchResource)
+			r.Put("/service-resources/{id}", handlers.AdminUpdateServiceResource)
+			r.Get("/workshop-schedule", handlers.AdminWorkshopSchedule)
+			r.Route("/service-history/{vin}", func(r chi.Router) {
+				r.Get("/", handlers.AdminGetServiceHistory)
+				r.Get("/pdf", handlers.AdminGetServiceHistoryPDF)
+				r.Get("/documents/{documentID}", handlers.AdminDownloadServiceHistoryDocument)
+			})
+			r.Get("/cars/{id}/transfers", handlers.AdminListCarTransfers)
+			r.Post("/cars/{id}/transfer", handlers.AdminForceCarTransfer)
+			r.Route("/maintenance-plans", func(r chi.Router) {
+				r.Get("/", handlers.AdminListMaintenancePlans)
+				r.Post("/", handlers.AdminCreateMaintenancePlan)
+				r.Put("/{id}", handlers.AdminUpdateMaintenancePlan)
+				r.Delete("/{id}", handlers.AdminDeleteMaintenancePlan)
+			})
+			r.Route("/service-campaigns", func(r chi.Router) {
+				r.Get("/", handlers.AdminListServiceCampaigns)
+				r.Post("/", handlers.AdminCreateServiceCampaign)
+				r.Get("/{id}", handlers.AdminGetServiceCampaign)
+				r.Put("/{id}", handlers.AdminUpdateServiceCampaign)
+				r.Delete("/{id}", handlers.AdminDeleteServiceCampaign)
+				r.Post("/{id}/match", handlers.AdminMatchServiceCampaign)
+				r.Get("/{id}/vehicles", handlers.AdminListServiceCampaignVehicles)
+				r.Post("/{id}/vehicles/{userCarID}/complete", handlers.AdminCompleteServiceCampaignVehicle)
+			})
 			r.Route("/catalog", func(r chi.Router) {
 				r.Route("/brands", func(r chi.Router) {
 					r.Post("/", handlers.AdminCreateBrand)
@@ -69,9 +131,38 @@ func NewRouter(handlers *handler.Handler, cfg *config.Config, db *database.DB) *
 			r.Route("/order-statuses", func(r chi.Router) {
 				r.Get("/", handlers.AdminListOrderStatuses)
 				r.Post("/", handlers.AdminCreateOrderStatus)
+				r.Get("/transitions", handlers.AdminListOrderStatusTransitions)
+				r.Post("/transitions", handlers.AdminCreateOrderStatusTransition)
+				r.Put("/transitions/{transitionId}", handlers.AdminUpdateOrderStatusTransition)
+				r.Delete("/transitions/{transitionId}", handlers.AdminDeleteOrderStatusTransition)
 				r.Patch("/{id}", handlers.AdminUpdateOrderStatus)
 				r.Delete("/{id}", handlers.AdminDeleteOrderStatus)
 			})
+			r.Route("/promotions", func(r chi.Router) {
+				r.Get("/", handlers.AdminListPromotions)
+				r.Post("/", handlers.AdminCreatePromotion)
+				r.Get("/{id}", handlers.AdminGetPromotion)
+				r.Put("/{id}", handlers.AdminUpdatePromotion)
+				r.Delete("/{id}", handlers.AdminDeletePromotion)
+				r.Get("/{id}/codes", handlers.AdminListPromotionCodes)
+				r.Post("/{id}/codes", handlers.AdminCreatePromotionCodes)
+			})
+			r.Route("/finance-products", func(r chi.Router) {
+				r.Get("/", handlers.AdminListFinanceProducts)
+				r.Post("/", handlers.AdminCreateFinanceProduct)
+				r.Put("/{id}", handlers.AdminUpdateFinanceProduct)
+				r.Delete("/{id}", handlers.AdminDeleteFinanceProduct)
+			})
+			r.Route("/trade-in", func(r chi.Router) {
+				r.Get("/rules", handlers.AdminListDepreciationRules)
+				r.Post("/rules", handlers.AdminCreateDepreciationRule)
+				r.Put("/rules/{id}", handlers.AdminUpdateDepreciationRule)
+				r.Delete("/rules/{id}", handlers.AdminDeleteDepreciationRule)
+				r.Post("/estimate", handlers.AdminEstimateTradeIn)
+				r.Get("/offers", handlers.AdminListTradeInOffers)
+				r.Post("/offers", handlers.AdminCreateTradeInOffer)
+				r.Post("/offers/{id}/cancel", handlers.AdminCancelTradeInOffer)
+			})
 		})
 
 		r.Route("/auth", func(r chi.Router) {
@@ -98,12 +189,20 @@ func NewRouter(handlers *handler.Handler, cfg *config.Config, db *database.DB) *
 		})
 
 		r.Route("/configurator", func(r chi.Router) {
+			r.With(httprate.LimitByIP(60, time.Minute)).Get("/shared/{token}", handlers.GetSharedConfiguration)
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.AuthMiddleware(handlers.Services().Auth))
 				r.Get("/colors", handlers.GetColors)
 				r.Get("/options", handlers.GetOptions)
 				r.Post("/configurations", handlers.CreateConfiguration)
 				r.Get("/configurations/{id}", handlers.GetConfiguration)
+				r.Get("/configurations/{id}/pricing", handlers.GetConfigurationPricing)
+				r.Post("/configurations/{id}/finance", handlers.CalculateConfigurationFinance)
+				r.Post("/configurations/{id}/clone", handlers.CloneConfiguration)
+				r.Get("/configurations/{id}/shares", handlers.ListConfigurationShares)
+				r.Post("/configurations/{id}/shares", handlers.CreateConfigurationShare)
+				r.Delete("/configurations/{id}/shares/{shareId}", handlers.RevokeConfigurationShare)
+				r.Get("/finance-products", handlers.GetFinanceProducts)
 				r.Put("/configurations/{id}", handlers.UpdateConfiguration)
 				r.Delete("/configurations/{id}", handlers.DeleteConfiguration)
 			})
@@ -116,21 +215,38 @@ func NewRouter(handlers *handler.Handler, cfg *config.Config, db *database.DB) *
 				r.Get("/", handlers.GetUserOrders)
 				r.Get("/{id}", handlers.GetOrder)
 				r.Patch("/{id}/status", handlers.UpdateOrderStatus)
+				r.Post("/{id}/trade-in", handlers.AttachOrderTradeIn)
+				r.Delete("/{id}/trade-in", handlers.DetachOrderTradeIn)
+				r.Get("/{id}/payments", handlers.GetOrderPayments)
+				r.Post("/{id}/invoices/{invoiceId}/pay", handlers.PayOrderInvoice)
+				r.Get("/{id}/delivery", handlers.GetOrderDelivery)
+				r.Put("/{id}/delivery", handlers.ScheduleOrderDelivery)
+				r.Delete("/{id}/delivery", handlers.CancelOrderDelivery)
+				r.Get("/{id}/messages", handlers.ListOrderMessages)
+				r.Post("/{id}/messages", handlers.PostOrderMessage)
+				r.Post("/{id}/messages/read", handlers.MarkOrderMessagesRead)
 			})
 		})
 
+		r.Post("/payments/webhook", handlers.PaymentWebhook)
+
 		r.Route("/service", func(r chi.Router) {
 			r.Get("/types", handlers.GetServiceTypes)
 			r.Get("/branches", handlers.GetBranches)
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.AuthMiddleware(handlers.Services().Auth))
 				r.Get("/branches/{branchID}/availability", handlers.GetBranchAvailability)
+				r.Get("/branches/{branchID}/availability/range", handlers.GetBranchAvailabilityRange)
+				r.Get("/branches/{branchID}/delivery-availability", handlers.GetBranchDeliveryAvailability)
 				r.Get("/user-cars", handlers.GetUserCars)
 				r.Post("/appointments", handlers.CreateAppointment)
 				r.Get("/appointments", handlers.GetUserAppointments)
 				r.Patch("/appointments/{id}/reschedule", handlers.RescheduleAppointment)
 				r.Get("/appointments/{id}", handlers.GetAppointment)
 				r.Patch("/appointments/{id}/cancel", handlers.CancelAppointment)
+				r.Get("/appointments/{id}/messages", handlers.ListAppointmentMessages)
+				r.Post("/appointments/{id}/messages", handlers.PostAppointmentMessage)
+				r.Post("/appointments/{id}/messages/read", handlers.MarkAppointmentMessagesRead)
 			})
 		})
 
@@ -157,7 +273,37 @@ func NewRouter(handlers *handler.Handler, cfg *config.Config, db *database.DB) *
 				r.Post("/cars", handlers.CreateUserCar)
 				r.Delete("/cars/{id}", handlers.DeleteUserCar)
 				r.Get("/cars/{id}", handlers.GetUserCar)
+				r.Get("/cars/{id}/maintenance", handlers.GetUserCarMaintenance)
+				r.Get("/cars/{id}/service-history", handlers.GetUserCarServiceHistory)
+				r.Get("/cars/{id}/service-history/pdf", handlers.GetUserCarServiceHistoryPDF)
+				r.Get("/cars/{id}/service-history/documents/{documentID}", handlers.DownloadUserCarServiceHistoryDocument)
+				r.Get("/cars/{id}/transfer", handlers.GetUserCarTransfer)
+				r.Post("/cars/{id}/transfer", handlers.StartUserCarTransfer)
+				r.Delete("/cars/{id}/transfer", handlers.CancelUserCarTransfer)
+				r.With(httprate.LimitByIP(10, time.Minute)).Post("/car-transfers/accept", handlers.AcceptCarTransfer)
+				r.Put("/cars/{id}/organization", handlers.ShareUserCar)
+				r.Delete("/cars/{id}/organization", handlers.UnshareUserCar)
+				r.Get("/organizations", handlers.ListOrganizations)
+				r.Post("/organizations", handlers.CreateOrganization)
+				r.Get("/organizations/{id}", handlers.GetOrganization)
+				r.Patch("/organizations/{id}", handlers.UpdateOrganization)
+				r.Delete("/organizations/{id}", handlers.DeleteOrganization)
+				r.Get("/organizations/{id}/members", handlers.ListOrganizationMembers)
+				r.Post("/organizations/{id}/members", handlers.AddOrganizationMember)
+				r.Patch("/organizations/{id}/members/{userID}", handlers.UpdateOrganizationMember)
+				r.Delete("/organizations/{id}/members/{userID}", handlers.RemoveOrganizationMember)
+				r.Get("/organizations/{id}/cars", handlers.ListOrganizationCars)
+				r.Get("/organizations/{id}/appointments", handlers.ListOrganizationAppointments)
+				r.Get("/organizations/{id}/spend", handlers.GetOrganizationSpend)
+				r.Get("/maintenance-reminders", handlers.GetMaintenanceReminders)
+				r.Post("/maintenance-reminders/{id}/dismiss", handlers.DismissMaintenanceReminder)
 				r.Get("/configurations", handlers.GetUserConfigurations)
+				r.Get("/trade-in-offers", handlers.GetUserTradeInOffers)
+				r.Post("/trade-in-offers/{id}/accept", handlers.AcceptTradeInOffer)
+				r.Post("/trade-in-offers/{id}/reject", handlers.RejectTradeInOffer)
+				r.Get("/notifications", handlers.GetUserNotifications)
+				r.Post("/notifications/read-all", handlers.MarkAllNotificationsRead)
+				r.Post("/notifications/{id}/read", handlers.MarkNotificationRead)
 			})
 		})
 
@@ -153,19 +153,13 @@ func CanViewOrder(orderUserID, requester uuid.UUID, role string) bool {
 	return IsOwnerOrHasPermission(orderUserID, requester, role, PermOrdersViewAny)
 }
 
-// CanUpdateOrderStatus encodes who may change order status and how.
-// Roles with orders.manage_status may set any valid status. Customers may only cancel their own order from pending or approved.
-func CanUpdateOrderStatus(orderUserID, requester uuid.UUID, role, currentStatus, newStatus string) bool {
-	if HasPermission(role, PermOrdersManageStatus) {
+// CanTriggerOrderTransition reports whether requester may perform a configured order status transition.
+// Roles holding the transition's permission may move any order; the owner only when customerMayTrigger.
+func CanTriggerOrderTransition(orderUserID, requester uuid.UUID, role, permission string, customerMayTrigger bool) bool {
+	if HasPermission(role, permission) {
 		return true
 	}
-	if orderUserID != requester {
-		return false
-	}
-	if newStatus != "cancelled" {
-		return false
-	}
-	return currentStatus == "pending" || currentStatus == "approved"
+	return customerMayTrigger && orderUserID == requester
 }
 
 // CanAccessConfiguration reports read/update access to a configuration record.
@@ -179,7 +173,10 @@ func CustomerMayChangeConfigurationStatus(fromStatus, toStatus string) bool {
 	case "confirmed":
 		return fromStatus == "draft"
 	case "cancelled":
-		return fromStatus == "draft" || fromStatus == "confirmed"
+		return fromStatus == "draft" || fromStatus == "confirmed" || fromStatus == "expired"
+	case "draft":
+		// Expired drafts can be revived by their owner.
+		return fromStatus == "expired"
 	default:
 		return false
 	}
@@ -189,3 +186,27 @@ func CustomerMayChangeConfigurationStatus(fromStatus, toStatus string) bool {
 func CanManageConfigurationStatus(role string) bool {
 	return HasPermission(role, PermConfigurationsManage)
 }
+
+// Organization member roles (organization_members.role). The registered owner of a car acts as
+// OrgRoleOwner for it whether or not the car is shared.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleDriver = "driver"
+	OrgRoleViewer = "viewer"
+)
+
+// OrgCanManage reports whether a member may change the organization, its members and its cars.
+func OrgCanManage(orgRole string) bool {
+	return orgRole == OrgRoleOwner
+}
+
+// OrgCanBook reports whether a member may book, move and cancel service for a shared car and talk
+// to the workshop about it.
+func OrgCanBook(orgRole string) bool {
+	return orgRole == OrgRoleOwner || orgRole == OrgRoleDriver
+}
+
+// OrgCanViewSpend reports whether a member may read the organization's service spend.
+func OrgCanViewSpend(orgRole string) bool {
+	return orgRole == OrgRoleOwner || orgRole == OrgRoleViewer
+}
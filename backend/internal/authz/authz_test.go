@@ -73,20 +73,23 @@ func TestCanViewOrder(t *testing.T) {
 	}
 }
 
-func TestCanUpdateOrderStatus(t *testing.T) {
+func TestCanTriggerOrderTransition(t *testing.T) {
 	owner := uuid.New()
 	other := uuid.New()
-	if !CanUpdateOrderStatus(owner, owner, "customer", "pending", "cancelled") {
-		t.Fatal("customer may cancel pending")
+	if !CanTriggerOrderTransition(owner, owner, "customer", PermOrdersManageStatus, true) {
+		t.Fatal("customer may trigger customer transitions on own order")
 	}
-	if CanUpdateOrderStatus(owner, owner, "customer", "paid", "cancelled") {
-		t.Fatal("customer should not cancel paid order")
+	if CanTriggerOrderTransition(owner, other, "customer", PermOrdersManageStatus, true) {
+		t.Fatal("customer should not move someone else's order")
 	}
-	if CanUpdateOrderStatus(owner, owner, "customer", "pending", "paid") {
-		t.Fatal("customer should not set paid")
+	if CanTriggerOrderTransition(owner, owner, "customer", PermOrdersManageStatus, false) {
+		t.Fatal("customer should not trigger staff-only transitions")
 	}
-	if !CanUpdateOrderStatus(owner, other, "admin", "pending", "paid") {
-		t.Fatal("staff with orders.manage_status may set any transition")
+	if !CanTriggerOrderTransition(owner, other, "admin", PermOrdersManageStatus, false) {
+		t.Fatal("staff with the transition permission may trigger it")
+	}
+	if CanTriggerOrderTransition(owner, other, "service_advisor", PermAdminOrderStatuses, false) {
+		t.Fatal("staff without the transition permission should be blocked")
 	}
 }
 
@@ -126,6 +129,12 @@ func TestCustomerMayChangeConfigurationStatus(t *testing.T) {
 	if !CustomerMayChangeConfigurationStatus("confirmed", "cancelled") {
 		t.Fatal("confirmed -> cancelled")
 	}
+	if !CustomerMayChangeConfigurationStatus("expired", "draft") {
+		t.Fatal("expired -> draft")
+	}
+	if CustomerMayChangeConfigurationStatus("confirmed", "draft") {
+		t.Fatal("confirmed -> draft blocked for customer")
+	}
 }
 
 func TestAllPermissionsCoveredByAdminDefaults(t *testing.T) {
@@ -160,6 +169,30 @@ func TestRolePermissionRegressionMatrix(t *testing.T) {
 		{"service advisor can manage service catalog", "service_advisor", PermServiceManage, true},
 		{"service advisor cannot manage brands", "service_advisor", PermCatalogManage, false},
 		{"service advisor cannot manage news", "service_advisor", PermNewsManage, false},
+		{"manager can manage promotions", "manager", PermPromotionsManage, true},
+		{"service advisor cannot manage promotions", "service_advisor", PermPromotionsManage, false},
+		{"customer cannot manage promotions", "customer", PermPromotionsManage, false},
+		{"manager can manage finance products", "manager", PermFinanceManage, true},
+		{"service advisor cannot manage finance products", "service_advisor", PermFinanceManage, false},
+		{"service advisor can appraise trade-ins", "service_advisor", PermTradeInAppraise, true},
+		{"service advisor cannot edit depreciation model", "service_advisor", PermTradeInManage, false},
+		{"customer cannot appraise trade-ins", "customer", PermTradeInAppraise, false},
+		{"service advisor can claim work", "service_advisor", PermAssignmentsClaim, true},
+		{"service advisor cannot assign others", "service_advisor", PermAssignmentsManage, false},
+		{"manager can assign and view workload", "manager", PermAssignmentsManage, true},
+		{"customer cannot claim work", "customer", PermAssignmentsClaim, false},
+		{"manager can invoice and record payments", "manager", PermPaymentsManage, true},
+		{"manager cannot refund payments", "manager", PermPaymentsRefund, false},
+		{"service advisor cannot record payments", "service_advisor", PermPaymentsManage, false},
+		{"admin can refund payments", "admin", PermPaymentsRefund, true},
+		{"service advisor can generate documents", "service_advisor", PermDocumentsGenerate, true},
+		{"customer cannot generate documents", "customer", PermDocumentsGenerate, false},
+		{"admin can view reports", "admin", PermReportsView, true},
+		{"manager cannot view reports", "manager", PermReportsView, false},
+		{"service advisor can complete appointments", "service_advisor", PermAppointmentsManage, true},
+		{"customer cannot check in appointments", "customer", PermAppointmentsManage, false},
+		{"manager can force a car transfer", "manager", PermGarageTransfer, true},
+		{"service advisor cannot force a car transfer", "service_advisor", PermGarageTransfer, false},
 		{"admin can view role definitions", "admin", PermAdminRolesView, true},
 		{"admin can manage catalog", "admin", PermCatalogManage, true},
 		{"admin can manage service", "admin", PermServiceManage, true},
@@ -246,3 +279,26 @@ func TestCanManageConfigurationStatusByRole(t *testing.T) {
 		t.Fatal("customer must not manage configuration status")
 	}
 }
+
+func TestOrganizationRoles(t *testing.T) {
+	cases := []struct {
+		role                string
+		manage, book, spend bool
+	}{
+		{OrgRoleOwner, true, true, true},
+		{OrgRoleDriver, false, true, false},
+		{OrgRoleViewer, false, false, true},
+		{"", false, false, false},
+	}
+	for _, tc := range cases {
+		if got := OrgCanManage(tc.role); got != tc.manage {
+			t.Errorf("OrgCanManage(%q) = %v", tc.role, got)
+		}
+		if got := OrgCanBook(tc.role); got != tc.book {
+			t.Errorf("OrgCanBook(%q) = %v", tc.role, got)
+		}
+		if got := OrgCanViewSpend(tc.role); got != tc.spend {
+			t.Errorf("OrgCanViewSpend(%q) = %v", tc.role, got)
+		}
+	}
+}
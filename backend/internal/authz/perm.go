@@ -14,6 +14,18 @@ const (
 	PermAdminRolesView        = "admin.roles_view"
 	PermCatalogManage         = "catalog.manage"
 	PermServiceManage         = "service.manage"
+	PermPromotionsManage      = "promotions.manage"
+	PermFinanceManage         = "finance.manage"
+	PermTradeInManage         = "tradein.manage"
+	PermTradeInAppraise       = "tradein.appraise"
+	PermAssignmentsClaim      = "assignments.claim"
+	PermAssignmentsManage     = "assignments.manage"
+	PermPaymentsManage        = "payments.manage"
+	PermPaymentsRefund        = "payments.refund"
+	PermDocumentsGenerate     = "documents.generate"
+	PermReportsView           = "reports.view"
+	PermAppointmentsManage    = "appointments.manage"
+	PermGarageTransfer        = "garage.transfer"
 )
 
 // AllPermissionCodes lists every defined permission (for admin role seed and tests).
@@ -30,6 +42,18 @@ var AllPermissionCodes = []string{
 	PermAdminRolesView,
 	PermCatalogManage,
 	PermServiceManage,
+	PermPromotionsManage,
+	PermFinanceManage,
+	PermTradeInManage,
+	PermTradeInAppraise,
+	PermAssignmentsClaim,
+	PermAssignmentsManage,
+	PermPaymentsManage,
+	PermPaymentsRefund,
+	PermDocumentsGenerate,
+	PermReportsView,
+	PermAppointmentsManage,
+	PermGarageTransfer,
 }
 
 // DefaultRolePermissions is used when the DB has no role_permissions rows (bootstrap / tests).
@@ -46,6 +70,16 @@ func DefaultRolePermissions() map[string][]string {
 		PermDocumentsViewAny,
 		PermNewsManage,
 		PermServiceManage,
+		PermPromotionsManage,
+		PermFinanceManage,
+		PermTradeInManage,
+		PermTradeInAppraise,
+		PermAssignmentsClaim,
+		PermAssignmentsManage,
+		PermPaymentsManage,
+		PermDocumentsGenerate,
+		PermAppointmentsManage,
+		PermGarageTransfer,
 	}
 
 	serviceAdvisor := []string{
@@ -56,6 +90,10 @@ func DefaultRolePermissions() map[string][]string {
 		PermGarageViewAny,
 		PermDocumentsViewAny,
 		PermServiceManage,
+		PermTradeInAppraise,
+		PermAssignmentsClaim,
+		PermDocumentsGenerate,
+		PermAppointmentsManage,
 	}
 
 	return map[string][]string{
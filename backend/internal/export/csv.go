@@ -0,0 +1,88 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const csvTimeLayout = "2006-01-02 15:04:05"
+
+// WriteCSV writes the header and rows as comma-separated UTF-8 with a byte order mark.
+func WriteCSV(w io.Writer, t Table) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(t.Columns))
+	for _, row := range t.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = csvCell(row[i])
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return guardFormula(x)
+	case *string:
+		if x == nil {
+			return ""
+		}
+		return guardFormula(*x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', 2, 64)
+	case int:
+		return strconv.Itoa(x)
+	case time.Time:
+		return x.Format(csvTimeLayout)
+	case *time.Time:
+		if x == nil {
+			return ""
+		}
+		return x.Format(csvTimeLayout)
+	default:
+		return ""
+	}
+}
+
+// guardFormula keeps user-entered text such as "=HYPERLINK(...)" from being evaluated when the
+// file is opened in a spreadsheet. A sign followed only by digits (a phone such as +79991234567
+// or a negative number) is left as is.
+func guardFormula(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return s
+	}
+	if (s[0] == '+' || s[0] == '-') && isDigits(s[1:]) {
+		return s
+	}
+	return "'" + s
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
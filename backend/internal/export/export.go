@@ -0,0 +1,57 @@
+// Package export writes tabular reports as CSV or XLSX without external dependencies.
+//
+// A Table holds typed cells: string, *string, float64, int, time.Time, *time.Time or nil. CSV
+// output is UTF-8 with a byte order mark so spreadsheet applications detect the encoding; XLSX
+// output is a single-sheet workbook with a bold, frozen header row, numbers as numeric cells and
+// times as date cells.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Table is one sheet of a report.
+type Table struct {
+	Sheet   string
+	Columns []string
+	Rows    [][]any
+}
+
+// ParseFormat normalises a requested format; empty means CSV.
+func ParseFormat(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("export: unsupported format %q", s)
+	}
+}
+
+// ContentType is the MIME type of the format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write renders the table in the given format.
+func Write(w io.Writer, format string, t Table) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, t)
+	case FormatXLSX:
+		return WriteXLSX(w, t)
+	default:
+		return fmt.Errorf("export: unsupported format %q", format)
+	}
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sample = Table{
+	Sheet:   "Orders: 2026/05",
+	Columns: []string{"Customer", "Amount", "Created", "Note"},
+	Rows: [][]any{
+		{"Иван Петров", 1234567.5, time.Date(2026, 5, 18, 12, 0, 0, 0, time.UTC), nil},
+		{"=HYPERLINK(\"x\")", 10, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC), "a, \"b\"\nc"},
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatCSV, "CSV": FormatCSV, " xlsx ": FormatXLSX} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("pdf should be rejected")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sample); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\ufeff") {
+		t.Fatal("missing byte order mark")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records", len(records))
+	}
+	if got := records[1]; got[0] != "Иван Петров" || got[1] != "1234567.50" || got[2] != "2026-05-18 12:00:00" || got[3] != "" {
+		t.Errorf("row 1 = %q", got)
+	}
+	if got := records[2]; got[0] != "'=HYPERLINK(\"x\")" || got[1] != "10" || got[3] != "a, \"b\"\nc" {
+		t.Errorf("row 2 = %q", got)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, sample); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Fatalf("missing part %s", name)
+		}
+		if err := wellFormed(body); err != nil {
+			t.Errorf("%s is not well-formed: %v", name, err)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Orders 202605"`) {
+		t.Errorf("sheet name not sanitised: %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Customer</t></is></c>`,
+		`<c r="B2" s="3"><v>1234567.5</v></c>`,
+		`<c r="C3" s="2"><v>61</v></c>`,
+		`<t xml:space="preserve">=HYPERLINK(&#34;x&#34;)</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet lacks %s", want)
+		}
+	}
+	if strings.Contains(sheet, `r="D2"`) {
+		t.Error("nil cell should be omitted")
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	for col, want := range map[int]string{0: "A1", 25: "Z1", 26: "AA1", 701: "ZZ1", 702: "AAA1"} {
+		if got := cellRef(col, 1); got != want {
+			t.Errorf("cellRef(%d) = %s, want %s", col, got, want)
+		}
+	}
+}
+
+func wellFormed(s string) error {
+	d := xml.NewDecoder(strings.NewReader(s))
+	for {
+		if _, err := d.Token(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func TestGuardFormula(t *testing.T) {
+	for in, want := range map[string]string{
+		"+79991234567": "+79991234567",
+		"-15":          "-15",
+		"+7 (999) 123": "'+7 (999) 123",
+		"-1+2":         "'-1+2",
+		"+":            "'+",
+		"=SUM(A1:A2)":  "'=SUM(A1:A2)",
+		"@cmd":         "'@cmd",
+		"Иван":         "Иван",
+	} {
+		if got := guardFormula(in); got != want {
+			t.Errorf("guardFormula(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -81,11 +81,14 @@ func (h *Handler) AdminCreateServiceType(w http.ResponseWriter, r *http.Request)
 		Price           float64 `json:"price"`
 		DurationMinutes *int    `json:"duration_minutes"`
 		IsAvailable     bool    `json:"is_available"`
+		// Skills a bay and a technician must have to perform the service.
+		RequiredBaySkills        []string `json:"required_bay_skills"`
+		RequiredTechnicianSkills []string `json:"required_technician_skills"`
 	}
 	if !DecodeJSON(w, r, &body) {
 		return
 	}
-	st, err := h.services.Service.AdminCreateServiceType(r.Context(), body.Name, body.Category, body.Description, body.Price, body.DurationMinutes, body.IsAvailable)
+	st, err := h.services.Service.AdminCreateServiceType(r.Context(), body.Name, body.Category, body.Description, body.Price, body.DurationMinutes, body.IsAvailable, body.RequiredBaySkills, body.RequiredTechnicianSkills)
 	if err != nil {
 		HandleError(w, r, err)
 		return
@@ -109,11 +112,14 @@ func (h *Handler) AdminUpdateServiceType(w http.ResponseWriter, r *http.Request)
 		Price           float64 `json:"price"`
 		DurationMinutes *int    `json:"duration_minutes"`
 		IsAvailable     bool    `json:"is_available"`
+		// Skills a bay and a technician must have to perform the service.
+		RequiredBaySkills        []string `json:"required_bay_skills"`
+		RequiredTechnicianSkills []string `json:"required_technician_skills"`
 	}
 	if !DecodeJSON(w, r, &body) {
 		return
 	}
-	if err := h.services.Service.AdminUpdateServiceType(r.Context(), id, body.Name, body.Category, body.Description, body.Price, body.DurationMinutes, body.IsAvailable); err != nil {
+	if err := h.services.Service.AdminUpdateServiceType(r.Context(), id, body.Name, body.Category, body.Description, body.Price, body.DurationMinutes, body.IsAvailable, body.RequiredBaySkills, body.RequiredTechnicianSkills); err != nil {
 		HandleError(w, r, err)
 		return
 	}
@@ -151,11 +157,15 @@ func (h *Handler) AdminUpdateBranch(w http.ResponseWriter, r *http.Request) {
 		Phone    *string `json:"phone"`
 		Email    *string `json:"email"`
 		IsActive *bool   `json:"is_active"`
+
+		HandoverBays            *int `json:"handover_bays"`
+		HandoverDurationMinutes *int `json:"handover_duration_minutes"`
 	}
 	if !DecodeJSON(w, r, &body) {
 		return
 	}
-	if err := h.services.Service.AdminUpdateBranch(r.Context(), id, body.Name, body.Address, body.Phone, body.Email, body.IsActive); err != nil {
+	if err := h.services.Service.AdminUpdateBranch(r.Context(), id, body.Name, body.Address, body.Phone, body.Email, body.IsActive,
+		body.HandoverBays, body.HandoverDurationMinutes); err != nil {
 		HandleError(w, r, err)
 		return
 	}
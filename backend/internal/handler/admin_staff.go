@@ -1,17 +1,31 @@
 package handler
 
 import (
+	"bytes"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/export"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
 )
 
-// AdminListAllOrders returns all orders (staff with orders.view_any).
+// AdminListAllOrders returns the orders matching the query filters (staff with orders.view_any).
 func (h *Handler) AdminListAllOrders(w http.ResponseWriter, r *http.Request) {
-	if _, ok := RequirePermission(w, r, authz.PermOrdersViewAny); !ok {
+	staffID, ok := RequirePermission(w, r, authz.PermOrdersViewAny)
+	if !ok {
+		return
+	}
+	filters, msg := parseOrderFilters(r.URL.Query())
+	if msg != "" {
+		BadRequest(w, msg)
 		return
 	}
-	list, err := h.services.Order.ListAllOrdersForStaff(r.Context())
+	list, err := h.services.Order.ListAllOrdersForStaff(r.Context(), staffID, filters)
 	if err != nil {
 		HandleError(w, r, err)
 		return
@@ -19,15 +33,152 @@ func (h *Handler) AdminListAllOrders(w http.ResponseWriter, r *http.Request) {
 	Success(w, list)
 }
 
-// AdminListAllAppointments returns all service appointments (staff with appointments.view_any).
+// AdminExportOrders downloads the filtered orders as CSV or XLSX (?format=csv|xlsx).
+func (h *Handler) AdminExportOrders(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermOrdersViewAny); !ok {
+		return
+	}
+	q := r.URL.Query()
+	format, err := export.ParseFormat(q.Get("format"))
+	if err != nil {
+		BadRequest(w, "format must be csv or xlsx")
+		return
+	}
+	filters, msg := parseOrderFilters(q)
+	if msg != "" {
+		BadRequest(w, msg)
+		return
+	}
+	table, err := h.services.Order.ExportOrdersForStaff(r.Context(), filters)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	writeExport(w, r, "orders", format, table)
+}
+
+// AdminListAllAppointments returns the service appointments matching the query filters (staff
+// with appointments.view_any).
 func (h *Handler) AdminListAllAppointments(w http.ResponseWriter, r *http.Request) {
-	if _, ok := RequirePermission(w, r, authz.PermAppointmentsViewAny); !ok {
+	staffID, ok := RequirePermission(w, r, authz.PermAppointmentsViewAny)
+	if !ok {
+		return
+	}
+	filters, msg := parseAppointmentFilters(r.URL.Query())
+	if msg != "" {
+		BadRequest(w, msg)
 		return
 	}
-	list, err := h.services.Service.ListAllAppointmentsForStaff(r.Context())
+	list, err := h.services.Service.ListAllAppointmentsForStaff(r.Context(), staffID, filters)
 	if err != nil {
 		HandleError(w, r, err)
 		return
 	}
 	Success(w, list)
 }
+
+// AdminExportAppointments downloads the filtered appointments as CSV or XLSX (?format=csv|xlsx).
+func (h *Handler) AdminExportAppointments(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAppointmentsViewAny); !ok {
+		return
+	}
+	q := r.URL.Query()
+	format, err := export.ParseFormat(q.Get("format"))
+	if err != nil {
+		BadRequest(w, "format must be csv or xlsx")
+		return
+	}
+	filters, msg := parseAppointmentFilters(q)
+	if msg != "" {
+		BadRequest(w, msg)
+		return
+	}
+	table, err := h.services.Service.ExportAppointmentsForStaff(r.Context(), filters)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	writeExport(w, r, "appointments", format, table)
+}
+
+// parseOrderFilters reads status (comma-separated), from, to, brand_id, model_id, manager_id,
+// min_price, max_price and customer.
+func parseOrderFilters(q url.Values) (model.OrderFilters, string) {
+	var f model.OrderFilters
+	var msg string
+	if f.Status, msg = validate.SearchStatuses(q.Get("status")); msg != "" {
+		return f, msg
+	}
+	if f.From, f.To, msg = validate.SearchDateRange(q.Get("from"), q.Get("to")); msg != "" {
+		return f, msg
+	}
+	var err error
+	if f.BrandID, err = parseOptionalUUID(q.Get("brand_id")); err != nil {
+		return f, "invalid brand_id"
+	}
+	if f.ModelID, err = parseOptionalUUID(q.Get("model_id")); err != nil {
+		return f, "invalid model_id"
+	}
+	if f.ManagerID, err = parseOptionalUUID(q.Get("manager_id")); err != nil {
+		return f, "invalid manager_id"
+	}
+	if f.MinPrice, err = parseOptionalFloat(q.Get("min_price")); err != nil {
+		return f, "invalid min_price"
+	}
+	if f.MaxPrice, err = parseOptionalFloat(q.Get("max_price")); err != nil {
+		return f, "invalid max_price"
+	}
+	if msg = validate.SearchPriceRange(f.MinPrice, f.MaxPrice); msg != "" {
+		return f, msg
+	}
+	f.Customer, msg = validate.SearchText("customer", q.Get("customer"))
+	return f, msg
+}
+
+// parseAppointmentFilters reads branch_id, status (comma-separated), from, to and category.
+func parseAppointmentFilters(q url.Values) (model.AppointmentFilters, string) {
+	var f model.AppointmentFilters
+	var msg string
+	var err error
+	if f.BranchID, err = parseOptionalUUID(q.Get("branch_id")); err != nil {
+		return f, "invalid branch_id"
+	}
+	if f.Status, msg = validate.AppointmentStatuses(q.Get("status")); msg != "" {
+		return f, msg
+	}
+	if f.From, f.To, msg = validate.SearchDateRange(q.Get("from"), q.Get("to")); msg != "" {
+		return f, msg
+	}
+	if category := strings.TrimSpace(q.Get("category")); category != "" {
+		if f.ServiceCategory, msg = validate.ServiceCategory(category); msg != "" {
+			return f, msg
+		}
+	}
+	return f, ""
+}
+
+func parseOptionalFloat(s string) (*float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// writeExport renders the table fully before sending so a failure still yields a JSON error.
+func writeExport(w http.ResponseWriter, r *http.Request, name, format string, table export.Table) {
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, table); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	filename := name + "-" + time.Now().UTC().Format("2006-01-02") + "." + format
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseOrderFilters(t *testing.T) {
+	q := url.Values{
+		"status":    {"pending,paid"},
+		"from":      {"2026-05-01"},
+		"to":        {"2026-05-31"},
+		"brand_id":  {"6f1c2b1e-2d1f-4b8e-9a57-0f3c6c1f4a10"},
+		"min_price": {"1000000"},
+		"customer":  {" Petrov "},
+	}
+	f, msg := parseOrderFilters(q)
+	if msg != "" {
+		t.Fatalf("unexpected error %q", msg)
+	}
+	if len(f.Status) != 2 || f.From == nil || f.To == nil || f.BrandID == nil || f.ModelID != nil ||
+		f.MinPrice == nil || *f.MinPrice != 1_000_000 || f.MaxPrice != nil || f.Customer != "Petrov" {
+		t.Fatalf("unexpected filters %+v", f)
+	}
+
+	for _, bad := range []url.Values{
+		{"manager_id": {"nope"}},
+		{"min_price": {"abc"}},
+		{"min_price": {"10"}, "max_price": {"5"}},
+		{"from": {"2026-05-02"}, "to": {"2026-05-01"}},
+	} {
+		if _, msg := parseOrderFilters(bad); msg == "" {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestParseAppointmentFilters(t *testing.T) {
+	f, msg := parseAppointmentFilters(url.Values{"status": {"scheduled"}, "category": {"tires"}})
+	if msg != "" || len(f.Status) != 1 || f.ServiceCategory != "tires" {
+		t.Fatalf("got %+v %q", f, msg)
+	}
+	if _, msg := parseAppointmentFilters(url.Values{"category": {"painting"}}); msg == "" {
+		t.Error("unknown category should fail")
+	}
+	if _, msg := parseAppointmentFilters(url.Values{"status": {"paid"}}); msg == "" {
+		t.Error("unknown status should fail")
+	}
+}
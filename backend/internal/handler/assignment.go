@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) AdminClaimOrder(w http.ResponseWriter, r *http.Request) {
+	h.claimAssignment(w, r, "order", "Invalid order ID")
+}
+
+func (h *Handler) AdminClaimAppointment(w http.ResponseWriter, r *http.Request) {
+	h.claimAssignment(w, r, "appointment", "Invalid appointment ID")
+}
+
+func (h *Handler) claimAssignment(w http.ResponseWriter, r *http.Request, entity, invalidIDMsg string) {
+	staffID, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	if !authz.HasPermission(role, authz.PermAssignmentsClaim) {
+		Forbidden(w, "You do not have permission for this action")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, invalidIDMsg)
+		return
+	}
+	if err := h.services.Assignment.Claim(r.Context(), entity, id, staffID, role); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Claimed"})
+}
+
+func (h *Handler) AdminAssignOrderManager(w http.ResponseWriter, r *http.Request) {
+	h.assignManager(w, r, "order", "Invalid order ID")
+}
+
+func (h *Handler) AdminAssignAppointmentManager(w http.ResponseWriter, r *http.Request) {
+	h.assignManager(w, r, "appointment", "Invalid appointment ID")
+}
+
+func (h *Handler) assignManager(w http.ResponseWriter, r *http.Request, entity, invalidIDMsg string) {
+	if _, ok := RequirePermission(w, r, authz.PermAssignmentsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, invalidIDMsg)
+		return
+	}
+	var in model.AssignManager
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	if err := h.services.Assignment.Assign(r.Context(), entity, id, in.ManagerID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Assignment updated"})
+}
+
+func (h *Handler) AdminMyQueue(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermAssignmentsClaim)
+	if !ok {
+		return
+	}
+	queue, err := h.services.Assignment.MyQueue(r.Context(), staffID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, queue)
+}
+
+func (h *Handler) AdminWorkload(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAssignmentsManage); !ok {
+		return
+	}
+	overview, err := h.services.Assignment.Workload(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, overview)
+}
+
+func (h *Handler) AdminListAssignmentRules(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAssignmentsManage); !ok {
+		return
+	}
+	list, err := h.services.Assignment.ListRules(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminUpdateAssignmentRule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAssignmentsManage); !ok {
+		return
+	}
+	var in model.AssignmentRuleUpdate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	rule, err := h.services.Assignment.UpdateRule(r.Context(), chi.URLParam(r, "entity"), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, rule)
+}
+
+func (h *Handler) AdminRunAutoAssign(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAssignmentsManage); !ok {
+		return
+	}
+	result, err := h.services.Assignment.RunAutoAssign(r.Context(), chi.URLParam(r, "entity"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, result)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AdminGetBranchSchedule returns the branch weekly schedule and upcoming calendar exceptions.
+func (h *Handler) AdminGetBranchSchedule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	schedule, err := h.services.Service.AdminGetBranchSchedule(r.Context(), branchID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, schedule)
+}
+
+// AdminReplaceBranchWeekly replaces the branch weekly schedule ({"days": [...]}).
+func (h *Handler) AdminReplaceBranchWeekly(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	var body struct {
+		Days []model.BranchWeeklyHours `json:"days"`
+	}
+	if !DecodeJSON(w, r, &body) {
+		return
+	}
+	schedule, err := h.services.Service.AdminReplaceBranchWeekly(r.Context(), branchID, body.Days)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, schedule)
+}
+
+// AdminUpsertBranchException sets the branch calendar exception for a date.
+func (h *Handler) AdminUpsertBranchException(w http.ResponseWriter, r *http.Request) {
+	requester, ok := RequirePermission(w, r, authz.PermServiceManage)
+	if !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	var in model.BranchCalendarExceptionUpsert
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	e, err := h.services.Service.AdminUpsertBranchException(r.Context(), branchID, requester, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, e)
+}
+
+func (h *Handler) AdminDeleteBranchException(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	exceptionID, err := uuid.Parse(chi.URLParam(r, "exceptionID"))
+	if err != nil {
+		BadRequest(w, "Invalid exception ID")
+		return
+	}
+	if err := h.services.Service.AdminDeleteBranchException(r.Context(), branchID, exceptionID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "deleted"})
+}
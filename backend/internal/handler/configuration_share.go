@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetSharedConfiguration is public: the token itself is the credential.
+func (h *Handler) GetSharedConfiguration(w http.ResponseWriter, r *http.Request) {
+	view, err := h.services.Configurator.GetSharedConfiguration(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, view)
+}
+
+func (h *Handler) CreateConfigurationShare(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+	var in model.ConfigurationShareCreate
+	if r.ContentLength != 0 && !DecodeJSON(w, r, &in) {
+		return
+	}
+	share, err := h.services.Configurator.CreateShare(r.Context(), configID, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: share})
+}
+
+func (h *Handler) ListConfigurationShares(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+	shares, err := h.services.Configurator.ListShares(r.Context(), configID, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, shares)
+}
+
+func (h *Handler) RevokeConfigurationShare(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+	shareID, err := uuid.Parse(chi.URLParam(r, "shareId"))
+	if err != nil {
+		BadRequest(w, "Invalid share ID")
+		return
+	}
+	if err := h.services.Configurator.RevokeShare(r.Context(), configID, shareID, requester, role); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Share link revoked"})
+}
+
+func (h *Handler) CloneConfiguration(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+	var in model.ConfigurationClone
+	if r.ContentLength != 0 && !DecodeJSON(w, r, &in) {
+		return
+	}
+	config, err := h.services.Configurator.CloneConfiguration(r.Context(), configID, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: config})
+}
@@ -121,6 +121,32 @@ func (h *Handler) GetConfiguration(w http.ResponseWriter, r *http.Request) {
 	Success(w, config)
 }
 
+// GetConfigurationPricing returns the price breakdown; ?promo_code= previews a code.
+func (h *Handler) GetConfigurationPricing(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+
+	var promoCode *string
+	if v := r.URL.Query().Get("promo_code"); v != "" {
+		promoCode = &v
+	}
+
+	quote, err := h.services.Configurator.QuoteConfiguration(r.Context(), configID, requester, role, promoCode)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, quote)
+}
+
 func (h *Handler) UpdateConfiguration(w http.ResponseWriter, r *http.Request) {
 	userID, role, ok := RequesterAndRole(w, r)
 	if !ok {
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetBranchDeliveryAvailability(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := RequesterAndRole(w, r); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "branchID"))
+	if err != nil {
+		BadRequest(w, "invalid branch id")
+		return
+	}
+	dateStr := strings.TrimSpace(r.URL.Query().Get("date"))
+	if dateStr == "" {
+		BadRequest(w, "date is required")
+		return
+	}
+	avail, err := h.services.Delivery.Availability(r.Context(), branchID, dateStr)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, avail)
+}
+
+func (h *Handler) GetOrderDelivery(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	d, err := h.services.Delivery.GetForOrder(r.Context(), orderID, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, d)
+}
+
+// ScheduleOrderDelivery books a delivery slot for the order, replacing the current one.
+func (h *Handler) ScheduleOrderDelivery(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	var in model.DeliveryScheduleCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	d, err := h.services.Delivery.Schedule(r.Context(), orderID, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, d)
+}
+
+func (h *Handler) CancelOrderDelivery(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	if err := h.services.Delivery.Cancel(r.Context(), orderID, requester, role); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "delivery cancelled"})
+}
+
+// AdminDeliveryCalendar lists deliveries in a date range (?from=&to=&branch_id=).
+func (h *Handler) AdminDeliveryCalendar(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermOrdersViewAny); !ok {
+		return
+	}
+	q := r.URL.Query()
+	branchID, err := parseOptionalUUID(q.Get("branch_id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	list, err := h.services.Delivery.Calendar(r.Context(), branchID, strings.TrimSpace(q.Get("from")), strings.TrimSpace(q.Get("to")))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
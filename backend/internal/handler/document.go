@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
 	"github.com/carkeeper/backend/internal/service"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -193,3 +195,20 @@ func (h *Handler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
 	}
 	Success(w, map[string]string{"message": "document deleted"})
 }
+
+// AdminGenerateDocument issues a document from its template for an order, appointment or configuration.
+func (h *Handler) AdminGenerateDocument(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermDocumentsGenerate); !ok {
+		return
+	}
+	var in model.DocumentGenerate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	doc, err := h.services.Generator.Generate(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: doc})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetFinanceProducts(w http.ResponseWriter, r *http.Request) {
+	list, err := h.services.Finance.ListProducts(r.Context(), true)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) CalculateConfigurationFinance(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	configID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid configuration ID")
+		return
+	}
+	var req model.FinanceRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	quote, err := h.services.Finance.CalculateForConfiguration(r.Context(), configID, requester, role, req)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, quote)
+}
+
+func (h *Handler) AdminListFinanceProducts(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermFinanceManage); !ok {
+		return
+	}
+	list, err := h.services.Finance.ListProducts(r.Context(), false)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateFinanceProduct(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermFinanceManage); !ok {
+		return
+	}
+	var in model.FinanceProductInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Finance.CreateProduct(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: p})
+}
+
+func (h *Handler) AdminUpdateFinanceProduct(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermFinanceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid finance product ID")
+		return
+	}
+	var in model.FinanceProductInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Finance.UpdateProduct(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, p)
+}
+
+func (h *Handler) AdminDeleteFinanceProduct(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermFinanceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid finance product ID")
+		return
+	}
+	if err := h.services.Finance.DeleteProduct(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Finance product deleted"})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AdminListMaintenancePlans lists maintenance plans (?model_id=&generation_id=).
+func (h *Handler) AdminListMaintenancePlans(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	q := r.URL.Query()
+	modelID, err := parseOptionalUUID(q.Get("model_id"))
+	if err != nil {
+		BadRequest(w, "Invalid model ID")
+		return
+	}
+	generationID, err := parseOptionalUUID(q.Get("generation_id"))
+	if err != nil {
+		BadRequest(w, "Invalid generation ID")
+		return
+	}
+	list, err := h.services.Maintenance.AdminListPlans(r.Context(), modelID, generationID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	var in model.MaintenancePlanInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	plan, err := h.services.Maintenance.AdminCreatePlan(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: plan})
+}
+
+func (h *Handler) AdminUpdateMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid maintenance plan ID")
+		return
+	}
+	var in model.MaintenancePlanInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	plan, err := h.services.Maintenance.AdminUpdatePlan(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, plan)
+}
+
+func (h *Handler) AdminDeleteMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid maintenance plan ID")
+		return
+	}
+	if err := h.services.Maintenance.AdminDeletePlan(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Maintenance plan deleted"})
+}
+
+// GetUserCarMaintenance reports when each maintenance plan of the car is next due.
+func (h *Handler) GetUserCarMaintenance(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	schedule, err := h.services.Maintenance.CarSchedule(r.Context(), id, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, schedule)
+}
+
+func (h *Handler) GetMaintenanceReminders(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.services.Maintenance.ListReminders(r.Context(), userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) DismissMaintenanceReminder(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid reminder ID")
+		return
+	}
+	reminder, err := h.services.Maintenance.DismissReminder(r.Context(), userID, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, reminder)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) ListOrderMessages(w http.ResponseWriter, r *http.Request) {
+	h.listMessages(w, r, "order", "Invalid order ID")
+}
+
+func (h *Handler) PostOrderMessage(w http.ResponseWriter, r *http.Request) {
+	h.postMessage(w, r, "order", "Invalid order ID")
+}
+
+func (h *Handler) MarkOrderMessagesRead(w http.ResponseWriter, r *http.Request) {
+	h.markMessagesRead(w, r, "order", "Invalid order ID")
+}
+
+func (h *Handler) ListAppointmentMessages(w http.ResponseWriter, r *http.Request) {
+	h.listMessages(w, r, "appointment", "Invalid appointment ID")
+}
+
+func (h *Handler) PostAppointmentMessage(w http.ResponseWriter, r *http.Request) {
+	h.postMessage(w, r, "appointment", "Invalid appointment ID")
+}
+
+func (h *Handler) MarkAppointmentMessagesRead(w http.ResponseWriter, r *http.Request) {
+	h.markMessagesRead(w, r, "appointment", "Invalid appointment ID")
+}
+
+func (h *Handler) listMessages(w http.ResponseWriter, r *http.Request, thread, invalidIDMsg string) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, invalidIDMsg)
+		return
+	}
+	list, err := h.services.Message.List(r.Context(), thread, id, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) postMessage(w http.ResponseWriter, r *http.Request, thread, invalidIDMsg string) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, invalidIDMsg)
+		return
+	}
+	var in model.MessageCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	msg, err := h.services.Message.Post(r.Context(), thread, id, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: msg})
+}
+
+func (h *Handler) markMessagesRead(w http.ResponseWriter, r *http.Request, thread, invalidIDMsg string) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, invalidIDMsg)
+		return
+	}
+	if err := h.services.Message.MarkRead(r.Context(), thread, id, requester, role); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Messages marked as read"})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetUserNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	list, err := h.services.Notification.List(r.Context(), userID, unreadOnly)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid notification ID")
+		return
+	}
+	if err := h.services.Notification.MarkRead(r.Context(), userID, id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Notification marked as read"})
+}
+
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	if err := h.services.Notification.MarkAllRead(r.Context(), userID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "All notifications marked as read"})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
 	"github.com/carkeeper/backend/internal/model"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -78,14 +79,12 @@ func (h *Handler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var update struct {
-		Status string `json:"status"`
-	}
+	var update model.OrderStatusChange
 	if !DecodeJSON(w, r, &update) {
 		return
 	}
 
-	if err := h.services.Order.UpdateOrderStatus(r.Context(), orderID, update.Status, requester, role); err != nil {
+	if err := h.services.Order.UpdateOrderStatus(r.Context(), orderID, update, requester, role); err != nil {
 		if errors.Is(err, apperr.ErrForbidden) {
 			Forbidden(w, "Not allowed to update this order")
 			return
@@ -101,3 +100,30 @@ func (h *Handler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	Success(w, order)
 }
+
+// AdminHandOverOrder records delivery of the ordered car and adds it to the customer's garage.
+func (h *Handler) AdminHandOverOrder(w http.ResponseWriter, r *http.Request) {
+	staffID, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	if !authz.HasPermission(role, authz.PermOrdersManageStatus) {
+		Forbidden(w, "You do not have permission for this action")
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	var in model.OrderHandover
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	car, err := h.services.Order.HandOver(r.Context(), orderID, staffID, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: car})
+}
@@ -84,3 +84,68 @@ func (h *Handler) AdminDeleteOrderStatus(w http.ResponseWriter, r *http.Request)
 	}
 	Success(w, map[string]string{"message": "Order status deleted"})
 }
+
+func (h *Handler) AdminListOrderStatusTransitions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermOrdersManageStatus); !ok {
+		return
+	}
+	list, err := h.services.OrderStatus.ListTransitions(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateOrderStatusTransition(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAdminOrderStatuses); !ok {
+		return
+	}
+	var in model.OrderStatusTransitionInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	t, err := h.services.OrderStatus.CreateTransition(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: t})
+}
+
+func (h *Handler) AdminUpdateOrderStatusTransition(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAdminOrderStatuses); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "transitionId"))
+	if err != nil {
+		BadRequest(w, "Invalid transition ID")
+		return
+	}
+	var in model.OrderStatusTransitionInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	t, err := h.services.OrderStatus.UpdateTransition(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, t)
+}
+
+func (h *Handler) AdminDeleteOrderStatusTransition(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAdminOrderStatuses); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "transitionId"))
+	if err != nil {
+		BadRequest(w, "Invalid transition ID")
+		return
+	}
+	if err := h.services.OrderStatus.DeleteTransition(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Order status transition deleted"})
+}
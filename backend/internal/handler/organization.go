@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.services.Organization.List(r.Context(), userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+// CreateOrganization starts a household or company with the requester as its owner.
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	var in model.OrganizationInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	o, err := h.services.Organization.Create(r.Context(), userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: o})
+}
+
+func (h *Handler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	o, err := h.services.Organization.Get(r.Context(), id, userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, o)
+}
+
+func (h *Handler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	var in model.OrganizationInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	o, err := h.services.Organization.Update(r.Context(), id, userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, o)
+}
+
+func (h *Handler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	if err := h.services.Organization.Delete(r.Context(), id, userID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Organization deleted"})
+}
+
+func (h *Handler) ListOrganizationMembers(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	list, err := h.services.Organization.Members(r.Context(), id, userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+// AddOrganizationMember adds a registered customer by email with the given role.
+func (h *Handler) AddOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	var in model.OrganizationMemberInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	list, err := h.services.Organization.AddMember(r.Context(), id, userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: list})
+}
+
+func (h *Handler) UpdateOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		BadRequest(w, "Invalid user ID")
+		return
+	}
+	var in model.OrganizationMemberUpdate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	list, err := h.services.Organization.UpdateMember(r.Context(), id, userID, memberID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) RemoveOrganizationMember(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	memberID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		BadRequest(w, "Invalid user ID")
+		return
+	}
+	if err := h.services.Organization.RemoveMember(r.Context(), id, userID, memberID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Member removed"})
+}
+
+func (h *Handler) ListOrganizationCars(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	list, err := h.services.Organization.Cars(r.Context(), id, userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+// ListOrganizationAppointments returns the fleet's appointments with the staff list filters.
+func (h *Handler) ListOrganizationAppointments(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	filters, msg := parseAppointmentFilters(r.URL.Query())
+	if msg != "" {
+		BadRequest(w, msg)
+		return
+	}
+	list, err := h.services.Organization.Appointments(r.Context(), id, userID, filters)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+// GetOrganizationSpend returns the service spend report (?from=&to=).
+func (h *Handler) GetOrganizationSpend(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid organization ID")
+		return
+	}
+	q := r.URL.Query()
+	report, err := h.services.Organization.Spend(r.Context(), id, userID, q.Get("from"), q.Get("to"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, report)
+}
+
+// ShareUserCar shares the requester's car with an organization.
+func (h *Handler) ShareUserCar(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	var in model.UserCarShare
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	car, err := h.services.Organization.ShareCar(r.Context(), id, userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, car)
+}
+
+func (h *Handler) UnshareUserCar(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	if err := h.services.Organization.UnshareCar(r.Context(), id, userID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Car is no longer shared"})
+}
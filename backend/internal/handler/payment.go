@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetOrderPayments(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	billing, err := h.services.Payment.OrderPayments(r.Context(), orderID, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, billing)
+}
+
+func (h *Handler) PayOrderInvoice(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "invoiceId"))
+	if err != nil {
+		BadRequest(w, "Invalid invoice ID")
+		return
+	}
+	var in model.PaymentCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Payment.PayOnline(r.Context(), orderID, invoiceID, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: p})
+}
+
+// PaymentWebhook receives asynchronous confirmations from the payment gateway (no user auth; the
+// gateway signs the body).
+func (h *Handler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var mb *http.MaxBytesError
+		if errors.As(err, &mb) {
+			Error(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
+		BadRequest(w, "Invalid request body")
+		return
+	}
+	if err := h.services.Payment.HandleWebhook(r.Context(), r.Header, body); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Processed"})
+}
+
+func (h *Handler) AdminCreateInvoice(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermPaymentsManage)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	var in model.InvoiceCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	inv, err := h.services.Payment.CreateInvoice(r.Context(), orderID, staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: inv})
+}
+
+func (h *Handler) AdminCancelInvoice(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPaymentsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid invoice ID")
+		return
+	}
+	if err := h.services.Payment.CancelInvoice(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Invoice cancelled"})
+}
+
+func (h *Handler) AdminRecordPayment(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermPaymentsManage)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid invoice ID")
+		return
+	}
+	var in model.PaymentCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Payment.RecordOfflinePayment(r.Context(), id, staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: p})
+}
+
+func (h *Handler) AdminRefundPayment(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermPaymentsRefund)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid payment ID")
+		return
+	}
+	var in model.RefundCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	rf, err := h.services.Payment.Refund(r.Context(), id, staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: rf})
+}
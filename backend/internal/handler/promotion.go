@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) AdminListPromotions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	list, err := h.services.Promotion.ListAll(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminGetPromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid promotion ID")
+		return
+	}
+	p, err := h.services.Promotion.Get(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, p)
+}
+
+func (h *Handler) AdminCreatePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	var in model.PromotionInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Promotion.Create(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: p})
+}
+
+func (h *Handler) AdminUpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid promotion ID")
+		return
+	}
+	var in model.PromotionInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	p, err := h.services.Promotion.Update(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, p)
+}
+
+func (h *Handler) AdminDeletePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid promotion ID")
+		return
+	}
+	if err := h.services.Promotion.Delete(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Promotion deleted"})
+}
+
+func (h *Handler) AdminListPromotionCodes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid promotion ID")
+		return
+	}
+	codes, err := h.services.Promotion.ListCodes(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, codes)
+}
+
+func (h *Handler) AdminCreatePromotionCodes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermPromotionsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid promotion ID")
+		return
+	}
+	var in model.PromotionCodesCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	codes, err := h.services.Promotion.CreateCodes(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: codes})
+}
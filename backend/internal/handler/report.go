@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+)
+
+// AdminOrdersReport returns orders and revenue (?from=&to=&group_by=period|brand|model|manager&period=).
+func (h *Handler) AdminOrdersReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermReportsView); !ok {
+		return
+	}
+	q := r.URL.Query()
+	report, err := h.services.Report.Orders(r.Context(), q.Get("from"), q.Get("to"), q.Get("group_by"), q.Get("period"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, report)
+}
+
+// AdminFunnelReport returns the configuration-to-payment funnel (?from=&to=).
+func (h *Handler) AdminFunnelReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermReportsView); !ok {
+		return
+	}
+	q := r.URL.Query()
+	report, err := h.services.Report.Funnel(r.Context(), q.Get("from"), q.Get("to"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, report)
+}
+
+// AdminServiceReport returns branch utilisation, cancellation and no-show rates (?from=&to=&branch_id=).
+func (h *Handler) AdminServiceReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermReportsView); !ok {
+		return
+	}
+	q := r.URL.Query()
+	branchID, err := parseOptionalUUID(q.Get("branch_id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	report, err := h.services.Report.Service(r.Context(), q.Get("from"), q.Get("to"), branchID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, report)
+}
+
+// AdminRefreshReports rebuilds the analytics views now instead of waiting for the scheduled job.
+func (h *Handler) AdminRefreshReports(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermReportsView); !ok {
+		return
+	}
+	refreshed, err := h.services.Report.Refresh(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	if !refreshed {
+		Error(w, http.StatusConflict, "reports are already being refreshed")
+		return
+	}
+	Success(w, map[string]string{"message": "Reports refreshed"})
+}
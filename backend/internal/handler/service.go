@@ -67,10 +67,48 @@ func (h *Handler) GetBranchAvailability(w http.ResponseWriter, r *http.Request)
 		BadRequest(w, "date is required")
 		return
 	}
+	ids, ok := parseServiceTypeIDs(w, r)
+	if !ok {
+		return
+	}
+	avail, err := h.services.Service.BranchAvailability(r.Context(), branchID, dateStr, ids)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, avail)
+}
+
+// GetBranchAvailabilityRange returns free slots per day for ?from=&to=&service_type_ids=.
+func (h *Handler) GetBranchAvailabilityRange(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := RequesterAndRole(w, r); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "branchID"))
+	if err != nil {
+		BadRequest(w, "invalid branch id")
+		return
+	}
+	ids, ok := parseServiceTypeIDs(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	avail, err := h.services.Service.BranchAvailabilityRange(r.Context(), branchID,
+		strings.TrimSpace(q.Get("from")), strings.TrimSpace(q.Get("to")), ids)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, avail)
+}
+
+// parseServiceTypeIDs reads the required comma-separated service_type_ids query parameter.
+func parseServiceTypeIDs(w http.ResponseWriter, r *http.Request) ([]uuid.UUID, bool) {
 	raw := strings.TrimSpace(r.URL.Query().Get("service_type_ids"))
 	if raw == "" {
 		BadRequest(w, "service_type_ids is required")
-		return
+		return nil, false
 	}
 	parts := strings.Split(raw, ",")
 	var ids []uuid.UUID
@@ -82,20 +120,15 @@ func (h *Handler) GetBranchAvailability(w http.ResponseWriter, r *http.Request)
 		id, err := uuid.Parse(p)
 		if err != nil {
 			BadRequest(w, "invalid service_type_ids")
-			return
+			return nil, false
 		}
 		ids = append(ids, id)
 	}
 	if len(ids) == 0 {
 		BadRequest(w, "service_type_ids is required")
-		return
+		return nil, false
 	}
-	avail, err := h.services.Service.BranchAvailability(r.Context(), branchID, dateStr, ids)
-	if err != nil {
-		HandleError(w, r, err)
-		return
-	}
-	Success(w, avail)
+	return ids, true
 }
 
 func (h *Handler) GetUserCars(w http.ResponseWriter, r *http.Request) {
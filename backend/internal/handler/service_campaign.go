@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AdminListServiceCampaigns lists recall and service campaigns (?is_active=).
+func (h *Handler) AdminListServiceCampaigns(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	var isActive *bool
+	if s := r.URL.Query().Get("is_active"); s != "" {
+		val, err := strconv.ParseBool(s)
+		if err != nil {
+			BadRequest(w, "is_active must be true or false")
+			return
+		}
+		isActive = &val
+	}
+	list, err := h.services.ServiceCampaign.AdminList(r.Context(), isActive)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminGetServiceCampaign(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	c, err := h.services.ServiceCampaign.AdminGet(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, c)
+}
+
+// AdminCreateServiceCampaign adds a campaign and notifies the owners of matching cars.
+func (h *Handler) AdminCreateServiceCampaign(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermServiceManage)
+	if !ok {
+		return
+	}
+	var in model.ServiceCampaignInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	c, err := h.services.ServiceCampaign.AdminCreate(r.Context(), staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: c})
+}
+
+func (h *Handler) AdminUpdateServiceCampaign(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	var in model.ServiceCampaignInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	c, err := h.services.ServiceCampaign.AdminUpdate(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, c)
+}
+
+func (h *Handler) AdminDeleteServiceCampaign(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	if err := h.services.ServiceCampaign.AdminDelete(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Campaign deleted"})
+}
+
+// AdminMatchServiceCampaign re-runs matching for a campaign, e.g. after cars were imported.
+func (h *Handler) AdminMatchServiceCampaign(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	n, err := h.services.ServiceCampaign.AdminMatch(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]int{"matched": n})
+}
+
+// AdminListServiceCampaignVehicles lists the affected cars with their owners (?status=open|done).
+func (h *Handler) AdminListServiceCampaignVehicles(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	list, err := h.services.ServiceCampaign.AdminVehicles(r.Context(), id, r.URL.Query().Get("status"))
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+// AdminCompleteServiceCampaignVehicle links campaign work on a car to a completed appointment.
+func (h *Handler) AdminCompleteServiceCampaignVehicle(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid campaign ID")
+		return
+	}
+	userCarID, err := uuid.Parse(chi.URLParam(r, "userCarID"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	var in model.ServiceCampaignCompletion
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	if err := h.services.ServiceCampaign.AdminCompleteVehicle(r.Context(), id, userCarID, in); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Campaign work recorded"})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// carServiceBookVIN resolves the {id} car of the requester to its VIN.
+func (h *Handler) carServiceBookVIN(w http.ResponseWriter, r *http.Request) (string, bool) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return "", false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return "", false
+	}
+	vin, err := h.services.ServiceHistory.CarVIN(r.Context(), id, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return "", false
+	}
+	return vin, true
+}
+
+// GetUserCarServiceHistory returns the service book of the car's VIN, including visits made
+// under previous owners.
+func (h *Handler) GetUserCarServiceHistory(w http.ResponseWriter, r *http.Request) {
+	vin, ok := h.carServiceBookVIN(w, r)
+	if !ok {
+		return
+	}
+	h.writeServiceBook(w, r, vin)
+}
+
+func (h *Handler) GetUserCarServiceHistoryPDF(w http.ResponseWriter, r *http.Request) {
+	vin, ok := h.carServiceBookVIN(w, r)
+	if !ok {
+		return
+	}
+	h.writeServiceBookPDF(w, r, vin)
+}
+
+func (h *Handler) DownloadUserCarServiceHistoryDocument(w http.ResponseWriter, r *http.Request) {
+	vin, ok := h.carServiceBookVIN(w, r)
+	if !ok {
+		return
+	}
+	h.writeServiceBookDocument(w, r, vin)
+}
+
+// AdminGetServiceHistory returns the service book of any VIN, also of cars no longer in a garage.
+func (h *Handler) AdminGetServiceHistory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermGarageViewAny); !ok {
+		return
+	}
+	h.writeServiceBook(w, r, chi.URLParam(r, "vin"))
+}
+
+func (h *Handler) AdminGetServiceHistoryPDF(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermGarageViewAny); !ok {
+		return
+	}
+	h.writeServiceBookPDF(w, r, chi.URLParam(r, "vin"))
+}
+
+func (h *Handler) AdminDownloadServiceHistoryDocument(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermGarageViewAny); !ok {
+		return
+	}
+	h.writeServiceBookDocument(w, r, chi.URLParam(r, "vin"))
+}
+
+func (h *Handler) writeServiceBook(w http.ResponseWriter, r *http.Request, vin string) {
+	book, err := h.services.ServiceHistory.ServiceBook(r.Context(), vin)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, book)
+}
+
+func (h *Handler) writeServiceBookPDF(w http.ResponseWriter, r *http.Request, vin string) {
+	pdf, fileName, err := h.services.ServiceHistory.ServiceBookPDF(r.Context(), vin)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	writeAttachment(w, bytes.NewReader(pdf), "application/pdf", fileName)
+}
+
+func (h *Handler) writeServiceBookDocument(w http.ResponseWriter, r *http.Request, vin string) {
+	id, err := uuid.Parse(chi.URLParam(r, "documentID"))
+	if err != nil {
+		BadRequest(w, "invalid document id")
+		return
+	}
+	rc, doc, err := h.services.ServiceHistory.OpenDocument(r.Context(), vin, id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	defer rc.Close()
+
+	ct := "application/octet-stream"
+	if doc.MimeType != nil && *doc.MimeType != "" {
+		ct = *doc.MimeType
+	}
+	fn := "document"
+	if doc.FileName != nil && *doc.FileName != "" {
+		fn = *doc.FileName
+	}
+	writeAttachment(w, rc, ct, fn)
+}
+
+func writeAttachment(w http.ResponseWriter, body io.Reader, contentType, fileName string) {
+	w.Header().Set("Content-Type", contentType)
+	cd := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	if cd == "" {
+		cd = `attachment; filename="document"`
+	}
+	w.Header().Set("Content-Disposition", cd)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, body)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AdminListBranchResources lists the bays and technicians of a branch.
+func (h *Handler) AdminListBranchResources(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	list, err := h.services.Service.AdminListBranchResources(r.Context(), branchID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateBranchResource(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	var in model.ServiceResourceInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	res, err := h.services.Service.AdminCreateBranchResource(r.Context(), branchID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: res})
+}
+
+func (h *Handler) AdminUpdateServiceResource(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermServiceManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid resource ID")
+		return
+	}
+	var in model.ServiceResourceInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	res, err := h.services.Service.AdminUpdateResource(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, res)
+}
+
+// AdminWorkshopSchedule returns a branch day's appointments by bay and technician
+// (?branch_id=&date=YYYY-MM-DD).
+func (h *Handler) AdminWorkshopSchedule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAppointmentsViewAny); !ok {
+		return
+	}
+	branchID, err := uuid.Parse(r.URL.Query().Get("branch_id"))
+	if err != nil {
+		BadRequest(w, "Invalid branch ID")
+		return
+	}
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		BadRequest(w, "date is required")
+		return
+	}
+	schedule, err := h.services.Service.WorkshopSchedule(r.Context(), branchID, date)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, schedule)
+}
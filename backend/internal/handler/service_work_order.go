@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// odometerBody is the optional odometer reading sent on check-in and completion.
+type odometerBody struct {
+	OdometerKm *int `json:"odometer_km"`
+}
+
+// AdminCheckInAppointment receives the car and starts work on a scheduled appointment.
+func (h *Handler) AdminCheckInAppointment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAppointmentsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid appointment ID")
+		return
+	}
+	var body odometerBody
+	if !DecodeJSON(w, r, &body) {
+		return
+	}
+	a, err := h.services.Service.CheckInAppointment(r.Context(), id, body.OdometerKm)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, a)
+}
+
+// AdminUpdateWorkOrder records the work performed on an appointment in progress.
+func (h *Handler) AdminUpdateWorkOrder(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermAppointmentsManage)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid appointment ID")
+		return
+	}
+	var in model.ServiceWorkOrderUpdate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	a, err := h.services.Service.UpdateWorkOrder(r.Context(), id, staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, a)
+}
+
+// AdminCompleteAppointment closes an appointment in progress and issues the service act.
+func (h *Handler) AdminCompleteAppointment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAppointmentsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid appointment ID")
+		return
+	}
+	var body odometerBody
+	if !DecodeJSON(w, r, &body) {
+		return
+	}
+	a, err := h.services.Service.CompleteAppointment(r.Context(), id, body.OdometerKm)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, a)
+}
+
+func (h *Handler) AdminMarkAppointmentNoShow(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermAppointmentsManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid appointment ID")
+		return
+	}
+	a, err := h.services.Service.MarkAppointmentNoShow(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, a)
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+)
+
+// AdminCreateAppointment books a visit for a customer by phone or registers a walk-in.
+func (h *Handler) AdminCreateAppointment(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermAppointmentsManage)
+	if !ok {
+		return
+	}
+	var in model.StaffAppointmentCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	a, err := h.services.Service.StaffCreateAppointment(r.Context(), staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: a})
+}
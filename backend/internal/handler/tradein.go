@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) AdminListDepreciationRules(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInAppraise); !ok {
+		return
+	}
+	list, err := h.services.TradeIn.ListRules(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateDepreciationRule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInManage); !ok {
+		return
+	}
+	var in model.DepreciationRuleInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	rule, err := h.services.TradeIn.CreateRule(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: rule})
+}
+
+func (h *Handler) AdminUpdateDepreciationRule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid rule ID")
+		return
+	}
+	var in model.DepreciationRuleInput
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	rule, err := h.services.TradeIn.UpdateRule(r.Context(), id, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, rule)
+}
+
+func (h *Handler) AdminDeleteDepreciationRule(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInManage); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid rule ID")
+		return
+	}
+	if err := h.services.TradeIn.DeleteRule(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Depreciation rule deleted"})
+}
+
+func (h *Handler) AdminEstimateTradeIn(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInAppraise); !ok {
+		return
+	}
+	var in model.TradeInAppraisalCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	v, err := h.services.TradeIn.Estimate(r.Context(), in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, v)
+}
+
+func (h *Handler) AdminListTradeInOffers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInAppraise); !ok {
+		return
+	}
+	list, err := h.services.TradeIn.ListOffers(r.Context())
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AdminCreateTradeInOffer(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermTradeInAppraise)
+	if !ok {
+		return
+	}
+	var in model.TradeInAppraisalCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	offer, err := h.services.TradeIn.CreateOffer(r.Context(), staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: offer})
+}
+
+func (h *Handler) AdminCancelTradeInOffer(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermTradeInAppraise); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid offer ID")
+		return
+	}
+	if err := h.services.TradeIn.CancelOffer(r.Context(), id); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Trade-in offer cancelled"})
+}
+
+func (h *Handler) GetUserTradeInOffers(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.services.TradeIn.ListUserOffers(r.Context(), userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
+
+func (h *Handler) AcceptTradeInOffer(w http.ResponseWriter, r *http.Request) {
+	h.decideTradeInOffer(w, r, true)
+}
+
+func (h *Handler) RejectTradeInOffer(w http.ResponseWriter, r *http.Request) {
+	h.decideTradeInOffer(w, r, false)
+}
+
+func (h *Handler) decideTradeInOffer(w http.ResponseWriter, r *http.Request, accept bool) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid offer ID")
+		return
+	}
+	offer, err := h.services.TradeIn.DecideOffer(r.Context(), id, userID, accept)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, offer)
+}
+
+func (h *Handler) AttachOrderTradeIn(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	var in model.TradeInAttach
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	order, err := h.services.TradeIn.AttachToOrder(r.Context(), orderID, requester, role, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, order)
+}
+
+func (h *Handler) DetachOrderTradeIn(w http.ResponseWriter, r *http.Request) {
+	requester, role, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid order ID")
+		return
+	}
+	order, err := h.services.TradeIn.DetachFromOrder(r.Context(), orderID, requester, role)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, order)
+}
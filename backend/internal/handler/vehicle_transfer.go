@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// StartUserCarTransfer issues a one-time code the seller passes to the buyer.
+func (h *Handler) StartUserCarTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	var in model.VehicleTransferCreate
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	t, err := h.services.VehicleTransfer.Start(r.Context(), id, userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: t})
+}
+
+func (h *Handler) GetUserCarTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	t, err := h.services.VehicleTransfer.Pending(r.Context(), id, userID)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, t)
+}
+
+func (h *Handler) CancelUserCarTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	if err := h.services.VehicleTransfer.Cancel(r.Context(), id, userID); err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, map[string]string{"message": "Transfer cancelled"})
+}
+
+// AcceptCarTransfer moves a car into the requester's garage by VIN and transfer code.
+func (h *Handler) AcceptCarTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, _, ok := RequesterAndRole(w, r)
+	if !ok {
+		return
+	}
+	var in model.VehicleTransferAccept
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	t, err := h.services.VehicleTransfer.Accept(r.Context(), userID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, t)
+}
+
+// AdminForceCarTransfer moves a car to another customer without the seller's code.
+func (h *Handler) AdminForceCarTransfer(w http.ResponseWriter, r *http.Request) {
+	staffID, ok := RequirePermission(w, r, authz.PermGarageTransfer)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	var in model.VehicleTransferForce
+	if !DecodeJSON(w, r, &in) {
+		return
+	}
+	t, err := h.services.VehicleTransfer.AdminForce(r.Context(), id, staffID, in)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	JSON(w, http.StatusCreated, Response{Success: true, Data: t})
+}
+
+// AdminListCarTransfers returns the ownership transfer log of a car.
+func (h *Handler) AdminListCarTransfers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := RequirePermission(w, r, authz.PermGarageViewAny); !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		BadRequest(w, "Invalid user car ID")
+		return
+	}
+	list, err := h.services.VehicleTransfer.AdminList(r.Context(), id)
+	if err != nil {
+		HandleError(w, r, err)
+		return
+	}
+	Success(w, list)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/carkeeper/backend/database"
 	"github.com/carkeeper/backend/internal/app"
 	"github.com/carkeeper/backend/internal/handler"
+	"github.com/carkeeper/backend/internal/payment"
 	"github.com/carkeeper/backend/internal/repository"
 	"github.com/carkeeper/backend/internal/service"
 	"github.com/carkeeper/backend/internal/testsupport"
@@ -52,7 +53,8 @@ func TestMain(m *testing.M) {
 
 	repos := repository.New(db)
 	service.BootstrapAuthz(context.Background(), repos)
-	services := service.New(repos, cfg, store)
+	gateway := payment.NewFake(cfg.Payment.WebhookSecret, cfg.Payment.FakeAutoConfirm)
+	services := service.New(repos, cfg, store, gateway)
 	handlers := handler.New(services, cfg)
 	testHandler = app.NewRouter(handlers, cfg, db)
 	testCfg = cfg
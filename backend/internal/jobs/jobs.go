@@ -0,0 +1,60 @@
+// Package jobs runs periodic in-process background tasks.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Task is a named function executed every Interval until the scheduler context is cancelled.
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start runs every task once immediately and then on its interval. The returned wait function
+// blocks until all task loops have exited after ctx is cancelled.
+func Start(ctx context.Context, tasks ...Task) (wait func()) {
+	var wg sync.WaitGroup
+	for _, t := range tasks {
+		wg.Add(1)
+		go func(t Task) {
+			defer wg.Done()
+			loop(ctx, t)
+		}(t)
+	}
+	return wg.Wait
+}
+
+func loop(ctx context.Context, t Task) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		runOnce(ctx, t)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce isolates a task run so a panic or error is logged instead of stopping the scheduler.
+func runOnce(ctx context.Context, t Task) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("background job panicked", "job", t.Name, "panic", rec)
+		}
+	}()
+	started := time.Now()
+	if err := t.Run(ctx); err != nil {
+		if ctx.Err() == nil {
+			slog.Error("background job failed", "job", t.Name, "err", err)
+		}
+		return
+	}
+	slog.Debug("background job finished", "job", t.Name, "duration", time.Since(started))
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStart_RunsImmediatelyAndStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+	wait := Start(ctx, Task{
+		Name:     "count",
+		Interval: time.Hour,
+		Run: func(context.Context) error {
+			if runs.Add(1) == 1 {
+				close(done)
+			}
+			return nil
+		},
+	})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task did not run on start")
+	}
+	cancel()
+	wait()
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs = %d, want 1", got)
+	}
+}
+
+func TestStart_SurvivesErrorsAndPanics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	wait := Start(ctx, Task{
+		Name:     "flaky",
+		Interval: time.Millisecond,
+		Run: func(context.Context) error {
+			switch runs.Add(1) {
+			case 1:
+				return errors.New("boom")
+			case 2:
+				panic("boom")
+			case 3:
+				cancel()
+			}
+			return nil
+		},
+	})
+	wait()
+	if got := runs.Load(); got < 3 {
+		t.Fatalf("runs = %d, want at least 3", got)
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssignmentRule matches table assignment_rules: how new orders or appointments get an owner.
+type AssignmentRule struct {
+	EntityType     string     `db:"entity_type" json:"entity_type"`
+	Strategy       string     `db:"strategy" json:"strategy"`
+	RoleCode       string     `db:"role_code" json:"role_code"`
+	LastAssigneeID *uuid.UUID `db:"last_assignee_id" json:"last_assignee_id,omitempty"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// AssignmentRuleUpdate is the admin payload for an assignment rule.
+type AssignmentRuleUpdate struct {
+	Strategy string `json:"strategy"`
+	RoleCode string `json:"role_code"`
+}
+
+// AssignManager sets or clears (null) the responsible staff member.
+type AssignManager struct {
+	ManagerID *uuid.UUID `json:"manager_id"`
+}
+
+// AssigneeLoad is a staff member with the number of open items assigned to them.
+type AssigneeLoad struct {
+	UserID           uuid.UUID `json:"user_id"`
+	Name             string    `json:"name"`
+	Role             string    `json:"role"`
+	OpenOrders       int       `json:"open_orders"`
+	OpenAppointments int       `json:"open_appointments"`
+}
+
+// StatusDuration is the average time orders spend in a status.
+type StatusDuration struct {
+	Status   string  `json:"status"`
+	Label    string  `json:"label"`
+	AvgHours float64 `json:"avg_hours"`
+	Samples  int     `json:"samples"`
+}
+
+// WorkloadOverview is the admin view of open work per staff member and order pipeline timing.
+type WorkloadOverview struct {
+	Managers               []AssigneeLoad   `json:"managers"`
+	UnassignedOrders       int              `json:"unassigned_orders"`
+	UnassignedAppointments int              `json:"unassigned_appointments"`
+	OrderStatusDurations   []StatusDuration `json:"order_status_durations"`
+}
+
+// MyQueue is the open work assigned to the requesting staff member.
+type MyQueue struct {
+	Orders       []OrderWithDetails              `json:"orders"`
+	Appointments []ServiceAppointmentWithDetails `json:"appointments"`
+}
+
+// AutoAssignResult reports how many unassigned items were distributed.
+type AutoAssignResult struct {
+	Assigned int `json:"assigned"`
+}
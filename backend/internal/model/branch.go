@@ -18,6 +18,9 @@ type Branch struct {
 	WorkdayEndMinutes   int       `db:"workday_end_minutes" json:"workday_end_minutes"`
 	SlotStepMinutes     int       `db:"slot_step_minutes" json:"slot_step_minutes"`
 	ConcurrentBays      int       `db:"concurrent_bays" json:"concurrent_bays"`
+	// HandoverBays is the number of parallel car deliveries (0: the branch does not deliver cars).
+	HandoverBays            int `db:"handover_bays" json:"handover_bays"`
+	HandoverDurationMinutes int `db:"handover_duration_minutes" json:"handover_duration_minutes"`
 	CreatedAt           time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at" json:"updated_at"`
 }
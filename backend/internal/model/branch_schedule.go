@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BranchDayHours are the working hours of one branch day in minutes from local midnight; the
+// optional break (lunch) is excluded from bookable time.
+type BranchDayHours struct {
+	OpenMinutes       int  `db:"open_minutes" json:"open_minutes"`
+	CloseMinutes      int  `db:"close_minutes" json:"close_minutes"`
+	BreakStartMinutes *int `db:"break_start_minutes" json:"break_start_minutes,omitempty"`
+	BreakEndMinutes   *int `db:"break_end_minutes" json:"break_end_minutes,omitempty"`
+}
+
+// BranchWeeklyHours matches table branch_weekly_hours; Weekday follows time.Weekday (0 is Sunday).
+// A weekday without a row is a day off.
+type BranchWeeklyHours struct {
+	Weekday int `db:"weekday" json:"weekday"`
+	BranchDayHours
+}
+
+// BranchCalendarException matches table branch_calendar_exceptions: it replaces the weekly
+// schedule on one date (holiday closure, shortened day or extra working day).
+type BranchCalendarException struct {
+	ExceptionID uuid.UUID `db:"exception_id" json:"exception_id"`
+	BranchID    uuid.UUID `db:"branch_id" json:"branch_id"`
+	// Day is the branch-local date, YYYY-MM-DD.
+	Day       string          `db:"day" json:"day"`
+	IsClosed  bool            `db:"is_closed" json:"is_closed"`
+	Hours     *BranchDayHours `json:"hours,omitempty"`
+	Note      *string         `db:"note" json:"note,omitempty"`
+	CreatedBy *uuid.UUID      `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// BranchCalendarExceptionUpsert creates or replaces the exception for a date. Hours are required
+// unless IsClosed is set.
+type BranchCalendarExceptionUpsert struct {
+	Day      string          `json:"day"`
+	IsClosed bool            `json:"is_closed"`
+	Hours    *BranchDayHours `json:"hours,omitempty"`
+	Note     *string         `json:"note,omitempty"`
+}
+
+// BranchSchedule is the admin view of a branch calendar: the weekly schedule and the exceptions
+// in the requested date range.
+type BranchSchedule struct {
+	BranchID   uuid.UUID                 `json:"branch_id"`
+	Timezone   string                    `json:"timezone"`
+	Weekly     []BranchWeeklyHours       `json:"weekly"`
+	Exceptions []BranchCalendarException `json:"exceptions"`
+}
+
+// BusyInterval is the [Start, End) time a booking occupies one bay of a branch, with the
+// resources it was assigned (service appointments only).
+type BusyInterval struct {
+	Start time.Time
+	End   time.Time
+	ResourceAssignment
+}
@@ -13,10 +13,22 @@ type Configuration struct {
 	ColorID         uuid.UUID `db:"color_id" json:"color_id"`
 	Status          string    `db:"status" json:"status"`
 	TotalPrice      float64   `db:"total_price" json:"total_price"`
+	// ExpiresAt is when a draft becomes "expired" unless it is edited or confirmed.
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	// PriceLockedUntil is set on confirmation; until then the locked catalog prices are quoted.
+	PriceLockedUntil *time.Time `db:"price_locked_until" json:"price_locked_until,omitempty"`
+	LockedTrimPrice  *float64   `db:"locked_trim_price" json:"-"`
+	LockedColorPrice *float64   `db:"locked_color_price" json:"-"`
 	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
+// PriceLockActive reports whether the prices captured on confirmation still apply at now.
+func (c *Configuration) PriceLockActive(now time.Time) bool {
+	return c.Status == "confirmed" && c.PriceLockedUntil != nil && now.Before(*c.PriceLockedUntil) &&
+		c.LockedTrimPrice != nil && c.LockedColorPrice != nil
+}
+
 type ConfigurationCreate struct {
 	TrimID     uuid.UUID   `json:"trim_id" validate:"required"`
 	ColorID    uuid.UUID   `json:"color_id" validate:"required"`
@@ -29,5 +41,6 @@ type ConfigurationWithDetails struct {
 	ColorName  string `db:"color_name" json:"color_name"`
 	ColorHex   *string `db:"color_hex" json:"color_hex,omitempty"`
 	Options    []Option `json:"options"`
+	Pricing    *PriceBreakdown `json:"pricing,omitempty"`
 }
 
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConfigurationShare matches table configuration_shares.
+type ConfigurationShare struct {
+	ShareID         uuid.UUID  `db:"share_id" json:"share_id"`
+	ConfigurationID uuid.UUID  `db:"configuration_id" json:"configuration_id"`
+	Token           string     `db:"token" json:"token"`
+	CreatedBy       *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	ExpiresAt       time.Time  `db:"expires_at" json:"expires_at"`
+	RevokedAt       *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	ViewCount       int        `db:"view_count" json:"view_count"`
+	LastViewedAt    *time.Time `db:"last_viewed_at" json:"last_viewed_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ConfigurationShareCreate sets the link lifetime in days (default applied by the service).
+type ConfigurationShareCreate struct {
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+// SharedConfiguration is the read-only public view behind a share token (no owner data).
+type SharedConfiguration struct {
+	ConfigurationID uuid.UUID       `json:"configuration_id"`
+	TrimID          uuid.UUID       `json:"trim_id"`
+	BrandName       string          `json:"brand_name"`
+	ModelName       string          `json:"model_name"`
+	GenerationName  string          `json:"generation_name"`
+	TrimName        string          `json:"trim_name"`
+	ImageURL        *string         `json:"image_url,omitempty"`
+	ColorName       string          `json:"color_name"`
+	ColorHex        *string         `json:"color_hex,omitempty"`
+	Options         []Option        `json:"options"`
+	TotalPrice      float64         `json:"total_price"`
+	Pricing         *PriceBreakdown `json:"pricing,omitempty"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+}
+
+// ConfigurationClone optionally carries the share token that grants access to a foreign configuration.
+type ConfigurationClone struct {
+	ShareToken *string `json:"share_token,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryAppointment matches table delivery_appointments: a slot for handing a paid order's car over.
+type DeliveryAppointment struct {
+	DeliveryID      uuid.UUID  `db:"delivery_id" json:"delivery_id"`
+	OrderID         uuid.UUID  `db:"order_id" json:"order_id"`
+	BranchID        uuid.UUID  `db:"branch_id" json:"branch_id"`
+	ScheduledAt     time.Time  `db:"scheduled_at" json:"scheduled_at"`
+	DurationMinutes int        `db:"duration_minutes" json:"duration_minutes"`
+	Status          string     `db:"status" json:"status"`
+	Notes           *string    `db:"notes" json:"notes,omitempty"`
+	CreatedBy       *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+	// Context fields populated by repository joins.
+	BranchName    string    `db:"branch_name" json:"branch_name"`
+	BranchAddress string    `db:"branch_address" json:"branch_address"`
+	Timezone      string    `db:"timezone" json:"timezone"`
+	OrderStatus   string    `db:"order_status" json:"order_status"`
+	CustomerID    uuid.UUID `db:"customer_id" json:"customer_id"`
+	CustomerName  string    `db:"customer_name" json:"customer_name,omitempty"`
+	CustomerPhone *string   `db:"customer_phone" json:"customer_phone,omitempty"`
+	TrimName      string    `db:"trim_name" json:"trim_name"`
+	ColorName     string    `db:"color_name" json:"color_name"`
+}
+
+// DeliveryScheduleCreate is the request to book (or re-book) a delivery slot for an order.
+type DeliveryScheduleCreate struct {
+	BranchID    uuid.UUID `json:"branch_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Notes       *string   `json:"notes,omitempty"`
+	// Set by the service layer from the branch settings.
+	DurationMinutes int `json:"-"`
+}
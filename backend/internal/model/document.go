@@ -12,6 +12,7 @@ type Document struct {
 	UserID               uuid.UUID  `db:"user_id" json:"user_id"`
 	OrderID              *uuid.UUID `db:"order_id" json:"order_id,omitempty"`
 	ServiceAppointmentID *uuid.UUID `db:"service_appointment_id" json:"service_appointment_id,omitempty"`
+	ConfigurationID      *uuid.UUID `db:"configuration_id" json:"configuration_id,omitempty"`
 	DocumentType         string     `db:"document_type" json:"document_type"`
 	FilePath             string     `db:"file_path" json:"-"`
 	FileName             *string    `db:"file_name" json:"file_name,omitempty"`
@@ -27,6 +28,14 @@ type Document struct {
 	AttachmentLabel *string `db:"attachment_label" json:"attachment_label,omitempty"`
 }
 
+// DocumentGenerate is the staff request to issue a document from its template; exactly one target is set.
+type DocumentGenerate struct {
+	DocumentType         string     `json:"document_type"`
+	OrderID              *uuid.UUID `json:"order_id,omitempty"`
+	ServiceAppointmentID *uuid.UUID `json:"service_appointment_id,omitempty"`
+	ConfigurationID      *uuid.UUID `json:"configuration_id,omitempty"`
+}
+
 // DocumentTypes lists allowed document_type values (must match DB CHECK).
 var DocumentTypes = map[string]struct{}{
 	"commercial_offer": {},
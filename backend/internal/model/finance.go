@@ -0,0 +1,124 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FinanceProduct matches table finance_products (bank loan or lease program).
+type FinanceProduct struct {
+	FinanceProductID      uuid.UUID `db:"finance_product_id" json:"finance_product_id"`
+	Name                  string    `db:"name" json:"name"`
+	Provider              string    `db:"provider" json:"provider"`
+	ProductType           string    `db:"product_type" json:"product_type"`
+	Description           *string   `db:"description" json:"description,omitempty"`
+	AnnualRate            float64   `db:"annual_rate" json:"annual_rate"`
+	MinTermMonths         int       `db:"min_term_months" json:"min_term_months"`
+	MaxTermMonths         int       `db:"max_term_months" json:"max_term_months"`
+	MinDownPaymentPercent float64   `db:"min_down_payment_percent" json:"min_down_payment_percent"`
+	MaxDownPaymentPercent float64   `db:"max_down_payment_percent" json:"max_down_payment_percent"`
+	BalloonPercent        float64   `db:"balloon_percent" json:"balloon_percent"`
+	MinAmount             *float64  `db:"min_amount" json:"min_amount,omitempty"`
+	MaxAmount             *float64  `db:"max_amount" json:"max_amount,omitempty"`
+	IsActive              bool      `db:"is_active" json:"is_active"`
+	CreatedAt             time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// FinanceProductInput is the admin create/replace payload for a finance product.
+type FinanceProductInput struct {
+	Name                  string   `json:"name"`
+	Provider              string   `json:"provider"`
+	ProductType           string   `json:"product_type"`
+	Description           *string  `json:"description,omitempty"`
+	AnnualRate            float64  `json:"annual_rate"`
+	MinTermMonths         int      `json:"min_term_months"`
+	MaxTermMonths         int      `json:"max_term_months"`
+	MinDownPaymentPercent float64  `json:"min_down_payment_percent"`
+	MaxDownPaymentPercent float64  `json:"max_down_payment_percent"`
+	BalloonPercent        float64  `json:"balloon_percent"`
+	MinAmount             *float64 `json:"min_amount,omitempty"`
+	MaxAmount             *float64 `json:"max_amount,omitempty"`
+	IsActive              bool     `json:"is_active"`
+}
+
+// FinanceRequest is the calculator input. Down payment is an amount or a percent of the price;
+// FinanceProductID narrows the comparison to one program.
+type FinanceRequest struct {
+	TermMonths         int        `json:"term_months"`
+	DownPayment        *float64   `json:"down_payment,omitempty"`
+	DownPaymentPercent *float64   `json:"down_payment_percent,omitempty"`
+	FinanceProductID   *uuid.UUID `json:"finance_product_id,omitempty"`
+	PromoCode          *string    `json:"promo_code,omitempty"`
+	IncludeSchedule    bool       `json:"include_schedule"`
+}
+
+// PaymentScheduleRow is one month of a repayment schedule.
+type PaymentScheduleRow struct {
+	Month     int     `json:"month"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"`
+}
+
+// PaymentPlan summarises an annuity or differentiated schedule.
+type PaymentPlan struct {
+	ScheduleType   string               `json:"schedule_type"`
+	FirstPayment   float64              `json:"first_payment"`
+	LastPayment    float64              `json:"last_payment"`
+	BalloonPayment float64              `json:"balloon_payment"`
+	TotalPayment   float64              `json:"total_payment"`
+	Overpayment    float64              `json:"overpayment"`
+	Schedule       []PaymentScheduleRow `json:"schedule,omitempty"`
+}
+
+// FinanceOffer is one program evaluated for the requested terms.
+type FinanceOffer struct {
+	FinanceProductID uuid.UUID    `json:"finance_product_id"`
+	Name             string       `json:"name"`
+	Provider         string       `json:"provider"`
+	ProductType      string       `json:"product_type"`
+	AnnualRate       float64      `json:"annual_rate"`
+	Eligible         bool         `json:"eligible"`
+	Reason           string       `json:"reason,omitempty"`
+	Annuity          *PaymentPlan `json:"annuity,omitempty"`
+	Differentiated   *PaymentPlan `json:"differentiated,omitempty"`
+}
+
+// FinanceQuote is the calculator response; eligible offers come first, cheapest overpayment first.
+type FinanceQuote struct {
+	VehiclePrice float64        `json:"vehicle_price"`
+	DownPayment  float64        `json:"down_payment"`
+	Principal    float64        `json:"principal"`
+	TermMonths   int            `json:"term_months"`
+	Offers       []FinanceOffer `json:"offers"`
+}
+
+// OrderFinanceSelection is the finance plan a customer picks when placing an order.
+type OrderFinanceSelection struct {
+	FinanceProductID uuid.UUID `json:"finance_product_id"`
+	ScheduleType     string    `json:"schedule_type"`
+	TermMonths       int       `json:"term_months"`
+	DownPayment      float64   `json:"down_payment"`
+}
+
+// OrderFinancePlan matches table order_finance_plans (snapshot of the chosen program).
+type OrderFinancePlan struct {
+	FinanceProductID *uuid.UUID `db:"finance_product_id" json:"finance_product_id,omitempty"`
+	ProductName      string     `db:"product_name" json:"product_name"`
+	Provider         string     `db:"provider" json:"provider"`
+	ProductType      string     `db:"product_type" json:"product_type"`
+	ScheduleType     string     `db:"schedule_type" json:"schedule_type"`
+	AnnualRate       float64    `db:"annual_rate" json:"annual_rate"`
+	TermMonths       int        `db:"term_months" json:"term_months"`
+	VehiclePrice     float64    `db:"vehicle_price" json:"vehicle_price"`
+	DownPayment      float64    `db:"down_payment" json:"down_payment"`
+	Principal        float64    `db:"principal" json:"principal"`
+	BalloonPayment   float64    `db:"balloon_payment" json:"balloon_payment"`
+	FirstPayment     float64    `db:"first_payment" json:"first_payment"`
+	LastPayment      float64    `db:"last_payment" json:"last_payment"`
+	TotalPayment     float64    `db:"total_payment" json:"total_payment"`
+	Overpayment      float64    `db:"overpayment" json:"overpayment"`
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Maintenance reminder statuses (maintenance_reminders.status).
+const (
+	MaintenanceReminderOpen      = "open"
+	MaintenanceReminderDismissed = "dismissed"
+	MaintenanceReminderDone      = "done"
+)
+
+// Maintenance due states reported for a car and plan.
+const (
+	MaintenanceOK      = "ok"
+	MaintenanceDueSoon = "due_soon"
+	MaintenanceOverdue = "overdue"
+	// MaintenanceUnknown: a mileage-only plan for a car whose mileage at the last service is unknown.
+	MaintenanceUnknown = "unknown"
+)
+
+// MaintenancePlan matches table maintenance_plans with its service types. A plan belongs to a model
+// or to a generation; generation plans replace the model plans for cars of that generation.
+type MaintenancePlan struct {
+	PlanID         uuid.UUID   `db:"plan_id" json:"plan_id"`
+	ModelID        *uuid.UUID  `db:"model_id" json:"model_id,omitempty"`
+	GenerationID   *uuid.UUID  `db:"generation_id" json:"generation_id,omitempty"`
+	Name           string      `db:"name" json:"name"`
+	IntervalKm     *int        `db:"interval_km" json:"interval_km,omitempty"`
+	IntervalMonths *int        `db:"interval_months" json:"interval_months,omitempty"`
+	IsActive       bool        `db:"is_active" json:"is_active"`
+	ServiceTypeIDs []uuid.UUID `json:"service_type_ids"`
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// MaintenancePlanInput creates or replaces a plan; exactly one of ModelID and GenerationID is set.
+type MaintenancePlanInput struct {
+	ModelID        *uuid.UUID  `json:"model_id,omitempty"`
+	GenerationID   *uuid.UUID  `json:"generation_id,omitempty"`
+	Name           string      `json:"name"`
+	IntervalKm     *int        `json:"interval_km,omitempty"`
+	IntervalMonths *int        `json:"interval_months,omitempty"`
+	ServiceTypeIDs []uuid.UUID `json:"service_type_ids"`
+	IsActive       *bool       `json:"is_active,omitempty"`
+}
+
+// MaintenanceBooking is a prefilled body for POST /api/service/appointments: the customer only
+// picks the date. BranchID is the branch of the last service, if any.
+type MaintenanceBooking struct {
+	UserCarID      uuid.UUID   `json:"user_car_id"`
+	BranchID       *uuid.UUID  `json:"branch_id,omitempty"`
+	ServiceTypeIDs []uuid.UUID `json:"service_type_ids"`
+	Description    string      `json:"description"`
+}
+
+// MaintenanceBaseline is the point the next service is counted from: the last completed
+// appointment with the plan's services, or the purchase of the car. MileageKm is unknown for
+// cars added by hand without a service history.
+type MaintenanceBaseline struct {
+	AppointmentID *uuid.UUID `json:"appointment_id,omitempty"`
+	BranchID      *uuid.UUID `json:"branch_id,omitempty"`
+	Date          time.Time  `json:"date"`
+	MileageKm     *int       `json:"mileage_km,omitempty"`
+}
+
+// MaintenanceDue is the state of one plan for a car. EstimatedDate is the earlier of DueDate and
+// the date the car is projected to reach DueMileage at its average daily mileage.
+type MaintenanceDue struct {
+	Plan          MaintenancePlan     `json:"plan"`
+	LastService   MaintenanceBaseline `json:"last_service"`
+	DueDate       *time.Time          `json:"due_date,omitempty"`
+	DueMileage    *int                `json:"due_mileage,omitempty"`
+	EstimatedDate *time.Time          `json:"estimated_date,omitempty"`
+	Status        string              `json:"status"`
+	Booking       MaintenanceBooking  `json:"booking"`
+}
+
+// MaintenanceCandidate is a car with an applicable plan and its baseline. BookableServiceTypeIDs
+// are the plan services currently available for booking.
+type MaintenanceCandidate struct {
+	UserCarID              uuid.UUID
+	UserID                 uuid.UUID
+	CarTitle               string
+	CurrentMileage         int
+	Plan                   MaintenancePlan
+	BookableServiceTypeIDs []uuid.UUID
+	Baseline               MaintenanceBaseline
+}
+
+// MaintenanceReminder matches table maintenance_reminders.
+type MaintenanceReminder struct {
+	ReminderID           uuid.UUID          `db:"reminder_id" json:"reminder_id"`
+	UserCarID            uuid.UUID          `db:"user_car_id" json:"user_car_id"`
+	PlanID               uuid.UUID          `db:"plan_id" json:"plan_id"`
+	PlanName             string             `db:"plan_name" json:"plan_name"`
+	LastAppointmentID    *uuid.UUID         `db:"last_appointment_id" json:"last_appointment_id,omitempty"`
+	DueDate              *time.Time         `db:"due_date" json:"due_date,omitempty"`
+	DueMileage           *int               `db:"due_mileage" json:"due_mileage,omitempty"`
+	Booking              MaintenanceBooking `db:"booking" json:"booking"`
+	Status               string             `db:"status" json:"status"`
+	ServiceAppointmentID *uuid.UUID         `db:"service_appointment_id" json:"service_appointment_id,omitempty"`
+	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// MaintenanceReminderCreate is a reminder raised by the job together with its notification text.
+type MaintenanceReminderCreate struct {
+	UserID            uuid.UUID
+	UserCarID         uuid.UUID
+	PlanID            uuid.UUID
+	LastAppointmentID *uuid.UUID
+	DueDate           *time.Time
+	DueMileage        *int
+	Booking           MaintenanceBooking
+	Title             string
+	Body              string
+}
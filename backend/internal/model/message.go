@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message matches table messages: one entry of an order or appointment thread.
+type Message struct {
+	MessageID            uuid.UUID  `db:"message_id" json:"message_id"`
+	OrderID              *uuid.UUID `db:"order_id" json:"order_id,omitempty"`
+	ServiceAppointmentID *uuid.UUID `db:"service_appointment_id" json:"service_appointment_id,omitempty"`
+	AuthorID             *uuid.UUID `db:"author_id" json:"author_id,omitempty"`
+	AuthorName           *string    `db:"author_name" json:"author_name,omitempty"`
+	Body                 string     `db:"body" json:"body"`
+	// IsInternal marks a staff-only note; such messages are never shown to the customer.
+	IsInternal bool      `db:"is_internal" json:"is_internal"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	// IsRead reports whether the requester has read the message (own messages count as read).
+	IsRead      bool                `db:"is_read" json:"is_read"`
+	Attachments []MessageAttachment `json:"attachments"`
+	ReadBy      []MessageRead       `json:"read_by"`
+}
+
+// MessageAttachment is a link from a message to a document of the same order or appointment.
+type MessageAttachment struct {
+	DocumentID   uuid.UUID `db:"document_id" json:"document_id"`
+	DocumentType string    `db:"document_type" json:"document_type"`
+	FileName     *string   `db:"file_name" json:"file_name,omitempty"`
+}
+
+// MessageRead is a read receipt.
+type MessageRead struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	UserName string    `db:"user_name" json:"user_name"`
+	ReadAt   time.Time `db:"read_at" json:"read_at"`
+}
+
+// MessageCreate is a new thread message; Internal is honoured for staff only.
+type MessageCreate struct {
+	Body        string      `json:"body"`
+	Internal    bool        `json:"internal"`
+	DocumentIDs []uuid.UUID `json:"document_ids,omitempty"`
+}
+
+// MessageThread is the messages visible to the requester, oldest first, with their unread count.
+type MessageThread struct {
+	Items       []Message `json:"items"`
+	UnreadCount int       `json:"unread_count"`
+}
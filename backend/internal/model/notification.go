@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification matches table notifications.
+type Notification struct {
+	NotificationID uuid.UUID  `db:"notification_id" json:"notification_id"`
+	UserID         uuid.UUID  `db:"user_id" json:"user_id"`
+	Kind           string     `db:"kind" json:"kind"`
+	Title          string     `db:"title" json:"title"`
+	Body           string     `db:"body" json:"body"`
+	EntityType     *string    `db:"entity_type" json:"entity_type,omitempty"`
+	EntityID       *uuid.UUID `db:"entity_id" json:"entity_id,omitempty"`
+	ReadAt         *time.Time `db:"read_at" json:"read_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// NotificationList is the profile inbox page with the total unread count.
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unread_count"`
+}
+
+// ConfigurationLifecycleRun summarizes one pass of the configuration lifecycle job.
+type ConfigurationLifecycleRun struct {
+	DraftsExpired     int
+	DraftWarnings     int
+	PriceLockWarnings int
+}
@@ -14,19 +14,78 @@ type Order struct {
 	Status          string     `db:"status" json:"status"`
 	StatusLabel     string     `db:"status_label" json:"status_label,omitempty"`
 	FinalPrice      float64    `db:"final_price" json:"final_price"`
+	DiscountTotal   float64    `db:"discount_total" json:"discount_total"`
+	TradeInCredit   float64    `db:"tradein_credit" json:"tradein_credit"`
+	AmountDue       float64    `db:"amount_due" json:"amount_due"`
 	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
 }
 
+// OrderFilters narrows the staff order list; zero values match everything. Dates bound
+// created_at as [From, To); Customer matches part of the customer's name, email or phone.
+type OrderFilters struct {
+	Status    []string
+	From      *time.Time
+	To        *time.Time
+	BrandID   *uuid.UUID
+	ModelID   *uuid.UUID
+	ManagerID *uuid.UUID
+	MinPrice  *float64
+	MaxPrice  *float64
+	Customer  string
+}
+
 type OrderCreate struct {
-	ConfigurationID uuid.UUID `json:"configuration_id" validate:"required"`
+	ConfigurationID uuid.UUID              `json:"configuration_id" validate:"required"`
+	PromoCode       *string                `json:"promo_code,omitempty"`
+	Finance         *OrderFinanceSelection `json:"finance,omitempty"`
 }
 
 type OrderWithDetails struct {
 	Order
 	Configuration   ConfigurationWithDetails `json:"configuration"`
 	ManagerName     *string                  `db:"manager_name" json:"manager_name,omitempty"`
+	// UserCarID is the garage car created on handover; filled on the order detail only.
+	UserCarID *uuid.UUID `db:"user_car_id" json:"user_car_id,omitempty"`
 	CustomerEmail   string                   `json:"customer_email,omitempty"`
 	CustomerName    string                   `json:"customer_name,omitempty"`
+	// Staff list fields.
+	CustomerPhone *string `json:"customer_phone,omitempty"`
+	BrandName     string  `json:"brand_name,omitempty"`
+	ModelName     string  `json:"model_name,omitempty"`
+	Promotions      []AppliedPromotion       `json:"promotions,omitempty"`
+	FinancePlan     *OrderFinancePlan        `json:"finance_plan,omitempty"`
+	TradeIn         *TradeInOfferWithDetails `json:"trade_in,omitempty"`
+	// NextStatuses lists transitions the requester may trigger; filled on the order detail only.
+	NextStatuses []OrderNextStatus `json:"next_statuses,omitempty"`
+	// Timeline is the status history, oldest first; filled on the order detail only.
+	Timeline []OrderEvent `json:"timeline,omitempty"`
+	// UnreadMessages counts thread messages the requester has not read; filled on order lists only.
+	UnreadMessages int `json:"unread_messages,omitempty"`
+}
+
+
+// OrderEvent matches table order_events: one entry of the order timeline.
+type OrderEvent struct {
+	EventID         uuid.UUID  `db:"event_id" json:"event_id"`
+	OrderID         uuid.UUID  `db:"order_id" json:"order_id"`
+	ActorID         *uuid.UUID `db:"actor_id" json:"actor_id,omitempty"`
+	ActorName       *string    `db:"actor_name" json:"actor_name,omitempty"`
+	EventType       string     `db:"event_type" json:"event_type"`
+	FromStatus      *string    `db:"from_status" json:"from_status,omitempty"`
+	ToStatus        string     `db:"to_status" json:"to_status"`
+	ToStatusLabel   string     `db:"to_status_label" json:"to_status_label"`
+	Reason          *string    `db:"reason" json:"reason,omitempty"`
+	Comment         *string    `db:"comment" json:"comment,omitempty"`
+	CommentInternal bool       `db:"comment_internal" json:"comment_internal,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 }
 
+// OrderStatusChange is the status update request; Internal hides the comment from the customer
+// and is honoured for staff only.
+type OrderStatusChange struct {
+	Status   string  `json:"status"`
+	Reason   *string `json:"reason,omitempty"`
+	Comment  *string `json:"comment,omitempty"`
+	Internal bool    `json:"internal"`
+}
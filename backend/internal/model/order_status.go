@@ -46,3 +46,35 @@ type OrderStatusUpdate struct {
 	IsTerminal      *bool   `json:"is_terminal,omitempty"`
 	Code            *string `json:"code,omitempty"`
 }
+
+// OrderStatusTransition matches table order_status_transitions, with labels of the target status.
+type OrderStatusTransition struct {
+	TransitionID       uuid.UUID `db:"transition_id" json:"transition_id"`
+	FromStatus         string    `db:"from_status" json:"from_status"`
+	ToStatus           string    `db:"to_status" json:"to_status"`
+	PermissionCode     string    `db:"permission_code" json:"permission_code"`
+	CustomerMayTrigger bool      `db:"customer_may_trigger" json:"customer_may_trigger"`
+	ToCustomerLabelRu  string    `db:"to_customer_label_ru" json:"to_customer_label_ru"`
+	ToAdminLabelRu     *string   `db:"to_admin_label_ru" json:"to_admin_label_ru,omitempty"`
+	ToIsActive         bool      `db:"to_is_active" json:"to_is_active"`
+	ToIsTerminal       bool      `db:"to_is_terminal" json:"to_is_terminal"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// OrderStatusTransitionInput is the admin create/replace payload; empty permission_code
+// defaults to orders.manage_status.
+type OrderStatusTransitionInput struct {
+	FromStatus         string  `json:"from_status"`
+	ToStatus           string  `json:"to_status"`
+	PermissionCode     *string `json:"permission_code,omitempty"`
+	CustomerMayTrigger bool    `json:"customer_may_trigger"`
+}
+
+// OrderNextStatus is a status the requester may move an order to from its current status.
+type OrderNextStatus struct {
+	Code            string  `json:"code"`
+	CustomerLabelRu string  `json:"customer_label_ru"`
+	AdminLabelRu    *string `json:"admin_label_ru,omitempty"`
+	IsTerminal      bool    `json:"is_terminal"`
+}
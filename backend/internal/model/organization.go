@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization matches table organizations: a household or a company sharing cars.
+type Organization struct {
+	OrganizationID uuid.UUID  `db:"organization_id" json:"organization_id"`
+	Name           string     `db:"name" json:"name"`
+	Kind           string     `db:"kind" json:"kind"`
+	CreatedBy      *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+	// Role is the requester's member role.
+	Role        string `json:"role"`
+	MemberCount int    `json:"member_count"`
+	CarCount    int    `json:"car_count"`
+}
+
+type OrganizationInput struct {
+	Name string `json:"name"`
+	// Kind is household (default) or company.
+	Kind string `json:"kind"`
+}
+
+// OrganizationMember matches table organization_members with the member's contact details.
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	Email          string    `json:"email"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	Role           string    `db:"role" json:"role"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// OrganizationMemberInput adds an existing customer to an organization by email.
+type OrganizationMemberInput struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type OrganizationMemberUpdate struct {
+	Role string `json:"role"`
+}
+
+// UserCarShare links a car to one of the owner's organizations.
+type UserCarShare struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+// OrganizationCarSpend is the service spend on one car of an organization. UserCarID is the garage
+// car that has the VIN now; it is unset once the car was removed from all garages.
+type OrganizationCarSpend struct {
+	UserCarID *uuid.UUID `json:"user_car_id,omitempty"`
+	VIN       string     `json:"vin"`
+	Title     string     `json:"title"`
+	Visits    int        `json:"visits"`
+	Total     float64    `json:"total"`
+}
+
+// OrganizationMonthSpend is the service spend of an organization in one calendar month.
+type OrganizationMonthSpend struct {
+	Month  time.Time `json:"month"`
+	Visits int       `json:"visits"`
+	Total  float64   `json:"total"`
+}
+
+// OrganizationSpendReport sums completed service visits of the organization's cars in [From, To).
+type OrganizationSpendReport struct {
+	OrganizationID uuid.UUID                `json:"organization_id"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	Visits         int                      `json:"visits"`
+	Total          float64                  `json:"total"`
+	Cars           []OrganizationCarSpend   `json:"cars"`
+	Months         []OrganizationMonthSpend `json:"months"`
+}
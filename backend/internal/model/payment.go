@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice matches table invoices: a deposit or balance bill for an order.
+type Invoice struct {
+	InvoiceID      uuid.UUID  `db:"invoice_id" json:"invoice_id"`
+	OrderID        uuid.UUID  `db:"order_id" json:"order_id"`
+	Kind           string     `db:"kind" json:"kind"`
+	Amount         float64    `db:"amount" json:"amount"`
+	AmountPaid     float64    `db:"amount_paid" json:"amount_paid"`
+	AmountRefunded float64    `db:"amount_refunded" json:"amount_refunded"`
+	Status         string     `db:"status" json:"status"`
+	DueDate        *time.Time `db:"due_date" json:"due_date,omitempty"`
+	CreatedBy      *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+	// PendingAmount is the sum of online payments still awaiting confirmation.
+	PendingAmount float64   `db:"pending_amount" json:"pending_amount"`
+	Payments      []Payment `json:"payments"`
+}
+
+// Payment matches table payments.
+type Payment struct {
+	PaymentID     uuid.UUID  `db:"payment_id" json:"payment_id"`
+	InvoiceID     uuid.UUID  `db:"invoice_id" json:"invoice_id"`
+	OrderID       uuid.UUID  `db:"order_id" json:"order_id"`
+	Amount        float64    `db:"amount" json:"amount"`
+	Method        string     `db:"method" json:"method"`
+	Status        string     `db:"status" json:"status"`
+	Gateway       *string    `db:"gateway" json:"gateway,omitempty"`
+	GatewayRef    *string    `db:"gateway_ref" json:"gateway_ref,omitempty"`
+	PaymentURL    *string    `db:"payment_url" json:"payment_url,omitempty"`
+	FailureReason *string    `db:"failure_reason" json:"failure_reason,omitempty"`
+	CreatedBy     *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	PaidAt        *time.Time `db:"paid_at" json:"paid_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	Refunds       []Refund   `json:"refunds,omitempty"`
+}
+
+// Refund matches table refunds.
+type Refund struct {
+	RefundID      uuid.UUID  `db:"refund_id" json:"refund_id"`
+	PaymentID     uuid.UUID  `db:"payment_id" json:"payment_id"`
+	Amount        float64    `db:"amount" json:"amount"`
+	Reason        string     `db:"reason" json:"reason"`
+	Status        string     `db:"status" json:"status"`
+	GatewayRef    *string    `db:"gateway_ref" json:"gateway_ref,omitempty"`
+	FailureReason *string    `db:"failure_reason" json:"failure_reason,omitempty"`
+	CreatedBy     *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	ProcessedAt   *time.Time `db:"processed_at" json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// InvoiceCreate is the staff request to bill an order. A balance invoice without amount covers
+// everything not billed yet.
+type InvoiceCreate struct {
+	Kind    string   `json:"kind"`
+	Amount  *float64 `json:"amount,omitempty"`
+	DueDate *string  `json:"due_date,omitempty"`
+}
+
+// PaymentCreate pays an invoice in full or in part; without amount the remainder is paid.
+// Method is set by staff recording an offline payment; customers always pay online.
+type PaymentCreate struct {
+	Amount *float64 `json:"amount,omitempty"`
+	Method string   `json:"method,omitempty"`
+}
+
+type RefundCreate struct {
+	Amount *float64 `json:"amount,omitempty"`
+	Reason string   `json:"reason"`
+}
+
+// OrderPayments is the billing state of an order.
+type OrderPayments struct {
+	AmountDue   float64   `json:"amount_due"`
+	Invoiced    float64   `json:"invoiced"`
+	Paid        float64   `json:"paid"`
+	Outstanding float64   `json:"outstanding"`
+	Invoices    []Invoice `json:"invoices"`
+}
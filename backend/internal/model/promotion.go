@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Promotion matches table promotions. Nil scope fields mean "any".
+type Promotion struct {
+	PromotionID     uuid.UUID  `db:"promotion_id" json:"promotion_id"`
+	Name            string     `db:"name" json:"name"`
+	Description     *string    `db:"description" json:"description,omitempty"`
+	DiscountType    string     `db:"discount_type" json:"discount_type"`
+	DiscountValue   float64    `db:"discount_value" json:"discount_value"`
+	BrandID         *uuid.UUID `db:"brand_id" json:"brand_id,omitempty"`
+	ModelID         *uuid.UUID `db:"model_id" json:"model_id,omitempty"`
+	TrimID          *uuid.UUID `db:"trim_id" json:"trim_id,omitempty"`
+	OptionID        *uuid.UUID `db:"option_id" json:"option_id,omitempty"`
+	VehicleSegment  *string    `db:"vehicle_segment" json:"vehicle_segment,omitempty"`
+	CustomerSegment *string    `db:"customer_segment" json:"customer_segment,omitempty"`
+	ValidFrom       *time.Time `db:"valid_from" json:"valid_from,omitempty"`
+	ValidTo         *time.Time `db:"valid_to" json:"valid_to,omitempty"`
+	MaxUses         *int       `db:"max_uses" json:"max_uses,omitempty"`
+	MaxUsesPerUser  *int       `db:"max_uses_per_user" json:"max_uses_per_user,omitempty"`
+	UsesCount       int        `db:"uses_count" json:"uses_count"`
+	IsStackable     bool       `db:"is_stackable" json:"is_stackable"`
+	RequiresCode    bool       `db:"requires_code" json:"requires_code"`
+	Priority        int        `db:"priority" json:"priority"`
+	IsActive        bool       `db:"is_active" json:"is_active"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// PromotionInput is the admin create/replace payload for a promotion.
+type PromotionInput struct {
+	Name            string     `json:"name"`
+	Description     *string    `json:"description,omitempty"`
+	DiscountType    string     `json:"discount_type"`
+	DiscountValue   float64    `json:"discount_value"`
+	BrandID         *uuid.UUID `json:"brand_id,omitempty"`
+	ModelID         *uuid.UUID `json:"model_id,omitempty"`
+	TrimID          *uuid.UUID `json:"trim_id,omitempty"`
+	OptionID        *uuid.UUID `json:"option_id,omitempty"`
+	VehicleSegment  *string    `json:"vehicle_segment,omitempty"`
+	CustomerSegment *string    `json:"customer_segment,omitempty"`
+	ValidFrom       *time.Time `json:"valid_from,omitempty"`
+	ValidTo         *time.Time `json:"valid_to,omitempty"`
+	MaxUses         *int       `json:"max_uses,omitempty"`
+	MaxUsesPerUser  *int       `json:"max_uses_per_user,omitempty"`
+	IsStackable     bool       `json:"is_stackable"`
+	RequiresCode    bool       `json:"requires_code"`
+	Priority        int        `json:"priority"`
+	IsActive        bool       `json:"is_active"`
+}
+
+// PromotionCode matches table promotion_codes.
+type PromotionCode struct {
+	PromotionCodeID uuid.UUID  `db:"promotion_code_id" json:"promotion_code_id"`
+	PromotionID     uuid.UUID  `db:"promotion_id" json:"promotion_id"`
+	Code            string     `db:"code" json:"code"`
+	IsSingleUse     bool       `db:"is_single_use" json:"is_single_use"`
+	RedeemedBy      *uuid.UUID `db:"redeemed_by" json:"redeemed_by,omitempty"`
+	RedeemedAt      *time.Time `db:"redeemed_at" json:"redeemed_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+// PromotionCodesCreate issues either one explicit code or Count random codes.
+type PromotionCodesCreate struct {
+	Code        *string `json:"code,omitempty"`
+	Count       int     `json:"count"`
+	IsSingleUse bool    `json:"is_single_use"`
+}
+
+// AppliedPromotion is one discount line in a price quote and in the order snapshot (order_promotions).
+type AppliedPromotion struct {
+	PromotionID     *uuid.UUID `db:"promotion_id" json:"promotion_id,omitempty"`
+	PromotionCodeID *uuid.UUID `db:"promotion_code_id" json:"-"`
+	Name            string     `db:"promotion_name" json:"name"`
+	Code            *string    `db:"code" json:"code,omitempty"`
+	DiscountType    string     `db:"discount_type" json:"discount_type"`
+	DiscountValue   float64    `db:"discount_value" json:"discount_value"`
+	DiscountAmount  float64    `db:"discount_amount" json:"discount_amount"`
+}
+
+// PriceBreakdown is a configuration price quote at current catalog prices with promotions applied.
+type PriceBreakdown struct {
+	TrimPrice     float64            `json:"trim_price"`
+	ColorPrice    float64            `json:"color_price"`
+	OptionsPrice  float64            `json:"options_price"`
+	ListPrice     float64            `json:"list_price"`
+	DiscountTotal float64            `json:"discount_total"`
+	FinalPrice    float64            `json:"final_price"`
+	Promotions    []AppliedPromotion `json:"promotions"`
+	// PriceLockedUntil is set when catalog prices come from a confirmed configuration's price lock.
+	PriceLockedUntil *time.Time `json:"price_locked_until,omitempty"`
+}
+
+// PricingScope is the catalog position of a trim used to match promotion rules.
+type PricingScope struct {
+	TrimID       uuid.UUID
+	GenerationID uuid.UUID
+	ModelID      uuid.UUID
+	BrandID      uuid.UUID
+	Segment      *string
+	BasePrice    float64
+}
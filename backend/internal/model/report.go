@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderReportRow aggregates orders of one group: a period bucket, brand, model or manager.
+// Revenue is the final price of orders that reached paid.
+type OrderReportRow struct {
+	Period            *time.Time `json:"period,omitempty"`
+	Key               *uuid.UUID `json:"key,omitempty"`
+	Label             string     `json:"label,omitempty"`
+	Orders            int        `json:"orders"`
+	OrdersValue       float64    `json:"orders_value"`
+	PaidOrders        int        `json:"paid_orders"`
+	Revenue           float64    `json:"revenue"`
+	CancelledOrders   int        `json:"cancelled_orders"`
+	AverageOrderValue float64    `json:"average_order_value"`
+	AveragePaidValue  float64    `json:"average_paid_value"`
+}
+
+// OrderReport is the orders and revenue report; To is exclusive.
+type OrderReport struct {
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	GroupBy     string           `json:"group_by"`
+	Period      string           `json:"period,omitempty"`
+	Rows        []OrderReportRow `json:"rows"`
+	Totals      OrderReportRow   `json:"totals"`
+	RefreshedAt *time.Time       `json:"refreshed_at,omitempty"`
+}
+
+// FunnelCounts are the raw stage counts for configurations created in a period.
+type FunnelCounts struct {
+	Configurations int
+	Confirmed      int
+	Ordered        int
+	Paid           int
+}
+
+// FunnelStage is one step of the sales funnel with conversion rates (0..1).
+type FunnelStage struct {
+	Stage              string  `json:"stage"`
+	Count              int     `json:"count"`
+	FromPreviousStage  float64 `json:"from_previous_stage"`
+	FromConfigurations float64 `json:"from_configurations"`
+}
+
+// FunnelReport covers configurations created in [From, To).
+type FunnelReport struct {
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Stages      []FunnelStage `json:"stages"`
+	RefreshedAt *time.Time    `json:"refreshed_at,omitempty"`
+}
+
+// BranchServiceStats summarises a branch's appointments. Capacity is bays times opening hours;
+// rates are shares (0..1) of all appointments, utilisation of capacity.
+type BranchServiceStats struct {
+	BranchID         uuid.UUID `json:"branch_id"`
+	BranchName       string    `json:"branch_name"`
+	Appointments     int       `json:"appointments"`
+	Completed        int       `json:"completed"`
+	Cancelled        int       `json:"cancelled"`
+	NoShow           int       `json:"no_show"`
+	BookedMinutes    int       `json:"booked_minutes"`
+	CapacityMinutes  int       `json:"capacity_minutes"`
+	Utilisation      float64   `json:"utilisation"`
+	CancellationRate float64   `json:"cancellation_rate"`
+	NoShowRate       float64   `json:"no_show_rate"`
+}
+
+// ServiceReport covers appointments on branch-local days in [From, To).
+type ServiceReport struct {
+	From        time.Time            `json:"from"`
+	To          time.Time            `json:"to"`
+	Branches    []BranchServiceStats `json:"branches"`
+	RefreshedAt *time.Time           `json:"refreshed_at,omitempty"`
+}
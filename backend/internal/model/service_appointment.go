@@ -15,6 +15,15 @@ type ServiceAppointment struct {
 	DurationMinutes        int        `db:"duration_minutes" json:"duration_minutes"`
 	Status                 string     `db:"status" json:"status"`
 	Description            *string    `db:"description" json:"description,omitempty"`
+	ResourceAssignment
+	// Work order progress: check-in, odometer reading at check-in, work actually done, completion.
+	CheckedInAt            *time.Time `db:"checked_in_at" json:"checked_in_at,omitempty"`
+	OdometerKm             *int       `db:"odometer_km" json:"odometer_km,omitempty"`
+	WorkSummary            *string    `db:"work_summary" json:"work_summary,omitempty"`
+	CompletedAt            *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	// Source is the booking channel (online, staff, walk_in); CreatedBy is who made the booking.
+	Source                 string     `db:"source" json:"source"`
+	CreatedBy              *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
 	CreatedAt              time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt              time.Time  `db:"updated_at" json:"updated_at"`
 }
@@ -27,6 +36,38 @@ type ServiceAppointmentCreate struct {
 	Description     *string     `json:"description,omitempty"`
 	// Set by service layer from selected service types (never trust client).
 	DurationMinutes int `json:"-"`
+	// Set by the service layer for staff bookings: channel, author, and for walk-ins an immediate
+	// check-in with the odometer reading.
+	Source     string     `json:"-"`
+	CreatedBy  *uuid.UUID `json:"-"`
+	CheckIn    bool       `json:"-"`
+	OdometerKm *int       `json:"-"`
+}
+
+// StaffBookingCustomer selects an existing customer by UserID or by Email, or creates one from
+// the contact details when no account has that email.
+type StaffBookingCustomer struct {
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	FirstName string     `json:"first_name,omitempty"`
+	LastName  string     `json:"last_name,omitempty"`
+	Phone     *string    `json:"phone,omitempty"`
+}
+
+// StaffAppointmentCreate is a booking made by staff for a customer: either an existing UserCarID
+// (the customer is its owner) or a NewCar added to the customer's garage. OffGrid allows any start
+// minute within working hours; a WalkIn starts now and is checked in at once.
+type StaffAppointmentCreate struct {
+	Customer        *StaffBookingCustomer `json:"customer,omitempty"`
+	UserCarID       *uuid.UUID            `json:"user_car_id,omitempty"`
+	NewCar          *UserCarCreate        `json:"new_car,omitempty"`
+	BranchID        uuid.UUID             `json:"branch_id"`
+	ServiceTypeIDs  []uuid.UUID           `json:"service_type_ids"`
+	AppointmentDate *time.Time            `json:"appointment_date,omitempty"`
+	Description     *string               `json:"description,omitempty"`
+	OffGrid         bool                  `json:"off_grid"`
+	WalkIn          bool                  `json:"walk_in"`
+	OdometerKm      *int                  `json:"odometer_km,omitempty"`
 }
 
 // BranchAvailability is returned by GET .../branches/{id}/availability
@@ -34,6 +75,41 @@ type BranchAvailability struct {
 	SlotStarts       []time.Time `json:"slot_starts"`
 	Timezone         string      `json:"timezone"`
 	DurationMinutes  int         `json:"duration_minutes"`
+	// Hours are the branch working hours on the requested day; nil when the branch is closed.
+	Hours *BranchDayHours `json:"hours,omitempty"`
+}
+
+// BranchAvailabilityRange is returned by GET .../branches/{id}/availability/range: free slots of
+// every branch-local day in the range.
+type BranchAvailabilityRange struct {
+	Timezone        string                  `json:"timezone"`
+	DurationMinutes int                     `json:"duration_minutes"`
+	From            string                  `json:"from"`
+	To              string                  `json:"to"`
+	Days            []BranchAvailabilityDay `json:"days"`
+}
+
+// BranchAvailabilityDay summarises one day of a BranchAvailabilityRange.
+type BranchAvailabilityDay struct {
+	// Date is the branch-local date, YYYY-MM-DD.
+	Date          string          `json:"date"`
+	Hours         *BranchDayHours `json:"hours,omitempty"`
+	FreeSlots     int             `json:"free_slots"`
+	FirstFreeSlot *time.Time      `json:"first_free_slot,omitempty"`
+	SlotStarts    []time.Time     `json:"slot_starts"`
+}
+
+// AppointmentFilters narrows the staff appointment list; zero values match everything. Dates
+// bound appointment_date as [From, To); ServiceCategory matches appointments with at least one
+// service type of that category.
+type AppointmentFilters struct {
+	BranchID        *uuid.UUID
+	Status          []string
+	From            *time.Time
+	To              *time.Time
+	ServiceCategory string
+	// OrganizationID limits the list to cars shared with the organization; set by the fleet view only.
+	OrganizationID *uuid.UUID
 }
 
 type ServiceAppointmentWithDetails struct {
@@ -43,8 +119,15 @@ type ServiceAppointmentWithDetails struct {
 	BranchName    string    `db:"branch_name" json:"branch_name"`
 	BranchAddress string    `db:"branch_address" json:"branch_address"`
 	ManagerName   *string   `db:"manager_name" json:"manager_name,omitempty"`
+	BayName        *string  `db:"bay_name" json:"bay_name,omitempty"`
+	TechnicianName *string  `db:"technician_name" json:"technician_name,omitempty"`
 	OwnerEmail    string    `json:"owner_email,omitempty"`
 	OwnerName     string    `json:"owner_name,omitempty"`
+	OwnerPhone    *string   `json:"owner_phone,omitempty"`
 	ServiceTypes  []ServiceType `json:"service_types"`
+	// WorkLines are the parts and labour recorded on the work order; filled on the detail view only.
+	WorkLines []ServiceWorkLine `json:"work_lines,omitempty"`
+	// UnreadMessages counts thread messages the requester has not read; filled on appointment lists only.
+	UnreadMessages int `json:"unread_messages,omitempty"`
 }
 
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service campaign kinds (service_campaigns.kind).
+const (
+	ServiceCampaignRecall  = "recall"
+	ServiceCampaignService = "service"
+)
+
+// Campaign vehicle statuses (service_campaign_vehicles.status).
+const (
+	CampaignVehicleOpen = "open"
+	CampaignVehicleDone = "done"
+)
+
+// ServiceCampaign matches table service_campaigns with its service types and progress. A car is
+// affected when it meets every criterion that is set.
+type ServiceCampaign struct {
+	CampaignID     uuid.UUID   `db:"campaign_id" json:"campaign_id"`
+	Code           string      `db:"code" json:"code"`
+	Kind           string      `db:"kind" json:"kind"`
+	Title          string      `db:"title" json:"title"`
+	Description    *string     `db:"description" json:"description,omitempty"`
+	GenerationID   *uuid.UUID  `db:"generation_id" json:"generation_id,omitempty"`
+	TrimID         *uuid.UUID  `db:"trim_id" json:"trim_id,omitempty"`
+	YearFrom       *int        `db:"year_from" json:"year_from,omitempty"`
+	YearTo         *int        `db:"year_to" json:"year_to,omitempty"`
+	VINPattern     *string     `db:"vin_pattern" json:"vin_pattern,omitempty"`
+	VINFrom        *string     `db:"vin_from" json:"vin_from,omitempty"`
+	VINTo          *string     `db:"vin_to" json:"vin_to,omitempty"`
+	IsActive       bool        `db:"is_active" json:"is_active"`
+	ServiceTypeIDs []uuid.UUID `json:"service_type_ids"`
+	AffectedCount  int         `json:"affected_count"`
+	DoneCount      int         `json:"done_count"`
+	CreatedBy      *uuid.UUID  `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// ServiceCampaignInput creates or replaces a campaign; at least one criterion is required and
+// VINFrom and VINTo go together.
+type ServiceCampaignInput struct {
+	Code           string      `json:"code"`
+	Kind           string      `json:"kind"`
+	Title          string      `json:"title"`
+	Description    *string     `json:"description,omitempty"`
+	GenerationID   *uuid.UUID  `json:"generation_id,omitempty"`
+	TrimID         *uuid.UUID  `json:"trim_id,omitempty"`
+	YearFrom       *int        `json:"year_from,omitempty"`
+	YearTo         *int        `json:"year_to,omitempty"`
+	VINPattern     *string     `json:"vin_pattern,omitempty"`
+	VINFrom        *string     `json:"vin_from,omitempty"`
+	VINTo          *string     `json:"vin_to,omitempty"`
+	ServiceTypeIDs []uuid.UUID `json:"service_type_ids"`
+	IsActive       *bool       `json:"is_active,omitempty"`
+}
+
+// ServiceCampaignVehicle is an affected car in the staff list, with its owner.
+type ServiceCampaignVehicle struct {
+	UserCarID            uuid.UUID  `db:"user_car_id" json:"user_car_id"`
+	VIN                  string     `db:"vin" json:"vin"`
+	CarTitle             string     `db:"car_title" json:"car_title"`
+	Year                 int        `db:"year" json:"year"`
+	OwnerUserID          uuid.UUID  `db:"owner_user_id" json:"owner_user_id"`
+	OwnerName            string     `db:"owner_name" json:"owner_name"`
+	OwnerEmail           string     `db:"owner_email" json:"owner_email"`
+	Status               string     `db:"status" json:"status"`
+	ServiceAppointmentID *uuid.UUID `db:"service_appointment_id" json:"service_appointment_id,omitempty"`
+	CreatedAt            time.Time  `db:"created_at" json:"created_at"`
+	CompletedAt          *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// ServiceCampaignVehicleFilters narrows the staff list of affected cars; empty Status means all.
+type ServiceCampaignVehicleFilters struct {
+	Status string
+}
+
+// ServiceCampaignCompletion links campaign work done outside the campaign services to a completed
+// appointment of the car.
+type ServiceCampaignCompletion struct {
+	ServiceAppointmentID uuid.UUID `json:"service_appointment_id"`
+}
+
+// CarCampaign is a campaign affecting a car as shown on the car detail.
+type CarCampaign struct {
+	CampaignID           uuid.UUID   `json:"campaign_id"`
+	Code                 string      `json:"code"`
+	Kind                 string      `json:"kind"`
+	Title                string      `json:"title"`
+	Description          *string     `json:"description,omitempty"`
+	ServiceTypeIDs       []uuid.UUID `json:"service_type_ids"`
+	Status               string      `json:"status"`
+	ServiceAppointmentID *uuid.UUID  `json:"service_appointment_id,omitempty"`
+	CreatedAt            time.Time   `json:"created_at"`
+	CompletedAt          *time.Time  `json:"completed_at,omitempty"`
+}
+
+// ServiceCampaignMatch is a car newly added to a campaign, for the owner's notification.
+type ServiceCampaignMatch struct {
+	CampaignID    uuid.UUID
+	CampaignKind  string
+	CampaignTitle string
+	UserCarID     uuid.UUID
+	UserID        uuid.UUID
+	CarTitle      string
+	VIN           string
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceHistoryLine is a work order line as recorded in the service book.
+type ServiceHistoryLine struct {
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	PartNumber  *string `json:"part_number,omitempty"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+// ServiceHistoryDocument is a document kept with a service book record; the file outlives the
+// documents row it was copied from.
+type ServiceHistoryDocument struct {
+	DocumentID   uuid.UUID `db:"document_id" json:"document_id"`
+	DocumentType string    `db:"document_type" json:"document_type"`
+	FilePath     string    `db:"file_path" json:"-"`
+	FileName     *string   `db:"file_name" json:"file_name,omitempty"`
+	FileSize     *int64    `db:"file_size" json:"file_size,omitempty"`
+	MimeType     *string   `db:"mime_type" json:"mime_type,omitempty"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// ServiceHistoryRecord matches table vehicle_service_records: an immutable snapshot of a completed
+// service visit keyed by VIN. The appointment and branch links are cleared when those are deleted.
+type ServiceHistoryRecord struct {
+	RecordID             uuid.UUID                `db:"record_id" json:"record_id"`
+	VIN                  string                   `db:"vin" json:"vin"`
+	ServiceAppointmentID *uuid.UUID               `db:"service_appointment_id" json:"service_appointment_id,omitempty"`
+	BranchID             *uuid.UUID               `db:"branch_id" json:"branch_id,omitempty"`
+	BranchName           string                   `db:"branch_name" json:"branch_name"`
+	VehicleTitle         string                   `db:"vehicle_title" json:"vehicle_title"`
+	VehicleYear          int                      `db:"vehicle_year" json:"vehicle_year"`
+	PerformedAt          time.Time                `db:"performed_at" json:"performed_at"`
+	OdometerKm           *int                     `db:"odometer_km" json:"odometer_km,omitempty"`
+	WorkSummary          *string                  `db:"work_summary" json:"work_summary,omitempty"`
+	Services             []string                 `db:"services" json:"services"`
+	Lines                []ServiceHistoryLine     `db:"lines" json:"lines"`
+	Total                float64                  `db:"total" json:"total"`
+	Documents            []ServiceHistoryDocument `json:"documents"`
+	CreatedAt            time.Time                `db:"created_at" json:"created_at"`
+}
+
+// ServiceBook is the service history of one VIN, oldest visit first.
+type ServiceBook struct {
+	VIN     string                 `json:"vin"`
+	Records []ServiceHistoryRecord `json:"records"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Service resource kinds (service_resources.kind).
+const (
+	ResourceKindBay        = "bay"
+	ResourceKindTechnician = "technician"
+)
+
+// ServiceResource matches table service_resources: a workshop bay or a technician of a branch.
+// Skills are equipment codes for bays and certifications for technicians.
+type ServiceResource struct {
+	ResourceID uuid.UUID  `db:"resource_id" json:"resource_id"`
+	BranchID   uuid.UUID  `db:"branch_id" json:"branch_id"`
+	Kind       string     `db:"kind" json:"kind"`
+	Name       string     `db:"name" json:"name"`
+	UserID     *uuid.UUID `db:"user_id" json:"user_id,omitempty"`
+	Skills     []string   `db:"skills" json:"skills"`
+	IsActive   bool       `db:"is_active" json:"is_active"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// ServiceResourceInput creates or updates a resource; Kind is fixed once the resource exists.
+type ServiceResourceInput struct {
+	Kind     string     `json:"kind"`
+	Name     string     `json:"name"`
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
+	Skills   []string   `json:"skills"`
+	IsActive *bool      `json:"is_active,omitempty"`
+}
+
+// ResourceAssignment is the bay and technician an appointment occupies.
+type ResourceAssignment struct {
+	BayID        *uuid.UUID `json:"bay_id,omitempty"`
+	TechnicianID *uuid.UUID `json:"technician_id,omitempty"`
+}
+
+// WorkshopLane is one resource row of the workshop schedule with its appointments of the day.
+type WorkshopLane struct {
+	Resource     ServiceResource                 `json:"resource"`
+	Appointments []ServiceAppointmentWithDetails `json:"appointments"`
+}
+
+// WorkshopSchedule is the branch workshop plan for one day: appointments per bay and technician.
+// Unassigned lists appointments holding no resource (booked before resources were set up).
+type WorkshopSchedule struct {
+	BranchID    uuid.UUID                       `json:"branch_id"`
+	Date        string                          `json:"date"`
+	Timezone    string                          `json:"timezone"`
+	Bays        []WorkshopLane                  `json:"bays"`
+	Technicians []WorkshopLane                  `json:"technicians"`
+	Unassigned  []ServiceAppointmentWithDetails `json:"unassigned"`
+}
@@ -14,6 +14,9 @@ type ServiceType struct {
 	Price          float64   `db:"price" json:"price"`
 	DurationMinutes *int     `db:"duration_minutes" json:"duration_minutes,omitempty"`
 	IsAvailable    bool      `db:"is_available" json:"is_available"`
+	// Skills a bay and a technician must have to perform the service.
+	RequiredBaySkills        []string `db:"required_bay_skills" json:"required_bay_skills"`
+	RequiredTechnicianSkills []string `db:"required_technician_skills" json:"required_technician_skills"`
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 }
 
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Work line kinds.
+const (
+	WorkLineLabour = "labour"
+	WorkLinePart   = "part"
+)
+
+// ServiceWorkLine matches table service_work_lines: a labour or part row of the work order.
+type ServiceWorkLine struct {
+	WorkLineID  uuid.UUID  `db:"work_line_id" json:"work_line_id"`
+	Kind        string     `db:"kind" json:"kind"`
+	Description string     `db:"description" json:"description"`
+	PartNumber  *string    `db:"part_number" json:"part_number,omitempty"`
+	Quantity    float64    `db:"quantity" json:"quantity"`
+	UnitPrice   float64    `db:"unit_price" json:"unit_price"`
+	Amount      float64    `json:"amount"`
+	CreatedBy   *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ServiceWorkLineInput is one line of a work order update.
+type ServiceWorkLineInput struct {
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	PartNumber  *string `json:"part_number,omitempty"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+// ServiceWorkOrderUpdate records progress on an in-progress appointment. Nil fields are left
+// unchanged; a non-nil Lines replaces all work lines.
+type ServiceWorkOrderUpdate struct {
+	OdometerKm  *int                   `json:"odometer_km,omitempty"`
+	WorkSummary *string                `json:"work_summary,omitempty"`
+	Lines       []ServiceWorkLineInput `json:"lines,omitempty"`
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DepreciationRule matches table tradein_depreciation_rules. Nil brand/segment mean "any".
+type DepreciationRule struct {
+	RuleID                       uuid.UUID  `db:"rule_id" json:"rule_id"`
+	Name                         string     `db:"name" json:"name"`
+	BrandID                      *uuid.UUID `db:"brand_id" json:"brand_id,omitempty"`
+	VehicleSegment               *string    `db:"vehicle_segment" json:"vehicle_segment,omitempty"`
+	FirstYearDepreciationPercent float64    `db:"first_year_depreciation_percent" json:"first_year_depreciation_percent"`
+	AnnualDepreciationPercent    float64    `db:"annual_depreciation_percent" json:"annual_depreciation_percent"`
+	ExpectedAnnualMileage        int        `db:"expected_annual_mileage" json:"expected_annual_mileage"`
+	MileageAdjustmentPercent     float64    `db:"mileage_adjustment_percent" json:"mileage_adjustment_percent"`
+	MinResidualPercent           float64    `db:"min_residual_percent" json:"min_residual_percent"`
+	IsActive                     bool       `db:"is_active" json:"is_active"`
+	CreatedAt                    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// DepreciationRuleInput is the admin create/replace payload for a depreciation rule.
+type DepreciationRuleInput struct {
+	Name                         string     `json:"name"`
+	BrandID                      *uuid.UUID `json:"brand_id,omitempty"`
+	VehicleSegment               *string    `json:"vehicle_segment,omitempty"`
+	FirstYearDepreciationPercent float64    `json:"first_year_depreciation_percent"`
+	AnnualDepreciationPercent    float64    `json:"annual_depreciation_percent"`
+	ExpectedAnnualMileage        int        `json:"expected_annual_mileage"`
+	MileageAdjustmentPercent     float64    `json:"mileage_adjustment_percent"`
+	MinResidualPercent           float64    `json:"min_residual_percent"`
+	IsActive                     bool       `json:"is_active"`
+}
+
+// TradeInAdjustment is a manual correction added by the appraiser (negative for defects).
+type TradeInAdjustment struct {
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount"`
+}
+
+// TradeInAppraisalCreate is the staff request to value a garage car and optionally issue an offer.
+type TradeInAppraisalCreate struct {
+	UserCarID   uuid.UUID           `json:"user_car_id"`
+	Adjustments []TradeInAdjustment `json:"adjustments"`
+	Notes       *string             `json:"notes,omitempty"`
+	ValidDays   int                 `json:"valid_days"`
+}
+
+// TradeInValuation is the depreciation model result plus manual adjustments.
+type TradeInValuation struct {
+	RuleID         *uuid.UUID          `json:"rule_id,omitempty"`
+	RuleName       string              `json:"rule_name"`
+	ReferencePrice float64             `json:"reference_price"`
+	AgeYears       int                 `json:"age_years"`
+	Mileage        int                 `json:"mileage"`
+	ModelValue     float64             `json:"model_value"`
+	Adjustments    []TradeInAdjustment `json:"adjustments"`
+	OfferAmount    float64             `json:"offer_amount"`
+}
+
+// TradeInOffer matches table tradein_offers.
+type TradeInOffer struct {
+	TradeInOfferID uuid.UUID           `db:"tradein_offer_id" json:"tradein_offer_id"`
+	UserCarID      uuid.UUID           `db:"user_car_id" json:"user_car_id"`
+	UserID         uuid.UUID           `db:"user_id" json:"user_id"`
+	AppraisedBy    *uuid.UUID          `db:"appraised_by" json:"appraised_by,omitempty"`
+	RuleID         *uuid.UUID          `db:"rule_id" json:"rule_id,omitempty"`
+	ReferencePrice float64             `db:"reference_price" json:"reference_price"`
+	AgeYears       int                 `db:"age_years" json:"age_years"`
+	Mileage        int                 `db:"mileage" json:"mileage"`
+	ModelValue     float64             `db:"model_value" json:"model_value"`
+	Adjustments    []TradeInAdjustment `db:"adjustments" json:"adjustments"`
+	OfferAmount    float64             `db:"offer_amount" json:"offer_amount"`
+	Status         string              `db:"status" json:"status"`
+	Notes          *string             `db:"notes" json:"notes,omitempty"`
+	ValidUntil     time.Time           `db:"valid_until" json:"valid_until"`
+	OrderID        *uuid.UUID          `db:"order_id" json:"order_id,omitempty"`
+	DecidedAt      *time.Time          `db:"decided_at" json:"decided_at,omitempty"`
+	CreatedAt      time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `db:"updated_at" json:"updated_at"`
+}
+
+// TradeInOfferWithDetails adds the car description for lists.
+type TradeInOfferWithDetails struct {
+	TradeInOffer
+	VIN       string `db:"vin" json:"vin"`
+	Year      int    `db:"year" json:"year"`
+	BrandName string `db:"brand_name" json:"brand_name"`
+	ModelName string `db:"model_name" json:"model_name"`
+	TrimName  string `db:"trim_name" json:"trim_name"`
+}
+
+// TradeInAttach links an accepted offer to an order.
+type TradeInAttach struct {
+	TradeInOfferID uuid.UUID `json:"tradein_offer_id"`
+}
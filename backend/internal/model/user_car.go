@@ -15,6 +15,10 @@ type UserCar struct {
 	Year          int        `db:"year" json:"year"`
 	CurrentMileage int       `db:"current_mileage" json:"current_mileage"`
 	PurchaseDate  *time.Time `db:"purchase_date" json:"purchase_date,omitempty"`
+	// OrderID is set when the car was handed over from a dealer order.
+	OrderID       *uuid.UUID `db:"order_id" json:"order_id,omitempty"`
+	// OrganizationID is set when the car is shared with an organization's members.
+	OrganizationID *uuid.UUID `db:"organization_id" json:"organization_id,omitempty"`
 	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
 }
 
@@ -35,5 +39,16 @@ type UserCarWithDetails struct {
 	ColorName  string  `db:"color_name" json:"color_name"`
 	ColorHex   *string `db:"color_hex" json:"color_hex,omitempty"`
 	ImageURL   *string `db:"image_url" json:"image_url,omitempty"`
+	// Campaigns is filled on the car detail only: open recall and service campaigns first.
+	Campaigns []CarCampaign `json:"campaigns,omitempty"`
 }
 
+
+// OrderHandover is the staff input for handing an ordered car over to the customer.
+type OrderHandover struct {
+	VIN          string `json:"vin"`
+	DeliveryDate string `json:"delivery_date"` // YYYY-MM-DD
+	Mileage      int    `json:"mileage"`
+	// Year defaults to the delivery year.
+	Year *int `json:"year,omitempty"`
+}
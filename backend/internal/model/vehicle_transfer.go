@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VehicleTransferPending   = "pending"
+	VehicleTransferAccepted  = "accepted"
+	VehicleTransferCancelled = "cancelled"
+	VehicleTransferExpired   = "expired"
+	VehicleTransferForced    = "forced"
+
+	// TransferPolicyCancel cancels the scheduled service appointments of the car on transfer;
+	// TransferPolicyTransfer keeps them, so they move to the new owner with the car.
+	TransferPolicyCancel   = "cancel"
+	TransferPolicyTransfer = "transfer"
+)
+
+// VehicleTransfer matches table vehicle_transfers: a change of a garage car's owner.
+type VehicleTransfer struct {
+	TransferID            uuid.UUID  `db:"transfer_id" json:"transfer_id"`
+	UserCarID             uuid.UUID  `db:"user_car_id" json:"user_car_id"`
+	VIN                   string     `db:"vin" json:"vin"`
+	FromUserID            *uuid.UUID `db:"from_user_id" json:"from_user_id,omitempty"`
+	ToUserID              *uuid.UUID `db:"to_user_id" json:"to_user_id,omitempty"`
+	Status                string     `db:"status" json:"status"`
+	AppointmentPolicy     string     `db:"appointment_policy" json:"appointment_policy"`
+	FailedAttempts        int        `db:"failed_attempts" json:"failed_attempts"`
+	CancelledAppointments int        `db:"cancelled_appointments" json:"cancelled_appointments"`
+	ExpiresAt             *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	ForcedBy              *uuid.UUID `db:"forced_by" json:"forced_by,omitempty"`
+	Reason                *string    `db:"reason" json:"reason,omitempty"`
+	CreatedAt             time.Time  `db:"created_at" json:"created_at"`
+	CompletedAt           *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	// Code is returned only once, when the seller starts the transfer.
+	Code string `json:"code,omitempty"`
+}
+
+// VehicleTransferCreate is the seller's request to hand a car over to another customer.
+type VehicleTransferCreate struct {
+	// AppointmentPolicy defaults to cancel.
+	AppointmentPolicy string `json:"appointment_policy"`
+}
+
+// VehicleTransferAccept is the buyer's confirmation with the code received from the seller.
+type VehicleTransferAccept struct {
+	VIN  string `json:"vin"`
+	Code string `json:"code"`
+}
+
+// VehicleTransferForce is a staff transfer without the seller's code; the reason is kept for audit.
+// The new owner is given by user_id or email.
+type VehicleTransferForce struct {
+	ToUserID          *uuid.UUID `json:"to_user_id,omitempty"`
+	ToEmail           *string    `json:"to_email,omitempty"`
+	Reason            string     `json:"reason"`
+	AppointmentPolicy string     `json:"appointment_policy"`
+}
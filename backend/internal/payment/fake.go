@@ -0,0 +1,101 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the webhook body.
+const SignatureHeader = "X-Payment-Signature"
+
+// Fake is a local gateway for development and tests. Payments stay pending until a signed webhook
+// confirms them, unless autoConfirm is set; refunds settle immediately.
+type Fake struct {
+	secret      []byte
+	autoConfirm bool
+
+	mu       sync.Mutex
+	payments map[string]float64
+	refunded map[string]float64
+}
+
+func NewFake(secret string, autoConfirm bool) *Fake {
+	return &Fake{
+		secret:      []byte(secret),
+		autoConfirm: autoConfirm,
+		payments:    map[string]float64{},
+		refunded:    map[string]float64{},
+	}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("payment: amount must be positive")
+	}
+	ref := "fake_pay_" + req.PaymentID.String()
+	f.mu.Lock()
+	f.payments[ref] = req.Amount
+	f.mu.Unlock()
+
+	res := &PaymentResult{Ref: ref, Status: StatusPending, PaymentURL: "/fake-checkout/" + ref}
+	if f.autoConfirm {
+		res.Status = StatusSucceeded
+		res.PaymentURL = ""
+	}
+	return res, nil
+}
+
+func (f *Fake) Refund(ctx context.Context, req RefundRequest) (*RefundResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paid, ok := f.payments[req.PaymentRef]
+	if !ok {
+		return &RefundResult{Status: StatusFailed, FailureReason: "unknown payment"}, nil
+	}
+	// Compare in cents so float rounding cannot reject an exact full refund.
+	if math.Round((f.refunded[req.PaymentRef]+req.Amount)*100) > math.Round(paid*100) {
+		return &RefundResult{Status: StatusFailed, FailureReason: "refund exceeds payment"}, nil
+	}
+	f.refunded[req.PaymentRef] += req.Amount
+	return &RefundResult{Ref: "fake_refund_" + req.RefundID.String(), Status: StatusSucceeded}, nil
+}
+
+func (f *Fake) ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	got, err := hex.DecodeString(header.Get(SignatureHeader))
+	if err != nil || !hmac.Equal(got, f.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+	var ev WebhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, fmt.Errorf("payment: decode webhook: %w", err)
+	}
+	switch ev.Type {
+	case EventPayment, EventRefund:
+	default:
+		return nil, fmt.Errorf("payment: unknown webhook type %q", ev.Type)
+	}
+	if ev.Status != StatusSucceeded && ev.Status != StatusFailed {
+		return nil, fmt.Errorf("payment: webhook status must be final, got %q", ev.Status)
+	}
+	return &ev, nil
+}
+
+// Sign returns the SignatureHeader value for body, for simulating provider callbacks.
+func (f *Fake) Sign(body []byte) string {
+	return hex.EncodeToString(f.sign(body))
+}
+
+func (f *Fake) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFakePaymentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	gw := NewFake("secret", false)
+
+	res, err := gw.CreatePayment(ctx, PaymentRequest{PaymentID: uuid.New(), Amount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != StatusPending || res.PaymentURL == "" {
+		t.Fatalf("CreatePayment() = %+v, want pending with checkout URL", res)
+	}
+
+	r1, _ := gw.Refund(ctx, RefundRequest{RefundID: uuid.New(), PaymentRef: res.Ref, Amount: 60})
+	if r1.Status != StatusSucceeded {
+		t.Fatalf("first refund = %+v, want succeeded", r1)
+	}
+	r2, _ := gw.Refund(ctx, RefundRequest{RefundID: uuid.New(), PaymentRef: res.Ref, Amount: 40.01})
+	if r2.Status != StatusFailed {
+		t.Fatalf("over-refund = %+v, want failed", r2)
+	}
+	r3, _ := gw.Refund(ctx, RefundRequest{RefundID: uuid.New(), PaymentRef: "missing", Amount: 1})
+	if r3.Status != StatusFailed {
+		t.Fatalf("unknown payment refund = %+v, want failed", r3)
+	}
+}
+
+func TestFakeAutoConfirm(t *testing.T) {
+	res, err := NewFake("secret", true).CreatePayment(context.Background(), PaymentRequest{PaymentID: uuid.New(), Amount: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != StatusSucceeded {
+		t.Fatalf("status = %s, want succeeded", res.Status)
+	}
+}
+
+func TestFakeParseWebhook(t *testing.T) {
+	gw := NewFake("secret", false)
+	body := []byte(`{"type":"payment","ref":"fake_pay_1","status":"succeeded"}`)
+
+	h := http.Header{}
+	h.Set(SignatureHeader, gw.Sign(body))
+	ev, err := gw.ParseWebhook(h, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != EventPayment || ev.Ref != "fake_pay_1" || ev.Status != StatusSucceeded {
+		t.Fatalf("ParseWebhook() = %+v", ev)
+	}
+
+	h.Set(SignatureHeader, NewFake("other", false).Sign(body))
+	if _, err := gw.ParseWebhook(h, body); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("foreign signature err = %v, want ErrInvalidSignature", err)
+	}
+
+	pending := []byte(`{"type":"payment","ref":"fake_pay_1","status":"pending"}`)
+	h.Set(SignatureHeader, gw.Sign(pending))
+	if _, err := gw.ParseWebhook(h, pending); err == nil {
+		t.Fatal("non-final status accepted")
+	}
+}
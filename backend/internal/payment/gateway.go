@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Outcome of a payment or refund at the provider.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Webhook event kinds.
+const (
+	EventPayment = "payment"
+	EventRefund  = "refund"
+)
+
+// ErrInvalidSignature is returned when a webhook body does not match its signature.
+var ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+
+// Gateway is an online payment provider. Payments are usually created pending and confirmed
+// later through a webhook; refunds may settle synchronously or the same way.
+type Gateway interface {
+	Name() string
+	CreatePayment(ctx context.Context, req PaymentRequest) (*PaymentResult, error)
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+	// ParseWebhook verifies the notification signature and decodes it.
+	ParseWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+}
+
+// PaymentRequest asks the provider to collect Amount; PaymentID is our idempotency key.
+type PaymentRequest struct {
+	PaymentID   uuid.UUID
+	Amount      float64
+	Currency    string
+	Description string
+}
+
+// PaymentResult carries the provider reference and, for redirect flows, the checkout URL.
+type PaymentResult struct {
+	Ref           string
+	Status        string
+	PaymentURL    string
+	FailureReason string
+}
+
+// RefundRequest returns Amount of the payment identified by the provider reference.
+type RefundRequest struct {
+	RefundID   uuid.UUID
+	PaymentRef string
+	Amount     float64
+}
+
+type RefundResult struct {
+	Ref           string
+	Status        string
+	FailureReason string
+}
+
+// WebhookEvent is a final status of a payment or refund reported by the provider.
+type WebhookEvent struct {
+	Type          string `json:"type"`
+	Ref           string `json:"ref"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
@@ -0,0 +1,115 @@
+package pdfgen
+
+import "time"
+
+// Party identifies a customer or staff member on a document.
+type Party struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+// Line is a priced row of a document table.
+type Line struct {
+	Description string
+	Amount      float64
+}
+
+// Vehicle describes the car a document refers to.
+type Vehicle struct {
+	Title   string // brand, model, generation and trim
+	Specs   string // engine, transmission, drive
+	Color   string
+	VIN     string
+	Year    int
+	Mileage int
+}
+
+// Offer is the data of a commercial offer for a configuration.
+type Offer struct {
+	Number        string
+	Date          time.Time
+	ValidUntil    *time.Time
+	Customer      Party
+	Vehicle       Vehicle
+	Lines         []Line
+	ListPrice     float64
+	Discounts     []Line
+	DiscountTotal float64
+	FinalPrice    float64
+}
+
+// Contract is the data of a vehicle sale contract for an order.
+type Contract struct {
+	Number        string
+	Date          time.Time
+	Customer      Party
+	Manager       string
+	Vehicle       Vehicle
+	Lines         []Line
+	ListPrice     float64
+	Discounts     []Line
+	DiscountTotal float64
+	FinalPrice    float64
+	TradeInCredit float64
+	AmountDue     float64
+	Finance       string
+}
+
+// ServiceDocument is the data of a service order (work authorization) or a service act (completed work).
+type ServiceDocument struct {
+	Number          string
+	Date            time.Time
+	Customer        Party
+	Manager         string
+	Vehicle         Vehicle
+	Branch          string
+	BranchAddress   string
+	AppointmentAt   time.Time
+	DurationMinutes int
+	Works           []Line
+	Total           float64
+	Notes           string
+}
+
+// ServiceBook is the digital service book of a vehicle: its completed service visits in order.
+type ServiceBook struct {
+	Date    time.Time
+	Vehicle Vehicle
+	Entries []ServiceBookEntry
+}
+
+// ServiceBookEntry is one completed visit; Mileage is 0 when no reading was recorded.
+type ServiceBookEntry struct {
+	Date    time.Time
+	Branch  string
+	Mileage int
+	Works   []Line
+	Total   float64
+	Notes   string
+}
+
+// CommercialOffer renders a commercial offer PDF.
+func CommercialOffer(o Offer) ([]byte, error) {
+	return render("commercial_offer", "Commercial offer No. "+o.Number, o)
+}
+
+// OrderContract renders a sale contract PDF.
+func OrderContract(c Contract) ([]byte, error) {
+	return render("order_contract", "Sale contract No. "+c.Number, c)
+}
+
+// ServiceOrder renders a service order PDF for a booked appointment.
+func ServiceOrder(d ServiceDocument) ([]byte, error) {
+	return render("service_order", "Service order No. "+d.Number, d)
+}
+
+// ServiceAct renders a certificate of completed service work.
+func ServiceAct(d ServiceDocument) ([]byte, error) {
+	return render("service_act", "Service act No. "+d.Number, d)
+}
+
+// DigitalServiceBook renders the service history of a vehicle.
+func DigitalServiceBook(b ServiceBook) ([]byte, error) {
+	return render("service_book", "Service book VIN "+b.Vehicle.VIN, b)
+}
@@ -0,0 +1,167 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransliterate(t *testing.T) {
+	cases := map[string]string{
+		"Москва, ул. Щукина": "Moskva, ul. Shhukina",
+		"Café № 5 — 100 ₽":   "Café No. 5 — 100 RUB",
+		"Ёжик\tв тумане":     "Yozhik v tumane",
+		"emoji 🚗":            "emoji ?",
+		"Объём":              "Obyom",
+	}
+	for in, want := range cases {
+		if got := Transliterate(in); got != want {
+			t.Errorf("Transliterate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	cases := map[float64]string{
+		0:           "0.00",
+		999.5:       "999.50",
+		1234567.891: "1\u00a0234\u00a0567.89",
+		-25000:      "-25\u00a0000.00",
+	}
+	for in, want := range cases {
+		if got := Money(in); got != want {
+			t.Errorf("Money(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap(regular, 10, 100, "Привет мир, это довольно длинная строка текста\nвторая")
+	if len(lines) < 3 {
+		t.Fatalf("expected the text to wrap, got %q", lines)
+	}
+	for _, ln := range lines {
+		if w := textWidth(regular, 10, ln); w > 100 {
+			t.Errorf("line %q is %.1fpt wide, limit 100", ln, w)
+		}
+	}
+	if lines[len(lines)-1] != "vtoraya" {
+		t.Errorf("explicit newline not preserved: %q", lines)
+	}
+
+	long := wrap(bold, 12, 50, strings.Repeat("W", 40))
+	if len(long) < 2 || strings.Join(long, "") != strings.Repeat("W", 40) {
+		t.Errorf("long word not hard-split losslessly: %q", long)
+	}
+
+	if got := wrap(regular, 10, 70, "Total "+Money(1234567)); len(got) != 2 {
+		t.Errorf("no-break spaces must not wrap: %q", got)
+	}
+}
+
+// checkPDF verifies the file framing and that every xref offset points at its object.
+func checkPDF(t *testing.T, pdf []byte) int {
+	t.Helper()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[off:off+10])
+		}
+	}
+	return bytes.Count(pdf, []byte("/Type /Page "))
+}
+
+func TestCommercialOffer(t *testing.T) {
+	until := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	pdf, err := CommercialOffer(Offer{
+		Number:     "1A2B3C4D",
+		Date:       time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		ValidUntil: &until,
+		Customer:   Party{Name: "Иван Петров", Email: "ivan@example.com"},
+		Vehicle:    Vehicle{Title: "Lada Vesta (Sport)", Color: "Белый"},
+		Lines:      []Line{{"Base price", 1500000}, {"Option | heated seats", 25000}},
+		ListPrice:  1525000,
+		Discounts:  []Line{{"Spring sale", 50000}},
+		FinalPrice: 1475000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pages := checkPDF(t, pdf); pages != 1 {
+		t.Errorf("pages = %d, want 1", pages)
+	}
+	for _, want := range []string{"Ivan Petrov", `Lada Vesta \(Sport\)`, "Option / heated seats", "1\xa0475\xa0000.00", "1 / 1"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
+
+func TestServiceActPaginates(t *testing.T) {
+	d := ServiceDocument{Number: "42", Date: time.Now(), AppointmentAt: time.Now(), Vehicle: Vehicle{VIN: "XTA21099012345678"}}
+	for i := 0; i < 120; i++ {
+		d.Works = append(d.Works, Line{fmt.Sprintf("Work item %d", i), 1000})
+		d.Total += 1000
+	}
+	pdf, err := ServiceAct(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := checkPDF(t, pdf)
+	if pages < 2 {
+		t.Fatalf("pages = %d, want a multi-page document", pages)
+	}
+	if !bytes.Contains(pdf, []byte(fmt.Sprintf("%d / %d", pages, pages))) {
+		t.Error("last page footer missing")
+	}
+}
+
+func TestAllTemplatesRender(t *testing.T) {
+	if _, err := OrderContract(Contract{Number: "1", Date: time.Now(), TradeInCredit: 10, Finance: "Bank, 36 months"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := ServiceOrder(ServiceDocument{Number: "1", Date: time.Now(), AppointmentAt: time.Now()}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDigitalServiceBook(t *testing.T) {
+	book := ServiceBook{
+		Date:    time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Vehicle: Vehicle{Title: "Lada Vesta", VIN: "XTA21099012345678", Year: 2024},
+		Entries: []ServiceBookEntry{
+			{Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), Branch: "Центр", Mileage: 15100, Works: []Line{{"Oil change", 4500}}, Total: 4500},
+			{Date: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Branch: "Центр", Works: []Line{{"Brake pads", 9000}}, Total: 9000, Notes: "Front axle"},
+		},
+	}
+	pdf, err := DigitalServiceBook(book)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPDF(t, pdf)
+	for _, want := range []string{"XTA21099012345678", "01.09.2025, mileage 15100 km", "Oil change", "Notes: Front axle"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+	if _, err := DigitalServiceBook(ServiceBook{Date: time.Now()}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package pdfgen renders document templates into simple single-column A4 PDFs without external
+// dependencies.
+//
+// Templates (templates/*.tmpl) are text/template files producing a small line-based markup:
+//
+//	# Title
+//	## Section heading
+//	| cell | cell | cell |   table row: first column left-aligned, the rest right-aligned
+//	!| cell | cell |          bold table row (totals)
+//	> note                    small grey text
+//	---                       horizontal rule
+//	(blank line)              vertical gap
+//	anything else             wrapped paragraph
+//
+// Text is drawn with the standard Helvetica fonts, so it is limited to WinAnsi; see Transliterate.
+package pdfgen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"money": Money,
+	"date": func(t time.Time) string {
+		return t.Format("02.01.2006")
+	},
+	"datetime": func(t time.Time) string {
+		return t.Format("02.01.2006 15:04")
+	},
+	// cell strips the table separator from values interpolated into table rows.
+	"cell": func(s string) string {
+		return strings.ReplaceAll(s, "|", "/")
+	},
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Money formats an amount with no-break-space grouped thousands and two decimals, e.g. "1 234 567.50".
+func Money(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(c)
+	}
+	if neg {
+		return "-" + b.String() + frac
+	}
+	return b.String() + frac
+}
+
+// render executes the named template (file name without extension) with data and lays the
+// resulting markup out as a PDF titled title.
+func render(name, title string, data any) ([]byte, error) {
+	var markup bytes.Buffer
+	if err := templates.ExecuteTemplate(&markup, name+".tmpl", data); err != nil {
+		return nil, fmt.Errorf("pdfgen: execute %s: %w", name, err)
+	}
+	return layout(title, markup.String()), nil
+}
+
+const (
+	bodySize    = 10.0
+	titleSize   = 16.0
+	headingSize = 12.0
+	noteSize    = 8.5
+	leading     = 1.35
+)
+
+type layouter struct {
+	pages []*page
+	cur   *page
+	y     float64
+}
+
+func (l *layouter) newPage() {
+	l.cur = &page{}
+	l.pages = append(l.pages, l.cur)
+	l.y = pageHeight - margin
+}
+
+// advance moves the cursor down by h, starting a new page when the line would cross the bottom margin.
+func (l *layouter) advance(h float64) {
+	if l.y-h < margin+footerSize*2 {
+		l.newPage()
+	}
+	l.y -= h
+}
+
+func layout(title, markup string) []byte {
+	l := &layouter{}
+	l.newPage()
+	width := pageWidth - 2*margin
+
+	for _, raw := range strings.Split(markup, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			l.y -= bodySize * 0.6
+		case line == "---":
+			l.advance(bodySize * 0.8)
+			l.cur.line(margin, l.y+bodySize*0.3, pageWidth-margin, l.y+bodySize*0.3, 0.5)
+		case strings.HasPrefix(line, "## "):
+			l.y -= headingSize * 0.4
+			l.paragraph(bold, headingSize, 0, width, strings.TrimPrefix(line, "## "))
+		case strings.HasPrefix(line, "# "):
+			l.paragraph(bold, titleSize, 0, width, strings.TrimPrefix(line, "# "))
+			l.y -= titleSize * 0.4
+		case strings.HasPrefix(line, "> "):
+			l.paragraph(regular, noteSize, 0.4, width, strings.TrimPrefix(line, "> "))
+		case strings.HasPrefix(line, "!|"):
+			l.tableRow(bold, width, line[1:])
+		case strings.HasPrefix(line, "|"):
+			l.tableRow(regular, width, line)
+		default:
+			l.paragraph(regular, bodySize, 0, width, line)
+		}
+	}
+
+	for i, p := range l.pages {
+		footer := fmt.Sprintf("%s - %d / %d", title, i+1, len(l.pages))
+		p.text(regular, footerSize, margin, margin-footerSize, 0.5, footer)
+	}
+	return writePDF(title, l.pages)
+}
+
+func (l *layouter) paragraph(f font, size, gray, width float64, text string) {
+	for _, ln := range wrap(f, size, width, text) {
+		l.advance(size * leading)
+		l.cur.text(f, size, margin, l.y, gray, ln)
+	}
+}
+
+// tableRow lays out "| a | b | c |": the first column takes half the width and wraps, the others
+// share the rest and are right-aligned.
+func (l *layouter) tableRow(f font, width float64, row string) {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	const gap = 8.0
+	firstW := width
+	otherW := 0.0
+	if len(cells) > 1 {
+		firstW = width / 2
+		otherW = (width - firstW) / float64(len(cells)-1)
+	}
+
+	first := wrap(f, bodySize, firstW-gap, cells[0])
+	for i, ln := range first {
+		l.advance(bodySize * leading)
+		l.cur.text(f, bodySize, margin, l.y, 0, ln)
+		if i > 0 {
+			continue
+		}
+		for j, c := range cells[1:] {
+			c = Transliterate(c)
+			right := margin + firstW + otherW*float64(j+1)
+			l.cur.text(f, bodySize, right-textWidth(f, bodySize, c), l.y, 0, c)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package pdfgen
+
+import "strings"
+
+// The standard PDF fonts only cover WinAnsi (roughly Latin-1), so Cyrillic is transliterated
+// (ГОСТ 7.79-2000 system B without diacritics) and other unsupported runes become "?".
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "cz", 'ч': "ch", 'ш': "sh", 'щ': "shh",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo", 'Ж': "Zh", 'З': "Z",
+	'И': "I", 'Й': "J", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N", 'О': "O", 'П': "P", 'Р': "R",
+	'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "X", 'Ц': "Cz", 'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shh",
+	'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu", 'Я': "Ya",
+}
+
+// winAnsiExtra maps runes outside Latin-1 that WinAnsi places in 0x80-0x9F.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+var substitutes = map[rune]string{
+	'№': "No.", '₽': "RUB", '\t': " ", '\u2009': " ", '\u202f': " ",
+}
+
+// Transliterate returns s restricted to characters the PDF fonts can draw.
+func Transliterate(s string) string {
+	return string(decodeWinAnsi(encodeWinAnsi(s)))
+}
+
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f:
+			out = append(out, byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			if t, ok := cyrillic[r]; ok {
+				out = append(out, t...)
+			} else if b, ok := winAnsiExtra[r]; ok {
+				out = append(out, b)
+			} else if t, ok := substitutes[r]; ok {
+				out = append(out, t...)
+			} else if r >= 0x20 {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func decodeWinAnsi(b []byte) []rune {
+	out := make([]rune, 0, len(b))
+	for _, c := range b {
+		r := rune(c)
+		for k, v := range winAnsiExtra {
+			if v == c {
+				r = k
+				break
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Helvetica and Helvetica-Bold advance widths (1/1000 em) for ASCII 32..126, from the Adobe AFMs.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// textWidth measures s in points; non-ASCII bytes use an average glyph width.
+func textWidth(f font, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if f == bold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range encodeWinAnsi(s) {
+		switch {
+		case c >= 32 && c <= 126:
+			total += widths[c-32]
+		case c == 0xa0:
+			total += widths[0]
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrap splits s into lines no wider than maxWidth, breaking on spaces and hard-splitting long words.
+func wrap(f font, size, maxWidth float64, s string) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		// Split on plain spaces only: no-break spaces (e.g. in amounts) keep words together.
+		var words []string
+		for _, w := range strings.Split(Transliterate(para), " ") {
+			if w != "" {
+				words = append(words, w)
+			}
+		}
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		cur := ""
+		for _, w := range words {
+			for textWidth(f, size, w) > maxWidth {
+				runes := []rune(w)
+				n := len(runes) - 1
+				for n > 1 && textWidth(f, size, string(runes[:n])) > maxWidth {
+					n--
+				}
+				if cur != "" {
+					lines = append(lines, cur)
+					cur = ""
+				}
+				lines = append(lines, string(runes[:n]))
+				w = string(runes[n:])
+			}
+			switch {
+			case cur == "":
+				cur = w
+			case textWidth(f, size, cur+" "+w) <= maxWidth:
+				cur += " " + w
+			default:
+				lines = append(lines, cur)
+				cur = w
+			}
+		}
+		lines = append(lines, cur)
+	}
+	return lines
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type AssignmentRepository struct {
+	db *database.DB
+}
+
+func NewAssignmentRepository(db *database.DB) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+// assignmentTarget describes the table behind an assignable entity and what counts as open work.
+type assignmentTarget struct {
+	table    string
+	idColumn string
+	open     string
+	notFound string
+}
+
+var assignmentTargets = map[string]assignmentTarget{
+	"order": {
+		table:    "orders",
+		idColumn: "order_id",
+		open:     "status IN (SELECT code FROM order_status_definitions WHERE is_terminal = false)",
+		notFound: "Order not found",
+	},
+	"appointment": {
+		table:    "service_appointments",
+		idColumn: "service_appointment_id",
+		open:     "status IN ('scheduled','in_progress')",
+		notFound: "Appointment not found",
+	},
+}
+
+func lookupAssignmentTarget(entity string) (assignmentTarget, error) {
+	t, ok := assignmentTargets[entity]
+	if !ok {
+		return assignmentTarget{}, apperr.BadRequest("Unknown assignment entity")
+	}
+	return t, nil
+}
+
+// assigneeLoadSelect lists staff with their open orders and open (scheduled or in progress) appointments.
+const assigneeLoadSelect = `
+	SELECT u.user_id, u.first_name || ' ' || u.last_name, u.role,
+		(SELECT COUNT(*) FROM orders o
+			JOIN order_status_definitions d ON d.code = o.status
+			WHERE o.manager_id = u.user_id AND d.is_terminal = false),
+		(SELECT COUNT(*) FROM service_appointments sa
+			WHERE sa.manager_id = u.user_id AND sa.status IN ('scheduled','in_progress'))
+	FROM users u
+`
+
+func scanAssigneeLoads(rows pgx.Rows) ([]model.AssigneeLoad, error) {
+	defer rows.Close()
+	out := []model.AssigneeLoad{}
+	for rows.Next() {
+		var l model.AssigneeLoad
+		if err := rows.Scan(&l.UserID, &l.Name, &l.Role, &l.OpenOrders, &l.OpenAppointments); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func (r *AssignmentRepository) ListRules(ctx context.Context) ([]model.AssignmentRule, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT entity_type, strategy, role_code, last_assignee_id, updated_at
+		FROM assignment_rules
+		ORDER BY entity_type
+	`)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.AssignmentRule{}
+	for rows.Next() {
+		var rule model.AssignmentRule
+		if err := rows.Scan(&rule.EntityType, &rule.Strategy, &rule.RoleCode, &rule.LastAssigneeID, &rule.UpdatedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, rule)
+	}
+	return out, rows.Err()
+}
+
+func (r *AssignmentRepository) UpdateRule(ctx context.Context, entity string, in model.AssignmentRuleUpdate) (*model.AssignmentRule, error) {
+	var rule model.AssignmentRule
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE assignment_rules SET strategy = $2, role_code = $3
+		WHERE entity_type = $1
+		RETURNING entity_type, strategy, role_code, last_assignee_id, updated_at
+	`, entity, in.Strategy, in.RoleCode).Scan(&rule.EntityType, &rule.Strategy, &rule.RoleCode, &rule.LastAssigneeID, &rule.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Assignment rule not found")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "23514") {
+			return nil, apperr.BadRequest("Invalid strategy or role")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &rule, nil
+}
+
+// Assign sets the responsible staff member of an open item; closed items fail with a conflict. With
+// onlyIfUnassigned the call also fails with a conflict when someone else already owns the item
+// (claiming).
+func (r *AssignmentRepository) Assign(ctx context.Context, entity string, id uuid.UUID, managerID *uuid.UUID, onlyIfUnassigned bool) error {
+	t, err := lookupAssignmentTarget(entity)
+	if err != nil {
+		return err
+	}
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE `+t.table+` SET manager_id = $2
+		WHERE `+t.idColumn+` = $1 AND `+t.open+` AND ($3 = false OR manager_id IS NULL OR manager_id = $2)
+	`, id, managerID, onlyIfUnassigned)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() > 0 {
+		return nil
+	}
+	var open bool
+	if err := r.db.Pool.QueryRow(ctx, `SELECT `+t.open+` FROM `+t.table+` WHERE `+t.idColumn+` = $1`, id).Scan(&open); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFoundErr(t.notFound)
+		}
+		return apperr.Internal(err)
+	}
+	if !open {
+		return apperr.Conflict("item is closed")
+	}
+	return apperr.Conflict("Already assigned to another staff member")
+}
+
+// AutoAssign assigns an unassigned open item according to the entity's rule. pick chooses among
+// candidates of the rule's role (ordered by user_id); a nil result leaves the item unassigned.
+// The rule row is locked so concurrent round-robin picks do not collide.
+func (r *AssignmentRepository) AutoAssign(
+	ctx context.Context,
+	entity string,
+	id uuid.UUID,
+	pick func(rule model.AssignmentRule, candidates []model.AssigneeLoad) *uuid.UUID,
+) (*uuid.UUID, error) {
+	t, err := lookupAssignmentTarget(entity)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var rule model.AssignmentRule
+	err = tx.QueryRow(ctx, `
+		SELECT entity_type, strategy, role_code, last_assignee_id, updated_at
+		FROM assignment_rules WHERE entity_type = $1
+		FOR UPDATE
+	`, entity).Scan(&rule.EntityType, &rule.Strategy, &rule.RoleCode, &rule.LastAssigneeID, &rule.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperr.Internal(err)
+	}
+	if rule.Strategy == "manual" {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, assigneeLoadSelect+`WHERE u.role = $1 ORDER BY u.user_id`, rule.RoleCode)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	candidates, err := scanAssigneeLoads(rows)
+	if err != nil {
+		return nil, err
+	}
+	assignee := pick(rule, candidates)
+	if assignee == nil {
+		return nil, nil
+	}
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE `+t.table+` SET manager_id = $2
+		WHERE `+t.idColumn+` = $1 AND manager_id IS NULL AND `+t.open,
+		id, *assignee)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return nil, nil
+	}
+	if _, err := tx.Exec(ctx, `UPDATE assignment_rules SET last_assignee_id = $2 WHERE entity_type = $1`, entity, *assignee); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return assignee, nil
+}
+
+// ListUnassignedOpen returns open items without a responsible staff member, oldest first.
+func (r *AssignmentRepository) ListUnassignedOpen(ctx context.Context, entity string) ([]uuid.UUID, error) {
+	t, err := lookupAssignmentTarget(entity)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+t.idColumn+` FROM `+t.table+`
+		WHERE manager_id IS NULL AND `+t.open+`
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// Workload returns open work per staff member, unassigned totals and the average time orders
+// spend in each status (from order_events; the current span of open orders counts up to now).
+func (r *AssignmentRepository) Workload(ctx context.Context) (*model.WorkloadOverview, error) {
+	rows, err := r.db.Pool.Query(ctx, assigneeLoadSelect+`
+		WHERE u.role IN (SELECT code FROM role_definitions WHERE is_staff = true)
+		ORDER BY u.last_name, u.first_name
+	`)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	out := &model.WorkloadOverview{}
+	if out.Managers, err = scanAssigneeLoads(rows); err != nil {
+		return nil, err
+	}
+
+	err = r.db.Pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM orders WHERE manager_id IS NULL AND `+assignmentTargets["order"].open+`),
+			(SELECT COUNT(*) FROM service_appointments WHERE manager_id IS NULL AND `+assignmentTargets["appointment"].open+`)
+	`).Scan(&out.UnassignedOrders, &out.UnassignedAppointments)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	rows, err = r.db.Pool.Query(ctx, `
+		WITH spans AS (
+			SELECT e.to_status AS status, e.created_at AS entered_at,
+				LEAD(e.created_at) OVER (PARTITION BY e.order_id ORDER BY e.created_at, e.event_id) AS left_at
+			FROM order_events e
+		)
+		SELECT s.status, d.customer_label_ru,
+			(AVG(EXTRACT(EPOCH FROM (COALESCE(s.left_at, now()) - s.entered_at))) / 3600)::float8,
+			COUNT(*)
+		FROM spans s
+		JOIN order_status_definitions d ON d.code = s.status
+		WHERE d.is_terminal = false OR s.left_at IS NOT NULL
+		GROUP BY s.status, d.customer_label_ru, d.sort_order
+		ORDER BY d.sort_order, s.status
+	`)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	out.OrderStatusDurations = []model.StatusDuration{}
+	for rows.Next() {
+		var d model.StatusDuration
+		if err := rows.Scan(&d.Status, &d.Label, &d.AvgHours, &d.Samples); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out.OrderStatusDurations = append(out.OrderStatusDurations, d)
+	}
+	return out, rows.Err()
+}
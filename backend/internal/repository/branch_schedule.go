@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// BranchScheduleRepository stores branch weekly hours and dated calendar exceptions.
+type BranchScheduleRepository struct {
+	db *database.DB
+}
+
+func NewBranchScheduleRepository(db *database.DB) *BranchScheduleRepository {
+	return &BranchScheduleRepository{db: db}
+}
+
+// Weekly returns the branch working weekdays ordered Sunday first.
+func (r *BranchScheduleRepository) Weekly(ctx context.Context, branchID uuid.UUID) ([]model.BranchWeeklyHours, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT weekday, open_minutes, close_minutes, break_start_minutes, break_end_minutes
+		FROM branch_weekly_hours
+		WHERE branch_id = $1
+		ORDER BY weekday
+	`, branchID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.BranchWeeklyHours{}
+	for rows.Next() {
+		var d model.BranchWeeklyHours
+		if err := rows.Scan(&d.Weekday, &d.OpenMinutes, &d.CloseMinutes, &d.BreakStartMinutes, &d.BreakEndMinutes); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// ReplaceWeekly replaces the whole weekly schedule of the branch.
+func (r *BranchScheduleRepository) ReplaceWeekly(ctx context.Context, branchID uuid.UUID, days []model.BranchWeeklyHours) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM branch_weekly_hours WHERE branch_id = $1`, branchID); err != nil {
+		return apperr.Internal(err)
+	}
+	for _, d := range days {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO branch_weekly_hours (branch_id, weekday, open_minutes, close_minutes, break_start_minutes, break_end_minutes)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, branchID, d.Weekday, d.OpenMinutes, d.CloseMinutes, d.BreakStartMinutes, d.BreakEndMinutes); err != nil {
+			if conflict := mapUniqueViolation(err, "Weekday listed twice"); conflict != nil {
+				return conflict
+			}
+			return apperr.Internal(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+const branchExceptionSelect = `
+	SELECT exception_id, branch_id, day, is_closed, open_minutes, close_minutes,
+		break_start_minutes, break_end_minutes, note, created_by, created_at, updated_at
+	FROM branch_calendar_exceptions
+`
+
+func scanBranchException(row pgx.Row) (*model.BranchCalendarException, error) {
+	var (
+		e             model.BranchCalendarException
+		day           time.Time
+		open, closeAt *int
+		h             model.BranchDayHours
+	)
+	if err := row.Scan(
+		&e.ExceptionID, &e.BranchID, &day, &e.IsClosed, &open, &closeAt,
+		&h.BreakStartMinutes, &h.BreakEndMinutes, &e.Note, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	e.Day = day.Format("2006-01-02")
+	if open != nil && closeAt != nil {
+		h.OpenMinutes, h.CloseMinutes = *open, *closeAt
+		e.Hours = &h
+	}
+	return &e, nil
+}
+
+// Exceptions returns the branch calendar exceptions for dates in [from, to).
+func (r *BranchScheduleRepository) Exceptions(ctx context.Context, branchID uuid.UUID, from, to time.Time) ([]model.BranchCalendarException, error) {
+	rows, err := r.db.Pool.Query(ctx, branchExceptionSelect+`
+		WHERE branch_id = $1 AND day >= $2::date AND day < $3::date
+		ORDER BY day
+	`, branchID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.BranchCalendarException{}
+	for rows.Next() {
+		e, err := scanBranchException(rows)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, *e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// UpsertException creates the exception for in.Day or replaces the existing one.
+func (r *BranchScheduleRepository) UpsertException(ctx context.Context, branchID uuid.UUID, in model.BranchCalendarExceptionUpsert, createdBy uuid.UUID) (*model.BranchCalendarException, error) {
+	var open, closeAt, breakStart, breakEnd *int
+	if in.Hours != nil {
+		open, closeAt = &in.Hours.OpenMinutes, &in.Hours.CloseMinutes
+		breakStart, breakEnd = in.Hours.BreakStartMinutes, in.Hours.BreakEndMinutes
+	}
+	e, err := scanBranchException(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO branch_calendar_exceptions
+			(branch_id, day, is_closed, open_minutes, close_minutes, break_start_minutes, break_end_minutes, note, created_by)
+		VALUES ($1, $2::date, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (branch_id, day) DO UPDATE SET
+			is_closed = EXCLUDED.is_closed,
+			open_minutes = EXCLUDED.open_minutes,
+			close_minutes = EXCLUDED.close_minutes,
+			break_start_minutes = EXCLUDED.break_start_minutes,
+			break_end_minutes = EXCLUDED.break_end_minutes,
+			note = EXCLUDED.note,
+			created_by = EXCLUDED.created_by
+		RETURNING exception_id, branch_id, day, is_closed, open_minutes, close_minutes,
+			break_start_minutes, break_end_minutes, note, created_by, created_at, updated_at
+	`, branchID, in.Day, in.IsClosed, open, closeAt, breakStart, breakEnd, in.Note, createdBy))
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return e, nil
+}
+
+// DeleteException removes a calendar exception of the branch.
+func (r *BranchScheduleRepository) DeleteException(ctx context.Context, branchID, exceptionID uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM branch_calendar_exceptions WHERE exception_id = $1 AND branch_id = $2
+	`, exceptionID, branchID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Calendar exception not found")
+	}
+	return nil
+}
+
+// scanBusyIntervals reads (start, end) rows of bookings that occupy branch capacity, followed by
+// (bay_id, technician_id) when withResources is set.
+func scanBusyIntervals(rows pgx.Rows, withResources bool) ([]model.BusyInterval, error) {
+	var out []model.BusyInterval
+	for rows.Next() {
+		var b model.BusyInterval
+		dest := []any{&b.Start, &b.End}
+		if withResources {
+			dest = append(dest, &b.BayID, &b.TechnicianID)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
@@ -318,6 +318,25 @@ func (r *TrimRepository) GetByID(ctx context.Context, trimID uuid.UUID) (*model.
 	return &trim, nil
 }
 
+// GetPricingScope returns the brand/model/generation position and base price of a trim.
+func (r *TrimRepository) GetPricingScope(ctx context.Context, trimID uuid.UUID) (*model.PricingScope, error) {
+	var scope model.PricingScope
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT t.trim_id, g.generation_id, m.model_id, m.brand_id, m.segment, t.base_price
+		FROM trims t
+		JOIN generations g ON t.generation_id = g.generation_id
+		JOIN models m ON g.model_id = m.model_id
+		WHERE t.trim_id = $1
+	`, trimID).Scan(&scope.TrimID, &scope.GenerationID, &scope.ModelID, &scope.BrandID, &scope.Segment, &scope.BasePrice)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &scope, nil
+}
+
 func (r *TrimRepository) GetWithFilters(ctx context.Context, filters model.TrimFilters) ([]model.TrimWithDetails, error) {
 	var conditions []string
 	var args []interface{}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConfigurationShareRepository struct {
+	db *database.DB
+}
+
+func NewConfigurationShareRepository(db *database.DB) *ConfigurationShareRepository {
+	return &ConfigurationShareRepository{db: db}
+}
+
+const configurationShareColumns = `
+	share_id, configuration_id, token, created_by, expires_at, revoked_at,
+	view_count, last_viewed_at, created_at
+`
+
+func scanConfigurationShare(row pgx.Row, s *model.ConfigurationShare) error {
+	return row.Scan(
+		&s.ShareID, &s.ConfigurationID, &s.Token, &s.CreatedBy, &s.ExpiresAt, &s.RevokedAt,
+		&s.ViewCount, &s.LastViewedAt, &s.CreatedAt,
+	)
+}
+
+func (r *ConfigurationShareRepository) Create(ctx context.Context, configID, createdBy uuid.UUID, token string, expiresAt time.Time) (*model.ConfigurationShare, error) {
+	var s model.ConfigurationShare
+	err := scanConfigurationShare(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO configuration_shares (configuration_id, token, created_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+configurationShareColumns,
+		configID, token, createdBy, expiresAt,
+	), &s)
+	if err != nil {
+		if conflict := mapUniqueViolation(err, "Share token collision, please retry"); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &s, nil
+}
+
+func (r *ConfigurationShareRepository) ListByConfiguration(ctx context.Context, configID uuid.UUID) ([]model.ConfigurationShare, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+configurationShareColumns+`
+		FROM configuration_shares
+		WHERE configuration_id = $1
+		ORDER BY created_at DESC
+	`, configID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	var out []model.ConfigurationShare
+	for rows.Next() {
+		var s model.ConfigurationShare
+		if err := scanConfigurationShare(rows, &s); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// Revoke disables a link of the given configuration; revoking twice is a no-op.
+func (r *ConfigurationShareRepository) Revoke(ctx context.Context, configID, shareID uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE configuration_shares SET revoked_at = COALESCE(revoked_at, now())
+		WHERE share_id = $1 AND configuration_id = $2
+	`, shareID, configID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Share link not found")
+	}
+	return nil
+}
+
+// ResolveActive returns a non-revoked, unexpired share by token and records the view.
+func (r *ConfigurationShareRepository) ResolveActive(ctx context.Context, token string, countView bool) (*model.ConfigurationShare, error) {
+	var s model.ConfigurationShare
+	var err error
+	if countView {
+		err = scanConfigurationShare(r.db.Pool.QueryRow(ctx, `
+			UPDATE configuration_shares
+			SET view_count = view_count + 1, last_viewed_at = now()
+			WHERE token = $1 AND revoked_at IS NULL AND expires_at > now()
+			RETURNING `+configurationShareColumns,
+			token,
+		), &s)
+	} else {
+		err = scanConfigurationShare(r.db.Pool.QueryRow(ctx, `
+			SELECT `+configurationShareColumns+`
+			FROM configuration_shares
+			WHERE token = $1 AND revoked_at IS NULL AND expires_at > now()
+		`, token), &s)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &s, nil
+}
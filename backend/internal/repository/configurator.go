@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/carkeeper/backend/database"
 	"github.com/carkeeper/backend/internal/apperr"
@@ -135,7 +136,7 @@ func NewConfigurationRepository(db *database.DB) *ConfigurationRepository {
 	return &ConfigurationRepository{db: db}
 }
 
-func (r *ConfigurationRepository) Create(ctx context.Context, userID uuid.UUID, create model.ConfigurationCreate, totalPrice float64) (*model.Configuration, error) {
+func (r *ConfigurationRepository) Create(ctx context.Context, userID uuid.UUID, create model.ConfigurationCreate, totalPrice float64, expiresAt time.Time) (*model.Configuration, error) {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -144,14 +145,17 @@ func (r *ConfigurationRepository) Create(ctx context.Context, userID uuid.UUID,
 
 	var config model.Configuration
 	query := `
-		INSERT INTO configurations (user_id, trim_id, color_id, status, total_price)
-		VALUES ($1, $2, $3, 'draft', $4)
-		RETURNING configuration_id, user_id, trim_id, color_id, status, total_price, created_at, updated_at
+		INSERT INTO configurations (user_id, trim_id, color_id, status, total_price, expires_at)
+		VALUES ($1, $2, $3, 'draft', $4, $5)
+		RETURNING configuration_id, user_id, trim_id, color_id, status, total_price,
+			expires_at, price_locked_until, locked_trim_price, locked_color_price, created_at, updated_at
 	`
 
-	err = tx.QueryRow(ctx, query, userID, create.TrimID, create.ColorID, totalPrice).Scan(
+	err = tx.QueryRow(ctx, query, userID, create.TrimID, create.ColorID, totalPrice, expiresAt).Scan(
 		&config.ConfigurationID, &config.UserID, &config.TrimID, &config.ColorID,
-		&config.Status, &config.TotalPrice, &config.CreatedAt, &config.UpdatedAt,
+		&config.Status, &config.TotalPrice,
+		&config.ExpiresAt, &config.PriceLockedUntil, &config.LockedTrimPrice, &config.LockedColorPrice,
+		&config.CreatedAt, &config.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create configuration: %w", err)
@@ -181,6 +185,7 @@ func (r *ConfigurationRepository) GetByID(ctx context.Context, configID uuid.UUI
 	query := `
 		SELECT 
 			c.configuration_id, c.user_id, c.trim_id, c.color_id, c.status, c.total_price,
+			c.expires_at, c.price_locked_until, c.locked_trim_price, c.locked_color_price,
 			c.created_at, c.updated_at,
 			t.name as trim_name, col.name as color_name, col.hex_code as color_hex
 		FROM configurations c
@@ -191,7 +196,9 @@ func (r *ConfigurationRepository) GetByID(ctx context.Context, configID uuid.UUI
 
 	err := r.db.Pool.QueryRow(ctx, query, configID).Scan(
 		&config.ConfigurationID, &config.UserID, &config.TrimID, &config.ColorID,
-		&config.Status, &config.TotalPrice, &config.CreatedAt, &config.UpdatedAt,
+		&config.Status, &config.TotalPrice,
+		&config.ExpiresAt, &config.PriceLockedUntil, &config.LockedTrimPrice, &config.LockedColorPrice,
+		&config.CreatedAt, &config.UpdatedAt,
 		&config.TrimName, &config.ColorName, &config.ColorHex,
 	)
 	if err != nil {
@@ -203,9 +210,10 @@ func (r *ConfigurationRepository) GetByID(ctx context.Context, configID uuid.UUI
 
 	// Get options
 	optQuery := `
-		SELECT o.option_id, o.name, o.description, o.price, o.is_available, o.created_at
+		SELECT o.option_id, o.name, o.description, ` + configurationOptionPriceSQL + `, o.is_available, o.created_at
 		FROM options o
 		JOIN configuration_options co ON o.option_id = co.option_id
+		JOIN configurations c ON c.configuration_id = co.configuration_id
 		WHERE co.configuration_id = $1
 	`
 	optRows, err := r.db.Pool.Query(ctx, optQuery, configID)
@@ -229,6 +237,7 @@ func (r *ConfigurationRepository) GetByUserID(ctx context.Context, userID uuid.U
 	query := `
 		SELECT 
 			c.configuration_id, c.user_id, c.trim_id, c.color_id, c.status, c.total_price,
+			c.expires_at, c.price_locked_until, c.locked_trim_price, c.locked_color_price,
 			c.created_at, c.updated_at,
 			t.name as trim_name, col.name as color_name, col.hex_code as color_hex
 		FROM configurations c
@@ -249,7 +258,9 @@ func (r *ConfigurationRepository) GetByUserID(ctx context.Context, userID uuid.U
 		var config model.ConfigurationWithDetails
 		if err := rows.Scan(
 			&config.ConfigurationID, &config.UserID, &config.TrimID, &config.ColorID,
-			&config.Status, &config.TotalPrice, &config.CreatedAt, &config.UpdatedAt,
+			&config.Status, &config.TotalPrice,
+			&config.ExpiresAt, &config.PriceLockedUntil, &config.LockedTrimPrice, &config.LockedColorPrice,
+			&config.CreatedAt, &config.UpdatedAt,
 			&config.TrimName, &config.ColorName, &config.ColorHex,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan configuration: %w", err)
@@ -257,9 +268,10 @@ func (r *ConfigurationRepository) GetByUserID(ctx context.Context, userID uuid.U
 
 		// Get options for each configuration
 		optQuery := `
-			SELECT o.option_id, o.name, o.description, o.price, o.is_available, o.created_at
+			SELECT o.option_id, o.name, o.description, ` + configurationOptionPriceSQL + `, o.is_available, o.created_at
 			FROM options o
 			JOIN configuration_options co ON o.option_id = co.option_id
+			JOIN configurations c ON c.configuration_id = co.configuration_id
 			WHERE co.configuration_id = $1
 		`
 		optRows, err := r.db.Pool.Query(ctx, optQuery, config.ConfigurationID)
@@ -291,7 +303,7 @@ func (r *ConfigurationRepository) UpdateStatus(ctx context.Context, configID uui
 	return nil
 }
 
-func (r *ConfigurationRepository) Update(ctx context.Context, configID uuid.UUID, update model.ConfigurationCreate, totalPrice float64) (*model.Configuration, error) {
+func (r *ConfigurationRepository) Update(ctx context.Context, configID uuid.UUID, update model.ConfigurationCreate, totalPrice float64, expiresAt time.Time) (*model.Configuration, error) {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -301,14 +313,18 @@ func (r *ConfigurationRepository) Update(ctx context.Context, configID uuid.UUID
 	// Update configuration
 	query := `
 		UPDATE configurations 
-		SET trim_id = $1, color_id = $2, total_price = $3, updated_at = NOW()
+		SET trim_id = $1, color_id = $2, total_price = $3, expires_at = $5, expiry_warned_at = NULL,
+			price_locked_until = NULL, locked_trim_price = NULL, locked_color_price = NULL, updated_at = NOW()
 		WHERE configuration_id = $4
-		RETURNING configuration_id, user_id, trim_id, color_id, status, total_price, created_at, updated_at
+		RETURNING configuration_id, user_id, trim_id, color_id, status, total_price,
+			expires_at, price_locked_until, locked_trim_price, locked_color_price, created_at, updated_at
 	`
 	var config model.Configuration
-	err = tx.QueryRow(ctx, query, update.TrimID, update.ColorID, totalPrice, configID).Scan(
+	err = tx.QueryRow(ctx, query, update.TrimID, update.ColorID, totalPrice, configID, expiresAt).Scan(
 		&config.ConfigurationID, &config.UserID, &config.TrimID, &config.ColorID,
-		&config.Status, &config.TotalPrice, &config.CreatedAt, &config.UpdatedAt,
+		&config.Status, &config.TotalPrice,
+		&config.ExpiresAt, &config.PriceLockedUntil, &config.LockedTrimPrice, &config.LockedColorPrice,
+		&config.CreatedAt, &config.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update configuration: %w", err)
@@ -348,3 +364,164 @@ func (r *ConfigurationRepository) Delete(ctx context.Context, configID uuid.UUID
 	return nil
 }
 
+
+// configurationOptionPriceSQL quotes the option price captured on confirmation while the
+// configuration's price lock is active, and the current catalog price otherwise.
+const configurationOptionPriceSQL = `CASE WHEN c.status = 'confirmed' AND c.price_locked_until > now() AND co.locked_price IS NOT NULL
+		THEN co.locked_price ELSE o.price END`
+
+// Confirm moves a draft to confirmed and captures current catalog prices until lockedUntil.
+func (r *ConfigurationRepository) Confirm(ctx context.Context, configID uuid.UUID, lockedUntil time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE configurations c
+		SET status = 'confirmed',
+			price_locked_until = $2,
+			locked_trim_price = t.base_price,
+			locked_color_price = col.price_delta,
+			total_price = t.base_price + col.price_delta + COALESCE((
+				SELECT SUM(o.price)
+				FROM configuration_options co
+				JOIN options o ON o.option_id = co.option_id
+				WHERE co.configuration_id = c.configuration_id
+			), 0),
+			expires_at = NULL,
+			expiry_warned_at = NULL
+		FROM trims t, colors col
+		WHERE c.configuration_id = $1 AND t.trim_id = c.trim_id AND col.color_id = c.color_id
+	`, configID, lockedUntil)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Configuration not found")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE configuration_options co
+		SET locked_price = o.price
+		FROM options o
+		WHERE co.configuration_id = $1 AND o.option_id = co.option_id
+	`, configID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// Reopen returns a configuration to draft, dropping any price lock and restarting the draft clock.
+func (r *ConfigurationRepository) Reopen(ctx context.Context, configID uuid.UUID, expiresAt time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE configurations
+		SET status = 'draft', expires_at = $2, expiry_warned_at = NULL,
+			price_locked_until = NULL, locked_trim_price = NULL, locked_color_price = NULL
+		WHERE configuration_id = $1
+	`, configID, expiresAt)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Configuration not found")
+	}
+	if _, err := tx.Exec(ctx, `UPDATE configuration_options SET locked_price = NULL WHERE configuration_id = $1`, configID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// configurationDraftExpirySQL is a draft's effective expiry; rows created before expires_at
+// existed fall back to their last update plus the configured draft lifetime ($1 days).
+const configurationDraftExpirySQL = `COALESCE(expires_at, updated_at + make_interval(days => $1::int))`
+
+// ExpireDrafts marks drafts past their expiry as expired and notifies the owners.
+func (r *ConfigurationRepository) ExpireDrafts(ctx context.Context, draftDays int, now time.Time, title, body string) (int, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH expired AS (
+			UPDATE configurations
+			SET status = 'expired', expires_at = `+configurationDraftExpirySQL+`
+			WHERE status = 'draft' AND `+configurationDraftExpirySQL+` <= $2
+			RETURNING configuration_id, user_id
+		), sent AS (
+			INSERT INTO notifications (user_id, kind, title, body, entity_type, entity_id)
+			SELECT user_id, 'configuration_draft_expired', $3, $4, 'configuration', configuration_id
+			FROM expired
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM sent
+	`, draftDays, now, title, body).Scan(&n)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return n, nil
+}
+
+// WarnExpiringDrafts notifies owners of drafts expiring before warnBefore, once per draft.
+// body is a format() template receiving the expiry date.
+func (r *ConfigurationRepository) WarnExpiringDrafts(ctx context.Context, draftDays int, warnBefore time.Time, title, body string) (int, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH due AS (
+			UPDATE configurations
+			SET expires_at = `+configurationDraftExpirySQL+`, expiry_warned_at = now()
+			WHERE status = 'draft' AND expiry_warned_at IS NULL AND `+configurationDraftExpirySQL+` <= $2
+			RETURNING configuration_id, user_id, expires_at
+		), sent AS (
+			INSERT INTO notifications (user_id, kind, title, body, entity_type, entity_id)
+			SELECT user_id, 'configuration_draft_expiring', $3, format($4::text, to_char(expires_at, 'DD.MM.YYYY')),
+				'configuration', configuration_id
+			FROM due
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM sent
+	`, draftDays, warnBefore, title, body).Scan(&n)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return n, nil
+}
+
+// WarnExpiringPriceLocks notifies owners of confirmed configurations whose price lock ends
+// between now and warnBefore, once per lock.
+func (r *ConfigurationRepository) WarnExpiringPriceLocks(ctx context.Context, now, warnBefore time.Time, title, body string) (int, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH due AS (
+			UPDATE configurations
+			SET expiry_warned_at = now()
+			WHERE status = 'confirmed' AND expiry_warned_at IS NULL
+				AND price_locked_until > $1 AND price_locked_until <= $2
+			RETURNING configuration_id, user_id, price_locked_until
+		), sent AS (
+			INSERT INTO notifications (user_id, kind, title, body, entity_type, entity_id)
+			SELECT user_id, 'configuration_price_lock_expiring', $3, format($4::text, to_char(price_locked_until, 'DD.MM.YYYY')),
+				'configuration', configuration_id
+			FROM due
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM sent
+	`, now, warnBefore, title, body).Scan(&n)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return n, nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DeliveryRepository struct {
+	db *database.DB
+}
+
+func NewDeliveryRepository(db *database.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+const deliverySelect = `
+	SELECT
+		d.delivery_id, d.order_id, d.branch_id, d.scheduled_at, d.duration_minutes, d.status,
+		d.notes, d.created_by, d.created_at, d.updated_at,
+		b.name, b.address, b.timezone, o.status, o.user_id,
+		TRIM(CONCAT_WS(' ', u.first_name, u.last_name)), u.phone,
+		t.name, col.name
+	FROM delivery_appointments d
+	JOIN branches b ON b.branch_id = d.branch_id
+	JOIN orders o ON o.order_id = d.order_id
+	JOIN users u ON u.user_id = o.user_id
+	JOIN configurations c ON c.configuration_id = o.configuration_id
+	JOIN trims t ON t.trim_id = c.trim_id
+	JOIN colors col ON col.color_id = c.color_id
+`
+
+func scanDelivery(row pgx.Row) (*model.DeliveryAppointment, error) {
+	var d model.DeliveryAppointment
+	err := row.Scan(
+		&d.DeliveryID, &d.OrderID, &d.BranchID, &d.ScheduledAt, &d.DurationMinutes, &d.Status,
+		&d.Notes, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt,
+		&d.BranchName, &d.BranchAddress, &d.Timezone, &d.OrderStatus, &d.CustomerID,
+		&d.CustomerName, &d.CustomerPhone,
+		&d.TrimName, &d.ColorName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *DeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.DeliveryAppointment, error) {
+	d, err := scanDelivery(r.db.Pool.QueryRow(ctx, deliverySelect+` WHERE d.delivery_id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return d, nil
+}
+
+// GetLatestByOrder returns the order's scheduled delivery, or else its most recent one.
+func (r *DeliveryRepository) GetLatestByOrder(ctx context.Context, orderID uuid.UUID) (*model.DeliveryAppointment, error) {
+	d, err := scanDelivery(r.db.Pool.QueryRow(ctx, deliverySelect+`
+		WHERE d.order_id = $1
+		ORDER BY (d.status = 'scheduled') DESC, d.created_at DESC
+		LIMIT 1
+	`, orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return d, nil
+}
+
+// ListCalendar returns scheduled and completed deliveries starting in [from, to), optionally for one branch.
+func (r *DeliveryRepository) ListCalendar(ctx context.Context, branchID *uuid.UUID, from, to time.Time) ([]model.DeliveryAppointment, error) {
+	rows, err := r.db.Pool.Query(ctx, deliverySelect+`
+		WHERE d.status <> 'cancelled'
+		  AND d.scheduled_at >= $1 AND d.scheduled_at < $2
+		  AND ($3::uuid IS NULL OR d.branch_id = $3)
+		ORDER BY d.scheduled_at, b.name
+	`, from, to, branchID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.DeliveryAppointment{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// ScheduledIntervals returns the [start, end) intervals of scheduled deliveries at the branch that
+// overlap [from, to).
+func (r *DeliveryRepository) ScheduledIntervals(ctx context.Context, branchID uuid.UUID, from, to time.Time) ([]model.BusyInterval, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT scheduled_at, scheduled_at + make_interval(mins => duration_minutes)
+		FROM delivery_appointments
+		WHERE branch_id = $1
+		  AND status = 'scheduled'
+		  AND scheduled_at < $3
+		  AND (scheduled_at + make_interval(mins => duration_minutes)) > $2
+		ORDER BY scheduled_at
+	`, branchID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled deliveries: %w", err)
+	}
+	defer rows.Close()
+	return scanBusyIntervals(rows, false)
+}
+
+// Schedule books a delivery slot for the order, replacing its current scheduled delivery. The branch
+// is locked so that concurrent bookings cannot exceed handoverBays.
+func (r *DeliveryRepository) Schedule(ctx context.Context, orderID uuid.UUID, create model.DeliveryScheduleCreate, handoverBays int, createdBy uuid.UUID) (uuid.UUID, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('delivery:' || $1::text))`, create.BranchID.String()); err != nil {
+		return uuid.Nil, fmt.Errorf("lock branch: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE delivery_appointments SET status = 'cancelled'
+		WHERE order_id = $1 AND status = 'scheduled'
+	`, orderID); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+
+	winEnd := create.ScheduledAt.Add(time.Duration(create.DurationMinutes) * time.Minute)
+	var overlap int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*)::int FROM delivery_appointments
+		WHERE branch_id = $1
+		  AND status = 'scheduled'
+		  AND scheduled_at < $3
+		  AND (scheduled_at + (duration_minutes || ' minutes')::interval) > $2
+	`, create.BranchID, create.ScheduledAt, winEnd).Scan(&overlap)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("overlap check: %w", err)
+	}
+	if overlap >= handoverBays {
+		return uuid.Nil, apperr.Conflict("This delivery slot is no longer available")
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO delivery_appointments (order_id, branch_id, scheduled_at, duration_minutes, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING delivery_id
+	`, orderID, create.BranchID, create.ScheduledAt, create.DurationMinutes, create.Notes, createdBy).Scan(&id)
+	if err != nil {
+		if conflict := mapUniqueViolation(err, "A delivery for this order is already being scheduled"); conflict != nil {
+			return uuid.Nil, conflict
+		}
+		return uuid.Nil, apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	return id, nil
+}
+
+// CancelScheduled cancels the order's scheduled delivery.
+func (r *DeliveryRepository) CancelScheduled(ctx context.Context, orderID uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE delivery_appointments SET status = 'cancelled'
+		WHERE order_id = $1 AND status = 'scheduled'
+	`, orderID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("No scheduled delivery for this order")
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ const documentSelectWithContext = `
 		d.user_id,
 		d.order_id,
 		d.service_appointment_id,
+		d.configuration_id,
 		d.document_type,
 		d.file_path,
 		d.file_name,
@@ -33,6 +34,7 @@ const documentSelectWithContext = `
 		CASE
 			WHEN d.order_id IS NOT NULL THEN 'order'
 			WHEN d.service_appointment_id IS NOT NULL THEN 'service_appointment'
+			WHEN d.configuration_id IS NOT NULL THEN 'configuration'
 		END AS attachment_kind,
 		CASE
 			WHEN d.order_id IS NOT NULL THEN
@@ -47,6 +49,8 @@ const documentSelectWithContext = `
 					COALESCE(' · ' || b.name, ''),
 					COALESCE(' · VIN ' || uc.vin, '')
 				)
+			WHEN d.configuration_id IS NOT NULL THEN
+				CONCAT('Конфигурация #', LEFT(d.configuration_id::text, 8))
 		END AS attachment_label
 	FROM documents d
 	JOIN users u ON u.user_id = d.user_id
@@ -61,15 +65,24 @@ func NewDocumentRepository(db *database.DB) *DocumentRepository {
 	return &DocumentRepository{db: db}
 }
 
+// Insert registers a document; one attached to a completed appointment is also kept in the
+// VIN's service book.
 func (r *DocumentRepository) Insert(ctx context.Context, d model.Document) error {
 	query := `
-		INSERT INTO documents (
-			document_id, user_id, order_id, service_appointment_id,
-			document_type, file_path, file_name, file_size, mime_type
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		WITH doc AS (
+			INSERT INTO documents (
+				document_id, user_id, order_id, service_appointment_id, configuration_id,
+				document_type, file_path, file_name, file_size, mime_type
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING document_id, service_appointment_id, document_type, file_path, file_name, file_size, mime_type, created_at
+		)
+		INSERT INTO vehicle_service_documents (record_id, document_id, document_type, file_path, file_name, file_size, mime_type, created_at)
+		SELECT r.record_id, doc.document_id, doc.document_type, doc.file_path, doc.file_name, doc.file_size, doc.mime_type, doc.created_at
+		FROM doc
+		JOIN vehicle_service_records r ON r.service_appointment_id = doc.service_appointment_id
 	`
 	_, err := r.db.Pool.Exec(ctx, query,
-		d.DocumentID, d.UserID, d.OrderID, d.ServiceAppointmentID,
+		d.DocumentID, d.UserID, d.OrderID, d.ServiceAppointmentID, d.ConfigurationID,
 		d.DocumentType, d.FilePath, d.FileName, d.FileSize, d.MimeType,
 	)
 	if err != nil {
@@ -82,7 +95,7 @@ func (r *DocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.
 	var d model.Document
 	query := documentSelectWithContext + ` WHERE d.document_id = $1`
 	err := r.db.Pool.QueryRow(ctx, query, id).Scan(
-		&d.DocumentID, &d.UserID, &d.OrderID, &d.ServiceAppointmentID,
+		&d.DocumentID, &d.UserID, &d.OrderID, &d.ServiceAppointmentID, &d.ConfigurationID,
 		&d.DocumentType, &d.FilePath, &d.FileName, &d.FileSize, &d.MimeType, &d.CreatedAt,
 		&d.OwnerName, &d.OwnerEmail, &d.AttachmentKind, &d.AttachmentLabel,
 	)
@@ -135,12 +148,35 @@ func (r *DocumentRepository) ListByServiceAppointmentID(ctx context.Context, app
 	return scanDocuments(rows)
 }
 
+func (r *DocumentRepository) ListByConfigurationID(ctx context.Context, configID uuid.UUID) ([]model.Document, error) {
+	query := documentSelectWithContext + ` WHERE d.configuration_id = $1 ORDER BY d.created_at DESC`
+	rows, err := r.db.Pool.Query(ctx, query, configID)
+	if err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+	defer rows.Close()
+	return scanDocuments(rows)
+}
+
+// AttachConfigurationToOrder links documents issued for a configuration (e.g. its commercial offer)
+// to the order placed from it and returns how many were linked.
+func (r *DocumentRepository) AttachConfigurationToOrder(ctx context.Context, configID, orderID uuid.UUID) (int, error) {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE documents SET order_id = $2
+		WHERE configuration_id = $1 AND order_id IS NULL
+	`, configID, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("attach configuration documents: %w", err)
+	}
+	return int(cmd.RowsAffected()), nil
+}
+
 func scanDocuments(rows pgx.Rows) ([]model.Document, error) {
 	var list []model.Document
 	for rows.Next() {
 		var d model.Document
 		if err := rows.Scan(
-			&d.DocumentID, &d.UserID, &d.OrderID, &d.ServiceAppointmentID,
+			&d.DocumentID, &d.UserID, &d.OrderID, &d.ServiceAppointmentID, &d.ConfigurationID,
 			&d.DocumentType, &d.FilePath, &d.FileName, &d.FileSize, &d.MimeType, &d.CreatedAt,
 			&d.OwnerName, &d.OwnerEmail, &d.AttachmentKind, &d.AttachmentLabel,
 		); err != nil {
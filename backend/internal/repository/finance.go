@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type FinanceProductRepository struct {
+	db *database.DB
+}
+
+func NewFinanceProductRepository(db *database.DB) *FinanceProductRepository {
+	return &FinanceProductRepository{db: db}
+}
+
+const financeProductColumns = `
+	finance_product_id, name, provider, product_type, description, annual_rate,
+	min_term_months, max_term_months, min_down_payment_percent, max_down_payment_percent,
+	balloon_percent, min_amount, max_amount, is_active, created_at, updated_at
+`
+
+func scanFinanceProduct(row pgx.Row, p *model.FinanceProduct) error {
+	return row.Scan(
+		&p.FinanceProductID, &p.Name, &p.Provider, &p.ProductType, &p.Description, &p.AnnualRate,
+		&p.MinTermMonths, &p.MaxTermMonths, &p.MinDownPaymentPercent, &p.MaxDownPaymentPercent,
+		&p.BalloonPercent, &p.MinAmount, &p.MaxAmount, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+// List returns finance products; activeOnly limits the result to programs offered to customers.
+func (r *FinanceProductRepository) List(ctx context.Context, activeOnly bool) ([]model.FinanceProduct, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+financeProductColumns+`
+		FROM finance_products
+		WHERE ($1 = false OR is_active = true)
+		ORDER BY annual_rate, name
+	`, activeOnly)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	var out []model.FinanceProduct
+	for rows.Next() {
+		var p model.FinanceProduct
+		if err := scanFinanceProduct(rows, &p); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *FinanceProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.FinanceProduct, error) {
+	var p model.FinanceProduct
+	err := scanFinanceProduct(r.db.Pool.QueryRow(ctx, `SELECT `+financeProductColumns+` FROM finance_products WHERE finance_product_id = $1`, id), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Finance product not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+func (r *FinanceProductRepository) Create(ctx context.Context, in model.FinanceProductInput) (*model.FinanceProduct, error) {
+	var p model.FinanceProduct
+	err := scanFinanceProduct(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO finance_products (
+			name, provider, product_type, description, annual_rate,
+			min_term_months, max_term_months, min_down_payment_percent, max_down_payment_percent,
+			balloon_percent, min_amount, max_amount, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING `+financeProductColumns,
+		in.Name, in.Provider, in.ProductType, in.Description, in.AnnualRate,
+		in.MinTermMonths, in.MaxTermMonths, in.MinDownPaymentPercent, in.MaxDownPaymentPercent,
+		in.BalloonPercent, in.MinAmount, in.MaxAmount, in.IsActive,
+	), &p)
+	if err != nil {
+		return nil, mapFinanceProductWriteError(err)
+	}
+	return &p, nil
+}
+
+func (r *FinanceProductRepository) Update(ctx context.Context, id uuid.UUID, in model.FinanceProductInput) (*model.FinanceProduct, error) {
+	var p model.FinanceProduct
+	err := scanFinanceProduct(r.db.Pool.QueryRow(ctx, `
+		UPDATE finance_products SET
+			name = $2, provider = $3, product_type = $4, description = $5, annual_rate = $6,
+			min_term_months = $7, max_term_months = $8,
+			min_down_payment_percent = $9, max_down_payment_percent = $10,
+			balloon_percent = $11, min_amount = $12, max_amount = $13, is_active = $14
+		WHERE finance_product_id = $1
+		RETURNING `+financeProductColumns,
+		id, in.Name, in.Provider, in.ProductType, in.Description, in.AnnualRate,
+		in.MinTermMonths, in.MaxTermMonths,
+		in.MinDownPaymentPercent, in.MaxDownPaymentPercent,
+		in.BalloonPercent, in.MinAmount, in.MaxAmount, in.IsActive,
+	), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Finance product not found")
+		}
+		return nil, mapFinanceProductWriteError(err)
+	}
+	return &p, nil
+}
+
+// Delete removes a finance product; order snapshots keep their copied terms.
+func (r *FinanceProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM finance_products WHERE finance_product_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Finance product not found")
+	}
+	return nil
+}
+
+func mapFinanceProductWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+		return apperr.BadRequest("Invalid finance product parameters")
+	}
+	return apperr.Internal(err)
+}
+
+// GetOrderPlan returns the finance plan stored with an order, or nil for cash orders.
+func (r *FinanceProductRepository) GetOrderPlan(ctx context.Context, orderID uuid.UUID) (*model.OrderFinancePlan, error) {
+	var p model.OrderFinancePlan
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT finance_product_id, product_name, provider, product_type, schedule_type,
+			annual_rate, term_months, vehicle_price, down_payment, principal, balloon_payment,
+			first_payment, last_payment, total_payment, overpayment
+		FROM order_finance_plans
+		WHERE order_id = $1
+	`, orderID).Scan(
+		&p.FinanceProductID, &p.ProductName, &p.Provider, &p.ProductType, &p.ScheduleType,
+		&p.AnnualRate, &p.TermMonths, &p.VehiclePrice, &p.DownPayment, &p.Principal, &p.BalloonPayment,
+		&p.FirstPayment, &p.LastPayment, &p.TotalPayment, &p.Overpayment,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+func insertOrderFinancePlanTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, p *model.OrderFinancePlan) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO order_finance_plans (
+			order_id, finance_product_id, product_name, provider, product_type, schedule_type,
+			annual_rate, term_months, vehicle_price, down_payment, principal, balloon_payment,
+			first_payment, last_payment, total_payment, overpayment
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`, orderID, p.FinanceProductID, p.ProductName, p.Provider, p.ProductType, p.ScheduleType,
+		p.AnnualRate, p.TermMonths, p.VehiclePrice, p.DownPayment, p.Principal, p.BalloonPayment,
+		p.FirstPayment, p.LastPayment, p.TotalPayment, p.Overpayment,
+	)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MaintenanceRepository stores maintenance plans and the service reminders raised from them.
+type MaintenanceRepository struct {
+	db *database.DB
+}
+
+func NewMaintenanceRepository(db *database.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+const maintenancePlanSelect = `
+	SELECT p.plan_id, p.model_id, p.generation_id, p.name, p.interval_km, p.interval_months, p.is_active,
+		ARRAY(SELECT service_type_id FROM maintenance_plan_service_types WHERE plan_id = p.plan_id ORDER BY service_type_id),
+		p.created_at, p.updated_at
+	FROM maintenance_plans p
+`
+
+func maintenancePlanDest(p *model.MaintenancePlan) []any {
+	return []any{
+		&p.PlanID, &p.ModelID, &p.GenerationID, &p.Name, &p.IntervalKm, &p.IntervalMonths, &p.IsActive,
+		&p.ServiceTypeIDs, &p.CreatedAt, &p.UpdatedAt,
+	}
+}
+
+// ListPlans returns plans, optionally of one model (its own and its generations') or one generation.
+func (r *MaintenanceRepository) ListPlans(ctx context.Context, modelID, generationID *uuid.UUID) ([]model.MaintenancePlan, error) {
+	rows, err := r.db.Pool.Query(ctx, maintenancePlanSelect+`
+		LEFT JOIN generations g ON g.generation_id = p.generation_id
+		WHERE ($1::uuid IS NULL OR p.model_id = $1 OR g.model_id = $1)
+			AND ($2::uuid IS NULL OR p.generation_id = $2)
+		ORDER BY p.name, p.created_at
+	`, modelID, generationID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.MaintenancePlan{}
+	for rows.Next() {
+		var p model.MaintenancePlan
+		if err := rows.Scan(maintenancePlanDest(&p)...); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+func (r *MaintenanceRepository) GetPlan(ctx context.Context, id uuid.UUID) (*model.MaintenancePlan, error) {
+	var p model.MaintenancePlan
+	if err := r.db.Pool.QueryRow(ctx, maintenancePlanSelect+` WHERE p.plan_id = $1`, id).Scan(maintenancePlanDest(&p)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Maintenance plan not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+// CreatePlan inserts a plan with its service types.
+func (r *MaintenanceRepository) CreatePlan(ctx context.Context, in model.MaintenancePlanInput) (*model.MaintenancePlan, error) {
+	active := true
+	if in.IsActive != nil {
+		active = *in.IsActive
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO maintenance_plans (model_id, generation_id, name, interval_km, interval_months, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING plan_id
+	`, in.ModelID, in.GenerationID, in.Name, in.IntervalKm, in.IntervalMonths, active).Scan(&id); err != nil {
+		return nil, mapMaintenancePlanWriteError(err)
+	}
+	if err := replacePlanServiceTypes(ctx, tx, id, in.ServiceTypeIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.GetPlan(ctx, id)
+}
+
+// UpdatePlan replaces the plan name, intervals and service types; the model or generation it
+// belongs to does not change. A nil IsActive keeps the flag.
+func (r *MaintenanceRepository) UpdatePlan(ctx context.Context, id uuid.UUID, in model.MaintenancePlanInput) (*model.MaintenancePlan, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE maintenance_plans
+		SET name = $2, interval_km = $3, interval_months = $4, is_active = COALESCE($5, is_active)
+		WHERE plan_id = $1
+	`, id, in.Name, in.IntervalKm, in.IntervalMonths, in.IsActive)
+	if err != nil {
+		return nil, mapMaintenancePlanWriteError(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return nil, apperr.NotFoundErr("Maintenance plan not found")
+	}
+	if err := replacePlanServiceTypes(ctx, tx, id, in.ServiceTypeIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.GetPlan(ctx, id)
+}
+
+// DeletePlan removes a plan together with its reminders.
+func (r *MaintenanceRepository) DeletePlan(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM maintenance_plans WHERE plan_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Maintenance plan not found")
+	}
+	return nil
+}
+
+func replacePlanServiceTypes(ctx context.Context, tx pgx.Tx, planID uuid.UUID, ids []uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM maintenance_plan_service_types WHERE plan_id = $1`, planID); err != nil {
+		return apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO maintenance_plan_service_types (plan_id, service_type_id)
+		SELECT $1, unnest($2::uuid[])
+	`, planID, ids); err != nil {
+		return mapMaintenancePlanWriteError(err)
+	}
+	return nil
+}
+
+func mapMaintenancePlanWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			return apperr.BadRequest("Unknown model, generation or service type")
+		case "23514":
+			return apperr.BadRequest("Invalid maintenance plan parameters")
+		}
+	}
+	return apperr.Internal(err)
+}
+
+// Candidates returns every car with the active plans that apply to it and the baseline of each:
+// the last completed appointment that included any of the plan's services, otherwise the
+// purchase (or registration) date. Mileage at purchase is taken as zero only for cars handed over
+// from a dealer order. With userCarID set only that car is returned; withoutReminder skips pairs
+// that already have a reminder for the same baseline.
+func (r *MaintenanceRepository) Candidates(ctx context.Context, userCarID *uuid.UUID, withoutReminder bool) ([]model.MaintenanceCandidate, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT uc.user_car_id, uc.user_id, b.name || ' ' || m.name, uc.current_mileage,
+			p.plan_id, p.model_id, p.generation_id, p.name, p.interval_km, p.interval_months, p.is_active,
+			ARRAY(SELECT service_type_id FROM maintenance_plan_service_types WHERE plan_id = p.plan_id ORDER BY service_type_id),
+			p.created_at, p.updated_at,
+			ARRAY(
+				SELECT pst.service_type_id
+				FROM maintenance_plan_service_types pst
+				JOIN service_types st ON st.service_type_id = pst.service_type_id
+				WHERE pst.plan_id = p.plan_id AND st.is_available
+				ORDER BY st.name
+			),
+			last.service_appointment_id, last.branch_id,
+			COALESCE(last.done_at, uc.purchase_date::timestamptz, uc.created_at),
+			CASE WHEN last.service_appointment_id IS NOT NULL THEN last.odometer_km
+				WHEN uc.order_id IS NOT NULL THEN 0 END
+		FROM user_cars uc
+		JOIN trims t ON t.trim_id = uc.trim_id
+		JOIN generations g ON g.generation_id = t.generation_id
+		JOIN models m ON m.model_id = g.model_id
+		JOIN brands b ON b.brand_id = m.brand_id
+		JOIN maintenance_plans p ON p.is_active AND (
+			p.generation_id = g.generation_id
+			OR (p.model_id = g.model_id AND NOT EXISTS (
+				SELECT 1 FROM maintenance_plans gp WHERE gp.generation_id = g.generation_id AND gp.is_active
+			))
+		)
+		LEFT JOIN LATERAL (
+			SELECT sa.service_appointment_id, sa.branch_id, sa.odometer_km,
+				COALESCE(sa.completed_at, sa.appointment_date) AS done_at
+			FROM service_appointments sa
+			WHERE sa.user_car_id = uc.user_car_id AND sa.status = 'completed'
+				AND EXISTS (
+					SELECT 1
+					FROM service_appointment_types sat
+					JOIN maintenance_plan_service_types pst ON pst.service_type_id = sat.service_type_id
+					WHERE sat.service_appointment_id = sa.service_appointment_id AND pst.plan_id = p.plan_id
+				)
+			ORDER BY done_at DESC
+			LIMIT 1
+		) last ON true
+		WHERE ($1::uuid IS NULL OR uc.user_car_id = $1)
+			AND (NOT $2 OR NOT EXISTS (
+				SELECT 1 FROM maintenance_reminders mr
+				WHERE mr.user_car_id = uc.user_car_id AND mr.plan_id = p.plan_id
+					AND mr.last_appointment_id IS NOT DISTINCT FROM last.service_appointment_id
+			))
+		ORDER BY uc.user_car_id, p.name
+	`, userCarID, withoutReminder)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.MaintenanceCandidate{}
+	for rows.Next() {
+		var c model.MaintenanceCandidate
+		dest := append([]any{&c.UserCarID, &c.UserID, &c.CarTitle, &c.CurrentMileage}, maintenancePlanDest(&c.Plan)...)
+		dest = append(dest, &c.BookableServiceTypeIDs,
+			&c.Baseline.AppointmentID, &c.Baseline.BranchID, &c.Baseline.Date, &c.Baseline.MileageKm)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// CreateReminder stores a reminder and notifies the car owner; it reports false when the car
+// already has a reminder for this plan and baseline.
+func (r *MaintenanceRepository) CreateReminder(ctx context.Context, in model.MaintenanceReminderCreate) (bool, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx, `
+		WITH created AS (
+			INSERT INTO maintenance_reminders (user_car_id, plan_id, last_appointment_id, due_date, due_mileage, booking)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (user_car_id, plan_id, last_appointment_id) DO NOTHING
+			RETURNING reminder_id
+		), sent AS (
+			INSERT INTO notifications (user_id, kind, title, body, entity_type, entity_id)
+			SELECT $7, 'maintenance_due', $8, $9, 'maintenance_reminder', reminder_id
+			FROM created
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM sent
+	`, in.UserCarID, in.PlanID, in.LastAppointmentID, in.DueDate, in.DueMileage, in.Booking,
+		in.UserID, in.Title, in.Body).Scan(&n)
+	if err != nil {
+		return false, apperr.Internal(err)
+	}
+	return n > 0, nil
+}
+
+const maintenanceReminderSelect = `
+	SELECT mr.reminder_id, mr.user_car_id, mr.plan_id, p.name, mr.last_appointment_id, mr.due_date,
+		mr.due_mileage, mr.booking, mr.status, mr.service_appointment_id, mr.created_at, mr.updated_at
+	FROM maintenance_reminders mr
+	JOIN maintenance_plans p ON p.plan_id = mr.plan_id
+	JOIN user_cars uc ON uc.user_car_id = mr.user_car_id
+`
+
+func scanMaintenanceReminder(row pgx.Row, m *model.MaintenanceReminder) error {
+	return row.Scan(
+		&m.ReminderID, &m.UserCarID, &m.PlanID, &m.PlanName, &m.LastAppointmentID, &m.DueDate,
+		&m.DueMileage, &m.Booking, &m.Status, &m.ServiceAppointmentID, &m.CreatedAt, &m.UpdatedAt,
+	)
+}
+
+// ListOpenReminders returns the open reminders for the user's cars, soonest due first.
+func (r *MaintenanceRepository) ListOpenReminders(ctx context.Context, userID uuid.UUID) ([]model.MaintenanceReminder, error) {
+	rows, err := r.db.Pool.Query(ctx, maintenanceReminderSelect+`
+		WHERE uc.user_id = $1 AND mr.status = 'open'
+		ORDER BY mr.due_date NULLS LAST, mr.created_at
+	`, userID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.MaintenanceReminder{}
+	for rows.Next() {
+		var m model.MaintenanceReminder
+		if err := scanMaintenanceReminder(rows, &m); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// DismissReminder hides an open reminder of one of the user's cars until the next service is due.
+func (r *MaintenanceRepository) DismissReminder(ctx context.Context, userID, reminderID uuid.UUID) (*model.MaintenanceReminder, error) {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE maintenance_reminders mr SET status = 'dismissed'
+		FROM user_cars uc
+		WHERE mr.reminder_id = $1 AND uc.user_car_id = mr.user_car_id AND uc.user_id = $2 AND mr.status = 'open'
+	`, reminderID, userID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	var m model.MaintenanceReminder
+	if err := scanMaintenanceReminder(r.db.Pool.QueryRow(ctx, maintenanceReminderSelect+`
+		WHERE mr.reminder_id = $1 AND uc.user_id = $2
+	`, reminderID, userID), &m); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 && m.Status == model.MaintenanceReminderDone {
+		return nil, apperr.BadRequest("the service has already been done")
+	}
+	return &m, nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+type MessageRepository struct {
+	db *database.DB
+}
+
+func NewMessageRepository(db *database.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+// messageThreadColumns maps a thread entity to its column in messages and documents.
+var messageThreadColumns = map[string]string{
+	"order":       "order_id",
+	"appointment": "service_appointment_id",
+}
+
+func lookupMessageThreadColumn(thread string) (string, error) {
+	col, ok := messageThreadColumns[thread]
+	if !ok {
+		return "", apperr.BadRequest("Unknown message thread")
+	}
+	return col, nil
+}
+
+// messageSelect reads messages as seen by the viewer ($1); is_read is true for the viewer's own messages.
+const messageSelect = `
+	SELECT m.message_id, m.order_id, m.service_appointment_id, m.author_id,
+		NULLIF(TRIM(BOTH FROM u.first_name || ' ' || u.last_name), ''),
+		m.body, m.is_internal, m.created_at,
+		(m.author_id = $1 OR EXISTS (
+			SELECT 1 FROM message_reads mr WHERE mr.message_id = m.message_id AND mr.user_id = $1
+		)) IS TRUE
+	FROM messages m
+	LEFT JOIN users u ON u.user_id = m.author_id
+`
+
+// List returns the thread oldest first; internal notes are included only when includeInternal is set.
+func (r *MessageRepository) List(ctx context.Context, thread string, id, viewer uuid.UUID, includeInternal bool) ([]model.Message, error) {
+	col, err := lookupMessageThreadColumn(thread)
+	if err != nil {
+		return nil, err
+	}
+	return r.list(ctx, messageSelect+`
+		WHERE m.`+col+` = $2 AND ($3 OR m.is_internal = false)
+		ORDER BY m.created_at, m.message_id
+	`, viewer, id, includeInternal)
+}
+
+func (r *MessageRepository) GetByID(ctx context.Context, messageID, viewer uuid.UUID) (*model.Message, error) {
+	list, err := r.list(ctx, messageSelect+` WHERE m.message_id = $2`, viewer, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, apperr.NotFoundErr("Message not found")
+	}
+	return &list[0], nil
+}
+
+func (r *MessageRepository) list(ctx context.Context, query string, args ...any) ([]model.Message, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.Message{}
+	index := map[uuid.UUID]int{}
+	for rows.Next() {
+		m := model.Message{Attachments: []model.MessageAttachment{}, ReadBy: []model.MessageRead{}}
+		if err := rows.Scan(
+			&m.MessageID, &m.OrderID, &m.ServiceAppointmentID, &m.AuthorID, &m.AuthorName,
+			&m.Body, &m.IsInternal, &m.CreatedAt, &m.IsRead,
+		); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		index[m.MessageID] = len(out)
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+	ids := make([]uuid.UUID, 0, len(out))
+	for _, m := range out {
+		ids = append(ids, m.MessageID)
+	}
+	if err := r.loadAttachments(ctx, ids, out, index); err != nil {
+		return nil, err
+	}
+	if err := r.loadReads(ctx, ids, out, index); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *MessageRepository) loadAttachments(ctx context.Context, ids []uuid.UUID, out []model.Message, index map[uuid.UUID]int) error {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT ma.message_id, d.document_id, d.document_type, d.file_name
+		FROM message_attachments ma
+		JOIN documents d ON d.document_id = ma.document_id
+		WHERE ma.message_id = ANY($1)
+		ORDER BY d.created_at, d.document_id
+	`, ids)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var messageID uuid.UUID
+		var a model.MessageAttachment
+		if err := rows.Scan(&messageID, &a.DocumentID, &a.DocumentType, &a.FileName); err != nil {
+			return apperr.Internal(err)
+		}
+		m := &out[index[messageID]]
+		m.Attachments = append(m.Attachments, a)
+	}
+	return rows.Err()
+}
+
+func (r *MessageRepository) loadReads(ctx context.Context, ids []uuid.UUID, out []model.Message, index map[uuid.UUID]int) error {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT mr.message_id, mr.user_id, TRIM(BOTH FROM u.first_name || ' ' || u.last_name), mr.read_at
+		FROM message_reads mr
+		JOIN users u ON u.user_id = mr.user_id
+		WHERE mr.message_id = ANY($1)
+		ORDER BY mr.read_at
+	`, ids)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var messageID uuid.UUID
+		var rd model.MessageRead
+		if err := rows.Scan(&messageID, &rd.UserID, &rd.UserName, &rd.ReadAt); err != nil {
+			return apperr.Internal(err)
+		}
+		m := &out[index[messageID]]
+		m.ReadBy = append(m.ReadBy, rd)
+	}
+	return rows.Err()
+}
+
+// Create posts a message to the thread. Attachments must be documents of the same order or
+// appointment; otherwise nothing is stored.
+func (r *MessageRepository) Create(ctx context.Context, thread string, id, authorID uuid.UUID, in model.MessageCreate) (uuid.UUID, error) {
+	col, err := lookupMessageThreadColumn(thread)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var messageID uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO messages (`+col+`, author_id, body, is_internal)
+		VALUES ($1, $2, $3, $4)
+		RETURNING message_id
+	`, id, authorID, in.Body, in.Internal).Scan(&messageID); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	if len(in.DocumentIDs) > 0 {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO message_attachments (message_id, document_id)
+			SELECT $1, d.document_id FROM documents d
+			WHERE d.document_id = ANY($2) AND d.`+col+` = $3
+		`, messageID, in.DocumentIDs, id)
+		if err != nil {
+			return uuid.Nil, apperr.Internal(err)
+		}
+		if int(tag.RowsAffected()) != len(in.DocumentIDs) {
+			return uuid.Nil, apperr.BadRequest("attachments must be documents of the same " + thread)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	return messageID, nil
+}
+
+// MarkThreadRead records read receipts for every visible message of the thread written by someone
+// else; repeated calls are no-ops. It returns the number of newly read messages.
+func (r *MessageRepository) MarkThreadRead(ctx context.Context, thread string, id, userID uuid.UUID, includeInternal bool) (int, error) {
+	col, err := lookupMessageThreadColumn(thread)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO message_reads (message_id, user_id)
+		SELECT m.message_id, $2 FROM messages m
+		WHERE m.`+col+` = $1 AND ($3 OR m.is_internal = false) AND m.author_id IS DISTINCT FROM $2
+		ON CONFLICT DO NOTHING
+	`, id, userID, includeInternal)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// UnreadCounts returns, per thread id, how many visible messages by others the user has not read.
+// Threads without unread messages are absent from the map.
+func (r *MessageRepository) UnreadCounts(ctx context.Context, thread string, ids []uuid.UUID, userID uuid.UUID, includeInternal bool) (map[uuid.UUID]int, error) {
+	col, err := lookupMessageThreadColumn(thread)
+	if err != nil {
+		return nil, err
+	}
+	out := map[uuid.UUID]int{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT m.`+col+`, COUNT(*)
+		FROM messages m
+		WHERE m.`+col+` = ANY($1) AND ($3 OR m.is_internal = false) AND m.author_id IS DISTINCT FROM $2
+			AND NOT EXISTS (
+				SELECT 1 FROM message_reads mr WHERE mr.message_id = m.message_id AND mr.user_id = $2
+			)
+		GROUP BY m.`+col+`
+	`, ids, userID, includeInternal)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out[id] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type NotificationRepository struct {
+	db *database.DB
+}
+
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const notificationColumns = `
+	notification_id, user_id, kind, title, body, entity_type, entity_id, read_at, created_at
+`
+
+func scanNotification(row pgx.Row, n *model.Notification) error {
+	return row.Scan(
+		&n.NotificationID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.EntityType, &n.EntityID,
+		&n.ReadAt, &n.CreatedAt,
+	)
+}
+
+// Create stores a notification for a user; entity fields point at the object it is about.
+func (r *NotificationRepository) Create(ctx context.Context, n model.Notification) (*model.Notification, error) {
+	var out model.Notification
+	err := scanNotification(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO notifications (user_id, kind, title, body, entity_type, entity_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+notificationColumns,
+		n.UserID, n.Kind, n.Title, n.Body, n.EntityType, n.EntityID,
+	), &out)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &out, nil
+}
+
+// ListByUser returns the newest notifications first.
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]model.Notification, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = false OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.Notification{}
+	for rows.Next() {
+		var n model.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&n); err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return n, nil
+}
+
+// MarkRead marks one of the user's notifications as read; repeated calls are no-ops.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, now())
+		WHERE notification_id = $1 AND user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Notification not found")
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/carkeeper/backend/database"
 	"github.com/carkeeper/backend/internal/apperr"
@@ -21,12 +22,14 @@ func NewOrderRepository(db *database.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
-// CreateAndMarkConfigurationOrdered inserts an order and sets configuration status to ordered atomically.
+// CreateAndMarkConfigurationOrdered inserts an order priced from quote, redeems its promotions,
+// stores the optional finance plan and sets configuration status to ordered atomically.
 func (r *OrderRepository) CreateAndMarkConfigurationOrdered(
 	ctx context.Context,
 	userID uuid.UUID,
 	create model.OrderCreate,
-	finalPrice float64,
+	quote model.PriceBreakdown,
+	financePlan *model.OrderFinancePlan,
 ) (*model.Order, error) {
 	tx, err := r.db.Pool.Begin(ctx)
 	if err != nil {
@@ -36,11 +39,12 @@ func (r *OrderRepository) CreateAndMarkConfigurationOrdered(
 
 	var configUserID uuid.UUID
 	var configStatus string
+	var expired bool
 	err = tx.QueryRow(ctx, `
-		SELECT user_id, status FROM configurations
+		SELECT user_id, status, COALESCE(expires_at <= now(), false) FROM configurations
 		WHERE configuration_id = $1
 		FOR UPDATE
-	`, create.ConfigurationID).Scan(&configUserID, &configStatus)
+	`, create.ConfigurationID).Scan(&configUserID, &configStatus, &expired)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperr.NotFoundErr("Configuration not found")
@@ -50,23 +54,36 @@ func (r *OrderRepository) CreateAndMarkConfigurationOrdered(
 	if configUserID != userID {
 		return nil, apperr.Forbidden("Configuration does not belong to your account")
 	}
-	if configStatus != "confirmed" && configStatus != "draft" {
+	// An expired draft the lifecycle sweep has not reached yet is treated as expired.
+	if (configStatus != "confirmed" && configStatus != "draft") || expired {
 		return nil, apperr.BadRequest("Configuration cannot be ordered in its current status")
 	}
 
 	var order model.Order
 	err = tx.QueryRow(ctx, `
-		INSERT INTO orders (user_id, configuration_id, status, final_price)
-		VALUES ($1, $2, 'pending', $3)
-		RETURNING order_id, user_id, configuration_id, manager_id, status, final_price, created_at, updated_at
-	`, userID, create.ConfigurationID, finalPrice).Scan(
+		INSERT INTO orders (user_id, configuration_id, status, final_price, discount_total)
+		VALUES ($1, $2, 'pending', $3, $4)
+		RETURNING order_id, user_id, configuration_id, manager_id, status, final_price, discount_total, tradein_credit, final_price - tradein_credit, created_at, updated_at
+	`, userID, create.ConfigurationID, quote.FinalPrice, quote.DiscountTotal).Scan(
 		&order.OrderID, &order.UserID, &order.ConfigurationID, &order.ManagerID,
-		&order.Status, &order.FinalPrice, &order.CreatedAt, &order.UpdatedAt,
+		&order.Status, &order.FinalPrice, &order.DiscountTotal, &order.TradeInCredit, &order.AmountDue, &order.CreatedAt, &order.UpdatedAt,
 	)
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
 
+	if err := insertOrderEventTx(ctx, tx, order.OrderID, &userID, "created", nil, order.Status, model.OrderStatusChange{}); err != nil {
+		return nil, err
+	}
+	if err := redeemPromotionsTx(ctx, tx, order.OrderID, userID, quote.Promotions); err != nil {
+		return nil, err
+	}
+	if financePlan != nil {
+		if err := insertOrderFinancePlanTx(ctx, tx, order.OrderID, financePlan); err != nil {
+			return nil, err
+		}
+	}
+
 	tag, err := tx.Exec(ctx, `
 		UPDATE configurations SET status = 'ordered', updated_at = NOW()
 		WHERE configuration_id = $1
@@ -90,9 +107,10 @@ func (r *OrderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (*mode
 		SELECT 
 			o.order_id, o.user_id, o.configuration_id, o.manager_id, o.status,
 			COALESCE(osd.customer_label_ru, o.status) AS status_label,
-			o.final_price,
+			o.final_price, o.discount_total, o.tradein_credit, o.final_price - o.tradein_credit AS amount_due,
 			o.created_at, o.updated_at,
-			u.first_name || ' ' || u.last_name as manager_name
+			u.first_name || ' ' || u.last_name as manager_name,
+			(SELECT uc.user_car_id FROM user_cars uc WHERE uc.order_id = o.order_id) AS user_car_id
 		FROM orders o
 		LEFT JOIN order_status_definitions osd ON o.status = osd.code
 		LEFT JOIN users u ON o.manager_id = u.user_id
@@ -101,8 +119,8 @@ func (r *OrderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (*mode
 
 	err := r.db.Pool.QueryRow(ctx, query, orderID).Scan(
 		&order.OrderID, &order.UserID, &order.ConfigurationID, &order.ManagerID,
-		&order.Status, &order.StatusLabel, &order.FinalPrice, &order.CreatedAt, &order.UpdatedAt,
-		&order.ManagerName,
+		&order.Status, &order.StatusLabel, &order.FinalPrice, &order.DiscountTotal, &order.TradeInCredit, &order.AmountDue, &order.CreatedAt, &order.UpdatedAt,
+		&order.ManagerName, &order.UserCarID,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -119,6 +137,24 @@ func (r *OrderRepository) GetByID(ctx context.Context, orderID uuid.UUID) (*mode
 	}
 	order.Configuration = *config
 
+	promotions, err := NewPromotionRepository(r.db).ListByOrderID(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	order.Promotions = promotions
+
+	financePlan, err := NewFinanceProductRepository(r.db).GetOrderPlan(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	order.FinancePlan = financePlan
+
+	tradeIn, err := NewTradeInRepository(r.db).GetOfferByOrderID(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	order.TradeIn = tradeIn
+
 	return &order, nil
 }
 
@@ -127,7 +163,7 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 		SELECT 
 			o.order_id, o.user_id, o.configuration_id, o.manager_id, o.status,
 			COALESCE(osd.customer_label_ru, o.status) AS status_label,
-			o.final_price,
+			o.final_price, o.discount_total, o.tradein_credit, o.final_price - o.tradein_credit AS amount_due,
 			o.created_at, o.updated_at,
 			u.first_name || ' ' || u.last_name as manager_name
 		FROM orders o
@@ -148,7 +184,7 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 		var order model.OrderWithDetails
 		if err := rows.Scan(
 			&order.OrderID, &order.UserID, &order.ConfigurationID, &order.ManagerID,
-			&order.Status, &order.StatusLabel, &order.FinalPrice, &order.CreatedAt, &order.UpdatedAt,
+			&order.Status, &order.StatusLabel, &order.FinalPrice, &order.DiscountTotal, &order.TradeInCredit, &order.AmountDue, &order.CreatedAt, &order.UpdatedAt,
 			&order.ManagerName,
 		); err != nil {
 			return nil, apperr.Internal(err)
@@ -167,25 +203,86 @@ func (r *OrderRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]
 	return orders, nil
 }
 
-// ListAllWithDetails returns all orders with configuration details (staff / admin).
-func (r *OrderRepository) ListAllWithDetails(ctx context.Context) ([]model.OrderWithDetails, error) {
+// Search returns the orders matching the staff filters, newest first (staff / admin).
+func (r *OrderRepository) Search(ctx context.Context, f model.OrderFilters) ([]model.OrderWithDetails, error) {
+	var conditions []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if len(f.Status) > 0 {
+		add("o.status = ANY($%d)", f.Status)
+	}
+	if f.From != nil {
+		add("o.created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("o.created_at < $%d", *f.To)
+	}
+	if f.BrandID != nil {
+		add("b.brand_id = $%d", *f.BrandID)
+	}
+	if f.ModelID != nil {
+		add("m.model_id = $%d", *f.ModelID)
+	}
+	if f.ManagerID != nil {
+		add("o.manager_id = $%d", *f.ManagerID)
+	}
+	if f.MinPrice != nil {
+		add("o.final_price >= $%d", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		add("o.final_price <= $%d", *f.MaxPrice)
+	}
+	if f.Customer != "" {
+		add(`(cust.first_name || ' ' || cust.last_name ILIKE $%[1]d
+			OR cust.last_name || ' ' || cust.first_name ILIKE $%[1]d
+			OR cust.email ILIKE $%[1]d OR cust.phone ILIKE $%[1]d)`, likePattern(f.Customer))
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.listWithDetails(ctx, where, args...)
+}
+
+// likePattern turns user text into an ILIKE "contains" pattern with wildcards escaped.
+func likePattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + r.Replace(s) + "%"
+}
+
+// ListOpenByManager returns orders in non-terminal statuses assigned to the manager.
+func (r *OrderRepository) ListOpenByManager(ctx context.Context, managerID uuid.UUID) ([]model.OrderWithDetails, error) {
+	return r.listWithDetails(ctx, "WHERE o.manager_id = $1 AND COALESCE(osd.is_terminal, false) = false", managerID)
+}
+
+func (r *OrderRepository) listWithDetails(ctx context.Context, where string, args ...any) ([]model.OrderWithDetails, error) {
 	query := `
 		SELECT 
 			o.order_id, o.user_id, o.configuration_id, o.manager_id, o.status,
 			COALESCE(osd.customer_label_ru, o.status) AS status_label,
-			o.final_price,
+			o.final_price, o.discount_total, o.tradein_credit, o.final_price - o.tradein_credit AS amount_due,
 			o.created_at, o.updated_at,
 			u.first_name || ' ' || u.last_name as manager_name,
 			cust.email::text,
-			TRIM(BOTH FROM cust.first_name || ' ' || cust.last_name)::text
+			TRIM(BOTH FROM cust.first_name || ' ' || cust.last_name)::text,
+			cust.phone, b.name, m.name
 		FROM orders o
 		LEFT JOIN order_status_definitions osd ON o.status = osd.code
 		LEFT JOIN users u ON o.manager_id = u.user_id
 		JOIN users cust ON o.user_id = cust.user_id
+		JOIN configurations c ON o.configuration_id = c.configuration_id
+		JOIN trims t ON c.trim_id = t.trim_id
+		JOIN generations g ON t.generation_id = g.generation_id
+		JOIN models m ON g.model_id = m.model_id
+		JOIN brands b ON m.brand_id = b.brand_id
+		` + where + `
 		ORDER BY o.created_at DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
@@ -196,9 +293,10 @@ func (r *OrderRepository) ListAllWithDetails(ctx context.Context) ([]model.Order
 		var order model.OrderWithDetails
 		if err := rows.Scan(
 			&order.OrderID, &order.UserID, &order.ConfigurationID, &order.ManagerID,
-			&order.Status, &order.StatusLabel, &order.FinalPrice, &order.CreatedAt, &order.UpdatedAt,
+			&order.Status, &order.StatusLabel, &order.FinalPrice, &order.DiscountTotal, &order.TradeInCredit, &order.AmountDue, &order.CreatedAt, &order.UpdatedAt,
 			&order.ManagerName,
 			&order.CustomerEmail, &order.CustomerName,
+			&order.CustomerPhone, &order.BrandName, &order.ModelName,
 		); err != nil {
 			return nil, apperr.Internal(err)
 		}
@@ -215,9 +313,17 @@ func (r *OrderRepository) ListAllWithDetails(ctx context.Context) ([]model.Order
 	return orders, nil
 }
 
-func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, status string) error {
-	query := `UPDATE orders SET status = $1 WHERE order_id = $2`
-	_, err := r.db.Pool.Exec(ctx, query, status, orderID)
+// UpdateStatus moves an order from fromStatus to change.Status and records the event in the same
+// transaction; it fails with a conflict if the order was moved by someone else in the meantime.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, fromStatus string, change model.OrderStatusChange, actorID *uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE orders SET status = $1 WHERE order_id = $2 AND status = $3`
+	cmd, err := tx.Exec(ctx, query, change.Status, orderID, fromStatus)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
@@ -225,6 +331,186 @@ func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, s
 		}
 		return apperr.Internal(err)
 	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.Conflict("Order status was changed concurrently, reload and retry")
+	}
+	if err := insertOrderEventTx(ctx, tx, orderID, actorID, "status_changed", &fromStatus, change.Status, change); err != nil {
+		return err
+	}
+	if change.Status == "cancelled" {
+		if err := releaseOrderTradeIn(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := cancelOrderInvoicesTx(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if err := releasePromotionsTx(ctx, tx, orderID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE delivery_appointments SET status = 'cancelled'
+			WHERE order_id = $1 AND status = 'scheduled'
+		`, orderID); err != nil {
+			return apperr.Internal(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
 	return nil
 }
 
+// releaseOrderTradeIn frees the trade-in applied to a cancelled order: the offer returns to
+// accepted while it is still valid and is cancelled otherwise, so the car can be offered again or
+// transferred.
+func releaseOrderTradeIn(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE tradein_offers
+		SET status = CASE WHEN valid_until > now() THEN 'accepted' ELSE 'cancelled' END,
+			order_id = NULL,
+			decided_at = CASE WHEN valid_until > now() THEN decided_at ELSE now() END
+		WHERE order_id = $1 AND status = 'applied'
+	`, orderID); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func insertOrderEventTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, actorID *uuid.UUID, eventType string, fromStatus *string, toStatus string, change model.OrderStatusChange) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO order_events (order_id, actor_id, event_type, from_status, to_status, reason, comment, comment_internal)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, orderID, actorID, eventType, fromStatus, toStatus, change.Reason, change.Comment, change.Internal && change.Comment != nil)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// ListEvents returns the order timeline, oldest first.
+func (r *OrderRepository) ListEvents(ctx context.Context, orderID uuid.UUID) ([]model.OrderEvent, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT e.event_id, e.order_id, e.actor_id,
+			u.first_name || ' ' || u.last_name AS actor_name,
+			e.event_type, e.from_status, e.to_status, COALESCE(d.customer_label_ru, e.to_status),
+			e.reason, e.comment, e.comment_internal, e.created_at
+		FROM order_events e
+		LEFT JOIN users u ON u.user_id = e.actor_id
+		LEFT JOIN order_status_definitions d ON d.code = e.to_status
+		WHERE e.order_id = $1
+		ORDER BY e.created_at, e.event_id
+	`, orderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrderEvent{}
+	for rows.Next() {
+		var e model.OrderEvent
+		if err := rows.Scan(
+			&e.EventID, &e.OrderID, &e.ActorID, &e.ActorName,
+			&e.EventType, &e.FromStatus, &e.ToStatus, &e.ToStatusLabel,
+			&e.Reason, &e.Comment, &e.CommentInternal, &e.CreatedAt,
+		); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// CountCompletedByUser returns how many paid or completed orders the user has (customer segment).
+func (r *OrderRepository) CountCompletedByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*)::int FROM orders
+		WHERE user_id = $1 AND status IN ('paid', 'completed')
+	`, userID).Scan(&n)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return n, nil
+}
+
+// HandOver registers the ordered car in the customer's garage, marks the configuration purchased,
+// closes the scheduled delivery and, when toStatus differs from fromStatus, moves the order to
+// toStatus — all in one transaction.
+func (r *OrderRepository) HandOver(ctx context.Context, orderID uuid.UUID, fromStatus, toStatus string, car model.UserCarCreate, change model.OrderStatusChange, actorID *uuid.UUID) (*model.UserCar, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID, configID uuid.UUID
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT o.user_id, o.status, c.configuration_id, c.trim_id, c.color_id
+		FROM orders o
+		JOIN configurations c ON c.configuration_id = o.configuration_id
+		WHERE o.order_id = $1
+		FOR UPDATE OF o, c
+	`, orderID).Scan(&userID, &status, &configID, &car.TrimID, &car.ColorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	if status != fromStatus {
+		return nil, apperr.Conflict("Order status was changed concurrently, reload and retry")
+	}
+	var handedOver bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM user_cars WHERE order_id = $1)`, orderID).Scan(&handedOver); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if handedOver {
+		return nil, apperr.Conflict("The car for this order has already been handed over")
+	}
+
+	var uc model.UserCar
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_cars (user_id, trim_id, color_id, vin, year, current_mileage, purchase_date, order_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING user_car_id, user_id, trim_id, color_id, vin, year, current_mileage, purchase_date, order_id, created_at
+	`, userID, car.TrimID, car.ColorID, car.VIN, car.Year, car.CurrentMileage, car.PurchaseDate, orderID).Scan(
+		&uc.UserCarID, &uc.UserID, &uc.TrimID, &uc.ColorID, &uc.VIN, &uc.Year, &uc.CurrentMileage,
+		&uc.PurchaseDate, &uc.OrderID, &uc.CreatedAt,
+	)
+	if err != nil {
+		if conflict := mapUniqueViolation(err, "This VIN is already registered"); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE configurations SET status = 'purchased', updated_at = NOW()
+		WHERE configuration_id = $1
+	`, configID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE delivery_appointments SET status = 'completed'
+		WHERE order_id = $1 AND status = 'scheduled'
+	`, orderID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	if toStatus != fromStatus {
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = $1 WHERE order_id = $2`, toStatus, orderID); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		change.Status = toStatus
+		if err := insertOrderEventTx(ctx, tx, orderID, actorID, "status_changed", &fromStatus, toStatus, change); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &uc, nil
+}
@@ -237,3 +237,114 @@ func (r *OrderStatusRepository) Delete(ctx context.Context, id uuid.UUID) error
 	}
 	return nil
 }
+
+const orderStatusTransitionSelect = `
+	SELECT t.transition_id, t.from_status, t.to_status, t.permission_code, t.customer_may_trigger,
+	       d.customer_label_ru, d.admin_label_ru, d.is_active, d.is_terminal, t.created_at, t.updated_at
+	FROM order_status_transitions t
+	JOIN order_status_definitions d ON d.code = t.to_status
+`
+
+func scanOrderStatusTransition(row pgx.Row, t *model.OrderStatusTransition) error {
+	return row.Scan(
+		&t.TransitionID, &t.FromStatus, &t.ToStatus, &t.PermissionCode, &t.CustomerMayTrigger,
+		&t.ToCustomerLabelRu, &t.ToAdminLabelRu, &t.ToIsActive, &t.ToIsTerminal, &t.CreatedAt, &t.UpdatedAt,
+	)
+}
+
+func (r *OrderStatusRepository) queryTransitions(ctx context.Context, query string, args ...any) ([]model.OrderStatusTransition, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrderStatusTransition{}
+	for rows.Next() {
+		var t model.OrderStatusTransition
+		if err := scanOrderStatusTransition(rows, &t); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *OrderStatusRepository) ListTransitions(ctx context.Context) ([]model.OrderStatusTransition, error) {
+	return r.queryTransitions(ctx, orderStatusTransitionSelect+`ORDER BY t.from_status, d.sort_order, t.to_status`)
+}
+
+// ListTransitionsFrom returns the configured transitions out of a status; terminal statuses have none.
+func (r *OrderStatusRepository) ListTransitionsFrom(ctx context.Context, fromStatus string) ([]model.OrderStatusTransition, error) {
+	return r.queryTransitions(ctx, orderStatusTransitionSelect+`
+		JOIN order_status_definitions f ON f.code = t.from_status
+		WHERE t.from_status = $1 AND f.is_terminal = false
+		ORDER BY d.sort_order, t.to_status
+	`, fromStatus)
+}
+
+func (r *OrderStatusRepository) GetTransition(ctx context.Context, id uuid.UUID) (*model.OrderStatusTransition, error) {
+	var t model.OrderStatusTransition
+	err := scanOrderStatusTransition(r.db.Pool.QueryRow(ctx, orderStatusTransitionSelect+`WHERE t.transition_id = $1`, id), &t)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Order status transition not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &t, nil
+}
+
+func (r *OrderStatusRepository) CreateTransition(ctx context.Context, in model.OrderStatusTransitionInput) (*model.OrderStatusTransition, error) {
+	var id uuid.UUID
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO order_status_transitions (from_status, to_status, permission_code, customer_may_trigger)
+		VALUES ($1, $2, $3, $4)
+		RETURNING transition_id
+	`, in.FromStatus, in.ToStatus, *in.PermissionCode, in.CustomerMayTrigger).Scan(&id)
+	if err != nil {
+		return nil, mapOrderStatusTransitionWriteError(err)
+	}
+	return r.GetTransition(ctx, id)
+}
+
+func (r *OrderStatusRepository) UpdateTransition(ctx context.Context, id uuid.UUID, in model.OrderStatusTransitionInput) (*model.OrderStatusTransition, error) {
+	cmd, err := r.db.Pool.Exec(ctx, `
+		UPDATE order_status_transitions
+		SET from_status = $2, to_status = $3, permission_code = $4, customer_may_trigger = $5
+		WHERE transition_id = $1
+	`, id, in.FromStatus, in.ToStatus, *in.PermissionCode, in.CustomerMayTrigger)
+	if err != nil {
+		return nil, mapOrderStatusTransitionWriteError(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return nil, apperr.NotFoundErr("Order status transition not found")
+	}
+	return r.GetTransition(ctx, id)
+}
+
+func (r *OrderStatusRepository) DeleteTransition(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM order_status_transitions WHERE transition_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Order status transition not found")
+	}
+	return nil
+}
+
+func mapOrderStatusTransitionWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return apperr.Conflict("This transition already exists")
+		case "23503":
+			return apperr.BadRequest("Unknown order status or permission")
+		case "23514":
+			return apperr.BadRequest("A status cannot transition to itself")
+		}
+	}
+	return apperr.Internal(err)
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrganizationRepository stores households and companies, their members and what they spend on
+// the cars shared with them.
+type OrganizationRepository struct {
+	db *database.DB
+}
+
+func NewOrganizationRepository(db *database.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// organizationSelect reads organizations with the member role of $1.
+const organizationSelect = `
+	SELECT o.organization_id, o.name, o.kind, o.created_by, o.created_at, o.updated_at, om.role,
+		(SELECT COUNT(*) FROM organization_members m WHERE m.organization_id = o.organization_id),
+		(SELECT COUNT(*) FROM user_cars uc WHERE uc.organization_id = o.organization_id)
+	FROM organizations o
+	JOIN organization_members om ON om.organization_id = o.organization_id AND om.user_id = $1
+`
+
+func scanOrganization(row pgx.Row, o *model.Organization) error {
+	return row.Scan(&o.OrganizationID, &o.Name, &o.Kind, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &o.Role,
+		&o.MemberCount, &o.CarCount)
+}
+
+// ListForUser returns the organizations the user belongs to.
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.Organization, error) {
+	rows, err := r.db.Pool.Query(ctx, organizationSelect+`ORDER BY o.name`, userID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.Organization{}
+	for rows.Next() {
+		var o model.Organization
+		if err := scanOrganization(rows, &o); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// GetForUser returns an organization of which the user is a member.
+func (r *OrganizationRepository) GetForUser(ctx context.Context, organizationID, userID uuid.UUID) (*model.Organization, error) {
+	var o model.Organization
+	err := scanOrganization(r.db.Pool.QueryRow(ctx, organizationSelect+`WHERE o.organization_id = $2`, userID, organizationID), &o)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &o, nil
+}
+
+// Create stores an organization with its creator as the first owner.
+func (r *OrganizationRepository) Create(ctx context.Context, name, kind string, creator uuid.UUID) (uuid.UUID, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO organizations (name, kind, created_by) VALUES ($1, $2, $3)
+		RETURNING organization_id
+	`, name, kind, creator).Scan(&id); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, 'owner')
+	`, id, creator); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	return id, nil
+}
+
+func (r *OrganizationRepository) Update(ctx context.Context, organizationID uuid.UUID, name, kind string) error {
+	ct, err := r.db.Pool.Exec(ctx, `
+		UPDATE organizations SET name = $2, kind = $3 WHERE organization_id = $1
+	`, organizationID, name, kind)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes an organization; its cars stay with their owners and stop being shared.
+func (r *OrganizationRepository) Delete(ctx context.Context, organizationID uuid.UUID) error {
+	ct, err := r.db.Pool.Exec(ctx, `DELETE FROM organizations WHERE organization_id = $1`, organizationID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return nil
+}
+
+// MemberRole returns the user's role in the organization, or "" for non-members.
+func (r *OrganizationRepository) MemberRole(ctx context.Context, organizationID, userID uuid.UUID) (string, error) {
+	var role string
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT role FROM organization_members WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", apperr.Internal(err)
+	}
+	return role, nil
+}
+
+// CarMemberRole returns the user's role in the organization the car is shared with, or "" when
+// the car is not shared with any organization of the user.
+func (r *OrganizationRepository) CarMemberRole(ctx context.Context, userCarID, userID uuid.UUID) (string, error) {
+	var role string
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT om.role
+		FROM user_cars uc
+		JOIN organization_members om ON om.organization_id = uc.organization_id
+		WHERE uc.user_car_id = $1 AND om.user_id = $2
+	`, userCarID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", apperr.Internal(err)
+	}
+	return role, nil
+}
+
+func (r *OrganizationRepository) ListMembers(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT om.organization_id, om.user_id, u.email, u.first_name, u.last_name, om.role, om.created_at
+		FROM organization_members om
+		JOIN users u ON u.user_id = om.user_id
+		WHERE om.organization_id = $1
+		ORDER BY CASE om.role WHEN 'owner' THEN 0 WHEN 'driver' THEN 1 ELSE 2 END, u.last_name, u.first_name
+	`, organizationID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrganizationMember{}
+	for rows.Next() {
+		var m model.OrganizationMember
+		if err := rows.Scan(&m.OrganizationID, &m.UserID, &m.Email, &m.FirstName, &m.LastName, &m.Role, &m.CreatedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+func (r *OrganizationRepository) AddMember(ctx context.Context, organizationID, userID uuid.UUID, role string) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role) VALUES ($1, $2, $3)
+	`, organizationID, userID, role); err != nil {
+		if conflict := mapUniqueViolation(err, "User is already a member"); conflict != nil {
+			return conflict
+		}
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// UpdateMemberRole changes a member's role; the last owner cannot be demoted.
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, organizationID, userID uuid.UUID, role string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := keepOrganizationOwner(ctx, tx, organizationID, userID, role); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE organization_members SET role = $3 WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID, role); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// RemoveMember drops a member and stops sharing the member's own cars with the organization; the
+// last owner cannot leave.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := keepOrganizationOwner(ctx, tx, organizationID, userID, ""); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID); err != nil {
+		return apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_cars SET organization_id = NULL WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// keepOrganizationOwner locks the organization and checks that giving the member newRole ("" when
+// the member leaves) still leaves it an owner.
+func keepOrganizationOwner(ctx context.Context, tx pgx.Tx, organizationID, userID uuid.UUID, newRole string) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM organizations WHERE organization_id = $1 FOR UPDATE`, organizationID); err != nil {
+		return apperr.Internal(err)
+	}
+	var role string
+	var owners int
+	err := tx.QueryRow(ctx, `
+		SELECT role, (SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = 'owner')
+		FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID).Scan(&role, &owners)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return apperr.Internal(err)
+	}
+	if role == "owner" && newRole != "owner" && owners <= 1 {
+		return apperr.Conflict("An organization needs at least one owner")
+	}
+	return nil
+}
+
+// organizationSpendFrom selects the service book records performed in [$2, $3) while the car was
+// shared with the organization, with the garage car that now has the VIN, if any.
+const organizationSpendFrom = `
+	FROM vehicle_service_records r
+	LEFT JOIN user_cars uc ON uc.vin = r.vin
+	WHERE r.organization_id = $1 AND r.performed_at >= $2 AND r.performed_at < $3
+`
+
+// SpendByCar sums the organization's service spend per car, highest first.
+func (r *OrganizationRepository) SpendByCar(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]model.OrganizationCarSpend, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT uc.user_car_id, r.vin, MAX(r.vehicle_title), COUNT(*), COALESCE(SUM(r.total), 0)::float8
+		`+organizationSpendFrom+`
+		GROUP BY uc.user_car_id, r.vin
+		ORDER BY 5 DESC, r.vin
+	`, organizationID, from, to)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrganizationCarSpend{}
+	for rows.Next() {
+		var c model.OrganizationCarSpend
+		if err := rows.Scan(&c.UserCarID, &c.VIN, &c.Title, &c.Visits, &c.Total); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// SpendByMonth sums the organization's service spend per calendar month (UTC).
+func (r *OrganizationRepository) SpendByMonth(ctx context.Context, organizationID uuid.UUID, from, to time.Time) ([]model.OrganizationMonthSpend, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT date_trunc('month', r.performed_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', COUNT(*),
+			COALESCE(SUM(r.total), 0)::float8
+		`+organizationSpendFrom+`
+		GROUP BY 1
+		ORDER BY 1
+	`, organizationID, from, to)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrganizationMonthSpend{}
+	for rows.Next() {
+		var m model.OrganizationMonthSpend
+		if err := rows.Scan(&m.Month, &m.Visits, &m.Total); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
@@ -0,0 +1,578 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PaymentRepository struct {
+	db *database.DB
+}
+
+func NewPaymentRepository(db *database.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+const invoiceColumns = `
+	i.invoice_id, i.order_id, i.kind, i.amount, i.amount_paid, i.amount_refunded, i.status, i.due_date,
+	i.created_by, i.created_at, i.updated_at,
+	COALESCE((SELECT SUM(p.amount) FROM payments p WHERE p.invoice_id = i.invoice_id AND p.status = 'pending'), 0)
+`
+
+func scanInvoice(row pgx.Row, inv *model.Invoice) error {
+	return row.Scan(
+		&inv.InvoiceID, &inv.OrderID, &inv.Kind, &inv.Amount, &inv.AmountPaid, &inv.AmountRefunded, &inv.Status, &inv.DueDate,
+		&inv.CreatedBy, &inv.CreatedAt, &inv.UpdatedAt,
+		&inv.PendingAmount,
+	)
+}
+
+const paymentColumns = `
+	payment_id, invoice_id, order_id, amount, method, status, gateway, gateway_ref, payment_url,
+	failure_reason, created_by, paid_at, created_at
+`
+
+func scanPayment(row pgx.Row, p *model.Payment) error {
+	return row.Scan(
+		&p.PaymentID, &p.InvoiceID, &p.OrderID, &p.Amount, &p.Method, &p.Status, &p.Gateway, &p.GatewayRef, &p.PaymentURL,
+		&p.FailureReason, &p.CreatedBy, &p.PaidAt, &p.CreatedAt,
+	)
+}
+
+const refundColumns = `
+	refund_id, payment_id, amount, reason, status, gateway_ref, failure_reason, created_by, processed_at, created_at
+`
+
+func scanRefund(row pgx.Row, rf *model.Refund) error {
+	return row.Scan(
+		&rf.RefundID, &rf.PaymentID, &rf.Amount, &rf.Reason, &rf.Status, &rf.GatewayRef, &rf.FailureReason,
+		&rf.CreatedBy, &rf.ProcessedAt, &rf.CreatedAt,
+	)
+}
+
+// ListInvoicesByOrder returns the order invoices, oldest first, with their payments and refunds.
+func (r *PaymentRepository) ListInvoicesByOrder(ctx context.Context, orderID uuid.UUID) ([]model.Invoice, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+invoiceColumns+`
+		FROM invoices i
+		WHERE i.order_id = $1
+		ORDER BY i.created_at, i.invoice_id
+	`, orderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	invoices := []model.Invoice{}
+	byID := map[uuid.UUID]int{}
+	for rows.Next() {
+		var inv model.Invoice
+		if err := scanInvoice(rows, &inv); err != nil {
+			rows.Close()
+			return nil, apperr.Internal(err)
+		}
+		inv.Payments = []model.Payment{}
+		byID[inv.InvoiceID] = len(invoices)
+		invoices = append(invoices, inv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+
+	payments, err := r.listPayments(ctx, `order_id = $1`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if i, ok := byID[p.InvoiceID]; ok {
+			invoices[i].Payments = append(invoices[i].Payments, p)
+		}
+	}
+	return invoices, nil
+}
+
+func (r *PaymentRepository) listPayments(ctx context.Context, where string, args ...any) ([]model.Payment, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+paymentColumns+`
+		FROM payments
+		WHERE `+where+`
+		ORDER BY created_at, payment_id
+	`, args...)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	var payments []model.Payment
+	byID := map[uuid.UUID]int{}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var p model.Payment
+		if err := scanPayment(rows, &p); err != nil {
+			rows.Close()
+			return nil, apperr.Internal(err)
+		}
+		byID[p.PaymentID] = len(payments)
+		ids = append(ids, p.PaymentID)
+		payments = append(payments, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if len(ids) == 0 {
+		return payments, nil
+	}
+
+	rows, err = r.db.Pool.Query(ctx, `
+		SELECT `+refundColumns+`
+		FROM refunds
+		WHERE payment_id = ANY($1)
+		ORDER BY created_at, refund_id
+	`, ids)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rf model.Refund
+		if err := scanRefund(rows, &rf); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		p := &payments[byID[rf.PaymentID]]
+		p.Refunds = append(p.Refunds, rf)
+	}
+	return payments, rows.Err()
+}
+
+func (r *PaymentRepository) GetInvoice(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	var inv model.Invoice
+	err := scanInvoice(r.db.Pool.QueryRow(ctx, `SELECT `+invoiceColumns+` FROM invoices i WHERE i.invoice_id = $1`, id), &inv)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Invoice not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &inv, nil
+}
+
+func (r *PaymentRepository) GetPayment(ctx context.Context, id uuid.UUID) (*model.Payment, error) {
+	var p model.Payment
+	err := scanPayment(r.db.Pool.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE payment_id = $1`, id), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Payment not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+// CreateInvoice bills the order under a row lock so concurrent invoices cannot exceed the amount due.
+// plan receives the order amount due and the total of active invoices and returns the amount to bill.
+func (r *PaymentRepository) CreateInvoice(
+	ctx context.Context,
+	orderID uuid.UUID,
+	kind string,
+	dueDate *time.Time,
+	createdBy uuid.UUID,
+	plan func(amountDue, invoiced float64) (float64, error),
+) (*model.Invoice, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var amountDue float64
+	err = tx.QueryRow(ctx, `
+		SELECT final_price - tradein_credit FROM orders WHERE order_id = $1 FOR UPDATE
+	`, orderID).Scan(&amountDue)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Order not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	var invoiced float64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM invoices WHERE order_id = $1 AND status <> 'cancelled'
+	`, orderID).Scan(&invoiced)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	amount, err := plan(amountDue, invoiced)
+	if err != nil {
+		return nil, err
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		INSERT INTO invoices (order_id, kind, amount, due_date, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING invoice_id
+	`, orderID, kind, amount, dueDate, createdBy).Scan(&id)
+	if err != nil {
+		if conflict := mapUniqueViolation(err, fmt.Sprintf("The order already has an active %s invoice", kind)); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.GetInvoice(ctx, id)
+}
+
+// CancelInvoice voids an invoice that holds no money and has no payment in flight.
+func (r *PaymentRepository) CancelInvoice(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE invoices i SET status = 'cancelled'
+		WHERE i.invoice_id = $1 AND i.status <> 'cancelled'
+			AND i.amount_paid = i.amount_refunded
+			AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.invoice_id = i.invoice_id AND p.status = 'pending')
+	`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetInvoice(ctx, id); err != nil {
+			return err
+		}
+		return apperr.Conflict("Invoice is already cancelled or holds payments")
+	}
+	return nil
+}
+
+// cancelOrderInvoicesTx voids the invoices of a cancelled order that hold no money and have no
+// payment in flight; invoices with payments stay for refunds.
+func cancelOrderInvoicesTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE invoices i SET status = 'cancelled'
+		WHERE i.order_id = $1 AND i.status IN ('open', 'partially_paid')
+			AND i.amount_paid = i.amount_refunded
+			AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.invoice_id = i.invoice_id AND p.status = 'pending')
+	`, orderID); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// CreatePayment registers a payment against a locked invoice; plan validates the invoice state and
+// returns the amount. The order is share-locked first and must not be closed (completed or
+// cancelled). Without a gateway the payment is an offline one recorded by staff and is settled in
+// the same transaction.
+func (r *PaymentRepository) CreatePayment(
+	ctx context.Context,
+	invoiceID uuid.UUID,
+	method string,
+	gateway *string,
+	createdBy uuid.UUID,
+	plan func(inv model.Invoice) (float64, error),
+) (*model.Payment, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The order is locked before the invoice, in the same order as status changes take them.
+	var terminal bool
+	err = tx.QueryRow(ctx, `
+		SELECT d.is_terminal
+		FROM orders o
+		JOIN order_status_definitions d ON d.code = o.status
+		WHERE o.order_id = (SELECT order_id FROM invoices WHERE invoice_id = $1)
+		FOR SHARE OF o
+	`, invoiceID).Scan(&terminal)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Invoice not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	if terminal {
+		return nil, apperr.BadRequest("Order is closed and cannot be paid")
+	}
+
+	var inv model.Invoice
+	err = scanInvoice(tx.QueryRow(ctx, `SELECT `+invoiceColumns+` FROM invoices i WHERE i.invoice_id = $1 FOR UPDATE`, invoiceID), &inv)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Invoice not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	amount, err := plan(inv)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "pending"
+	var paidAt *time.Time
+	if gateway == nil {
+		now := time.Now()
+		status, paidAt = "succeeded", &now
+	}
+	var p model.Payment
+	err = scanPayment(tx.QueryRow(ctx, `
+		INSERT INTO payments (invoice_id, order_id, amount, method, status, gateway, created_by, paid_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+paymentColumns,
+		inv.InvoiceID, inv.OrderID, amount, method, status, gateway, createdBy, paidAt,
+	), &p)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if gateway == nil {
+		if err := refreshInvoiceTx(ctx, tx, inv.InvoiceID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+// SetPaymentGatewayRef stores the provider reference and checkout URL of an online payment.
+func (r *PaymentRepository) SetPaymentGatewayRef(ctx context.Context, paymentID uuid.UUID, ref string, paymentURL *string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE payments SET gateway_ref = $2, payment_url = $3 WHERE payment_id = $1
+	`, paymentID, ref, paymentURL)
+	if err != nil {
+		if conflict := mapUniqueViolation(err, "Duplicate gateway payment reference"); conflict != nil {
+			return conflict
+		}
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+func (r *PaymentRepository) GetPaymentByGatewayRef(ctx context.Context, gateway, ref string) (*model.Payment, error) {
+	var p model.Payment
+	err := scanPayment(r.db.Pool.QueryRow(ctx, `
+		SELECT `+paymentColumns+` FROM payments WHERE gateway = $1 AND gateway_ref = $2
+	`, gateway, ref), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Payment not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+// SettlePayment moves a pending payment to succeeded or failed and updates the invoice totals.
+// Settling an already final payment is a no-op and reports changed=false, so webhooks may repeat.
+func (r *PaymentRepository) SettlePayment(ctx context.Context, paymentID uuid.UUID, status string, failureReason *string) (p *model.Payment, changed bool, err error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, false, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var cur model.Payment
+	err = scanPayment(tx.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE payment_id = $1 FOR UPDATE`, paymentID), &cur)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, apperr.NotFoundErr("Payment not found")
+		}
+		return nil, false, apperr.Internal(err)
+	}
+	if cur.Status != "pending" {
+		return &cur, false, nil
+	}
+
+	var settled model.Payment
+	err = scanPayment(tx.QueryRow(ctx, `
+		UPDATE payments
+		SET status = $2, failure_reason = $3, paid_at = CASE WHEN $2 = 'succeeded' THEN now() END
+		WHERE payment_id = $1
+		RETURNING `+paymentColumns,
+		paymentID, status, failureReason,
+	), &settled)
+	if err != nil {
+		return nil, false, apperr.Internal(err)
+	}
+	if err := refreshInvoiceTx(ctx, tx, settled.InvoiceID); err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, apperr.Internal(err)
+	}
+	return &settled, true, nil
+}
+
+// CreateRefund registers a refund of a locked, succeeded payment; plan receives the payment and the
+// amount already refunded or being refunded and returns the refund amount. Refunds of offline
+// payments (no gateway) are settled in the same transaction.
+func (r *PaymentRepository) CreateRefund(
+	ctx context.Context,
+	paymentID uuid.UUID,
+	reason string,
+	createdBy uuid.UUID,
+	plan func(p model.Payment, refunded float64) (float64, error),
+) (*model.Refund, *model.Payment, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var p model.Payment
+	err = scanPayment(tx.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE payment_id = $1 FOR UPDATE`, paymentID), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, apperr.NotFoundErr("Payment not found")
+		}
+		return nil, nil, apperr.Internal(err)
+	}
+	var refunded float64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE payment_id = $1 AND status <> 'failed'
+	`, paymentID).Scan(&refunded)
+	if err != nil {
+		return nil, nil, apperr.Internal(err)
+	}
+	amount, err := plan(p, refunded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status := "pending"
+	var processedAt *time.Time
+	if p.Gateway == nil {
+		now := time.Now()
+		status, processedAt = "succeeded", &now
+	}
+	var rf model.Refund
+	err = scanRefund(tx.QueryRow(ctx, `
+		INSERT INTO refunds (payment_id, amount, reason, status, created_by, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+refundColumns,
+		paymentID, amount, reason, status, createdBy, processedAt,
+	), &rf)
+	if err != nil {
+		return nil, nil, apperr.Internal(err)
+	}
+	if p.Gateway == nil {
+		if err := refreshInvoiceTx(ctx, tx, p.InvoiceID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, apperr.Internal(err)
+	}
+	return &rf, &p, nil
+}
+
+func (r *PaymentRepository) GetRefundByGatewayRef(ctx context.Context, ref string) (*model.Refund, error) {
+	var rf model.Refund
+	err := scanRefund(r.db.Pool.QueryRow(ctx, `SELECT `+refundColumns+` FROM refunds WHERE gateway_ref = $1`, ref), &rf)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Refund not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &rf, nil
+}
+
+// SettleRefund records the gateway outcome of a pending refund (and its reference, when known) and
+// updates the invoice totals. Settling an already final refund is a no-op.
+func (r *PaymentRepository) SettleRefund(ctx context.Context, refundID uuid.UUID, gatewayRef *string, status string, failureReason *string) (*model.Refund, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var rf model.Refund
+	err = scanRefund(tx.QueryRow(ctx, `
+		UPDATE refunds
+		SET gateway_ref = COALESCE($2, gateway_ref), status = $3, failure_reason = $4,
+			processed_at = CASE WHEN $3 = 'pending' THEN NULL ELSE now() END
+		WHERE refund_id = $1 AND status = 'pending'
+		RETURNING `+refundColumns,
+		refundID, gatewayRef, status, failureReason,
+	), &rf)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = scanRefund(r.db.Pool.QueryRow(ctx, `SELECT `+refundColumns+` FROM refunds WHERE refund_id = $1`, refundID), &rf)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperr.NotFoundErr("Refund not found")
+			}
+			if err != nil {
+				return nil, apperr.Internal(err)
+			}
+			return &rf, nil
+		}
+		if conflict := mapUniqueViolation(err, "Duplicate gateway refund reference"); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+	if rf.Status == "succeeded" {
+		var invoiceID uuid.UUID
+		if err := tx.QueryRow(ctx, `SELECT invoice_id FROM payments WHERE payment_id = $1`, rf.PaymentID).Scan(&invoiceID); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if err := refreshInvoiceTx(ctx, tx, invoiceID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return &rf, nil
+}
+
+// NetPaidByOrder returns the money kept on the order: succeeded payments minus succeeded refunds.
+func (r *PaymentRepository) NetPaidByOrder(ctx context.Context, orderID uuid.UUID) (float64, error) {
+	var net float64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount_paid - amount_refunded), 0) FROM invoices WHERE order_id = $1
+	`, orderID).Scan(&net)
+	if err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return net, nil
+}
+
+// refreshInvoiceTx recomputes invoice totals and status from its succeeded payments and refunds.
+func refreshInvoiceTx(ctx context.Context, tx pgx.Tx, invoiceID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		WITH totals AS (
+			SELECT
+				COALESCE(SUM(p.amount) FILTER (WHERE p.status = 'succeeded'), 0) AS paid,
+				COALESCE((SELECT SUM(rf.amount) FROM refunds rf
+					JOIN payments rp ON rp.payment_id = rf.payment_id
+					WHERE rp.invoice_id = $1 AND rf.status = 'succeeded'), 0) AS refunded
+			FROM payments p
+			WHERE p.invoice_id = $1
+		)
+		UPDATE invoices i
+		SET amount_paid = t.paid,
+			amount_refunded = t.refunded,
+			status = CASE
+				WHEN i.status = 'cancelled' THEN 'cancelled'
+				WHEN t.paid - t.refunded >= i.amount THEN 'paid'
+				WHEN t.paid - t.refunded > 0 THEN 'partially_paid'
+				WHEN t.refunded > 0 THEN 'refunded'
+				ELSE 'open'
+			END
+		FROM totals t
+		WHERE i.invoice_id = $1
+	`, invoiceID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
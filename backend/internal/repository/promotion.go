@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PromotionRepository struct {
+	db *database.DB
+}
+
+func NewPromotionRepository(db *database.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `
+	promotion_id, name, description, discount_type, discount_value,
+	brand_id, model_id, trim_id, option_id, vehicle_segment, customer_segment,
+	valid_from, valid_to, max_uses, max_uses_per_user, uses_count,
+	is_stackable, requires_code, priority, is_active, created_at, updated_at
+`
+
+func scanPromotion(row pgx.Row, p *model.Promotion) error {
+	return row.Scan(
+		&p.PromotionID, &p.Name, &p.Description, &p.DiscountType, &p.DiscountValue,
+		&p.BrandID, &p.ModelID, &p.TrimID, &p.OptionID, &p.VehicleSegment, &p.CustomerSegment,
+		&p.ValidFrom, &p.ValidTo, &p.MaxUses, &p.MaxUsesPerUser, &p.UsesCount,
+		&p.IsStackable, &p.RequiresCode, &p.Priority, &p.IsActive, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+func scanPromotionRows(rows pgx.Rows) ([]model.Promotion, error) {
+	var out []model.Promotion
+	for rows.Next() {
+		var p model.Promotion
+		if err := scanPromotion(rows, &p); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// ListAll returns every promotion for the admin UI.
+func (r *PromotionRepository) ListAll(ctx context.Context) ([]model.Promotion, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+promotionColumns+` FROM promotions ORDER BY priority DESC, created_at DESC`)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	return scanPromotionRows(rows)
+}
+
+// ListActiveForTrim returns active promotions whose vehicle scope can match the trim; the vehicle
+// segment is compared case-insensitively, as promotionEligible does. Validity window, usage limits
+// and customer segment are evaluated by the caller.
+func (r *PromotionRepository) ListActiveForTrim(ctx context.Context, scope model.PricingScope) ([]model.Promotion, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+promotionColumns+`
+		FROM promotions
+		WHERE is_active = true
+		  AND (brand_id IS NULL OR brand_id = $1)
+		  AND (model_id IS NULL OR model_id = $2)
+		  AND (trim_id IS NULL OR trim_id = $3)
+		  AND (vehicle_segment IS NULL OR lower(vehicle_segment) = lower($4))
+		ORDER BY priority DESC, created_at
+	`, scope.BrandID, scope.ModelID, scope.TrimID, scope.Segment)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	return scanPromotionRows(rows)
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Promotion, error) {
+	var p model.Promotion
+	err := scanPromotion(r.db.Pool.QueryRow(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE promotion_id = $1`, id), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Promotion not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, in model.PromotionInput) (*model.Promotion, error) {
+	var p model.Promotion
+	err := scanPromotion(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO promotions (
+			name, description, discount_type, discount_value,
+			brand_id, model_id, trim_id, option_id, vehicle_segment, customer_segment,
+			valid_from, valid_to, max_uses, max_uses_per_user,
+			is_stackable, requires_code, priority, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING `+promotionColumns,
+		in.Name, in.Description, in.DiscountType, in.DiscountValue,
+		in.BrandID, in.ModelID, in.TrimID, in.OptionID, in.VehicleSegment, in.CustomerSegment,
+		in.ValidFrom, in.ValidTo, in.MaxUses, in.MaxUsesPerUser,
+		in.IsStackable, in.RequiresCode, in.Priority, in.IsActive,
+	), &p)
+	if err != nil {
+		return nil, mapPromotionWriteError(err)
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, id uuid.UUID, in model.PromotionInput) (*model.Promotion, error) {
+	var p model.Promotion
+	err := scanPromotion(r.db.Pool.QueryRow(ctx, `
+		UPDATE promotions SET
+			name = $2, description = $3, discount_type = $4, discount_value = $5,
+			brand_id = $6, model_id = $7, trim_id = $8, option_id = $9,
+			vehicle_segment = $10, customer_segment = $11,
+			valid_from = $12, valid_to = $13, max_uses = $14, max_uses_per_user = $15,
+			is_stackable = $16, requires_code = $17, priority = $18, is_active = $19
+		WHERE promotion_id = $1
+		RETURNING `+promotionColumns,
+		id, in.Name, in.Description, in.DiscountType, in.DiscountValue,
+		in.BrandID, in.ModelID, in.TrimID, in.OptionID,
+		in.VehicleSegment, in.CustomerSegment,
+		in.ValidFrom, in.ValidTo, in.MaxUses, in.MaxUsesPerUser,
+		in.IsStackable, in.RequiresCode, in.Priority, in.IsActive,
+	), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Promotion not found")
+		}
+		return nil, mapPromotionWriteError(err)
+	}
+	return &p, nil
+}
+
+// Delete removes a promotion; order snapshots keep their copied name and amounts.
+func (r *PromotionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM promotions WHERE promotion_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Promotion not found")
+	}
+	return nil
+}
+
+func mapPromotionWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			return apperr.BadRequest("Promotion scope references an unknown catalog item")
+		case "23514":
+			return apperr.BadRequest("Invalid promotion parameters")
+		}
+	}
+	return apperr.Internal(err)
+}
+
+// CountUsesByUser returns how many non-cancelled orders of userID used each promotion.
+func (r *PromotionRepository) CountUsesByUser(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT op.promotion_id, COUNT(*)::int
+		FROM order_promotions op
+		JOIN orders o ON o.order_id = op.order_id
+		WHERE o.user_id = $1 AND o.status <> 'cancelled' AND op.promotion_id IS NOT NULL
+		GROUP BY op.promotion_id
+	`, userID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	out := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var id uuid.UUID
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out[id] = n
+	}
+	return out, rows.Err()
+}
+
+// GetCode looks up a promo code (case-insensitive input is normalized by the caller).
+func (r *PromotionRepository) GetCode(ctx context.Context, code string) (*model.PromotionCode, error) {
+	var c model.PromotionCode
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT promotion_code_id, promotion_id, code, is_single_use, redeemed_by, redeemed_at, created_at
+		FROM promotion_codes WHERE code = $1
+	`, code).Scan(&c.PromotionCodeID, &c.PromotionID, &c.Code, &c.IsSingleUse, &c.RedeemedBy, &c.RedeemedAt, &c.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &c, nil
+}
+
+func (r *PromotionRepository) ListCodes(ctx context.Context, promotionID uuid.UUID) ([]model.PromotionCode, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT promotion_code_id, promotion_id, code, is_single_use, redeemed_by, redeemed_at, created_at
+		FROM promotion_codes WHERE promotion_id = $1
+		ORDER BY created_at DESC, code
+	`, promotionID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	var out []model.PromotionCode
+	for rows.Next() {
+		var c model.PromotionCode
+		if err := rows.Scan(&c.PromotionCodeID, &c.PromotionID, &c.Code, &c.IsSingleUse, &c.RedeemedBy, &c.RedeemedAt, &c.CreatedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// InsertCodes stores codes for a promotion in one transaction; any duplicate aborts the batch.
+func (r *PromotionRepository) InsertCodes(ctx context.Context, promotionID uuid.UUID, codes []string, singleUse bool) ([]model.PromotionCode, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	out := make([]model.PromotionCode, 0, len(codes))
+	for _, code := range codes {
+		var c model.PromotionCode
+		err := tx.QueryRow(ctx, `
+			INSERT INTO promotion_codes (promotion_id, code, is_single_use)
+			VALUES ($1, $2, $3)
+			RETURNING promotion_code_id, promotion_id, code, is_single_use, redeemed_by, redeemed_at, created_at
+		`, promotionID, code, singleUse).Scan(&c.PromotionCodeID, &c.PromotionID, &c.Code, &c.IsSingleUse, &c.RedeemedBy, &c.RedeemedAt, &c.CreatedAt)
+		if err != nil {
+			if conflict := mapUniqueViolation(err, "Promo code already exists: "+code); conflict != nil {
+				return nil, conflict
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return nil, apperr.NotFoundErr("Promotion not found")
+			}
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// ListByOrderID returns the promotions snapshot stored with an order.
+func (r *PromotionRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]model.AppliedPromotion, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT promotion_id, promotion_code_id, promotion_name, code, discount_type, discount_value, discount_amount
+		FROM order_promotions
+		WHERE order_id = $1
+		ORDER BY created_at, promotion_name
+	`, orderID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+	var out []model.AppliedPromotion
+	for rows.Next() {
+		var a model.AppliedPromotion
+		if err := rows.Scan(&a.PromotionID, &a.PromotionCodeID, &a.Name, &a.Code, &a.DiscountType, &a.DiscountValue, &a.DiscountAmount); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// releasePromotionsTx returns the usage slots and single-use codes of a cancelled order, so the
+// promotion limits agree with the per-user count, which already skips cancelled orders. The order
+// snapshot in order_promotions is kept.
+func releasePromotionsTx(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE promotions p SET uses_count = GREATEST(p.uses_count - op.n, 0)
+		FROM (
+			SELECT promotion_id, COUNT(*)::int AS n
+			FROM order_promotions
+			WHERE order_id = $1 AND promotion_id IS NOT NULL
+			GROUP BY promotion_id
+		) op
+		WHERE p.promotion_id = op.promotion_id
+	`, orderID); err != nil {
+		return apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE promotion_codes pc SET redeemed_by = NULL, redeemed_at = NULL
+		FROM order_promotions op
+		WHERE op.order_id = $1 AND op.promotion_code_id = pc.promotion_code_id AND pc.is_single_use
+	`, orderID); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// redeemPromotionsTx consumes usage limits and codes for applied promotions and writes the
+// order snapshot. Any limit reached concurrently turns into a conflict and aborts the order.
+func redeemPromotionsTx(ctx context.Context, tx pgx.Tx, orderID, userID uuid.UUID, applied []model.AppliedPromotion) error {
+	for _, a := range applied {
+		if a.PromotionID != nil {
+			var perUser *int
+			var name string
+			err := tx.QueryRow(ctx, `
+				UPDATE promotions SET uses_count = uses_count + 1
+				WHERE promotion_id = $1 AND is_active = true
+				  AND (max_uses IS NULL OR uses_count < max_uses)
+				RETURNING max_uses_per_user, name
+			`, *a.PromotionID).Scan(&perUser, &name)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return apperr.Conflict("Promotion is no longer available: " + a.Name)
+				}
+				return apperr.Internal(err)
+			}
+			if perUser != nil {
+				var used int
+				err := tx.QueryRow(ctx, `
+					SELECT COUNT(*)::int
+					FROM order_promotions op
+					JOIN orders o ON o.order_id = op.order_id
+					WHERE o.user_id = $1 AND o.status <> 'cancelled' AND op.promotion_id = $2
+				`, userID, *a.PromotionID).Scan(&used)
+				if err != nil {
+					return apperr.Internal(err)
+				}
+				if used >= *perUser {
+					return apperr.Conflict("Promotion usage limit reached: " + name)
+				}
+			}
+		}
+		if a.PromotionCodeID != nil {
+			tag, err := tx.Exec(ctx, `
+				UPDATE promotion_codes SET redeemed_by = $2, redeemed_at = now()
+				WHERE promotion_code_id = $1 AND (is_single_use = false OR redeemed_at IS NULL)
+			`, *a.PromotionCodeID, userID)
+			if err != nil {
+				return apperr.Internal(err)
+			}
+			if tag.RowsAffected() == 0 {
+				return apperr.Conflict("Promo code has already been used")
+			}
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_promotions (
+				order_id, promotion_id, promotion_code_id, promotion_name, code,
+				discount_type, discount_value, discount_amount
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, orderID, a.PromotionID, a.PromotionCodeID, a.Name, a.Code, a.DiscountType, a.DiscountValue, a.DiscountAmount)
+		if err != nil {
+			return apperr.Internal(err)
+		}
+	}
+	return nil
+}
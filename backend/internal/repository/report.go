@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+type ReportRepository struct {
+	db *database.DB
+}
+
+func NewReportRepository(db *database.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Analytics materialized views, see schema.sql.
+const (
+	ReportViewOrders  = "mv_order_stats_daily"
+	ReportViewFunnel  = "mv_sales_funnel_daily"
+	ReportViewService = "mv_service_stats_daily"
+)
+
+var reportViews = []string{ReportViewOrders, ReportViewFunnel, ReportViewService}
+
+const orderStatSums = `
+	SUM(s.orders_count)::int, SUM(s.orders_value)::float8,
+	SUM(s.paid_count)::int, SUM(s.paid_value)::float8, SUM(s.cancelled_count)::int
+`
+
+// orderStatQueries select one row per group for days in [$1, $2), largest revenue first; the period
+// query takes the date_trunc unit as $3.
+var orderStatQueries = map[string]string{
+	"period": `
+		SELECT date_trunc($3, s.day)::date, NULL::uuid, NULL::text, ` + orderStatSums + `
+		FROM mv_order_stats_daily s
+		WHERE s.day >= $1 AND s.day < $2
+		GROUP BY 1
+		ORDER BY 1`,
+	"brand": `
+		SELECT NULL::date, s.brand_id, b.name, ` + orderStatSums + `
+		FROM mv_order_stats_daily s
+		JOIN brands b ON b.brand_id = s.brand_id
+		WHERE s.day >= $1 AND s.day < $2
+		GROUP BY s.brand_id, b.name
+		ORDER BY 7 DESC, b.name`,
+	"model": `
+		SELECT NULL::date, s.model_id, b.name || ' ' || m.name, ` + orderStatSums + `
+		FROM mv_order_stats_daily s
+		JOIN models m ON m.model_id = s.model_id
+		JOIN brands b ON b.brand_id = s.brand_id
+		WHERE s.day >= $1 AND s.day < $2
+		GROUP BY s.model_id, b.name, m.name
+		ORDER BY 7 DESC, 3`,
+	"manager": `
+		SELECT NULL::date, s.manager_id, NULLIF(TRIM(BOTH FROM u.first_name || ' ' || u.last_name), ''), ` + orderStatSums + `
+		FROM mv_order_stats_daily s
+		LEFT JOIN users u ON u.user_id = s.manager_id
+		WHERE s.day >= $1 AND s.day < $2
+		GROUP BY s.manager_id, u.first_name, u.last_name
+		ORDER BY 7 DESC, 3`,
+}
+
+// OrderStats aggregates orders created on days in [from, to) by groupBy (period, brand, model or
+// manager); period buckets use the date_trunc unit. Unassigned orders form a manager row without key.
+func (r *ReportRepository) OrderStats(ctx context.Context, from, to time.Time, groupBy, period string) ([]model.OrderReportRow, error) {
+	query, ok := orderStatQueries[groupBy]
+	if !ok {
+		return nil, apperr.BadRequest("Unknown report grouping")
+	}
+	args := []any{from, to}
+	if groupBy == "period" {
+		args = append(args, period)
+	}
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.OrderReportRow{}
+	for rows.Next() {
+		var row model.OrderReportRow
+		var label *string
+		if err := rows.Scan(
+			&row.Period, &row.Key, &label,
+			&row.Orders, &row.OrdersValue, &row.PaidOrders, &row.Revenue, &row.CancelledOrders,
+		); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if label != nil {
+			row.Label = *label
+		}
+		out = append(out, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// FunnelCounts sums the funnel stages of configurations created on days in [from, to).
+func (r *ReportRepository) FunnelCounts(ctx context.Context, from, to time.Time) (model.FunnelCounts, error) {
+	var c model.FunnelCounts
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(configurations), 0)::int, COALESCE(SUM(confirmed), 0)::int,
+			COALESCE(SUM(ordered), 0)::int, COALESCE(SUM(paid), 0)::int
+		FROM mv_sales_funnel_daily
+		WHERE day >= $1 AND day < $2
+	`, from, to).Scan(&c.Configurations, &c.Confirmed, &c.Ordered, &c.Paid)
+	if err != nil {
+		return c, apperr.Internal(err)
+	}
+	return c, nil
+}
+
+// ServiceStats returns appointment totals per branch for branch-local days in [from, to) with the
+// branch capacity over the same days: bays times working minutes from the weekly schedule and
+// calendar exceptions, breaks excluded. Inactive branches are listed only when they have
+// appointments in the range.
+func (r *ReportRepository) ServiceStats(ctx context.Context, from, to time.Time, branchID *uuid.UUID) ([]model.BranchServiceStats, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH days AS (
+			SELECT d::date AS day FROM generate_series($1::date, $2::date - 1, interval '1 day') d
+		), working AS (
+			SELECT br.branch_id, COALESCE(SUM(CASE
+				WHEN e.exception_id IS NOT NULL AND e.is_closed THEN 0
+				WHEN e.exception_id IS NOT NULL THEN e.close_minutes - e.open_minutes
+					- COALESCE(e.break_end_minutes - e.break_start_minutes, 0)
+				ELSE COALESCE(w.close_minutes - w.open_minutes
+					- COALESCE(w.break_end_minutes - w.break_start_minutes, 0), 0)
+			END), 0)::int AS minutes
+			FROM branches br
+			CROSS JOIN days
+			LEFT JOIN branch_calendar_exceptions e ON e.branch_id = br.branch_id AND e.day = days.day
+			LEFT JOIN branch_weekly_hours w ON w.branch_id = br.branch_id AND w.weekday = EXTRACT(DOW FROM days.day)
+			GROUP BY br.branch_id
+		), stats AS (
+			SELECT branch_id,
+				SUM(appointments)::int AS appointments, SUM(completed)::int AS completed,
+				SUM(cancelled)::int AS cancelled, SUM(no_show)::int AS no_show,
+				SUM(booked_minutes)::int AS booked_minutes
+			FROM mv_service_stats_daily
+			WHERE day >= $1 AND day < $2
+			GROUP BY branch_id
+		)
+		SELECT br.branch_id, br.name,
+			COALESCE(s.appointments, 0), COALESCE(s.completed, 0), COALESCE(s.cancelled, 0),
+			COALESCE(s.no_show, 0), COALESCE(s.booked_minutes, 0),
+			wk.minutes * br.concurrent_bays
+		FROM branches br
+		JOIN working wk ON wk.branch_id = br.branch_id
+		LEFT JOIN stats s ON s.branch_id = br.branch_id
+		WHERE ($3::uuid IS NULL OR br.branch_id = $3) AND (br.is_active OR s.branch_id IS NOT NULL)
+		ORDER BY br.name
+	`, from, to, branchID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.BranchServiceStats{}
+	for rows.Next() {
+		var s model.BranchServiceStats
+		if err := rows.Scan(
+			&s.BranchID, &s.BranchName, &s.Appointments, &s.Completed, &s.Cancelled,
+			&s.NoShow, &s.BookedMinutes, &s.CapacityMinutes,
+		); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// RefreshedAt returns when the view was last refreshed by Refresh (nil if never).
+func (r *ReportRepository) RefreshedAt(ctx context.Context, view string) (*time.Time, error) {
+	var t *time.Time
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT MAX(refreshed_at) FROM report_refreshes WHERE view_name = $1
+	`, view).Scan(&t)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return t, nil
+}
+
+// Refresh rebuilds all analytics views without blocking readers. Only one instance refreshes at a
+// time; it returns false when another refresh is already running.
+func (r *ReportRepository) Refresh(ctx context.Context) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('report-refresh'))`).Scan(&locked); err != nil {
+		return false, apperr.Internal(err)
+	}
+	if !locked {
+		return false, nil
+	}
+	for _, view := range reportViews {
+		if _, err := tx.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY `+view); err != nil {
+			return false, apperr.Internal(err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO report_refreshes (view_name, refreshed_at) VALUES ($1, now())
+			ON CONFLICT (view_name) DO UPDATE SET refreshed_at = EXCLUDED.refreshed_at
+		`, view); err != nil {
+			return false, apperr.Internal(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, apperr.Internal(err)
+	}
+	return true, nil
+}
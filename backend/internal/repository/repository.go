@@ -11,6 +11,7 @@ type Repository struct {
 	Color               *ColorRepository
 	Option              *OptionRepository
 	Configuration       *ConfigurationRepository
+	ConfigurationShare  *ConfigurationShareRepository
 	Order               *OrderRepository
 	UserCar             *UserCarRepository
 	ServiceType         *ServiceTypeRepository
@@ -21,6 +22,22 @@ type Repository struct {
 	Document            *DocumentRepository
 	OrderStatus         *OrderStatusRepository
 	Role                *RoleRepository
+	Promotion           *PromotionRepository
+	FinanceProduct      *FinanceProductRepository
+	TradeIn             *TradeInRepository
+	Notification        *NotificationRepository
+	Assignment          *AssignmentRepository
+	Payment             *PaymentRepository
+	Delivery            *DeliveryRepository
+	Message             *MessageRepository
+	Report              *ReportRepository
+	BranchSchedule      *BranchScheduleRepository
+	ServiceResource     *ServiceResourceRepository
+	Maintenance         *MaintenanceRepository
+	ServiceHistory      *ServiceHistoryRepository
+	VehicleTransfer     *VehicleTransferRepository
+	Organization        *OrganizationRepository
+	ServiceCampaign     *ServiceCampaignRepository
 }
 
 func New(db *database.DB) *Repository {
@@ -33,6 +50,7 @@ func New(db *database.DB) *Repository {
 		Color:              NewColorRepository(db),
 		Option:             NewOptionRepository(db),
 		Configuration:      NewConfigurationRepository(db),
+		ConfigurationShare: NewConfigurationShareRepository(db),
 		Order:              NewOrderRepository(db),
 		UserCar:            NewUserCarRepository(db),
 		ServiceType:        NewServiceTypeRepository(db),
@@ -43,6 +61,22 @@ func New(db *database.DB) *Repository {
 		Document:           NewDocumentRepository(db),
 		OrderStatus:        NewOrderStatusRepository(db),
 		Role:               NewRoleRepository(db),
+		Promotion:          NewPromotionRepository(db),
+		FinanceProduct:     NewFinanceProductRepository(db),
+		TradeIn:            NewTradeInRepository(db),
+		Notification:       NewNotificationRepository(db),
+		Assignment:         NewAssignmentRepository(db),
+		Payment:            NewPaymentRepository(db),
+		Delivery:           NewDeliveryRepository(db),
+		Message:            NewMessageRepository(db),
+		Report:             NewReportRepository(db),
+		BranchSchedule:     NewBranchScheduleRepository(db),
+		ServiceResource:    NewServiceResourceRepository(db),
+		Maintenance:        NewMaintenanceRepository(db),
+		ServiceHistory:     NewServiceHistoryRepository(db),
+		VehicleTransfer:    NewVehicleTransferRepository(db),
+		Organization:       NewOrganizationRepository(db),
+		ServiceCampaign:    NewServiceCampaignRepository(db),
 	}
 }
 
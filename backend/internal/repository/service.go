@@ -15,6 +15,14 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// serviceTypeDest lists scan targets for the service type columns in select order.
+func serviceTypeDest(st *model.ServiceType) []any {
+	return []any{
+		&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes,
+		&st.IsAvailable, &st.RequiredBaySkills, &st.RequiredTechnicianSkills, &st.CreatedAt,
+	}
+}
+
 type ServiceTypeRepository struct {
 	db *database.DB
 }
@@ -24,7 +32,7 @@ func NewServiceTypeRepository(db *database.DB) *ServiceTypeRepository {
 }
 
 func (r *ServiceTypeRepository) GetAll(ctx context.Context, category *string, isAvailable *bool) ([]model.ServiceType, error) {
-	query := `SELECT service_type_id, name, category, description, price, duration_minutes, is_available, created_at FROM service_types WHERE 1=1`
+	query := `SELECT service_type_id, name, category, description, price, duration_minutes, is_available, required_bay_skills, required_technician_skills, created_at FROM service_types WHERE 1=1`
 	var args []interface{}
 	argPos := 1
 
@@ -51,7 +59,7 @@ func (r *ServiceTypeRepository) GetAll(ctx context.Context, category *string, is
 	var serviceTypes []model.ServiceType
 	for rows.Next() {
 		var st model.ServiceType
-		if err := rows.Scan(&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt); err != nil {
+		if err := rows.Scan(serviceTypeDest(&st)...); err != nil {
 			return nil, fmt.Errorf("failed to scan service type: %w", err)
 		}
 		serviceTypes = append(serviceTypes, st)
@@ -65,7 +73,7 @@ func (r *ServiceTypeRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (
 		return nil, nil
 	}
 	query := `
-		SELECT service_type_id, name, category, description, price, duration_minutes, is_available, created_at
+		SELECT service_type_id, name, category, description, price, duration_minutes, is_available, required_bay_skills, required_technician_skills, created_at
 		FROM service_types WHERE service_type_id = ANY($1) AND is_available = true
 	`
 	rows, err := r.db.Pool.Query(ctx, query, ids)
@@ -77,7 +85,7 @@ func (r *ServiceTypeRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (
 	var out []model.ServiceType
 	for rows.Next() {
 		var st model.ServiceType
-		if err := rows.Scan(&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt); err != nil {
+		if err := rows.Scan(serviceTypeDest(&st)...); err != nil {
 			return nil, fmt.Errorf("failed to scan service type: %w", err)
 		}
 		out = append(out, st)
@@ -86,15 +94,13 @@ func (r *ServiceTypeRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (
 }
 
 // Create inserts a service type row.
-func (r *ServiceTypeRepository) Create(ctx context.Context, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool) (*model.ServiceType, error) {
+func (r *ServiceTypeRepository) Create(ctx context.Context, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool, baySkills, technicianSkills []string) (*model.ServiceType, error) {
 	var st model.ServiceType
 	err := r.db.Pool.QueryRow(ctx, `
-		INSERT INTO service_types (name, category, description, price, duration_minutes, is_available)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING service_type_id, name, category, description, price, duration_minutes, is_available, created_at
-	`, name, category, description, price, durationMinutes, isAvailable).Scan(
-		&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt,
-	)
+		INSERT INTO service_types (name, category, description, price, duration_minutes, is_available, required_bay_skills, required_technician_skills)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING service_type_id, name, category, description, price, duration_minutes, is_available, required_bay_skills, required_technician_skills, created_at
+	`, name, category, description, price, durationMinutes, isAvailable, baySkills, technicianSkills).Scan(serviceTypeDest(&st)...)
 	if err != nil {
 		if conflict := mapUniqueViolation(err, "Service type name already exists"); conflict != nil {
 			return nil, conflict
@@ -105,12 +111,13 @@ func (r *ServiceTypeRepository) Create(ctx context.Context, name, category strin
 }
 
 // Update patches a service type.
-func (r *ServiceTypeRepository) Update(ctx context.Context, id uuid.UUID, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool) error {
+func (r *ServiceTypeRepository) Update(ctx context.Context, id uuid.UUID, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool, baySkills, technicianSkills []string) error {
 	cmd, err := r.db.Pool.Exec(ctx, `
 		UPDATE service_types
-		SET name = $1, category = $2, description = $3, price = $4, duration_minutes = $5, is_available = $6
-		WHERE service_type_id = $7
-	`, name, category, description, price, durationMinutes, isAvailable, id)
+		SET name = $1, category = $2, description = $3, price = $4, duration_minutes = $5, is_available = $6,
+			required_bay_skills = $7, required_technician_skills = $8
+		WHERE service_type_id = $9
+	`, name, category, description, price, durationMinutes, isAvailable, baySkills, technicianSkills, id)
 	if err != nil {
 		if conflict := mapUniqueViolation(err, "Service type name already exists"); conflict != nil {
 			return conflict
@@ -148,7 +155,7 @@ func NewBranchRepository(db *database.DB) *BranchRepository {
 }
 
 func (r *BranchRepository) GetAll(ctx context.Context, isActive *bool) ([]model.Branch, error) {
-	query := `SELECT branch_id, name, address, phone, email, is_active, timezone, workday_start_minutes, workday_end_minutes, slot_step_minutes, concurrent_bays, created_at, updated_at FROM branches`
+	query := `SELECT branch_id, name, address, phone, email, is_active, timezone, workday_start_minutes, workday_end_minutes, slot_step_minutes, concurrent_bays, handover_bays, handover_duration_minutes, created_at, updated_at FROM branches`
 	var args []interface{}
 
 	if isActive != nil {
@@ -168,7 +175,7 @@ func (r *BranchRepository) GetAll(ctx context.Context, isActive *bool) ([]model.
 		var branch model.Branch
 		if err := rows.Scan(&branch.BranchID, &branch.Name, &branch.Address, &branch.Phone, &branch.Email, &branch.IsActive,
 			&branch.Timezone, &branch.WorkdayStartMinutes, &branch.WorkdayEndMinutes, &branch.SlotStepMinutes, &branch.ConcurrentBays,
-			&branch.CreatedAt, &branch.UpdatedAt); err != nil {
+			&branch.HandoverBays, &branch.HandoverDurationMinutes, &branch.CreatedAt, &branch.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan branch: %w", err)
 		}
 		branches = append(branches, branch)
@@ -179,12 +186,12 @@ func (r *BranchRepository) GetAll(ctx context.Context, isActive *bool) ([]model.
 
 func (r *BranchRepository) GetByID(ctx context.Context, branchID uuid.UUID) (*model.Branch, error) {
 	var branch model.Branch
-	query := `SELECT branch_id, name, address, phone, email, is_active, timezone, workday_start_minutes, workday_end_minutes, slot_step_minutes, concurrent_bays, created_at, updated_at FROM branches WHERE branch_id = $1`
+	query := `SELECT branch_id, name, address, phone, email, is_active, timezone, workday_start_minutes, workday_end_minutes, slot_step_minutes, concurrent_bays, handover_bays, handover_duration_minutes, created_at, updated_at FROM branches WHERE branch_id = $1`
 
 	err := r.db.Pool.QueryRow(ctx, query, branchID).Scan(
 		&branch.BranchID, &branch.Name, &branch.Address, &branch.Phone, &branch.Email,
 		&branch.IsActive, &branch.Timezone, &branch.WorkdayStartMinutes, &branch.WorkdayEndMinutes, &branch.SlotStepMinutes, &branch.ConcurrentBays,
-		&branch.CreatedAt, &branch.UpdatedAt,
+		&branch.HandoverBays, &branch.HandoverDurationMinutes, &branch.CreatedAt, &branch.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -197,7 +204,7 @@ func (r *BranchRepository) GetByID(ctx context.Context, branchID uuid.UUID) (*mo
 }
 
 // UpdateBranch updates branch fields used in operations UI.
-func (r *BranchRepository) UpdateBranch(ctx context.Context, id uuid.UUID, name, address *string, phone, email *string, isActive *bool) error {
+func (r *BranchRepository) UpdateBranch(ctx context.Context, id uuid.UUID, name, address *string, phone, email *string, isActive *bool, handoverBays, handoverDuration *int) error {
 	q := `UPDATE branches SET `
 	var sets []string
 	var args []interface{}
@@ -227,6 +234,16 @@ func (r *BranchRepository) UpdateBranch(ctx context.Context, id uuid.UUID, name,
 		args = append(args, *isActive)
 		n++
 	}
+	if handoverBays != nil {
+		sets = append(sets, fmt.Sprintf("handover_bays = $%d", n))
+		args = append(args, *handoverBays)
+		n++
+	}
+	if handoverDuration != nil {
+		sets = append(sets, fmt.Sprintf("handover_duration_minutes = $%d", n))
+		args = append(args, *handoverDuration)
+		n++
+	}
 	if len(sets) == 0 {
 		return nil
 	}
@@ -251,22 +268,58 @@ func NewServiceAppointmentRepository(db *database.DB) *ServiceAppointmentReposit
 	return &ServiceAppointmentRepository{db: db}
 }
 
-func (r *ServiceAppointmentRepository) CountOverlappingScheduled(ctx context.Context, branchID uuid.UUID, windowStart, windowEnd time.Time) (int, error) {
-	var n int
-	err := r.db.Pool.QueryRow(ctx, `
-		SELECT COUNT(*)::int FROM service_appointments
+// ScheduledIntervals returns the [start, end) intervals of scheduled and in-progress appointments
+// at the branch that overlap [from, to), so that availability for a whole range is computed from one query.
+func (r *ServiceAppointmentRepository) ScheduledIntervals(ctx context.Context, branchID uuid.UUID, from, to time.Time) ([]model.BusyInterval, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT appointment_date, appointment_date + make_interval(mins => duration_minutes), bay_id, technician_id
+		FROM service_appointments
 		WHERE branch_id = $1
-		  AND status = 'scheduled'
+		  AND status IN ('scheduled','in_progress')
 		  AND appointment_date < $3
-		  AND (appointment_date + (duration_minutes || ' minutes')::interval) > $2
-	`, branchID, windowStart, windowEnd).Scan(&n)
+		  AND (appointment_date + make_interval(mins => duration_minutes)) > $2
+		ORDER BY appointment_date
+	`, branchID, from, to)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count overlapping appointments: %w", err)
+		return nil, fmt.Errorf("failed to list scheduled appointments: %w", err)
 	}
-	return n, nil
+	defer rows.Close()
+	return scanBusyIntervals(rows, true)
 }
 
-func (r *ServiceAppointmentRepository) Create(ctx context.Context, create model.ServiceAppointmentCreate, concurrentBays int) (*model.ServiceAppointment, error) {
+// allocateInTx loads the open appointments overlapping [from, to) except skipID and lets
+// allocate choose resources; a full slot is a conflict.
+func allocateInTx(ctx context.Context, tx pgx.Tx, branchID, skipID uuid.UUID, from, to time.Time, allocate SlotAllocator) (model.ResourceAssignment, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT appointment_date, appointment_date + make_interval(mins => duration_minutes), bay_id, technician_id
+		FROM service_appointments
+		WHERE branch_id = $1
+		  AND status IN ('scheduled','in_progress')
+		  AND service_appointment_id <> $2
+		  AND appointment_date < $4
+		  AND (appointment_date + make_interval(mins => duration_minutes)) > $3
+	`, branchID, skipID, from, to)
+	if err != nil {
+		return model.ResourceAssignment{}, fmt.Errorf("overlap check: %w", err)
+	}
+	busy, err := scanBusyIntervals(rows, true)
+	rows.Close()
+	if err != nil {
+		return model.ResourceAssignment{}, err
+	}
+	assigned, ok := allocate(busy, from, to)
+	if !ok {
+		return model.ResourceAssignment{}, apperr.Conflict("This time slot is no longer available")
+	}
+	return assigned, nil
+}
+
+// SlotAllocator picks resources for a booking [from, to) given the scheduled appointments that
+// overlap it; ok is false when the slot is full.
+type SlotAllocator func(busy []model.BusyInterval, from, to time.Time) (assignment model.ResourceAssignment, ok bool)
+
+// Create books the appointment under the branch lock with resources chosen by allocate.
+func (r *ServiceAppointmentRepository) Create(ctx context.Context, create model.ServiceAppointmentCreate, allocate SlotAllocator) (*model.ServiceAppointment, error) {
 	if create.DurationMinutes <= 0 {
 		return nil, fmt.Errorf("invalid duration")
 	}
@@ -281,32 +334,31 @@ func (r *ServiceAppointmentRepository) Create(ctx context.Context, create model.
 	}
 
 	winEnd := create.AppointmentDate.Add(time.Duration(create.DurationMinutes) * time.Minute)
-	var overlap int
-	err = tx.QueryRow(ctx, `
-		SELECT COUNT(*)::int FROM service_appointments
-		WHERE branch_id = $1
-		  AND status = 'scheduled'
-		  AND appointment_date < $3
-		  AND (appointment_date + (duration_minutes || ' minutes')::interval) > $2
-	`, create.BranchID, create.AppointmentDate, winEnd).Scan(&overlap)
+	assigned, err := allocateInTx(ctx, tx, create.BranchID, uuid.Nil, create.AppointmentDate, winEnd, allocate)
 	if err != nil {
-		return nil, fmt.Errorf("overlap check: %w", err)
-	}
-	if overlap >= concurrentBays {
-		return nil, apperr.Conflict("This time slot is no longer available")
+		return nil, err
 	}
 
+	source := create.Source
+	if source == "" {
+		source = "online"
+	}
 	var appointment model.ServiceAppointment
 	query := `
-		INSERT INTO service_appointments (user_car_id, branch_id, appointment_date, duration_minutes, status, description)
-		VALUES ($1, $2, $3, $4, 'scheduled', $5)
-		RETURNING service_appointment_id, user_car_id, branch_id, manager_id, appointment_date, duration_minutes, status, description, created_at, updated_at
+		INSERT INTO service_appointments (user_car_id, branch_id, appointment_date, duration_minutes, status, description, bay_id, technician_id,
+			source, created_by, checked_in_at, odometer_km)
+		VALUES ($1, $2, $3, $4, CASE WHEN $10 THEN 'in_progress' ELSE 'scheduled' END, $5, $6, $7,
+			$8, $9, CASE WHEN $10 THEN now() END, $11)
+		RETURNING service_appointment_id, user_car_id, branch_id, manager_id, appointment_date, duration_minutes, status, description,
+			bay_id, technician_id, checked_in_at, odometer_km, source, created_by, created_at, updated_at
 	`
 
-	err = tx.QueryRow(ctx, query, create.UserCarID, create.BranchID, create.AppointmentDate, create.DurationMinutes, create.Description).Scan(
+	err = tx.QueryRow(ctx, query, create.UserCarID, create.BranchID, create.AppointmentDate, create.DurationMinutes, create.Description,
+		assigned.BayID, assigned.TechnicianID, source, create.CreatedBy, create.CheckIn, create.OdometerKm).Scan(
 		&appointment.ServiceAppointmentID, &appointment.UserCarID, &appointment.BranchID,
 		&appointment.ManagerID, &appointment.AppointmentDate, &appointment.DurationMinutes, &appointment.Status,
-		&appointment.Description, &appointment.CreatedAt, &appointment.UpdatedAt,
+		&appointment.Description, &appointment.BayID, &appointment.TechnicianID, &appointment.CheckedInAt, &appointment.OdometerKm,
+		&appointment.Source, &appointment.CreatedBy, &appointment.CreatedAt, &appointment.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create appointment: %w", err)
@@ -335,7 +387,9 @@ func (r *ServiceAppointmentRepository) GetByID(ctx context.Context, appointmentI
 	query := `
 		SELECT 
 			sa.service_appointment_id, sa.user_car_id, sa.branch_id, sa.manager_id,
-			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.created_at, sa.updated_at,
+			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.bay_id, sa.technician_id,
+			sa.checked_in_at, sa.odometer_km, sa.work_summary, sa.completed_at, sa.source, sa.created_by,
+			sa.created_at, sa.updated_at, bay.name, tech.name,
 			uc.user_id,
 			uc.vin as user_car_vin, b.name as branch_name, b.address as branch_address,
 			u.first_name || ' ' || u.last_name as manager_name
@@ -343,13 +397,18 @@ func (r *ServiceAppointmentRepository) GetByID(ctx context.Context, appointmentI
 		JOIN user_cars uc ON sa.user_car_id = uc.user_car_id
 		JOIN branches b ON sa.branch_id = b.branch_id
 		LEFT JOIN users u ON sa.manager_id = u.user_id
+		LEFT JOIN service_resources bay ON bay.resource_id = sa.bay_id
+		LEFT JOIN service_resources tech ON tech.resource_id = sa.technician_id
 		WHERE sa.service_appointment_id = $1
 	`
 
 	err := r.db.Pool.QueryRow(ctx, query, appointmentID).Scan(
 		&appointment.ServiceAppointmentID, &appointment.UserCarID, &appointment.BranchID,
 		&appointment.ManagerID, &appointment.AppointmentDate, &appointment.DurationMinutes, &appointment.Status,
-		&appointment.Description, &appointment.CreatedAt, &appointment.UpdatedAt,
+		&appointment.Description, &appointment.BayID, &appointment.TechnicianID,
+		&appointment.CheckedInAt, &appointment.OdometerKm, &appointment.WorkSummary, &appointment.CompletedAt,
+		&appointment.Source, &appointment.CreatedBy,
+		&appointment.CreatedAt, &appointment.UpdatedAt, &appointment.BayName, &appointment.TechnicianName,
 		&appointment.OwnerUserID,
 		&appointment.UserCarVIN, &appointment.BranchName, &appointment.BranchAddress,
 		&appointment.ManagerName,
@@ -363,7 +422,7 @@ func (r *ServiceAppointmentRepository) GetByID(ctx context.Context, appointmentI
 
 	// Get service types
 	query = `
-		SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.created_at
+		SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.required_bay_skills, st.required_technician_skills, st.created_at
 		FROM service_types st
 		JOIN service_appointment_types sat ON st.service_type_id = sat.service_type_id
 		WHERE sat.service_appointment_id = $1
@@ -376,11 +435,17 @@ func (r *ServiceAppointmentRepository) GetByID(ctx context.Context, appointmentI
 
 	for rows.Next() {
 		var st model.ServiceType
-		if err := rows.Scan(&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt); err != nil {
+		if err := rows.Scan(serviceTypeDest(&st)...); err != nil {
 			return nil, fmt.Errorf("failed to scan service type: %w", err)
 		}
 		appointment.ServiceTypes = append(appointment.ServiceTypes, st)
 	}
+	rows.Close()
+
+	appointment.WorkLines, err = r.WorkLines(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &appointment, nil
 }
@@ -389,13 +454,17 @@ func (r *ServiceAppointmentRepository) GetByUserID(ctx context.Context, userID u
 	query := `
 		SELECT 
 			sa.service_appointment_id, sa.user_car_id, sa.branch_id, sa.manager_id,
-			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.created_at, sa.updated_at,
+			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.bay_id, sa.technician_id,
+			sa.checked_in_at, sa.odometer_km, sa.work_summary, sa.completed_at, sa.source, sa.created_by,
+			sa.created_at, sa.updated_at, bay.name, tech.name,
 			uc.vin as user_car_vin, b.name as branch_name, b.address as branch_address,
 			u.first_name || ' ' || u.last_name as manager_name
 		FROM service_appointments sa
 		JOIN user_cars uc ON sa.user_car_id = uc.user_car_id
 		JOIN branches b ON sa.branch_id = b.branch_id
 		LEFT JOIN users u ON sa.manager_id = u.user_id
+		LEFT JOIN service_resources bay ON bay.resource_id = sa.bay_id
+		LEFT JOIN service_resources tech ON tech.resource_id = sa.technician_id
 		WHERE uc.user_id = $1
 		ORDER BY sa.appointment_date DESC
 	`
@@ -412,7 +481,10 @@ func (r *ServiceAppointmentRepository) GetByUserID(ctx context.Context, userID u
 		if err := rows.Scan(
 			&appointment.ServiceAppointmentID, &appointment.UserCarID, &appointment.BranchID,
 			&appointment.ManagerID, &appointment.AppointmentDate, &appointment.DurationMinutes, &appointment.Status,
-			&appointment.Description, &appointment.CreatedAt, &appointment.UpdatedAt,
+			&appointment.Description, &appointment.BayID, &appointment.TechnicianID,
+			&appointment.CheckedInAt, &appointment.OdometerKm, &appointment.WorkSummary, &appointment.CompletedAt,
+			&appointment.Source, &appointment.CreatedBy,
+			&appointment.CreatedAt, &appointment.UpdatedAt, &appointment.BayName, &appointment.TechnicianName,
 			&appointment.UserCarVIN, &appointment.BranchName, &appointment.BranchAddress,
 			&appointment.ManagerName,
 		); err != nil {
@@ -422,7 +494,7 @@ func (r *ServiceAppointmentRepository) GetByUserID(ctx context.Context, userID u
 
 		// Get service types for each appointment
 		query = `
-			SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.created_at
+			SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.required_bay_skills, st.required_technician_skills, st.created_at
 			FROM service_types st
 			JOIN service_appointment_types sat ON st.service_type_id = sat.service_type_id
 			WHERE sat.service_appointment_id = $1
@@ -431,7 +503,7 @@ func (r *ServiceAppointmentRepository) GetByUserID(ctx context.Context, userID u
 		if err == nil {
 			for rows2.Next() {
 				var st model.ServiceType
-				if err := rows2.Scan(&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt); err == nil {
+				if err := rows2.Scan(serviceTypeDest(&st)...); err == nil {
 					appointment.ServiceTypes = append(appointment.ServiceTypes, st)
 				}
 			}
@@ -444,26 +516,73 @@ func (r *ServiceAppointmentRepository) GetByUserID(ctx context.Context, userID u
 	return appointments, nil
 }
 
-// ListAllWithDetails returns all appointments with details (staff / admin).
-func (r *ServiceAppointmentRepository) ListAllWithDetails(ctx context.Context) ([]model.ServiceAppointmentWithDetails, error) {
+// Search returns the appointments matching the staff filters, latest first (staff / admin).
+func (r *ServiceAppointmentRepository) Search(ctx context.Context, f model.AppointmentFilters) ([]model.ServiceAppointmentWithDetails, error) {
+	var conditions []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if f.BranchID != nil {
+		add("sa.branch_id = $%d", *f.BranchID)
+	}
+	if len(f.Status) > 0 {
+		add("sa.status = ANY($%d)", f.Status)
+	}
+	if f.From != nil {
+		add("sa.appointment_date >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("sa.appointment_date < $%d", *f.To)
+	}
+	if f.OrganizationID != nil {
+		add("uc.organization_id = $%d", *f.OrganizationID)
+	}
+	if f.ServiceCategory != "" {
+		add(`EXISTS (
+			SELECT 1 FROM service_appointment_types sat
+			JOIN service_types st ON st.service_type_id = sat.service_type_id
+			WHERE sat.service_appointment_id = sa.service_appointment_id AND st.category = $%d
+		)`, f.ServiceCategory)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.listWithDetails(ctx, where, args...)
+}
+
+// ListOpenByManager returns scheduled and in-progress appointments assigned to the manager.
+func (r *ServiceAppointmentRepository) ListOpenByManager(ctx context.Context, managerID uuid.UUID) ([]model.ServiceAppointmentWithDetails, error) {
+	return r.listWithDetails(ctx, "WHERE sa.manager_id = $1 AND sa.status IN ('scheduled','in_progress')", managerID)
+}
+
+func (r *ServiceAppointmentRepository) listWithDetails(ctx context.Context, where string, args ...any) ([]model.ServiceAppointmentWithDetails, error) {
 	query := `
 		SELECT 
 			sa.service_appointment_id, sa.user_car_id, sa.branch_id, sa.manager_id,
-			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.created_at, sa.updated_at,
+			sa.appointment_date, sa.duration_minutes, sa.status, sa.description, sa.bay_id, sa.technician_id,
+			sa.checked_in_at, sa.odometer_km, sa.work_summary, sa.completed_at, sa.source, sa.created_by,
+			sa.created_at, sa.updated_at, bay.name, tech.name,
 			uc.vin as user_car_vin, b.name as branch_name, b.address as branch_address,
 			u.first_name || ' ' || u.last_name as manager_name,
 			uc.user_id,
 			owner.email::text,
-			TRIM(BOTH FROM owner.first_name || ' ' || owner.last_name)::text
+			TRIM(BOTH FROM owner.first_name || ' ' || owner.last_name)::text,
+			owner.phone
 		FROM service_appointments sa
 		JOIN user_cars uc ON sa.user_car_id = uc.user_car_id
 		JOIN users owner ON uc.user_id = owner.user_id
 		JOIN branches b ON sa.branch_id = b.branch_id
 		LEFT JOIN users u ON sa.manager_id = u.user_id
+		LEFT JOIN service_resources bay ON bay.resource_id = sa.bay_id
+		LEFT JOIN service_resources tech ON tech.resource_id = sa.technician_id
+		` + where + `
 		ORDER BY sa.appointment_date DESC
 	`
 
-	rows, err := r.db.Pool.Query(ctx, query)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get appointments: %w", err)
 	}
@@ -475,17 +594,21 @@ func (r *ServiceAppointmentRepository) ListAllWithDetails(ctx context.Context) (
 		if err := rows.Scan(
 			&appointment.ServiceAppointmentID, &appointment.UserCarID, &appointment.BranchID,
 			&appointment.ManagerID, &appointment.AppointmentDate, &appointment.DurationMinutes, &appointment.Status,
-			&appointment.Description, &appointment.CreatedAt, &appointment.UpdatedAt,
+			&appointment.Description, &appointment.BayID, &appointment.TechnicianID,
+			&appointment.CheckedInAt, &appointment.OdometerKm, &appointment.WorkSummary, &appointment.CompletedAt,
+			&appointment.Source, &appointment.CreatedBy,
+			&appointment.CreatedAt, &appointment.UpdatedAt, &appointment.BayName, &appointment.TechnicianName,
 			&appointment.UserCarVIN, &appointment.BranchName, &appointment.BranchAddress,
 			&appointment.ManagerName,
 			&appointment.OwnerUserID,
 			&appointment.OwnerEmail, &appointment.OwnerName,
+			&appointment.OwnerPhone,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan appointment: %w", err)
 		}
 
 		q2 := `
-			SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.created_at
+			SELECT st.service_type_id, st.name, st.category, st.description, st.price, st.duration_minutes, st.is_available, st.required_bay_skills, st.required_technician_skills, st.created_at
 			FROM service_types st
 			JOIN service_appointment_types sat ON st.service_type_id = sat.service_type_id
 			WHERE sat.service_appointment_id = $1
@@ -494,7 +617,7 @@ func (r *ServiceAppointmentRepository) ListAllWithDetails(ctx context.Context) (
 		if err == nil {
 			for rows2.Next() {
 				var st model.ServiceType
-				if err := rows2.Scan(&st.ServiceTypeID, &st.Name, &st.Category, &st.Description, &st.Price, &st.DurationMinutes, &st.IsAvailable, &st.CreatedAt); err == nil {
+				if err := rows2.Scan(serviceTypeDest(&st)...); err == nil {
 					appointment.ServiceTypes = append(appointment.ServiceTypes, st)
 				}
 			}
@@ -528,8 +651,9 @@ func (r *ServiceAppointmentRepository) UpdateStatusIfCurrent(ctx context.Context
 	return tag.RowsAffected() > 0, nil
 }
 
-// RescheduleOwned moves a scheduled appointment to newStart after advisory lock and overlap check (excluding this row).
-func (r *ServiceAppointmentRepository) RescheduleOwned(ctx context.Context, appointmentID, ownerUserID, branchID uuid.UUID, newStart time.Time, durationMin, concurrentBays int) error {
+// RescheduleOwned moves a scheduled appointment to newStart under the branch lock, re-allocating its
+// resources with the appointment itself left out of the busy intervals.
+func (r *ServiceAppointmentRepository) RescheduleOwned(ctx context.Context, appointmentID, ownerUserID, branchID uuid.UUID, newStart time.Time, durationMin int, allocate SlotAllocator) error {
 	if durationMin <= 0 {
 		return fmt.Errorf("invalid duration")
 	}
@@ -560,28 +684,17 @@ func (r *ServiceAppointmentRepository) RescheduleOwned(ctx context.Context, appo
 	}
 
 	newEnd := newStart.Add(time.Duration(durationMin) * time.Minute)
-	var overlap int
-	err = tx.QueryRow(ctx, `
-		SELECT COUNT(*)::int FROM service_appointments
-		WHERE branch_id = $1
-		  AND status = 'scheduled'
-		  AND service_appointment_id <> $2
-		  AND appointment_date < $4
-		  AND (appointment_date + (duration_minutes || ' minutes')::interval) > $3
-	`, branchID, appointmentID, newStart, newEnd).Scan(&overlap)
+	assigned, err := allocateInTx(ctx, tx, branchID, appointmentID, newStart, newEnd, allocate)
 	if err != nil {
-		return fmt.Errorf("overlap check: %w", err)
-	}
-	if overlap >= concurrentBays {
-		return apperr.Conflict("This time slot is no longer available")
+		return err
 	}
 
 	tag, err := tx.Exec(ctx, `
 		UPDATE service_appointments
-		SET appointment_date = $1, updated_at = now()
+		SET appointment_date = $1, bay_id = $3, technician_id = $4, updated_at = now()
 		WHERE service_appointment_id = $2
 		  AND status = 'scheduled'
-	`, newStart, appointmentID)
+	`, newStart, appointmentID, assigned.BayID, assigned.TechnicianID)
 	if err != nil {
 		return fmt.Errorf("failed to reschedule: %w", err)
 	}
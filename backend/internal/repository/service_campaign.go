@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ServiceCampaignRepository stores recall and service campaigns and the garage cars they affect.
+type ServiceCampaignRepository struct {
+	db *database.DB
+}
+
+func NewServiceCampaignRepository(db *database.DB) *ServiceCampaignRepository {
+	return &ServiceCampaignRepository{db: db}
+}
+
+// campaignCriteria matches car uc (with trim t) against campaign c; unset criteria match any car.
+const campaignCriteria = `
+	(c.generation_id IS NULL OR t.generation_id = c.generation_id)
+	AND (c.trim_id IS NULL OR uc.trim_id = c.trim_id)
+	AND (c.year_from IS NULL OR uc.year >= c.year_from)
+	AND (c.year_to IS NULL OR uc.year <= c.year_to)
+	AND (c.vin_pattern IS NULL OR uc.vin LIKE translate(c.vin_pattern, '?*', '_%'))
+	AND (c.vin_from IS NULL OR uc.vin BETWEEN c.vin_from AND c.vin_to)
+`
+
+const serviceCampaignSelect = `
+	SELECT c.campaign_id, c.code, c.kind, c.title, c.description, c.generation_id, c.trim_id,
+		c.year_from, c.year_to, c.vin_pattern, c.vin_from, c.vin_to, c.is_active,
+		ARRAY(SELECT service_type_id FROM service_campaign_service_types WHERE campaign_id = c.campaign_id ORDER BY service_type_id),
+		(SELECT COUNT(*) FROM service_campaign_vehicles v WHERE v.campaign_id = c.campaign_id),
+		(SELECT COUNT(*) FROM service_campaign_vehicles v WHERE v.campaign_id = c.campaign_id AND v.status = 'done'),
+		c.created_by, c.created_at, c.updated_at
+	FROM service_campaigns c
+`
+
+func serviceCampaignDest(c *model.ServiceCampaign) []any {
+	return []any{
+		&c.CampaignID, &c.Code, &c.Kind, &c.Title, &c.Description, &c.GenerationID, &c.TrimID,
+		&c.YearFrom, &c.YearTo, &c.VINPattern, &c.VINFrom, &c.VINTo, &c.IsActive,
+		&c.ServiceTypeIDs, &c.AffectedCount, &c.DoneCount,
+		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt,
+	}
+}
+
+// List returns campaigns, newest first, optionally only active or closed ones.
+func (r *ServiceCampaignRepository) List(ctx context.Context, isActive *bool) ([]model.ServiceCampaign, error) {
+	rows, err := r.db.Pool.Query(ctx, serviceCampaignSelect+`
+		WHERE ($1::boolean IS NULL OR c.is_active = $1)
+		ORDER BY c.created_at DESC
+	`, isActive)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceCampaign{}
+	for rows.Next() {
+		var c model.ServiceCampaign
+		if err := rows.Scan(serviceCampaignDest(&c)...); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+func (r *ServiceCampaignRepository) Get(ctx context.Context, id uuid.UUID) (*model.ServiceCampaign, error) {
+	var c model.ServiceCampaign
+	if err := r.db.Pool.QueryRow(ctx, serviceCampaignSelect+` WHERE c.campaign_id = $1`, id).Scan(serviceCampaignDest(&c)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Campaign not found")
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &c, nil
+}
+
+// Create inserts a campaign with its service types.
+func (r *ServiceCampaignRepository) Create(ctx context.Context, in model.ServiceCampaignInput, createdBy uuid.UUID) (uuid.UUID, error) {
+	active := true
+	if in.IsActive != nil {
+		active = *in.IsActive
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO service_campaigns (code, kind, title, description, generation_id, trim_id,
+			year_from, year_to, vin_pattern, vin_from, vin_to, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING campaign_id
+	`, in.Code, in.Kind, in.Title, in.Description, in.GenerationID, in.TrimID,
+		in.YearFrom, in.YearTo, in.VINPattern, in.VINFrom, in.VINTo, active, createdBy).Scan(&id); err != nil {
+		return uuid.Nil, mapServiceCampaignWriteError(err)
+	}
+	if err := replaceCampaignServiceTypes(ctx, tx, id, in.ServiceTypeIDs); err != nil {
+		return uuid.Nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	return id, nil
+}
+
+// Update replaces the campaign fields and service types. Open cars that no longer meet the
+// criteria are dropped; cars with the work done stay. A nil IsActive keeps the flag.
+func (r *ServiceCampaignRepository) Update(ctx context.Context, id uuid.UUID, in model.ServiceCampaignInput) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE service_campaigns
+		SET code = $2, kind = $3, title = $4, description = $5, generation_id = $6, trim_id = $7,
+			year_from = $8, year_to = $9, vin_pattern = $10, vin_from = $11, vin_to = $12,
+			is_active = COALESCE($13, is_active)
+		WHERE campaign_id = $1
+	`, id, in.Code, in.Kind, in.Title, in.Description, in.GenerationID, in.TrimID,
+		in.YearFrom, in.YearTo, in.VINPattern, in.VINFrom, in.VINTo, in.IsActive)
+	if err != nil {
+		return mapServiceCampaignWriteError(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Campaign not found")
+	}
+	if err := replaceCampaignServiceTypes(ctx, tx, id, in.ServiceTypeIDs); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM service_campaign_vehicles v
+		WHERE v.campaign_id = $1 AND v.status = 'open' AND NOT EXISTS (
+			SELECT 1
+			FROM service_campaigns c
+			JOIN user_cars uc ON uc.user_car_id = v.user_car_id
+			JOIN trims t ON t.trim_id = uc.trim_id
+			WHERE c.campaign_id = v.campaign_id AND `+campaignCriteria+`
+		)
+	`, id); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// Delete removes a campaign together with its list of affected cars.
+func (r *ServiceCampaignRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM service_campaigns WHERE campaign_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Campaign not found")
+	}
+	return nil
+}
+
+func replaceCampaignServiceTypes(ctx context.Context, tx pgx.Tx, campaignID uuid.UUID, ids []uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM service_campaign_service_types WHERE campaign_id = $1`, campaignID); err != nil {
+		return apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO service_campaign_service_types (campaign_id, service_type_id)
+		SELECT $1, unnest($2::uuid[])
+	`, campaignID, ids); err != nil {
+		return mapServiceCampaignWriteError(err)
+	}
+	return nil
+}
+
+func mapServiceCampaignWriteError(err error) error {
+	if mapped := mapUniqueViolation(err, "A campaign with this code already exists"); mapped != nil {
+		return mapped
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			return apperr.BadRequest("Unknown generation, trim or service type")
+		case "23514":
+			return apperr.BadRequest("Invalid campaign parameters")
+		}
+	}
+	return apperr.Internal(err)
+}
+
+// Match adds the garage cars meeting the criteria of active campaigns (one campaign when
+// campaignID is set) and returns the cars added by this run. Cars already on a campaign are
+// skipped, so runs are idempotent.
+func (r *ServiceCampaignRepository) Match(ctx context.Context, campaignID *uuid.UUID) ([]model.ServiceCampaignMatch, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH created AS (
+			INSERT INTO service_campaign_vehicles (campaign_id, user_car_id)
+			SELECT c.campaign_id, uc.user_car_id
+			FROM service_campaigns c
+			CROSS JOIN user_cars uc
+			JOIN trims t ON t.trim_id = uc.trim_id
+			WHERE c.is_active AND ($1::uuid IS NULL OR c.campaign_id = $1) AND `+campaignCriteria+`
+			ON CONFLICT (campaign_id, user_car_id) DO NOTHING
+			RETURNING campaign_id, user_car_id
+		)
+		SELECT c.campaign_id, c.kind, c.title, uc.user_car_id, uc.user_id, b.name || ' ' || m.name, uc.vin
+		FROM created cr
+		JOIN service_campaigns c ON c.campaign_id = cr.campaign_id
+		JOIN user_cars uc ON uc.user_car_id = cr.user_car_id
+		JOIN trims t ON t.trim_id = uc.trim_id
+		JOIN generations g ON g.generation_id = t.generation_id
+		JOIN models m ON m.model_id = g.model_id
+		JOIN brands b ON b.brand_id = m.brand_id
+		ORDER BY c.campaign_id, uc.vin
+	`, campaignID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceCampaignMatch{}
+	for rows.Next() {
+		var m model.ServiceCampaignMatch
+		if err := rows.Scan(&m.CampaignID, &m.CampaignKind, &m.CampaignTitle, &m.UserCarID, &m.UserID, &m.CarTitle, &m.VIN); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// ListVehicles returns the cars affected by a campaign with their owners, open ones first.
+func (r *ServiceCampaignRepository) ListVehicles(ctx context.Context, campaignID uuid.UUID, f model.ServiceCampaignVehicleFilters) ([]model.ServiceCampaignVehicle, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT uc.user_car_id, uc.vin, b.name || ' ' || m.name, uc.year,
+			u.user_id, u.first_name || ' ' || u.last_name, u.email,
+			v.status, v.service_appointment_id, v.created_at, v.completed_at
+		FROM service_campaign_vehicles v
+		JOIN user_cars uc ON uc.user_car_id = v.user_car_id
+		JOIN users u ON u.user_id = uc.user_id
+		JOIN trims t ON t.trim_id = uc.trim_id
+		JOIN generations g ON g.generation_id = t.generation_id
+		JOIN models m ON m.model_id = g.model_id
+		JOIN brands b ON b.brand_id = m.brand_id
+		WHERE v.campaign_id = $1 AND ($2 = '' OR v.status = $2)
+		ORDER BY v.status = 'done', uc.vin
+	`, campaignID, f.Status)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceCampaignVehicle{}
+	for rows.Next() {
+		var v model.ServiceCampaignVehicle
+		if err := rows.Scan(&v.UserCarID, &v.VIN, &v.CarTitle, &v.Year,
+			&v.OwnerUserID, &v.OwnerName, &v.OwnerEmail,
+			&v.Status, &v.ServiceAppointmentID, &v.CreatedAt, &v.CompletedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// ListForCar returns the campaigns affecting a car: open ones of active campaigns and every
+// campaign whose work is done, open first.
+func (r *ServiceCampaignRepository) ListForCar(ctx context.Context, userCarID uuid.UUID) ([]model.CarCampaign, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT c.campaign_id, c.code, c.kind, c.title, c.description,
+			ARRAY(SELECT service_type_id FROM service_campaign_service_types WHERE campaign_id = c.campaign_id ORDER BY service_type_id),
+			v.status, v.service_appointment_id, v.created_at, v.completed_at
+		FROM service_campaign_vehicles v
+		JOIN service_campaigns c ON c.campaign_id = v.campaign_id
+		WHERE v.user_car_id = $1 AND (c.is_active OR v.status = 'done')
+		ORDER BY v.status = 'done', c.kind = 'service', v.created_at DESC
+	`, userCarID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.CarCampaign{}
+	for rows.Next() {
+		var c model.CarCampaign
+		if err := rows.Scan(&c.CampaignID, &c.Code, &c.Kind, &c.Title, &c.Description,
+			&c.ServiceTypeIDs, &c.Status, &c.ServiceAppointmentID, &c.CreatedAt, &c.CompletedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// CompleteVehicle marks the campaign work on a car done by a completed appointment of that car.
+func (r *ServiceCampaignRepository) CompleteVehicle(ctx context.Context, campaignID, userCarID, appointmentID uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx, `
+		SELECT status FROM service_campaign_vehicles WHERE campaign_id = $1 AND user_car_id = $2 FOR UPDATE
+	`, campaignID, userCarID).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return apperr.Internal(err)
+	}
+	if status == model.CampaignVehicleDone {
+		return apperr.Conflict("campaign work on this car is already done")
+	}
+	var apptCarID uuid.UUID
+	var apptStatus string
+	var completedAt *time.Time
+	if err := tx.QueryRow(ctx, `
+		SELECT user_car_id, status, completed_at FROM service_appointments WHERE service_appointment_id = $1
+	`, appointmentID).Scan(&apptCarID, &apptStatus, &completedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.BadRequest("appointment not found")
+		}
+		return apperr.Internal(err)
+	}
+	if apptCarID != userCarID {
+		return apperr.BadRequest("the appointment is for another car")
+	}
+	if apptStatus != "completed" {
+		return apperr.BadRequest("the appointment is not completed")
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE service_campaign_vehicles
+		SET status = 'done', service_appointment_id = $3, completed_at = COALESCE($4, now())
+		WHERE campaign_id = $1 AND user_car_id = $2
+	`, campaignID, userCarID, appointmentID, completedAt); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// completeCampaignWork marks open campaigns of the car done when the appointment included any of
+// the campaign services.
+func completeCampaignWork(ctx context.Context, tx pgx.Tx, appointmentID, userCarID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE service_campaign_vehicles v
+		SET status = 'done', service_appointment_id = $1, completed_at = now()
+		WHERE v.user_car_id = $2 AND v.status = 'open' AND EXISTS (
+			SELECT 1
+			FROM service_campaign_service_types cst
+			JOIN service_appointment_types sat ON sat.service_type_id = cst.service_type_id
+			WHERE cst.campaign_id = v.campaign_id AND sat.service_appointment_id = $1
+		)
+	`, appointmentID, userCarID); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
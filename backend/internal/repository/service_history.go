@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ServiceHistoryRepository reads the per-VIN service book. Records are written only when an
+// appointment is completed and are never changed afterwards.
+type ServiceHistoryRepository struct {
+	db *database.DB
+}
+
+func NewServiceHistoryRepository(db *database.DB) *ServiceHistoryRepository {
+	return &ServiceHistoryRepository{db: db}
+}
+
+// recordServiceHistory snapshots a completed appointment into the service book of its car's VIN,
+// with the documents already attached to it. Like the service act, it lists the work order lines,
+// or the booked services at their list price when there are none.
+func recordServiceHistory(ctx context.Context, tx pgx.Tx, appointmentID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO vehicle_service_records (
+			vin, service_appointment_id, branch_id, branch_name, vehicle_title, vehicle_year,
+			performed_at, odometer_km, work_summary, services, lines, total, organization_id
+		)
+		SELECT uc.vin, sa.service_appointment_id, sa.branch_id, br.name,
+			concat_ws(' ', b.name, m.name, g.name, t.name), uc.year,
+			COALESCE(sa.completed_at, now()), sa.odometer_km, sa.work_summary,
+			ARRAY(
+				SELECT st.name
+				FROM service_appointment_types sat
+				JOIN service_types st ON st.service_type_id = sat.service_type_id
+				WHERE sat.service_appointment_id = sa.service_appointment_id
+				ORDER BY st.name
+			),
+			COALESCE(wl.lines, svc.lines, '[]'::jsonb), COALESCE(wl.total, svc.total, 0), uc.organization_id
+		FROM service_appointments sa
+		JOIN user_cars uc ON uc.user_car_id = sa.user_car_id
+		JOIN trims t ON t.trim_id = uc.trim_id
+		JOIN generations g ON g.generation_id = t.generation_id
+		JOIN models m ON m.model_id = g.model_id
+		JOIN brands b ON b.brand_id = m.brand_id
+		JOIN branches br ON br.branch_id = sa.branch_id
+		LEFT JOIN LATERAL (
+			SELECT
+				jsonb_agg(jsonb_build_object(
+					'kind', l.kind, 'description', l.description, 'part_number', l.part_number,
+					'quantity', l.quantity, 'unit_price', l.unit_price,
+					'amount', round(l.quantity * l.unit_price, 2)
+				) ORDER BY l.position) AS lines,
+				SUM(round(l.quantity * l.unit_price, 2)) AS total
+			FROM service_work_lines l
+			WHERE l.service_appointment_id = sa.service_appointment_id
+			HAVING COUNT(*) > 0
+		) wl ON true
+		LEFT JOIN LATERAL (
+			SELECT
+				jsonb_agg(jsonb_build_object(
+					'kind', 'labour', 'description', st.name, 'part_number', NULL,
+					'quantity', 1, 'unit_price', st.price, 'amount', st.price
+				) ORDER BY st.name) AS lines,
+				SUM(st.price) AS total
+			FROM service_appointment_types sat
+			JOIN service_types st ON st.service_type_id = sat.service_type_id
+			WHERE sat.service_appointment_id = sa.service_appointment_id
+			HAVING COUNT(*) > 0
+		) svc ON true
+		WHERE sa.service_appointment_id = $1
+	`, appointmentID); err != nil {
+		return apperr.Internal(err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO vehicle_service_documents (record_id, document_id, document_type, file_path, file_name, file_size, mime_type, created_at)
+		SELECT r.record_id, d.document_id, d.document_type, d.file_path, d.file_name, d.file_size, d.mime_type, d.created_at
+		FROM documents d
+		JOIN vehicle_service_records r ON r.service_appointment_id = d.service_appointment_id
+		WHERE d.service_appointment_id = $1
+	`, appointmentID); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// ListByVIN returns the service book records of a VIN, oldest first, with their documents.
+func (r *ServiceHistoryRepository) ListByVIN(ctx context.Context, vin string) ([]model.ServiceHistoryRecord, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT record_id, vin, service_appointment_id, branch_id, branch_name, vehicle_title, vehicle_year,
+			performed_at, odometer_km, work_summary, services, lines, total, created_at
+		FROM vehicle_service_records
+		WHERE vin = $1
+		ORDER BY performed_at, created_at
+	`, vin)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceHistoryRecord{}
+	index := map[uuid.UUID]int{}
+	for rows.Next() {
+		var rec model.ServiceHistoryRecord
+		if err := rows.Scan(
+			&rec.RecordID, &rec.VIN, &rec.ServiceAppointmentID, &rec.BranchID, &rec.BranchName,
+			&rec.VehicleTitle, &rec.VehicleYear, &rec.PerformedAt, &rec.OdometerKm, &rec.WorkSummary,
+			&rec.Services, &rec.Lines, &rec.Total, &rec.CreatedAt,
+		); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		rec.Documents = []model.ServiceHistoryDocument{}
+		index[rec.RecordID] = len(out)
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	docRows, err := r.db.Pool.Query(ctx, `
+		SELECT d.record_id, d.document_id, d.document_type, d.file_path, d.file_name, d.file_size, d.mime_type, d.created_at
+		FROM vehicle_service_documents d
+		JOIN vehicle_service_records r ON r.record_id = d.record_id
+		WHERE r.vin = $1
+		ORDER BY d.created_at
+	`, vin)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer docRows.Close()
+	for docRows.Next() {
+		var recordID uuid.UUID
+		var d model.ServiceHistoryDocument
+		if err := docRows.Scan(&recordID, &d.DocumentID, &d.DocumentType, &d.FilePath, &d.FileName, &d.FileSize, &d.MimeType, &d.CreatedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if i, ok := index[recordID]; ok {
+			out[i].Documents = append(out[i].Documents, d)
+		}
+	}
+	if err := docRows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// GetDocument returns a document of the VIN's service book.
+func (r *ServiceHistoryRepository) GetDocument(ctx context.Context, vin string, documentID uuid.UUID) (*model.ServiceHistoryDocument, error) {
+	var d model.ServiceHistoryDocument
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT d.document_id, d.document_type, d.file_path, d.file_name, d.file_size, d.mime_type, d.created_at
+		FROM vehicle_service_documents d
+		JOIN vehicle_service_records r ON r.record_id = d.record_id
+		WHERE r.vin = $1 AND d.document_id = $2
+	`, vin, documentID).Scan(&d.DocumentID, &d.DocumentType, &d.FilePath, &d.FileName, &d.FileSize, &d.MimeType, &d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &d, nil
+}
+
+// FileReferenced reports whether a stored file belongs to a service book and must be kept.
+func (r *ServiceHistoryRepository) FileReferenced(ctx context.Context, filePath string) (bool, error) {
+	var ok bool
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM vehicle_service_documents WHERE file_path = $1)
+	`, filePath).Scan(&ok); err != nil {
+		return false, apperr.Internal(err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ServiceResourceRepository stores branch workshop bays and technicians.
+type ServiceResourceRepository struct {
+	db *database.DB
+}
+
+func NewServiceResourceRepository(db *database.DB) *ServiceResourceRepository {
+	return &ServiceResourceRepository{db: db}
+}
+
+const serviceResourceSelect = `
+	SELECT resource_id, branch_id, kind, name, user_id, skills, is_active, created_at, updated_at
+	FROM service_resources
+`
+
+func scanServiceResource(row pgx.Row) (*model.ServiceResource, error) {
+	var res model.ServiceResource
+	if err := row.Scan(
+		&res.ResourceID, &res.BranchID, &res.Kind, &res.Name, &res.UserID, &res.Skills,
+		&res.IsActive, &res.CreatedAt, &res.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListByBranch returns the branch resources, bays first, optionally only the active ones.
+func (r *ServiceResourceRepository) ListByBranch(ctx context.Context, branchID uuid.UUID, activeOnly bool) ([]model.ServiceResource, error) {
+	rows, err := r.db.Pool.Query(ctx, serviceResourceSelect+`
+		WHERE branch_id = $1 AND (is_active OR NOT $2)
+		ORDER BY kind, name
+	`, branchID, activeOnly)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceResource{}
+	for rows.Next() {
+		res, err := scanServiceResource(rows)
+		if err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, *res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+func (r *ServiceResourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.ServiceResource, error) {
+	res, err := scanServiceResource(r.db.Pool.QueryRow(ctx, serviceResourceSelect+` WHERE resource_id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return res, nil
+}
+
+func (r *ServiceResourceRepository) Create(ctx context.Context, branchID uuid.UUID, in model.ServiceResourceInput) (*model.ServiceResource, error) {
+	active := true
+	if in.IsActive != nil {
+		active = *in.IsActive
+	}
+	res, err := scanServiceResource(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO service_resources (branch_id, kind, name, user_id, skills, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING resource_id, branch_id, kind, name, user_id, skills, is_active, created_at, updated_at
+	`, branchID, in.Kind, in.Name, in.UserID, in.Skills, active))
+	if err != nil {
+		if conflict := mapUniqueViolation(err, "A resource with this name already exists at the branch"); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+	return res, nil
+}
+
+// Update replaces the resource name, staff account, skills and active flag.
+func (r *ServiceResourceRepository) Update(ctx context.Context, id uuid.UUID, in model.ServiceResourceInput) (*model.ServiceResource, error) {
+	res, err := scanServiceResource(r.db.Pool.QueryRow(ctx, `
+		UPDATE service_resources
+		SET name = $2, user_id = $3, skills = $4, is_active = COALESCE($5, is_active)
+		WHERE resource_id = $1
+		RETURNING resource_id, branch_id, kind, name, user_id, skills, is_active, created_at, updated_at
+	`, id, in.Name, in.UserID, in.Skills, in.IsActive))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Resource not found")
+		}
+		if conflict := mapUniqueViolation(err, "A resource with this name already exists at the branch"); conflict != nil {
+			return nil, conflict
+		}
+		return nil, apperr.Internal(err)
+	}
+	return res, nil
+}
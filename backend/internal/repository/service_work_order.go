@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CheckIn moves a scheduled appointment to in_progress, recording the odometer reading if given.
+// It reports false when the appointment is no longer scheduled.
+func (r *ServiceAppointmentRepository) CheckIn(ctx context.Context, appointmentID uuid.UUID, odometerKm *int) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE service_appointments
+		SET status = 'in_progress', checked_in_at = now(), odometer_km = COALESCE($2, odometer_km), updated_at = now()
+		WHERE service_appointment_id = $1 AND status = 'scheduled'
+	`, appointmentID, odometerKm)
+	if err != nil {
+		return false, apperr.Internal(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// WorkLines returns the work order lines of an appointment in entry order.
+func (r *ServiceAppointmentRepository) WorkLines(ctx context.Context, appointmentID uuid.UUID) ([]model.ServiceWorkLine, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT work_line_id, kind, description, part_number, quantity, unit_price,
+			round(quantity * unit_price, 2), created_by, created_at
+		FROM service_work_lines
+		WHERE service_appointment_id = $1
+		ORDER BY position
+	`, appointmentID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.ServiceWorkLine{}
+	for rows.Next() {
+		var l model.ServiceWorkLine
+		if err := rows.Scan(&l.WorkLineID, &l.Kind, &l.Description, &l.PartNumber, &l.Quantity, &l.UnitPrice,
+			&l.Amount, &l.CreatedBy, &l.CreatedAt); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// UpdateWorkOrder records the odometer reading, work summary and (when upd.Lines is non-nil) replaces
+// the work lines of an in-progress appointment.
+func (r *ServiceAppointmentRepository) UpdateWorkOrder(ctx context.Context, appointmentID, staffID uuid.UUID, upd model.ServiceWorkOrderUpdate) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockInProgress(ctx, tx, appointmentID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE service_appointments
+		SET odometer_km = COALESCE($2, odometer_km), work_summary = COALESCE($3, work_summary), updated_at = now()
+		WHERE service_appointment_id = $1
+	`, appointmentID, upd.OdometerKm, upd.WorkSummary); err != nil {
+		return apperr.Internal(err)
+	}
+	if upd.Lines != nil {
+		if _, err := tx.Exec(ctx, `DELETE FROM service_work_lines WHERE service_appointment_id = $1`, appointmentID); err != nil {
+			return apperr.Internal(err)
+		}
+		batch := &pgx.Batch{}
+		for i, l := range upd.Lines {
+			batch.Queue(`
+				INSERT INTO service_work_lines
+					(service_appointment_id, kind, description, part_number, quantity, unit_price, position, created_by)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			`, appointmentID, l.Kind, l.Description, l.PartNumber, l.Quantity, l.UnitPrice, i+1, staffID)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return apperr.Internal(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// Complete closes an in-progress appointment and raises the car's recorded mileage to the work
+// order odometer reading (odometerKm if given), which must be set. Open maintenance reminders and
+// service campaigns for the performed services are marked done and the visit is written to the
+// VIN's service book.
+func (r *ServiceAppointmentRepository) Complete(ctx context.Context, appointmentID uuid.UUID, odometerKm *int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockInProgress(ctx, tx, appointmentID); err != nil {
+		return err
+	}
+	var userCarID uuid.UUID
+	var odometer *int
+	if err := tx.QueryRow(ctx, `
+		UPDATE service_appointments
+		SET status = 'completed', completed_at = now(), odometer_km = COALESCE($2, odometer_km), updated_at = now()
+		WHERE service_appointment_id = $1
+		RETURNING user_car_id, odometer_km
+	`, appointmentID, odometerKm).Scan(&userCarID, &odometer); err != nil {
+		return apperr.Internal(err)
+	}
+	if odometer == nil {
+		return apperr.BadRequest("record the odometer reading before completing the appointment")
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE user_cars SET current_mileage = GREATEST(current_mileage, $2) WHERE user_car_id = $1
+	`, userCarID, *odometer); err != nil {
+		return apperr.Internal(err)
+	}
+	// Maintenance reminders for the services just performed are fulfilled.
+	if _, err := tx.Exec(ctx, `
+		UPDATE maintenance_reminders mr SET status = 'done', service_appointment_id = $1
+		WHERE mr.user_car_id = $2 AND mr.status = 'open' AND EXISTS (
+			SELECT 1
+			FROM maintenance_plan_service_types pst
+			JOIN service_appointment_types sat ON sat.service_type_id = pst.service_type_id
+			WHERE pst.plan_id = mr.plan_id AND sat.service_appointment_id = $1
+		)
+	`, appointmentID, userCarID); err != nil {
+		return apperr.Internal(err)
+	}
+	if err := completeCampaignWork(ctx, tx, appointmentID, userCarID); err != nil {
+		return err
+	}
+	if err := recordServiceHistory(ctx, tx, appointmentID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// lockInProgress locks the appointment row and checks it is in progress.
+func lockInProgress(ctx context.Context, tx pgx.Tx, appointmentID uuid.UUID) error {
+	var status string
+	err := tx.QueryRow(ctx, `
+		SELECT status FROM service_appointments WHERE service_appointment_id = $1 FOR UPDATE
+	`, appointmentID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return apperr.Internal(err)
+	}
+	if status != "in_progress" {
+		return apperr.Conflict("appointment is not in progress")
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type TradeInRepository struct {
+	db *database.DB
+}
+
+func NewTradeInRepository(db *database.DB) *TradeInRepository {
+	return &TradeInRepository{db: db}
+}
+
+const depreciationRuleColumns = `
+	rule_id, name, brand_id, vehicle_segment, first_year_depreciation_percent,
+	annual_depreciation_percent, expected_annual_mileage, mileage_adjustment_percent,
+	min_residual_percent, is_active, created_at, updated_at
+`
+
+func scanDepreciationRule(row pgx.Row, d *model.DepreciationRule) error {
+	return row.Scan(
+		&d.RuleID, &d.Name, &d.BrandID, &d.VehicleSegment, &d.FirstYearDepreciationPercent,
+		&d.AnnualDepreciationPercent, &d.ExpectedAnnualMileage, &d.MileageAdjustmentPercent,
+		&d.MinResidualPercent, &d.IsActive, &d.CreatedAt, &d.UpdatedAt,
+	)
+}
+
+// ListRules returns depreciation rules; activeOnly is used by the valuation.
+func (r *TradeInRepository) ListRules(ctx context.Context, activeOnly bool) ([]model.DepreciationRule, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+depreciationRuleColumns+`
+		FROM tradein_depreciation_rules
+		WHERE ($1 = false OR is_active = true)
+		ORDER BY name
+	`, activeOnly)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	var out []model.DepreciationRule
+	for rows.Next() {
+		var d model.DepreciationRule
+		if err := scanDepreciationRule(rows, &d); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *TradeInRepository) CreateRule(ctx context.Context, in model.DepreciationRuleInput) (*model.DepreciationRule, error) {
+	var d model.DepreciationRule
+	err := scanDepreciationRule(r.db.Pool.QueryRow(ctx, `
+		INSERT INTO tradein_depreciation_rules (
+			name, brand_id, vehicle_segment, first_year_depreciation_percent,
+			annual_depreciation_percent, expected_annual_mileage, mileage_adjustment_percent,
+			min_residual_percent, is_active
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+depreciationRuleColumns,
+		in.Name, in.BrandID, in.VehicleSegment, in.FirstYearDepreciationPercent,
+		in.AnnualDepreciationPercent, in.ExpectedAnnualMileage, in.MileageAdjustmentPercent,
+		in.MinResidualPercent, in.IsActive,
+	), &d)
+	if err != nil {
+		return nil, mapDepreciationRuleWriteError(err)
+	}
+	return &d, nil
+}
+
+func (r *TradeInRepository) UpdateRule(ctx context.Context, id uuid.UUID, in model.DepreciationRuleInput) (*model.DepreciationRule, error) {
+	var d model.DepreciationRule
+	err := scanDepreciationRule(r.db.Pool.QueryRow(ctx, `
+		UPDATE tradein_depreciation_rules SET
+			name = $2, brand_id = $3, vehicle_segment = $4, first_year_depreciation_percent = $5,
+			annual_depreciation_percent = $6, expected_annual_mileage = $7,
+			mileage_adjustment_percent = $8, min_residual_percent = $9, is_active = $10
+		WHERE rule_id = $1
+		RETURNING `+depreciationRuleColumns,
+		id, in.Name, in.BrandID, in.VehicleSegment, in.FirstYearDepreciationPercent,
+		in.AnnualDepreciationPercent, in.ExpectedAnnualMileage,
+		in.MileageAdjustmentPercent, in.MinResidualPercent, in.IsActive,
+	), &d)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.NotFoundErr("Depreciation rule not found")
+		}
+		return nil, mapDepreciationRuleWriteError(err)
+	}
+	return &d, nil
+}
+
+func (r *TradeInRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	cmd, err := r.db.Pool.Exec(ctx, `DELETE FROM tradein_depreciation_rules WHERE rule_id = $1`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Depreciation rule not found")
+	}
+	return nil
+}
+
+func mapDepreciationRuleWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503":
+			return apperr.BadRequest("Unknown brand")
+		case "23514":
+			return apperr.BadRequest("Invalid depreciation rule parameters")
+		}
+	}
+	return apperr.Internal(err)
+}
+
+const tradeInOfferSelect = `
+	SELECT
+		o.tradein_offer_id, o.user_car_id, o.user_id, o.appraised_by, o.rule_id,
+		o.reference_price, o.age_years, o.mileage, o.model_value, o.adjustments,
+		o.offer_amount, o.status, o.notes, o.valid_until, o.order_id, o.decided_at,
+		o.created_at, o.updated_at,
+		uc.vin, uc.year, b.name, m.name, t.name
+	FROM tradein_offers o
+	JOIN user_cars uc ON o.user_car_id = uc.user_car_id
+	JOIN trims t ON uc.trim_id = t.trim_id
+	JOIN generations g ON t.generation_id = g.generation_id
+	JOIN models m ON g.model_id = m.model_id
+	JOIN brands b ON m.brand_id = b.brand_id
+`
+
+func scanTradeInOffer(row pgx.Row, o *model.TradeInOfferWithDetails) error {
+	return row.Scan(
+		&o.TradeInOfferID, &o.UserCarID, &o.UserID, &o.AppraisedBy, &o.RuleID,
+		&o.ReferencePrice, &o.AgeYears, &o.Mileage, &o.ModelValue, &o.Adjustments,
+		&o.OfferAmount, &o.Status, &o.Notes, &o.ValidUntil, &o.OrderID, &o.DecidedAt,
+		&o.CreatedAt, &o.UpdatedAt,
+		&o.VIN, &o.Year, &o.BrandName, &o.ModelName, &o.TrimName,
+	)
+}
+
+func (r *TradeInRepository) queryOffers(ctx context.Context, where string, args ...any) ([]model.TradeInOfferWithDetails, error) {
+	rows, err := r.db.Pool.Query(ctx, tradeInOfferSelect+where+` ORDER BY o.created_at DESC`, args...)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	var out []model.TradeInOfferWithDetails
+	for rows.Next() {
+		var o model.TradeInOfferWithDetails
+		if err := scanTradeInOffer(rows, &o); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// ListOffers returns all offers (staff).
+func (r *TradeInRepository) ListOffers(ctx context.Context) ([]model.TradeInOfferWithDetails, error) {
+	return r.queryOffers(ctx, ``)
+}
+
+// ListOffersByUser returns offers for a customer's cars.
+func (r *TradeInRepository) ListOffersByUser(ctx context.Context, userID uuid.UUID) ([]model.TradeInOfferWithDetails, error) {
+	return r.queryOffers(ctx, ` WHERE o.user_id = $1`, userID)
+}
+
+func (r *TradeInRepository) GetOffer(ctx context.Context, id uuid.UUID) (*model.TradeInOfferWithDetails, error) {
+	var o model.TradeInOfferWithDetails
+	err := scanTradeInOffer(r.db.Pool.QueryRow(ctx, tradeInOfferSelect+` WHERE o.tradein_offer_id = $1`, id), &o)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &o, nil
+}
+
+// GetOfferByOrderID returns the trade-in applied to an order, or nil.
+func (r *TradeInRepository) GetOfferByOrderID(ctx context.Context, orderID uuid.UUID) (*model.TradeInOfferWithDetails, error) {
+	var o model.TradeInOfferWithDetails
+	err := scanTradeInOffer(r.db.Pool.QueryRow(ctx, tradeInOfferSelect+` WHERE o.order_id = $1`, orderID), &o)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &o, nil
+}
+
+// CreateOffer stores an appraisal issued to the car owner.
+func (r *TradeInRepository) CreateOffer(ctx context.Context, car *model.UserCarWithDetails, appraisedBy uuid.UUID, v model.TradeInValuation, notes *string, validUntil time.Time) (*model.TradeInOfferWithDetails, error) {
+	var id uuid.UUID
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO tradein_offers (
+			user_car_id, user_id, appraised_by, rule_id, reference_price, age_years, mileage,
+			model_value, adjustments, offer_amount, notes, valid_until
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING tradein_offer_id
+	`, car.UserCarID, car.UserID, appraisedBy, v.RuleID, v.ReferencePrice, v.AgeYears, v.Mileage,
+		v.ModelValue, v.Adjustments, v.OfferAmount, notes, validUntil,
+	).Scan(&id)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.GetOffer(ctx, id)
+}
+
+// DecideOffer moves an open, unexpired offer of userID to accepted or rejected.
+func (r *TradeInRepository) DecideOffer(ctx context.Context, id, userID uuid.UUID, status string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE tradein_offers SET status = $3, decided_at = now()
+		WHERE tradein_offer_id = $1 AND user_id = $2 AND status = 'offered' AND valid_until > now()
+	`, id, userID, status)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.BadRequest("Trade-in offer is no longer open")
+	}
+	return nil
+}
+
+// CancelOffer withdraws an offer that has not been applied to an order yet.
+func (r *TradeInRepository) CancelOffer(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE tradein_offers SET status = 'cancelled', decided_at = now()
+		WHERE tradein_offer_id = $1 AND status IN ('offered', 'accepted')
+	`, id)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.BadRequest("Only open or accepted offers can be cancelled")
+	}
+	return nil
+}
+
+// AttachToOrder applies an accepted offer to an unpaid order and deducts it from the amount due.
+// The credit is capped at the order price.
+func (r *TradeInRepository) AttachToOrder(ctx context.Context, orderID, offerID uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var orderUserID uuid.UUID
+	var finalPrice float64
+	var status string
+	err = tx.QueryRow(ctx, `SELECT user_id, final_price, status FROM orders WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&orderUserID, &finalPrice, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return apperr.Internal(err)
+	}
+	if err := checkTradeInOrderStatus(status); err != nil {
+		return err
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tradein_offers WHERE order_id = $1)`, orderID).Scan(&exists); err != nil {
+		return apperr.Internal(err)
+	}
+	if exists {
+		return apperr.Conflict("Order already has a trade-in")
+	}
+
+	var amount float64
+	err = tx.QueryRow(ctx, `
+		UPDATE tradein_offers SET status = 'applied', order_id = $1
+		WHERE tradein_offer_id = $2 AND user_id = $3 AND status = 'accepted' AND valid_until > now()
+		RETURNING offer_amount
+	`, orderID, offerID, orderUserID).Scan(&amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.BadRequest("Trade-in offer must be accepted, unexpired and belong to the order owner")
+		}
+		return apperr.Internal(err)
+	}
+	if amount > finalPrice {
+		amount = finalPrice
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET tradein_credit = $2 WHERE order_id = $1`, orderID, amount); err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// DetachFromOrder returns the applied offer of an unpaid order to accepted and clears the order
+// credit.
+func (r *TradeInRepository) DetachFromOrder(ctx context.Context, orderID uuid.UUID) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	if err := tx.QueryRow(ctx, `SELECT status FROM orders WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return apperr.Internal(err)
+	}
+	if err := checkTradeInOrderStatus(status); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE tradein_offers SET status = 'accepted', order_id = NULL
+		WHERE order_id = $1
+	`, orderID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return apperr.NotFoundErr("Order has no trade-in")
+	}
+	if _, err := tx.Exec(ctx, `UPDATE orders SET tradein_credit = 0 WHERE order_id = $1`, orderID); err != nil {
+		return apperr.Internal(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperr.Internal(err)
+	}
+	return nil
+}
+
+// checkTradeInOrderStatus rechecks, under the order row lock, that the order is not paid yet.
+func checkTradeInOrderStatus(status string) error {
+	if status != "pending" && status != "approved" {
+		return apperr.BadRequest("Trade-in can only be changed before the order is paid")
+	}
+	return nil
+}
@@ -25,7 +25,7 @@ func (r *UserCarRepository) Create(ctx context.Context, userID uuid.UUID, create
 	query := `
 		INSERT INTO user_cars (user_id, trim_id, color_id, vin, year, current_mileage, purchase_date)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING user_car_id, user_id, trim_id, color_id, vin, year, current_mileage, purchase_date, created_at
+		RETURNING user_car_id, user_id, trim_id, color_id, vin, year, current_mileage, purchase_date, order_id, organization_id, created_at
 	`
 
 	err := r.db.Pool.QueryRow(ctx, query,
@@ -34,7 +34,7 @@ func (r *UserCarRepository) Create(ctx context.Context, userID uuid.UUID, create
 	).Scan(
 		&userCar.UserCarID, &userCar.UserID, &userCar.TrimID, &userCar.ColorID,
 		&userCar.VIN, &userCar.Year, &userCar.CurrentMileage, &userCar.PurchaseDate,
-		&userCar.CreatedAt,
+		&userCar.OrderID, &userCar.OrganizationID, &userCar.CreatedAt,
 	)
 	if err != nil {
 		if conflict := mapUniqueViolation(err, "This VIN is already registered"); conflict != nil {
@@ -46,30 +46,34 @@ func (r *UserCarRepository) Create(ctx context.Context, userID uuid.UUID, create
 	return &userCar, nil
 }
 
-func (r *UserCarRepository) GetByID(ctx context.Context, userCarID uuid.UUID) (*model.UserCarWithDetails, error) {
-	var userCar model.UserCarWithDetails
-	query := `
-		SELECT 
-			uc.user_car_id, uc.user_id, uc.trim_id, uc.color_id, uc.vin, uc.year,
-			uc.current_mileage, uc.purchase_date, uc.created_at,
-			t.name as trim_name, b.name as brand_name, m.name as model_name,
-			c.name as color_name, c.hex_code as color_hex,
-			CASE WHEN m.image_key IS NOT NULL THEN '/api/catalog/models/' || m.model_id::text || '/image?v=' || m.image_key ELSE NULL END as image_url
-		FROM user_cars uc
-		JOIN trims t ON uc.trim_id = t.trim_id
-		JOIN generations g ON t.generation_id = g.generation_id
-		JOIN models m ON g.model_id = m.model_id
-		JOIN brands b ON m.brand_id = b.brand_id
-		JOIN colors c ON uc.color_id = c.color_id
-		WHERE uc.user_car_id = $1
-	`
-
-	err := r.db.Pool.QueryRow(ctx, query, userCarID).Scan(
+// userCarDetailsSelect reads a car with its catalog names; callers append the WHERE clause.
+const userCarDetailsSelect = `
+	SELECT
+		uc.user_car_id, uc.user_id, uc.trim_id, uc.color_id, uc.vin, uc.year,
+		uc.current_mileage, uc.purchase_date, uc.order_id, uc.organization_id, uc.created_at,
+		t.name as trim_name, b.name as brand_name, m.name as model_name,
+		c.name as color_name, c.hex_code as color_hex,
+		CASE WHEN m.image_key IS NOT NULL THEN '/api/catalog/models/' || m.model_id::text || '/image?v=' || m.image_key ELSE NULL END as image_url
+	FROM user_cars uc
+	JOIN trims t ON uc.trim_id = t.trim_id
+	JOIN generations g ON t.generation_id = g.generation_id
+	JOIN models m ON g.model_id = m.model_id
+	JOIN brands b ON m.brand_id = b.brand_id
+	JOIN colors c ON uc.color_id = c.color_id
+`
+
+func scanUserCarDetails(row pgx.Row, userCar *model.UserCarWithDetails) error {
+	return row.Scan(
 		&userCar.UserCarID, &userCar.UserID, &userCar.TrimID, &userCar.ColorID,
 		&userCar.VIN, &userCar.Year, &userCar.CurrentMileage, &userCar.PurchaseDate,
-		&userCar.CreatedAt, &userCar.TrimName, &userCar.BrandName, &userCar.ModelName,
+		&userCar.OrderID, &userCar.OrganizationID, &userCar.CreatedAt, &userCar.TrimName, &userCar.BrandName, &userCar.ModelName,
 		&userCar.ColorName, &userCar.ColorHex, &userCar.ImageURL,
 	)
+}
+
+func (r *UserCarRepository) GetByID(ctx context.Context, userCarID uuid.UUID) (*model.UserCarWithDetails, error) {
+	var userCar model.UserCarWithDetails
+	err := scanUserCarDetails(r.db.Pool.QueryRow(ctx, userCarDetailsSelect+`WHERE uc.user_car_id = $1`, userCarID), &userCar)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
@@ -81,24 +85,16 @@ func (r *UserCarRepository) GetByID(ctx context.Context, userCarID uuid.UUID) (*
 }
 
 func (r *UserCarRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserCarWithDetails, error) {
-	query := `
-		SELECT 
-			uc.user_car_id, uc.user_id, uc.trim_id, uc.color_id, uc.vin, uc.year,
-			uc.current_mileage, uc.purchase_date, uc.created_at,
-			t.name as trim_name, b.name as brand_name, m.name as model_name,
-			c.name as color_name, c.hex_code as color_hex,
-			CASE WHEN m.image_key IS NOT NULL THEN '/api/catalog/models/' || m.model_id::text || '/image?v=' || m.image_key ELSE NULL END as image_url
-		FROM user_cars uc
-		JOIN trims t ON uc.trim_id = t.trim_id
-		JOIN generations g ON t.generation_id = g.generation_id
-		JOIN models m ON g.model_id = m.model_id
-		JOIN brands b ON m.brand_id = b.brand_id
-		JOIN colors c ON uc.color_id = c.color_id
-		WHERE uc.user_id = $1
-		ORDER BY uc.created_at DESC
-	`
+	return r.list(ctx, `WHERE uc.user_id = $1 ORDER BY uc.created_at DESC`, userID)
+}
 
-	rows, err := r.db.Pool.Query(ctx, query, userID)
+// ListByOrganization returns the cars shared with an organization.
+func (r *UserCarRepository) ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]model.UserCarWithDetails, error) {
+	return r.list(ctx, `WHERE uc.organization_id = $1 ORDER BY b.name, m.name, uc.vin`, organizationID)
+}
+
+func (r *UserCarRepository) list(ctx context.Context, where string, args ...any) ([]model.UserCarWithDetails, error) {
+	rows, err := r.db.Pool.Query(ctx, userCarDetailsSelect+where, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user cars: %w", err)
 	}
@@ -107,12 +103,7 @@ func (r *UserCarRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	var userCars []model.UserCarWithDetails
 	for rows.Next() {
 		var userCar model.UserCarWithDetails
-		if err := rows.Scan(
-			&userCar.UserCarID, &userCar.UserID, &userCar.TrimID, &userCar.ColorID,
-			&userCar.VIN, &userCar.Year, &userCar.CurrentMileage, &userCar.PurchaseDate,
-			&userCar.CreatedAt, &userCar.TrimName, &userCar.BrandName, &userCar.ModelName,
-			&userCar.ColorName, &userCar.ColorHex, &userCar.ImageURL,
-		); err != nil {
+		if err := scanUserCarDetails(rows, &userCar); err != nil {
 			return nil, fmt.Errorf("failed to scan user car: %w", err)
 		}
 		userCars = append(userCars, userCar)
@@ -121,6 +112,18 @@ func (r *UserCarRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	return userCars, nil
 }
 
+// SetOrganization shares the car with an organization, or stops sharing it when organizationID is nil.
+func (r *UserCarRepository) SetOrganization(ctx context.Context, userCarID uuid.UUID, organizationID *uuid.UUID) error {
+	ct, err := r.db.Pool.Exec(ctx, `UPDATE user_cars SET organization_id = $2 WHERE user_car_id = $1`, userCarID, organizationID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return nil
+}
+
 func (r *UserCarRepository) VINExists(ctx context.Context, vin string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM user_cars WHERE vin = $1)`
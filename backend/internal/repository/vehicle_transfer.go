@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/database"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// VehicleTransferRepository changes the owner of a garage car. The car row itself moves, so its
+// appointments, reminders and service book go with it.
+type VehicleTransferRepository struct {
+	db *database.DB
+}
+
+func NewVehicleTransferRepository(db *database.DB) *VehicleTransferRepository {
+	return &VehicleTransferRepository{db: db}
+}
+
+const vehicleTransferSelect = `
+	SELECT vt.transfer_id, vt.user_car_id, uc.vin, vt.from_user_id, vt.to_user_id, vt.status,
+		vt.appointment_policy, vt.failed_attempts, vt.cancelled_appointments, vt.expires_at,
+		vt.forced_by, vt.reason, vt.created_at, vt.completed_at
+	FROM vehicle_transfers vt
+	JOIN user_cars uc ON uc.user_car_id = vt.user_car_id
+`
+
+func scanVehicleTransfer(row pgx.Row, t *model.VehicleTransfer) error {
+	return row.Scan(
+		&t.TransferID, &t.UserCarID, &t.VIN, &t.FromUserID, &t.ToUserID, &t.Status,
+		&t.AppointmentPolicy, &t.FailedAttempts, &t.CancelledAppointments, &t.ExpiresAt,
+		&t.ForcedBy, &t.Reason, &t.CreatedAt, &t.CompletedAt,
+	)
+}
+
+// Start replaces any pending transfer of the seller's car with a new one.
+func (r *VehicleTransferRepository) Start(ctx context.Context, userCarID, sellerID uuid.UUID, codeHash []byte, policy string, expiresAt time.Time) (*model.VehicleTransfer, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE vehicle_transfers SET status = 'cancelled', completed_at = now()
+		WHERE user_car_id = $1 AND status = 'pending'
+	`, userCarID); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO vehicle_transfers (user_car_id, from_user_id, code_hash, appointment_policy, expires_at)
+		SELECT user_car_id, user_id, $3, $4, $5
+		FROM user_cars
+		WHERE user_car_id = $1 AND user_id = $2
+		RETURNING transfer_id
+	`, userCarID, sellerID, codeHash, policy, expiresAt).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *VehicleTransferRepository) Get(ctx context.Context, transferID uuid.UUID) (*model.VehicleTransfer, error) {
+	var t model.VehicleTransfer
+	if err := scanVehicleTransfer(r.db.Pool.QueryRow(ctx, vehicleTransferSelect+`WHERE vt.transfer_id = $1`, transferID), &t); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &t, nil
+}
+
+// GetPending returns the pending transfer of a car; an expired one is reported as not found.
+func (r *VehicleTransferRepository) GetPending(ctx context.Context, userCarID uuid.UUID, now time.Time) (*model.VehicleTransfer, error) {
+	var t model.VehicleTransfer
+	err := scanVehicleTransfer(r.db.Pool.QueryRow(ctx, vehicleTransferSelect+`
+		WHERE vt.user_car_id = $1 AND vt.status = 'pending' AND vt.expires_at > $2
+	`, userCarID, now), &t)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	return &t, nil
+}
+
+// ListByCar returns the transfer log of a car, newest first.
+func (r *VehicleTransferRepository) ListByCar(ctx context.Context, userCarID uuid.UUID) ([]model.VehicleTransfer, error) {
+	rows, err := r.db.Pool.Query(ctx, vehicleTransferSelect+`
+		WHERE vt.user_car_id = $1
+		ORDER BY vt.created_at DESC
+	`, userCarID)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer rows.Close()
+
+	out := []model.VehicleTransfer{}
+	for rows.Next() {
+		var t model.VehicleTransfer
+		if err := scanVehicleTransfer(rows, &t); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return out, nil
+}
+
+// CancelPending withdraws the seller's pending transfer of a car.
+func (r *VehicleTransferRepository) CancelPending(ctx context.Context, userCarID, sellerID uuid.UUID) error {
+	ct, err := r.db.Pool.Exec(ctx, `
+		UPDATE vehicle_transfers SET status = 'cancelled', completed_at = now()
+		WHERE user_car_id = $1 AND from_user_id = $2 AND status = 'pending'
+	`, userCarID, sellerID)
+	if err != nil {
+		return apperr.Internal(err)
+	}
+	if ct.RowsAffected() == 0 {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return nil
+}
+
+// Accept completes the pending transfer of the VIN for the buyer when the code hash matches.
+// A wrong code counts as a failed attempt and the transfer is cancelled after maxAttempts; an
+// expired transfer is closed. Every failure is reported as not found so that VINs cannot be probed.
+func (r *VehicleTransferRepository) Accept(ctx context.Context, vin string, codeHash []byte, buyerID uuid.UUID, maxAttempts int, now time.Time) (*model.VehicleTransfer, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		t      model.VehicleTransfer
+		stored []byte
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT vt.transfer_id, vt.user_car_id, vt.from_user_id, vt.appointment_policy, vt.failed_attempts,
+			vt.expires_at, vt.code_hash
+		FROM vehicle_transfers vt
+		JOIN user_cars uc ON uc.user_car_id = vt.user_car_id
+		WHERE uc.vin = $1 AND vt.status = 'pending'
+		FOR UPDATE OF vt
+	`, vin).Scan(&t.TransferID, &t.UserCarID, &t.FromUserID, &t.AppointmentPolicy, &t.FailedAttempts, &t.ExpiresAt, &stored)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+
+	switch {
+	case !t.ExpiresAt.After(now):
+		if _, err := tx.Exec(ctx, `
+			UPDATE vehicle_transfers SET status = 'expired', completed_at = $2 WHERE transfer_id = $1
+		`, t.TransferID, now); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	case subtle.ConstantTimeCompare(stored, codeHash) != 1:
+		if _, err := tx.Exec(ctx, `
+			UPDATE vehicle_transfers
+			SET failed_attempts = failed_attempts + 1,
+				status = CASE WHEN failed_attempts + 1 >= $2 THEN 'cancelled' ELSE status END,
+				completed_at = CASE WHEN failed_attempts + 1 >= $2 THEN $3 ELSE completed_at END
+			WHERE transfer_id = $1
+		`, t.TransferID, maxAttempts, now); err != nil {
+			return nil, apperr.Internal(err)
+		}
+	case t.FromUserID != nil && *t.FromUserID == buyerID:
+		return nil, apperr.Conflict("You already own this car")
+	default:
+		cancelled, err := moveUserCar(ctx, tx, t.UserCarID, buyerID, t.AppointmentPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE vehicle_transfers
+			SET status = 'accepted', to_user_id = $2, cancelled_appointments = $3, completed_at = $4
+			WHERE transfer_id = $1
+		`, t.TransferID, buyerID, cancelled, now); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, apperr.Internal(err)
+		}
+		return r.Get(ctx, t.TransferID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+}
+
+// Force moves a car to another customer on behalf of staff, closing any pending transfer. The
+// transfer row records who did it and why.
+func (r *VehicleTransferRepository) Force(ctx context.Context, userCarID, toUserID, staffID uuid.UUID, reason, policy string, now time.Time) (*model.VehicleTransfer, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	var fromUserID uuid.UUID
+	if err := tx.QueryRow(ctx, `SELECT user_id FROM user_cars WHERE user_car_id = $1 FOR UPDATE`, userCarID).Scan(&fromUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return nil, apperr.Internal(err)
+	}
+	if fromUserID == toUserID {
+		return nil, apperr.Conflict("The car already belongs to this customer")
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE vehicle_transfers SET status = 'cancelled', completed_at = $2
+		WHERE user_car_id = $1 AND status = 'pending'
+	`, userCarID, now); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	cancelled, err := moveUserCar(ctx, tx, userCarID, toUserID, policy)
+	if err != nil {
+		return nil, err
+	}
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO vehicle_transfers (
+			user_car_id, from_user_id, to_user_id, status, appointment_policy, cancelled_appointments,
+			forced_by, reason, created_at, completed_at
+		)
+		VALUES ($1, $2, $3, 'forced', $4, $5, $6, $7, $8, $8)
+		RETURNING transfer_id
+	`, userCarID, fromUserID, toUserID, policy, cancelled, staffID, reason, now).Scan(&id); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return r.Get(ctx, id)
+}
+
+// moveUserCar hands the car row to the new owner. A car in the workshop or pledged in a trade-in
+// cannot change hands; open trade-in offers made to the seller are withdrawn and the car is no
+// longer shared with the seller's organization. Scheduled appointments are cancelled or left to the
+// new owner according to policy; it returns how many were cancelled.
+func moveUserCar(ctx context.Context, tx pgx.Tx, userCarID, toUserID uuid.UUID, policy string) (int, error) {
+	var inWorkshop, pledged bool
+	if err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM service_appointments WHERE user_car_id = $1 AND status = 'in_progress'),
+			EXISTS (SELECT 1 FROM tradein_offers WHERE user_car_id = $1 AND status IN ('accepted','applied'))
+	`, userCarID).Scan(&inWorkshop, &pledged); err != nil {
+		return 0, apperr.Internal(err)
+	}
+	if inWorkshop {
+		return 0, apperr.Conflict("The car is in the workshop; complete the visit before transferring it")
+	}
+	if pledged {
+		return 0, apperr.Conflict("The car is pledged in a trade-in; cancel the trade-in before transferring it")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE tradein_offers SET status = 'cancelled', decided_at = now()
+		WHERE user_car_id = $1 AND status = 'offered'
+	`, userCarID); err != nil {
+		return 0, apperr.Internal(err)
+	}
+	cancelled := 0
+	if policy == model.TransferPolicyCancel {
+		ct, err := tx.Exec(ctx, `
+			UPDATE service_appointments SET status = 'cancelled'
+			WHERE user_car_id = $1 AND status = 'scheduled'
+		`, userCarID)
+		if err != nil {
+			return 0, apperr.Internal(err)
+		}
+		cancelled = int(ct.RowsAffected())
+	}
+	if _, err := tx.Exec(ctx, `UPDATE user_cars SET user_id = $2, organization_id = NULL WHERE user_car_id = $1`, userCarID, toUserID); err != nil {
+		return 0, apperr.Internal(err)
+	}
+	return cancelled, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	assignmentEntityOrder       = "order"
+	assignmentEntityAppointment = "appointment"
+
+	assignmentStrategyManual      = "manual"
+	assignmentStrategyRoundRobin  = "round_robin"
+	assignmentStrategyLeastLoaded = "least_loaded"
+)
+
+type AssignmentService struct {
+	repo *repository.Repository
+}
+
+func NewAssignmentService(repos *repository.Repository) *AssignmentService {
+	return &AssignmentService{repo: repos}
+}
+
+// assignmentQueuePermission is what a staff role needs to own items of the entity.
+func assignmentQueuePermission(entity string) (string, error) {
+	switch entity {
+	case assignmentEntityOrder:
+		return authz.PermOrdersManageStatus, nil
+	case assignmentEntityAppointment:
+		return authz.PermAppointmentsViewAny, nil
+	default:
+		return "", apperr.BadRequest("Unknown assignment entity")
+	}
+}
+
+// Claim assigns an unowned item to the requester; claiming an item someone else owns is a conflict.
+func (s *AssignmentService) Claim(ctx context.Context, entity string, id, requester uuid.UUID, role string) error {
+	perm, err := assignmentQueuePermission(entity)
+	if err != nil {
+		return err
+	}
+	if !authz.HasPermission(role, perm) {
+		return apperr.Forbidden("Your role cannot own this kind of work")
+	}
+	return s.repo.Assignment.Assign(ctx, entity, id, &requester, true)
+}
+
+// Assign sets the responsible staff member, or clears it when managerID is nil.
+func (s *AssignmentService) Assign(ctx context.Context, entity string, id uuid.UUID, managerID *uuid.UUID) error {
+	perm, err := assignmentQueuePermission(entity)
+	if err != nil {
+		return err
+	}
+	if managerID != nil {
+		user, err := s.repo.User.GetByID(ctx, *managerID)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return apperr.BadRequest("Assignee not found")
+			}
+			return err
+		}
+		if !authz.HasPermission(user.Role, perm) {
+			return apperr.BadRequest("Assignee's role cannot own this kind of work")
+		}
+	}
+	return s.repo.Assignment.Assign(ctx, entity, id, managerID, false)
+}
+
+func (s *AssignmentService) ListRules(ctx context.Context) ([]model.AssignmentRule, error) {
+	return s.repo.Assignment.ListRules(ctx)
+}
+
+func (s *AssignmentService) UpdateRule(ctx context.Context, entity string, in model.AssignmentRuleUpdate) (*model.AssignmentRule, error) {
+	perm, err := assignmentQueuePermission(entity)
+	if err != nil {
+		return nil, err
+	}
+	switch in.Strategy {
+	case assignmentStrategyManual, assignmentStrategyRoundRobin, assignmentStrategyLeastLoaded:
+	default:
+		return nil, apperr.BadRequest("strategy must be manual, round_robin or least_loaded")
+	}
+	if !authz.HasPermission(in.RoleCode, perm) {
+		return nil, apperr.BadRequest("role_code cannot own this kind of work")
+	}
+	return s.repo.Assignment.UpdateRule(ctx, entity, in)
+}
+
+// RunAutoAssign distributes all unassigned open items of the entity according to its rule.
+func (s *AssignmentService) RunAutoAssign(ctx context.Context, entity string) (*model.AutoAssignResult, error) {
+	if _, err := assignmentQueuePermission(entity); err != nil {
+		return nil, err
+	}
+	ids, err := s.repo.Assignment.ListUnassignedOpen(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.AutoAssignResult{}
+	for _, id := range ids {
+		assignee, err := s.repo.Assignment.AutoAssign(ctx, entity, id, pickAssignee)
+		if err != nil {
+			return out, err
+		}
+		if assignee == nil {
+			// Manual strategy or no eligible staff: the rest would not be assigned either.
+			break
+		}
+		out.Assigned++
+	}
+	return out, nil
+}
+
+// MyQueue returns the open orders and scheduled appointments assigned to the staff member.
+func (s *AssignmentService) MyQueue(ctx context.Context, userID uuid.UUID) (*model.MyQueue, error) {
+	orders, err := s.repo.Order.ListOpenByManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.repo.ServiceAppointment.ListOpenByManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		orders = []model.OrderWithDetails{}
+	}
+	if appointments == nil {
+		appointments = []model.ServiceAppointmentWithDetails{}
+	}
+	return &model.MyQueue{Orders: orders, Appointments: appointments}, nil
+}
+
+func (s *AssignmentService) Workload(ctx context.Context) (*model.WorkloadOverview, error) {
+	w, err := s.repo.Assignment.Workload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range w.OrderStatusDurations {
+		w.OrderStatusDurations[i].AvgHours = math.Round(w.OrderStatusDurations[i].AvgHours*10) / 10
+	}
+	return w, nil
+}
+
+// autoAssign applies the entity's assignment rule to a newly created item. Failures are logged
+// and never fail the creation itself; the item simply stays in the unassigned pool.
+func autoAssign(ctx context.Context, repo *repository.Repository, entity string, id uuid.UUID) {
+	if _, err := repo.Assignment.AutoAssign(ctx, entity, id, pickAssignee); err != nil {
+		slog.Warn("auto-assignment failed", "entity", entity, "id", id, "err", err)
+	}
+}
+
+// pickAssignee chooses a candidate (ordered by user_id) per the rule's strategy. Round-robin takes
+// the candidate after the last assignee; least-loaded takes the one with the fewest open items of
+// the entity, breaking ties in round-robin order.
+func pickAssignee(rule model.AssignmentRule, candidates []model.AssigneeLoad) *uuid.UUID {
+	if len(candidates) == 0 || rule.Strategy == assignmentStrategyManual {
+		return nil
+	}
+	start := 0
+	if rule.LastAssigneeID != nil {
+		for i, c := range candidates {
+			if c.UserID == *rule.LastAssigneeID {
+				start = (i + 1) % len(candidates)
+				break
+			}
+		}
+	}
+	if rule.Strategy != assignmentStrategyLeastLoaded {
+		id := candidates[start].UserID
+		return &id
+	}
+
+	load := func(c model.AssigneeLoad) int {
+		if rule.EntityType == assignmentEntityAppointment {
+			return c.OpenAppointments
+		}
+		return c.OpenOrders
+	}
+	best := start
+	for k := 1; k < len(candidates); k++ {
+		i := (start + k) % len(candidates)
+		if load(candidates[i]) < load(candidates[best]) {
+			best = i
+		}
+	}
+	id := candidates[best].UserID
+	return &id
+}
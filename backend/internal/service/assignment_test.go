@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestPickAssignee(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	c := uuid.MustParse("00000000-0000-0000-0000-00000000000c")
+	stranger := uuid.MustParse("00000000-0000-0000-0000-0000000000ff")
+	candidates := []model.AssigneeLoad{
+		{UserID: a, OpenOrders: 3, OpenAppointments: 0},
+		{UserID: b, OpenOrders: 1, OpenAppointments: 2},
+		{UserID: c, OpenOrders: 1, OpenAppointments: 2},
+	}
+
+	tests := []struct {
+		name string
+		rule model.AssignmentRule
+		want *uuid.UUID
+	}{
+		{"manual never picks", model.AssignmentRule{EntityType: "order", Strategy: "manual"}, nil},
+		{"round robin starts at first", model.AssignmentRule{EntityType: "order", Strategy: "round_robin"}, &a},
+		{"round robin continues after last", model.AssignmentRule{EntityType: "order", Strategy: "round_robin", LastAssigneeID: &a}, &b},
+		{"round robin wraps around", model.AssignmentRule{EntityType: "order", Strategy: "round_robin", LastAssigneeID: &c}, &a},
+		{"round robin with departed last assignee", model.AssignmentRule{EntityType: "order", Strategy: "round_robin", LastAssigneeID: &stranger}, &a},
+		{"least loaded by orders", model.AssignmentRule{EntityType: "order", Strategy: "least_loaded"}, &b},
+		{"least loaded tie follows rotation", model.AssignmentRule{EntityType: "order", Strategy: "least_loaded", LastAssigneeID: &b}, &c},
+		{"least loaded by appointments", model.AssignmentRule{EntityType: "appointment", Strategy: "least_loaded", LastAssigneeID: &a}, &a},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickAssignee(tt.rule, candidates)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("pickAssignee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := pickAssignee(model.AssignmentRule{Strategy: "round_robin"}, nil); got != nil {
+		t.Fatalf("pickAssignee() with no candidates = %v, want nil", got)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// resourcePlan allocates a bay and a technician to a booking. A branch without bays falls back to
+// capacity (concurrent_bays); technicians are allocated only where the branch has any.
+type resourcePlan struct {
+	capacity int
+	// All active resources of each kind and the ones qualified for the booked services, least
+	// specialised first so that scarce equipment stays free for the services that need it.
+	bays, technicians                 []model.ServiceResource
+	matchingBays, matchingTechnicians []model.ServiceResource
+}
+
+// newResourcePlan builds the plan for the requirements of the selected service types; it fails
+// when no resource of the branch can perform them.
+func newResourcePlan(branch *model.Branch, resources []model.ServiceResource, types []model.ServiceType) (*resourcePlan, error) {
+	var needBay, needTech []string
+	for _, t := range types {
+		needBay = append(needBay, t.RequiredBaySkills...)
+		needTech = append(needTech, t.RequiredTechnicianSkills...)
+	}
+	p := &resourcePlan{capacity: branch.ConcurrentBays}
+	for _, r := range resources {
+		if !r.IsActive {
+			continue
+		}
+		switch r.Kind {
+		case model.ResourceKindBay:
+			p.bays = append(p.bays, r)
+			if hasSkills(r, needBay) {
+				p.matchingBays = append(p.matchingBays, r)
+			}
+		case model.ResourceKindTechnician:
+			p.technicians = append(p.technicians, r)
+			if hasSkills(r, needTech) {
+				p.matchingTechnicians = append(p.matchingTechnicians, r)
+			}
+		}
+	}
+	if len(needBay) > 0 && len(p.matchingBays) == 0 {
+		return nil, apperr.BadRequest("no bay at this branch is equipped for the selected services")
+	}
+	if len(needTech) > 0 && len(p.matchingTechnicians) == 0 {
+		return nil, apperr.BadRequest("no technician at this branch is qualified for the selected services")
+	}
+	sortBySpecialisation(p.matchingBays)
+	sortBySpecialisation(p.matchingTechnicians)
+	return p, nil
+}
+
+// loadResourcePlan reads the active branch resources and builds the plan for the service types.
+func loadResourcePlan(ctx context.Context, repos *repository.Repository, branch *model.Branch, types []model.ServiceType) (*resourcePlan, error) {
+	resources, err := repos.ServiceResource.ListByBranch(ctx, branch.BranchID, true)
+	if err != nil {
+		return nil, err
+	}
+	return newResourcePlan(branch, resources, types)
+}
+
+// allocate picks a free qualified bay and technician for [from, to) among the busy intervals.
+// Appointments booked before resources were set up hold no bay and take any free one.
+func (p *resourcePlan) allocate(busy []model.BusyInterval, from, to time.Time) (model.ResourceAssignment, bool) {
+	var overlapping []model.BusyInterval
+	for _, b := range busy {
+		if b.Start.Before(to) && b.End.After(from) {
+			overlapping = append(overlapping, b)
+		}
+	}
+	var out model.ResourceAssignment
+	if len(p.bays) == 0 {
+		if len(overlapping) >= p.capacity {
+			return model.ResourceAssignment{}, false
+		}
+	} else {
+		bay, ok := pickResource(p.bays, p.matchingBays, overlapping, func(b model.BusyInterval) *uuid.UUID { return b.BayID })
+		if !ok {
+			return model.ResourceAssignment{}, false
+		}
+		out.BayID = bay
+	}
+	if len(p.technicians) == 0 {
+		return out, true
+	}
+	tech, ok := pickResource(p.technicians, p.matchingTechnicians, overlapping, func(b model.BusyInterval) *uuid.UUID { return b.TechnicianID })
+	if !ok {
+		return model.ResourceAssignment{}, false
+	}
+	out.TechnicianID = tech
+	return out, true
+}
+
+// fits adapts allocate for slot listing.
+func (p *resourcePlan) fits(busy []model.BusyInterval) func(from, to time.Time) bool {
+	return func(from, to time.Time) bool {
+		_, ok := p.allocate(busy, from, to)
+		return ok
+	}
+}
+
+// pickResource returns the first candidate not held by an overlapping booking, provided the
+// bookings without a resource of this kind still leave one of all resources free.
+func pickResource(all, candidates []model.ServiceResource, overlapping []model.BusyInterval, held func(model.BusyInterval) *uuid.UUID) (*uuid.UUID, bool) {
+	taken := make(map[uuid.UUID]bool, len(overlapping))
+	unassigned := 0
+	for _, b := range overlapping {
+		if id := held(b); id != nil {
+			taken[*id] = true
+		} else {
+			unassigned++
+		}
+	}
+	free := 0
+	for _, r := range all {
+		if !taken[r.ResourceID] {
+			free++
+		}
+	}
+	if free-unassigned <= 0 {
+		return nil, false
+	}
+	for _, r := range candidates {
+		if !taken[r.ResourceID] {
+			id := r.ResourceID
+			return &id, true
+		}
+	}
+	return nil, false
+}
+
+// capacityFits reports a slot free while fewer than capacity busy intervals overlap it.
+func capacityFits(busy []model.BusyInterval, capacity int) func(from, to time.Time) bool {
+	return func(from, to time.Time) bool {
+		n := 0
+		for _, b := range busy {
+			if b.Start.Before(to) && b.End.After(from) {
+				n++
+			}
+		}
+		return n < capacity
+	}
+}
+
+func hasSkills(r model.ServiceResource, need []string) bool {
+	have := make(map[string]bool, len(r.Skills))
+	for _, s := range r.Skills {
+		have[s] = true
+	}
+	for _, s := range need {
+		if !have[s] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortBySpecialisation(rs []model.ServiceResource) {
+	sort.SliceStable(rs, func(i, j int) bool {
+		if len(rs[i].Skills) != len(rs[j].Skills) {
+			return len(rs[i].Skills) < len(rs[j].Skills)
+		}
+		return rs[i].Name < rs[j].Name
+	})
+}
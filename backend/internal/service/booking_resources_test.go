@@ -0,0 +1,148 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func testResource(kind, name string, skills ...string) model.ServiceResource {
+	return model.ServiceResource{ResourceID: uuid.New(), Kind: kind, Name: name, Skills: skills, IsActive: true}
+}
+
+func TestNewResourcePlan_Requirements(t *testing.T) {
+	branch := &model.Branch{ConcurrentBays: 2}
+	resources := []model.ServiceResource{
+		testResource(model.ResourceKindBay, "Bay 1"),
+		testResource(model.ResourceKindTechnician, "Ivan", "engine"),
+	}
+	if _, err := newResourcePlan(branch, resources, []model.ServiceType{{RequiredBaySkills: []string{"lift"}}}); err == nil {
+		t.Fatal("no bay with a lift should fail")
+	}
+	if _, err := newResourcePlan(branch, resources, []model.ServiceType{{RequiredTechnicianSkills: []string{"diagnostics"}}}); err == nil {
+		t.Fatal("no diagnostics technician should fail")
+	}
+	if _, err := newResourcePlan(branch, resources, []model.ServiceType{{RequiredTechnicianSkills: []string{"engine"}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResourcePlan_PrefersLeastSpecialised(t *testing.T) {
+	lift := testResource(model.ResourceKindBay, "Bay A", "lift", "alignment")
+	plain := testResource(model.ResourceKindBay, "Bay B")
+	plan, err := newResourcePlan(&model.Branch{ConcurrentBays: 2}, []model.ServiceResource{lift, plain}, []model.ServiceType{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	got, ok := plan.allocate(nil, from, to)
+	if !ok || got.BayID == nil || *got.BayID != plain.ResourceID {
+		t.Fatalf("plain bay first: %+v %v", got, ok)
+	}
+	got, ok = plan.allocate([]model.BusyInterval{{Start: from, End: to, ResourceAssignment: model.ResourceAssignment{BayID: &plain.ResourceID}}}, from, to)
+	if !ok || *got.BayID != lift.ResourceID {
+		t.Fatalf("falls back to the lift bay: %+v %v", got, ok)
+	}
+	if got.TechnicianID != nil {
+		t.Fatal("branch without technicians assigns none")
+	}
+}
+
+func TestResourcePlan_AllocatesTechnician(t *testing.T) {
+	bay1 := testResource(model.ResourceKindBay, "Bay 1")
+	bay2 := testResource(model.ResourceKindBay, "Bay 2")
+	tech := testResource(model.ResourceKindTechnician, "Ivan", "engine")
+	plan, err := newResourcePlan(&model.Branch{ConcurrentBays: 2}, []model.ServiceResource{bay1, bay2, tech},
+		[]model.ServiceType{{RequiredTechnicianSkills: []string{"engine"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	got, ok := plan.allocate(nil, from, to)
+	if !ok || got.TechnicianID == nil || *got.TechnicianID != tech.ResourceID {
+		t.Fatalf("got %+v %v", got, ok)
+	}
+	// A bay is free but the only technician is busy.
+	busy := []model.BusyInterval{{Start: from, End: to, ResourceAssignment: model.ResourceAssignment{BayID: &bay1.ResourceID, TechnicianID: &tech.ResourceID}}}
+	if _, ok := plan.allocate(busy, from, to); ok {
+		t.Fatal("technician is already booked")
+	}
+	if _, ok := plan.allocate(busy, to, to.Add(time.Hour)); !ok {
+		t.Fatal("next hour is free")
+	}
+}
+
+func TestResourcePlan_LegacyBookingsHoldABay(t *testing.T) {
+	bay1 := testResource(model.ResourceKindBay, "Bay 1")
+	bay2 := testResource(model.ResourceKindBay, "Bay 2")
+	plan, err := newResourcePlan(&model.Branch{ConcurrentBays: 5}, []model.ServiceResource{bay1, bay2}, []model.ServiceType{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	// One booking made before bays were configured and one on Bay 1 leave no bay free.
+	busy := []model.BusyInterval{
+		{Start: from, End: to},
+		{Start: from, End: to, ResourceAssignment: model.ResourceAssignment{BayID: &bay1.ResourceID}},
+	}
+	if _, ok := plan.allocate(busy, from, to); ok {
+		t.Fatal("both bays are occupied")
+	}
+	if _, ok := plan.allocate(busy[:1], from, to); !ok {
+		t.Fatal("one bay is still free")
+	}
+}
+
+func TestResourcePlan_NoBaysUsesCapacity(t *testing.T) {
+	plan, err := newResourcePlan(&model.Branch{ConcurrentBays: 1}, nil, []model.ServiceType{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	if got, ok := plan.allocate(nil, from, to); !ok || got.BayID != nil {
+		t.Fatalf("got %+v %v", got, ok)
+	}
+	if plan.fits([]model.BusyInterval{{Start: from, End: to}})(from, to) {
+		t.Fatal("capacity 1 is full")
+	}
+}
+
+func TestBuildWorkshopSchedule(t *testing.T) {
+	bay := testResource(model.ResourceKindBay, "Bay 1")
+	retired := testResource(model.ResourceKindBay, "Bay 0")
+	retired.IsActive = false
+	tech := testResource(model.ResourceKindTechnician, "Ivan")
+	at := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+
+	appt := func(h int, a model.ResourceAssignment) model.ServiceAppointmentWithDetails {
+		var out model.ServiceAppointmentWithDetails
+		out.AppointmentDate = at.Add(time.Duration(h) * time.Hour)
+		out.ResourceAssignment = a
+		return out
+	}
+	got := buildWorkshopSchedule(&model.Branch{}, "2026-05-18", []model.ServiceResource{retired, bay, tech}, []model.ServiceAppointmentWithDetails{
+		appt(3, model.ResourceAssignment{BayID: &bay.ResourceID, TechnicianID: &tech.ResourceID}),
+		appt(1, model.ResourceAssignment{BayID: &bay.ResourceID}),
+		appt(2, model.ResourceAssignment{}),
+	})
+	if len(got.Bays) != 1 || len(got.Bays[0].Appointments) != 2 {
+		t.Fatalf("bays: %+v", got.Bays)
+	}
+	if !got.Bays[0].Appointments[0].AppointmentDate.Before(got.Bays[0].Appointments[1].AppointmentDate) {
+		t.Fatal("lane is not in start order")
+	}
+	if len(got.Technicians) != 1 || len(got.Technicians[0].Appointments) != 1 {
+		t.Fatalf("technicians: %+v", got.Technicians)
+	}
+	if len(got.Unassigned) != 1 {
+		t.Fatalf("unassigned: %+v", got.Unassigned)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/carkeeper/backend/internal/apperr"
 	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
 	"github.com/google/uuid"
 )
 
@@ -25,107 +26,285 @@ func totalDurationMinutes(types []model.ServiceType) int {
 	return sum
 }
 
-func validateAppointmentSlot(branch *model.Branch, start time.Time, durationMin int) error {
+// branchDay is one branch-local calendar day with its resolved working hours.
+type branchDay struct {
+	start time.Time // local midnight
+	hours *model.BranchDayHours
+}
+
+// closed reports whether the branch does not work on the day.
+func (d branchDay) closed() bool {
+	return d.hours == nil
+}
+
+// resolveBranchDays resolves n consecutive days from first (local midnight): a calendar exception
+// replaces the weekly schedule, and a weekday missing from the schedule is a day off.
+func resolveBranchDays(weekly []model.BranchWeeklyHours, exceptions []model.BranchCalendarException, first time.Time, n int) []branchDay {
+	byWeekday := make(map[time.Weekday]model.BranchDayHours, len(weekly))
+	for _, w := range weekly {
+		byWeekday[time.Weekday(w.Weekday)] = w.BranchDayHours
+	}
+	byDate := make(map[string]model.BranchCalendarException, len(exceptions))
+	for _, e := range exceptions {
+		byDate[e.Day] = e
+	}
+	days := make([]branchDay, 0, n)
+	for i := 0; i < n; i++ {
+		d := branchDay{start: first.AddDate(0, 0, i)}
+		if e, ok := byDate[d.start.Format("2006-01-02")]; ok {
+			if !e.IsClosed {
+				d.hours = e.Hours
+			}
+		} else if h, ok := byWeekday[d.start.Weekday()]; ok {
+			d.hours = &h
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+// loadBranchDays resolves the branch calendar for n days from first (local midnight).
+func loadBranchDays(ctx context.Context, repos *repository.Repository, branchID uuid.UUID, first time.Time, n int) ([]branchDay, error) {
+	weekly, err := repos.BranchSchedule.Weekly(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := repos.BranchSchedule.Exceptions(ctx, branchID, first, first.AddDate(0, 0, n))
+	if err != nil {
+		return nil, err
+	}
+	return resolveBranchDays(weekly, exceptions, first, n), nil
+}
+
+// checkBranchSlot validates a booking start against the branch calendar of its local day.
+func checkBranchSlot(ctx context.Context, repos *repository.Repository, branch *model.Branch, start time.Time, durationMin int, onGrid bool) error {
+	local := start.In(loadBranchLocation(branch.Timezone))
+	first := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	days, err := loadBranchDays(ctx, repos, branch.BranchID, first, 1)
+	if err != nil {
+		return err
+	}
+	return validateAppointmentSlot(branch, days[0], start, durationMin, onGrid)
+}
+
+// workingSegments splits the day hours around the break into bookable [start, end) minute ranges.
+func workingSegments(h model.BranchDayHours) [][2]int {
+	if h.BreakStartMinutes == nil || h.BreakEndMinutes == nil {
+		return [][2]int{{h.OpenMinutes, h.CloseMinutes}}
+	}
+	return [][2]int{{h.OpenMinutes, *h.BreakStartMinutes}, {*h.BreakEndMinutes, h.CloseMinutes}}
+}
+
+// validateAppointmentSlot checks that start lies on day and that the booking fits one working
+// segment of it; with onGrid it must also start on the slot grid, which restarts after the break.
+// Staff may book off the grid.
+func validateAppointmentSlot(branch *model.Branch, day branchDay, start time.Time, durationMin int, onGrid bool) error {
 	if durationMin <= 0 {
 		return apperr.BadRequest("invalid service duration")
 	}
 	if err := validateBranchScheduleConfig(branch); err != nil {
 		return err
 	}
-	loc := loadBranchLocation(branch.Timezone)
-	local := start.In(loc)
-	if local.Weekday() == time.Sunday {
+	local := start.In(day.start.Location())
+	if !sameDay(local, day.start) {
+		return apperr.BadRequest("invalid appointment time slot")
+	}
+	if day.closed() {
 		return apperr.BadRequest("branch is closed on this day")
 	}
-	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
-	minsFromMidnight := int(local.Sub(dayStart).Minutes())
-	if minsFromMidnight < branch.WorkdayStartMinutes || minsFromMidnight+durationMin > branch.WorkdayEndMinutes {
+	mins := int(local.Sub(day.start).Minutes())
+	if mins < day.hours.OpenMinutes || mins+durationMin > day.hours.CloseMinutes {
 		return apperr.BadRequest("appointment outside branch working hours")
 	}
-	if (minsFromMidnight-branch.WorkdayStartMinutes)%branch.SlotStepMinutes != 0 {
-		return apperr.BadRequest("invalid appointment time slot")
+	for _, seg := range workingSegments(*day.hours) {
+		if mins >= seg[0] && mins+durationMin <= seg[1] {
+			if onGrid && (mins-seg[0])%branch.SlotStepMinutes != 0 {
+				return apperr.BadRequest("invalid appointment time slot")
+			}
+			return nil
+		}
 	}
-	return nil
+	return apperr.BadRequest("appointment overlaps the branch break")
 }
 
+const (
+	availabilityDefaultDays = 14
+	availabilityMaxDays     = 62
+)
+
 // BranchAvailability returns concrete UTC start times for which booking is possible on a calendar day.
 func (s *ServiceService) BranchAvailability(ctx context.Context, branchID uuid.UUID, dateStr string, serviceTypeIDs []uuid.UUID) (*model.BranchAvailability, error) {
+	branch, types, err := s.availabilityTarget(ctx, branchID, serviceTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadBranchLocation(branch.Timezone)
+	dayStart, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return nil, apperr.BadRequest("invalid date format")
+	}
+	days, err := s.availabilityDays(ctx, branch, types, dayStart, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &model.BranchAvailability{
+		SlotStarts:      days[0].SlotStarts,
+		Timezone:        branch.Timezone,
+		DurationMinutes: totalDurationMinutes(types),
+		Hours:           days[0].Hours,
+	}, nil
+}
+
+// BranchAvailabilityRange returns free slots with a per-day summary for every branch-local day from
+// fromStr to toStr inclusive (YYYY-MM-DD; defaults to two weeks from today).
+func (s *ServiceService) BranchAvailabilityRange(ctx context.Context, branchID uuid.UUID, fromStr, toStr string, serviceTypeIDs []uuid.UUID) (*model.BranchAvailabilityRange, error) {
+	branch, types, err := s.availabilityTarget(ctx, branchID, serviceTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadBranchLocation(branch.Timezone)
+	from, to, msg := dateWindow(fromStr, toStr, loc, time.Now(), availabilityDefaultDays, availabilityMaxDays)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	days, err := s.availabilityDays(ctx, branch, types, from, daysBetween(from, to))
+	if err != nil {
+		return nil, err
+	}
+	return &model.BranchAvailabilityRange{
+		Timezone:        branch.Timezone,
+		DurationMinutes: totalDurationMinutes(types),
+		From:            from.Format("2006-01-02"),
+		To:              to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:            days,
+	}, nil
+}
+
+// availabilityTarget loads the active branch and the requested service types.
+func (s *ServiceService) availabilityTarget(ctx context.Context, branchID uuid.UUID, serviceTypeIDs []uuid.UUID) (*model.Branch, []model.ServiceType, error) {
 	branch, err := s.repo.Branch.GetByID(ctx, branchID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get branch: %w", err)
+		return nil, nil, fmt.Errorf("failed to get branch: %w", err)
 	}
 	if !branch.IsActive {
-		return nil, apperr.BadRequest("branch is not active")
+		return nil, nil, apperr.BadRequest("branch is not active")
 	}
 	if err := validateBranchScheduleConfig(branch); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	uniq := dedupeUUIDs(serviceTypeIDs)
 	if len(uniq) == 0 {
-		return nil, apperr.BadRequest("at least one service type is required")
+		return nil, nil, apperr.BadRequest("at least one service type is required")
 	}
 
 	types, err := s.repo.ServiceType.GetByIDs(ctx, uniq)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(types) != len(uniq) {
-		return nil, apperr.BadRequest("one or more service types are not available")
+		return nil, nil, apperr.BadRequest("one or more service types are not available")
 	}
-	duration := totalDurationMinutes(types)
+	return branch, types, nil
+}
 
-	loc := loadBranchLocation(branch.Timezone)
-	dayStart, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+// availabilityDays loads the branch calendar, resources and all scheduled appointments for n days
+// from first with one query each and lists the free slots of every day.
+func (s *ServiceService) availabilityDays(ctx context.Context, branch *model.Branch, types []model.ServiceType, first time.Time, n int) ([]model.BranchAvailabilityDay, error) {
+	plan, err := loadResourcePlan(ctx, s.repo, branch, types)
 	if err != nil {
-		return nil, apperr.BadRequest("invalid date format")
+		return nil, err
 	}
-	if dayStart.Weekday() == time.Sunday {
-		return &model.BranchAvailability{
-			SlotStarts:      nil,
-			Timezone:        branch.Timezone,
-			DurationMinutes: duration,
-		}, nil
+	days, err := loadBranchDays(ctx, s.repo, branch.BranchID, first, n)
+	if err != nil {
+		return nil, err
 	}
+	busy, err := s.repo.ServiceAppointment.ScheduledIntervals(ctx, branch.BranchID, first, first.AddDate(0, 0, n))
+	if err != nil {
+		return nil, err
+	}
+	return summarizeAvailability(branch, days, totalDurationMinutes(types), time.Now(), plan.fits(busy)), nil
+}
 
-	workStart := dayStart.Add(time.Duration(branch.WorkdayStartMinutes) * time.Minute)
-	workEnd := dayStart.Add(time.Duration(branch.WorkdayEndMinutes) * time.Minute)
-	window := int(workEnd.Sub(workStart).Minutes())
-	if duration > window {
-		return &model.BranchAvailability{
-			SlotStarts:      nil,
-			Timezone:        branch.Timezone,
-			DurationMinutes: duration,
-		}, nil
+// summarizeAvailability lists the free slots of each day with their count and the first one.
+func summarizeAvailability(branch *model.Branch, days []branchDay, durationMin int, now time.Time, fits func(from, to time.Time) bool) []model.BranchAvailabilityDay {
+	out := make([]model.BranchAvailabilityDay, 0, len(days))
+	for _, d := range days {
+		slots := freeSlotStarts(branch, d, durationMin, now, fits)
+		day := model.BranchAvailabilityDay{
+			Date:       d.start.Format("2006-01-02"),
+			Hours:      d.hours,
+			FreeSlots:  len(slots),
+			SlotStarts: []time.Time{},
+		}
+		if len(slots) > 0 {
+			day.SlotStarts = slots
+			day.FirstFreeSlot = &slots[0]
+		}
+		out = append(out, day)
 	}
+	return out
+}
 
-	step := time.Duration(branch.SlotStepMinutes) * time.Minute
-	now := time.Now()
-	minLead := 15 * time.Minute
+// freeSlotStarts lists the UTC starts of durationMin-long slots on the branch day for which fits
+// finds room. Slots never span the break and must start at least a short lead time after now.
+func freeSlotStarts(branch *model.Branch, day branchDay, durationMin int, now time.Time, fits func(from, to time.Time) bool) []time.Time {
+	if day.closed() {
+		return nil
+	}
+	earliest := now.Add(15 * time.Minute)
 
 	var slots []time.Time
-	for t := workStart; ; t = t.Add(step) {
-		tEnd := t.Add(time.Duration(duration) * time.Minute)
-		if tEnd.After(workEnd) {
-			break
+	for _, seg := range workingSegments(*day.hours) {
+		for m := seg[0]; m+durationMin <= seg[1]; m += branch.SlotStepMinutes {
+			t := day.start.Add(time.Duration(m) * time.Minute)
+			if t.Before(earliest) {
+				continue
+			}
+			if fits(t, t.Add(time.Duration(durationMin)*time.Minute)) {
+				slots = append(slots, t.UTC())
+			}
 		}
-		if sameDay(t, now.In(loc)) && t.Before(now.In(loc).Add(minLead)) {
-			continue
-		}
-		n, err := s.repo.ServiceAppointment.CountOverlappingScheduled(ctx, branchID, t, tEnd)
+	}
+	return slots
+}
+
+// dateWindow resolves [from, to) in loc from inclusive YYYY-MM-DD dates; from defaults to today
+// and to to defaultDays after from, and the range may not exceed maxDays.
+func dateWindow(fromStr, toStr string, loc *time.Location, now time.Time, defaultDays, maxDays int) (time.Time, time.Time, string) {
+	local := now.In(loc)
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if fromStr != "" {
+		d, err := time.ParseInLocation("2006-01-02", fromStr, loc)
 		if err != nil {
-			return nil, err
+			return time.Time{}, time.Time{}, "from must be YYYY-MM-DD"
 		}
-		if n < branch.ConcurrentBays {
-			slots = append(slots, t.UTC())
+		from = d
+	}
+	to := from.AddDate(0, 0, defaultDays)
+	if toStr != "" {
+		d, err := time.ParseInLocation("2006-01-02", toStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, "to must be YYYY-MM-DD"
 		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, "to must not be before from"
+	}
+	if to.After(from.AddDate(0, 0, maxDays)) {
+		return time.Time{}, time.Time{}, fmt.Sprintf("calendar range cannot exceed %d days", maxDays)
 	}
+	return from, to, ""
+}
 
-	return &model.BranchAvailability{
-		SlotStarts:      slots,
-		Timezone:        branch.Timezone,
-		DurationMinutes: duration,
-	}, nil
+// daysBetween counts the calendar days in [from, to) for local midnights from and to.
+func daysBetween(from, to time.Time) int {
+	n := 0
+	for from.AddDate(0, 0, n).Before(to) {
+		n++
+	}
+	return n
 }
 
 func sameDay(a, b time.Time) bool {
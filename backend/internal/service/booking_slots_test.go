@@ -34,6 +34,15 @@ func TestDedupeUUIDs(t *testing.T) {
 	}
 }
 
+// weekdayHours is a Monday to Saturday schedule with the given hours.
+func weekdayHours(h model.BranchDayHours) []model.BranchWeeklyHours {
+	var out []model.BranchWeeklyHours
+	for d := 1; d <= 6; d++ {
+		out = append(out, model.BranchWeeklyHours{Weekday: d, BranchDayHours: h})
+	}
+	return out
+}
+
 func TestValidateAppointmentSlot_OutsideWorkday(t *testing.T) {
 	branch := &model.Branch{
 		Timezone:            "Europe/Moscow",
@@ -45,7 +54,195 @@ func TestValidateAppointmentSlot_OutsideWorkday(t *testing.T) {
 	loc, _ := time.LoadLocation("Europe/Moscow")
 	// Monday 2026-05-18 06:00 local — before workday
 	start := time.Date(2026, 5, 18, 6, 0, 0, 0, loc)
-	if err := validateAppointmentSlot(branch, start, 30); err == nil {
+	day := resolveBranchDays(weekdayHours(model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 1080}), nil,
+		time.Date(2026, 5, 18, 0, 0, 0, 0, loc), 1)[0]
+	if err := validateAppointmentSlot(branch, day, start, 30, true); err == nil {
 		t.Fatal("expected outside working hours error")
 	}
 }
+
+func TestValidateAppointmentSlot_Break(t *testing.T) {
+	branch := &model.Branch{
+		WorkdayStartMinutes: 540,
+		WorkdayEndMinutes:   1080,
+		SlotStepMinutes:     60,
+		ConcurrentBays:      1,
+	}
+	breakStart, breakEnd := 780, 810 // 13:00-13:30
+	monday := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	days := resolveBranchDays(weekdayHours(model.BranchDayHours{
+		OpenMinutes: 540, CloseMinutes: 1080, BreakStartMinutes: &breakStart, BreakEndMinutes: &breakEnd,
+	}), nil, monday.AddDate(0, 0, -1), 2)
+	sunday, day := days[0], days[1]
+
+	cases := []struct {
+		name     string
+		at       time.Duration
+		duration int
+		ok       bool
+	}{
+		{"ends at the break", 11 * time.Hour, 120, true},
+		{"overlaps the break", 12 * time.Hour, 90, false},
+		{"starts the afternoon grid", 13*time.Hour + 30*time.Minute, 60, true},
+		{"off the afternoon grid", 14 * time.Hour, 60, false},
+	}
+	for _, c := range cases {
+		err := validateAppointmentSlot(branch, day, monday.Add(c.at), c.duration, true)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: err = %v, want ok %v", c.name, err, c.ok)
+		}
+	}
+	if err := validateAppointmentSlot(branch, sunday, sunday.start.Add(10*time.Hour), 60, true); err == nil {
+		t.Fatal("sunday is not in the weekly schedule")
+	}
+	if err := validateAppointmentSlot(branch, sunday, monday.Add(10*time.Hour), 60, true); err == nil {
+		t.Fatal("start must lie on the resolved day")
+	}
+	// Staff bookings may start off the grid but still respect hours and the break.
+	if err := validateAppointmentSlot(branch, day, monday.Add(14*time.Hour+10*time.Minute), 60, false); err != nil {
+		t.Fatalf("off-grid staff booking: %v", err)
+	}
+	if err := validateAppointmentSlot(branch, day, monday.Add(12*time.Hour+40*time.Minute), 30, false); err == nil {
+		t.Fatal("off-grid booking still cannot overlap the break")
+	}
+}
+
+func TestResolveBranchDays_Exceptions(t *testing.T) {
+	monday := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	weekly := weekdayHours(model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 1080})
+	exceptions := []model.BranchCalendarException{
+		{Day: "2026-05-19", IsClosed: true},
+		{Day: "2026-05-20", Hours: &model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 780}},
+		{Day: "2026-05-24", Hours: &model.BranchDayHours{OpenMinutes: 600, CloseMinutes: 900}},
+	}
+	days := resolveBranchDays(weekly, exceptions, monday, 7)
+	if len(days) != 7 {
+		t.Fatalf("got %d days", len(days))
+	}
+	if days[0].closed() || days[0].hours.CloseMinutes != 1080 {
+		t.Fatalf("monday follows the weekly schedule: %+v", days[0].hours)
+	}
+	if !days[1].closed() {
+		t.Fatal("tuesday is a holiday")
+	}
+	if days[2].closed() || days[2].hours.CloseMinutes != 780 {
+		t.Fatalf("wednesday is shortened: %+v", days[2].hours)
+	}
+	if days[6].closed() || days[6].hours.OpenMinutes != 600 {
+		t.Fatalf("sunday is an extra working day: %+v", days[6].hours)
+	}
+}
+
+func TestFreeSlotStarts_RespectsCapacityAndSunday(t *testing.T) {
+	branch := &model.Branch{
+		WorkdayStartMinutes: 540,
+		WorkdayEndMinutes:   660,
+		SlotStepMinutes:     60,
+	}
+	// Monday 2026-05-18: slots at 09:00 and 10:00; the 09:00 one already has a booking.
+	day := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	busy := []model.BusyInterval{{Start: day.Add(9 * time.Hour), End: day.Add(10 * time.Hour)}}
+	now := day.AddDate(0, 0, -1)
+	days := resolveBranchDays(weekdayHours(model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 660}), nil, day.AddDate(0, 0, -1), 2)
+	sunday, monday := days[0], days[1]
+
+	slots := freeSlotStarts(branch, monday, 60, now, capacityFits(busy, 1))
+	if len(slots) != 1 || !slots[0].Equal(day.Add(10*time.Hour)) {
+		t.Fatalf("capacity 1: got %v", slots)
+	}
+	slots = freeSlotStarts(branch, monday, 60, now, capacityFits(busy, 2))
+	if len(slots) != 2 {
+		t.Fatalf("capacity 2: got %v", slots)
+	}
+	slots = freeSlotStarts(branch, sunday, 60, now, capacityFits(busy, 2))
+	if len(slots) != 0 {
+		t.Fatalf("sunday: got %v", slots)
+	}
+}
+
+func TestFreeSlotStarts_SkipsBreak(t *testing.T) {
+	branch := &model.Branch{SlotStepMinutes: 60}
+	breakStart, breakEnd := 780, 810 // 13:00-13:30
+	monday := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	day := resolveBranchDays(weekdayHours(model.BranchDayHours{
+		OpenMinutes: 660, CloseMinutes: 930, BreakStartMinutes: &breakStart, BreakEndMinutes: &breakEnd,
+	}), nil, monday, 1)[0]
+
+	slots := freeSlotStarts(branch, day, 60, monday.AddDate(0, 0, -1), capacityFits(nil, 1))
+	want := []time.Duration{11 * time.Hour, 12 * time.Hour, 13*time.Hour + 30*time.Minute, 14*time.Hour + 30*time.Minute}
+	if len(slots) != len(want) {
+		t.Fatalf("got %v", slots)
+	}
+	for i, w := range want {
+		if !slots[i].Equal(monday.Add(w)) {
+			t.Fatalf("slot %d: got %v, want %v", i, slots[i], monday.Add(w))
+		}
+	}
+}
+
+func TestSummarizeAvailability(t *testing.T) {
+	branch := &model.Branch{SlotStepMinutes: 60}
+	monday := time.Date(2026, 5, 18, 0, 0, 0, 0, time.UTC)
+	days := resolveBranchDays(weekdayHours(model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 720}), nil, monday.AddDate(0, 0, -1), 3)
+	// Monday 09:00-11:00 is fully booked on the single bay; Tuesday is free.
+	fits := capacityFits([]model.BusyInterval{{Start: monday.Add(9 * time.Hour), End: monday.Add(11 * time.Hour)}}, 1)
+	now := monday.AddDate(0, 0, -2)
+
+	got := summarizeAvailability(branch, days, 60, now, fits)
+	if len(got) != 3 {
+		t.Fatalf("got %d days", len(got))
+	}
+	if got[0].Date != "2026-05-17" || got[0].FreeSlots != 0 || got[0].FirstFreeSlot != nil || got[0].Hours != nil {
+		t.Fatalf("sunday is closed: %+v", got[0])
+	}
+	if got[1].FreeSlots != 1 || !got[1].FirstFreeSlot.Equal(monday.Add(11*time.Hour)) {
+		t.Fatalf("monday: %+v", got[1])
+	}
+	if got[2].FreeSlots != 3 || !got[2].FirstFreeSlot.Equal(monday.AddDate(0, 0, 1).Add(9*time.Hour)) {
+		t.Fatalf("tuesday: %+v", got[2])
+	}
+
+	// Days already past have no slots.
+	got = summarizeAvailability(branch, days, 60, monday.AddDate(0, 0, 2), fits)
+	if got[1].FreeSlots != 0 || got[2].FreeSlots != 0 {
+		t.Fatalf("past days: %+v", got)
+	}
+}
+
+func TestCapacityFits(t *testing.T) {
+	base := time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC)
+	busy := []model.BusyInterval{
+		{Start: base, End: base.Add(time.Hour)},
+		{Start: base.Add(30 * time.Minute), End: base.Add(90 * time.Minute)},
+	}
+	cases := []struct {
+		from, to time.Duration
+		capacity int
+		want     bool
+	}{
+		{0, 30 * time.Minute, 1, false},
+		{0, 30 * time.Minute, 2, true},
+		{30 * time.Minute, time.Hour, 2, false},
+		{time.Hour, 2 * time.Hour, 2, true},
+		{90 * time.Minute, 2 * time.Hour, 1, true},
+		{-time.Hour, 0, 1, true},
+	}
+	for _, c := range cases {
+		if got := capacityFits(busy, c.capacity)(base.Add(c.from), base.Add(c.to)); got != c.want {
+			t.Errorf("[%v, %v) capacity %d: got %v, want %v", c.from, c.to, c.capacity, got, c.want)
+		}
+	}
+}
+
+func TestDaysBetween_DST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	// The range spans the switch to summer time on 2026-03-29.
+	from := time.Date(2026, 3, 27, 0, 0, 0, 0, loc)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, loc)
+	if got := daysBetween(from, to); got != 5 {
+		t.Fatalf("got %d", got)
+	}
+}
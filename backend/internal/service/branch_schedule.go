@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// AdminGetBranchSchedule returns the branch weekly schedule and its upcoming calendar exceptions.
+func (s *ServiceService) AdminGetBranchSchedule(ctx context.Context, branchID uuid.UUID) (*model.BranchSchedule, error) {
+	branch, err := s.scheduleBranch(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	weekly, err := s.repo.BranchSchedule.Weekly(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	local := time.Now().In(loadBranchLocation(branch.Timezone))
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	exceptions, err := s.repo.BranchSchedule.Exceptions(ctx, branchID, today, today.AddDate(0, 0, validate.BranchExceptionMaxDaysAhead+1))
+	if err != nil {
+		return nil, err
+	}
+	return &model.BranchSchedule{
+		BranchID:   branchID,
+		Timezone:   branch.Timezone,
+		Weekly:     weekly,
+		Exceptions: exceptions,
+	}, nil
+}
+
+// AdminReplaceBranchWeekly replaces the weekly schedule; weekdays left out become days off.
+// Existing bookings are kept even if they fall outside the new hours.
+func (s *ServiceService) AdminReplaceBranchWeekly(ctx context.Context, branchID uuid.UUID, days []model.BranchWeeklyHours) (*model.BranchSchedule, error) {
+	if _, err := s.scheduleBranch(ctx, branchID); err != nil {
+		return nil, err
+	}
+	days, msg := normalizeWeeklyHours(days)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if err := s.repo.BranchSchedule.ReplaceWeekly(ctx, branchID, days); err != nil {
+		return nil, err
+	}
+	return s.AdminGetBranchSchedule(ctx, branchID)
+}
+
+// AdminUpsertBranchException sets the calendar exception for a date, replacing an existing one.
+func (s *ServiceService) AdminUpsertBranchException(ctx context.Context, branchID, requester uuid.UUID, in model.BranchCalendarExceptionUpsert) (*model.BranchCalendarException, error) {
+	branch, err := s.scheduleBranch(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	in, msg := normalizeBranchException(in, loadBranchLocation(branch.Timezone), time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	return s.repo.BranchSchedule.UpsertException(ctx, branchID, in, requester)
+}
+
+// AdminDeleteBranchException drops a calendar exception so the weekly schedule applies again.
+func (s *ServiceService) AdminDeleteBranchException(ctx context.Context, branchID, exceptionID uuid.UUID) error {
+	return s.repo.BranchSchedule.DeleteException(ctx, branchID, exceptionID)
+}
+
+func (s *ServiceService) scheduleBranch(ctx context.Context, branchID uuid.UUID) (*model.Branch, error) {
+	branch, err := s.repo.Branch.GetByID(ctx, branchID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Branch not found")
+		}
+		return nil, err
+	}
+	return branch, nil
+}
+
+// normalizeWeeklyHours validates the weekly schedule and orders it Sunday first.
+func normalizeWeeklyHours(days []model.BranchWeeklyHours) ([]model.BranchWeeklyHours, string) {
+	if len(days) == 0 {
+		return nil, "weekly schedule needs at least one working day"
+	}
+	seen := make(map[int]bool, len(days))
+	for _, d := range days {
+		if msg := validate.Weekday(d.Weekday); msg != "" {
+			return nil, msg
+		}
+		if seen[d.Weekday] {
+			return nil, "weekday listed twice"
+		}
+		seen[d.Weekday] = true
+		if msg := validate.BranchHours(d.OpenMinutes, d.CloseMinutes, d.BreakStartMinutes, d.BreakEndMinutes); msg != "" {
+			return nil, msg
+		}
+	}
+	out := append([]model.BranchWeeklyHours(nil), days...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Weekday < out[j].Weekday })
+	return out, ""
+}
+
+// normalizeBranchException validates an exception: a closure carries no hours, an open day must.
+func normalizeBranchException(in model.BranchCalendarExceptionUpsert, loc *time.Location, now time.Time) (model.BranchCalendarExceptionUpsert, string) {
+	var msg string
+	if in.Day, msg = validate.BranchExceptionDay(in.Day, loc, now); msg != "" {
+		return in, msg
+	}
+	if in.Note, msg = validate.BranchExceptionNote(in.Note); msg != "" {
+		return in, msg
+	}
+	if in.IsClosed {
+		in.Hours = nil
+		return in, ""
+	}
+	if in.Hours == nil {
+		return in, "hours are required unless the branch is closed"
+	}
+	h := in.Hours
+	if msg := validate.BranchHours(h.OpenMinutes, h.CloseMinutes, h.BreakStartMinutes, h.BreakEndMinutes); msg != "" {
+		return in, msg
+	}
+	return in, ""
+}
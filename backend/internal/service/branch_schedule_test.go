@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestNormalizeWeeklyHours(t *testing.T) {
+	day := func(wd int) model.BranchWeeklyHours {
+		return model.BranchWeeklyHours{Weekday: wd, BranchDayHours: model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 1080}}
+	}
+	got, msg := normalizeWeeklyHours([]model.BranchWeeklyHours{day(6), day(0), day(3)})
+	if msg != "" {
+		t.Fatal(msg)
+	}
+	if got[0].Weekday != 0 || got[1].Weekday != 3 || got[2].Weekday != 6 {
+		t.Fatalf("not ordered: %+v", got)
+	}
+	if _, msg := normalizeWeeklyHours(nil); msg == "" {
+		t.Fatal("empty schedule should fail")
+	}
+	if _, msg := normalizeWeeklyHours([]model.BranchWeeklyHours{day(1), day(1)}); msg == "" {
+		t.Fatal("duplicate weekday should fail")
+	}
+	bad := day(2)
+	bad.CloseMinutes = 500
+	if _, msg := normalizeWeeklyHours([]model.BranchWeeklyHours{bad}); msg == "" {
+		t.Fatal("reversed hours should fail")
+	}
+}
+
+func TestNormalizeBranchException(t *testing.T) {
+	now := time.Date(2026, 12, 20, 10, 0, 0, 0, time.UTC)
+	closed, msg := normalizeBranchException(model.BranchCalendarExceptionUpsert{
+		Day: "2026-12-31", IsClosed: true, Hours: &model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 900},
+	}, time.UTC, now)
+	if msg != "" || closed.Hours != nil {
+		t.Fatalf("closure drops hours: %+v %q", closed, msg)
+	}
+	if _, msg := normalizeBranchException(model.BranchCalendarExceptionUpsert{Day: "2026-12-27"}, time.UTC, now); msg == "" {
+		t.Fatal("an open day needs hours")
+	}
+	short, msg := normalizeBranchException(model.BranchCalendarExceptionUpsert{
+		Day: "2026-12-30", Hours: &model.BranchDayHours{OpenMinutes: 540, CloseMinutes: 900},
+	}, time.UTC, now)
+	if msg != "" || short.Hours == nil || short.Hours.CloseMinutes != 900 {
+		t.Fatalf("shortened day: %+v %q", short, msg)
+	}
+}
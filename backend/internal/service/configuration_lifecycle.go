@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+// Notification texts are shown to customers as-is; %s receives the expiry date.
+const (
+	draftExpiringTitle     = "Черновик конфигурации скоро истечёт"
+	draftExpiringBody      = "Черновик конфигурации станет недоступен для заказа %s. Измените или подтвердите его, чтобы продлить срок."
+	draftExpiredTitle      = "Черновик конфигурации истёк"
+	draftExpiredBody       = "Черновик конфигурации перенесён в архив. Его можно восстановить в личном кабинете."
+	priceLockExpiringTitle = "Фиксация цены скоро закончится"
+	priceLockExpiringBody  = "Цена подтверждённой конфигурации действует до %s. Оформите заказ, чтобы сохранить её."
+)
+
+func draftExpiresAt(now time.Time, draftDays int) time.Time {
+	return now.AddDate(0, 0, draftDays)
+}
+
+func priceLockUntil(now time.Time, lockDays int) time.Time {
+	return now.AddDate(0, 0, lockDays)
+}
+
+// RunLifecycle expires stale drafts and warns owners of drafts and price locks that are about to
+// expire. Each step is idempotent, so overlapping runs on several instances are harmless.
+func (s *ConfiguratorService) RunLifecycle(ctx context.Context, now time.Time) (model.ConfigurationLifecycleRun, error) {
+	var run model.ConfigurationLifecycleRun
+	var err error
+	days := s.lifecycle.DraftExpiryDays
+	warnBefore := now.AddDate(0, 0, s.lifecycle.ExpiryWarningDays)
+
+	if run.DraftsExpired, err = s.repo.Configuration.ExpireDrafts(ctx, days, now, draftExpiredTitle, draftExpiredBody); err != nil {
+		return run, err
+	}
+	if s.lifecycle.ExpiryWarningDays == 0 {
+		return run, nil
+	}
+	if run.DraftWarnings, err = s.repo.Configuration.WarnExpiringDrafts(ctx, days, warnBefore, draftExpiringTitle, draftExpiringBody); err != nil {
+		return run, err
+	}
+	if run.PriceLockWarnings, err = s.repo.Configuration.WarnExpiringPriceLocks(ctx, now, warnBefore, priceLockExpiringTitle, priceLockExpiringBody); err != nil {
+		return run, err
+	}
+	return run, nil
+}
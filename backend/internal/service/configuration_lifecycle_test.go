@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func lockedConfiguration(status string, until time.Time) *model.Configuration {
+	trim, color := 2_000_000.0, 50_000.0
+	return &model.Configuration{
+		ConfigurationID:  uuid.New(),
+		Status:           status,
+		PriceLockedUntil: &until,
+		LockedTrimPrice:  &trim,
+		LockedColorPrice: &color,
+	}
+}
+
+func TestApplyPriceLock_ActiveLockUsesCapturedPrices(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := lockedConfiguration("confirmed", now.Add(48*time.Hour))
+	in := pricingInput{Scope: model.PricingScope{BasePrice: 2_300_000}, ColorPrice: 70_000, Now: now}
+
+	until := applyPriceLock(&in, cfg)
+	if until == nil || !until.Equal(*cfg.PriceLockedUntil) {
+		t.Fatalf("lock expiry = %v", until)
+	}
+	quote := applyPromotions(in, nil)
+	if quote.ListPrice != 2_050_000 {
+		t.Fatalf("list price = %v, want locked 2050000", quote.ListPrice)
+	}
+}
+
+func TestApplyPriceLock_IgnoresExpiredOrNonConfirmed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, cfg := range []*model.Configuration{
+		lockedConfiguration("confirmed", now),
+		lockedConfiguration("confirmed", now.Add(-time.Hour)),
+		lockedConfiguration("draft", now.Add(time.Hour)),
+		{Status: "confirmed"},
+	} {
+		in := pricingInput{Scope: model.PricingScope{BasePrice: 2_300_000}, ColorPrice: 70_000, Now: now}
+		if until := applyPriceLock(&in, cfg); until != nil {
+			t.Fatalf("unexpected lock for %+v", cfg)
+		}
+		if in.Scope.BasePrice != 2_300_000 || in.ColorPrice != 70_000 {
+			t.Fatalf("prices changed without an active lock: %+v", in)
+		}
+	}
+}
+
+func TestLifecycleDeadlines(t *testing.T) {
+	now := time.Date(2026, 3, 30, 9, 0, 0, 0, time.UTC)
+	if got := draftExpiresAt(now, 30); !got.Equal(time.Date(2026, 4, 29, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("draft expiry = %v", got)
+	}
+	if got := priceLockUntil(now, 7); !got.Equal(time.Date(2026, 4, 6, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("price lock = %v", got)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+const (
+	shareDefaultDays = 7
+	shareMaxDays     = 90
+	shareTokenBytes  = 32
+)
+
+// CreateShare issues a public read-only link for a configuration the requester can access.
+func (s *ConfiguratorService) CreateShare(ctx context.Context, configID, requester uuid.UUID, role string, in model.ConfigurationShareCreate) (*model.ConfigurationShare, error) {
+	if _, err := s.accessibleConfiguration(ctx, configID, requester, role); err != nil {
+		return nil, err
+	}
+	days := in.ExpiresInDays
+	if days == 0 {
+		days = shareDefaultDays
+	}
+	if days < 1 || days > shareMaxDays {
+		return nil, apperr.BadRequest("expires_in_days must be between 1 and 90")
+	}
+	token, err := newShareToken()
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return s.repo.ConfigurationShare.Create(ctx, configID, requester, token, time.Now().AddDate(0, 0, days))
+}
+
+func (s *ConfiguratorService) ListShares(ctx context.Context, configID, requester uuid.UUID, role string) ([]model.ConfigurationShare, error) {
+	if _, err := s.accessibleConfiguration(ctx, configID, requester, role); err != nil {
+		return nil, err
+	}
+	return s.repo.ConfigurationShare.ListByConfiguration(ctx, configID)
+}
+
+func (s *ConfiguratorService) RevokeShare(ctx context.Context, configID, shareID, requester uuid.UUID, role string) error {
+	if _, err := s.accessibleConfiguration(ctx, configID, requester, role); err != nil {
+		return err
+	}
+	return s.repo.ConfigurationShare.Revoke(ctx, configID, shareID)
+}
+
+// GetSharedConfiguration resolves a public token into a read-only view with the price breakdown.
+func (s *ConfiguratorService) GetSharedConfiguration(ctx context.Context, token string) (*model.SharedConfiguration, error) {
+	share, err := s.repo.ConfigurationShare.ResolveActive(ctx, strings.TrimSpace(token), true)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Share link is invalid or has expired")
+		}
+		return nil, err
+	}
+	config, err := s.repo.Configuration.GetByID(ctx, share.ConfigurationID)
+	if err != nil {
+		return nil, err
+	}
+	trim, err := s.repo.Trim.GetByID(ctx, config.TrimID)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.SharedConfiguration{
+		ConfigurationID: config.ConfigurationID,
+		TrimID:          config.TrimID,
+		BrandName:       trim.BrandName,
+		ModelName:       trim.ModelName,
+		GenerationName:  trim.GenerationName,
+		TrimName:        config.TrimName,
+		ImageURL:        trim.ImageURL,
+		ColorName:       config.ColorName,
+		ColorHex:        config.ColorHex,
+		Options:         config.Options,
+		TotalPrice:      config.TotalPrice,
+		ExpiresAt:       share.ExpiresAt,
+	}
+	if out.Options == nil {
+		out.Options = []model.Option{}
+	}
+	if quote, err := quoteConfiguration(ctx, s.repo, config, nil, time.Now()); err == nil {
+		out.Pricing = quote
+	}
+	return out, nil
+}
+
+// CloneConfiguration copies trim, color and options into a new draft owned by the requester.
+// Access is granted to the owner, staff with configurations.view_any, or a valid share token.
+func (s *ConfiguratorService) CloneConfiguration(ctx context.Context, configID, requester uuid.UUID, role string, in model.ConfigurationClone) (*model.ConfigurationWithDetails, error) {
+	config, err := s.repo.Configuration.GetByID(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
+		if in.ShareToken == nil {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		share, err := s.repo.ConfigurationShare.ResolveActive(ctx, strings.TrimSpace(*in.ShareToken), false)
+		if err != nil || share.ConfigurationID != configID {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+	}
+
+	optionIDs := make([]uuid.UUID, 0, len(config.Options))
+	for _, opt := range config.Options {
+		optionIDs = append(optionIDs, opt.OptionID)
+	}
+	return s.CreateConfiguration(ctx, requester, model.ConfigurationCreate{
+		TrimID:    config.TrimID,
+		ColorID:   config.ColorID,
+		OptionIDs: optionIDs,
+	})
+}
+
+func (s *ConfiguratorService) accessibleConfiguration(ctx context.Context, configID, requester uuid.UUID, role string) (*model.ConfigurationWithDetails, error) {
+	config, err := s.repo.Configuration.GetByID(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return config, nil
+}
+
+func newShareToken() (string, error) {
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
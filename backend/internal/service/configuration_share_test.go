@@ -0,0 +1,19 @@
+package service
+
+import "testing"
+
+func TestNewShareToken_URLSafeAndUnique(t *testing.T) {
+	a, err := newShareToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := newShareToken()
+	if len(a) != 43 || a == b {
+		t.Fatalf("unexpected tokens %q %q", a, b)
+	}
+	for _, c := range a {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			t.Fatalf("token is not URL-safe: %q", a)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/carkeeper/backend/config"
 	"github.com/carkeeper/backend/internal/apperr"
 	"github.com/carkeeper/backend/internal/authz"
 	"github.com/carkeeper/backend/internal/model"
@@ -12,11 +14,13 @@ import (
 )
 
 type ConfiguratorService struct {
-	repo *repository.Repository
+	repo      *repository.Repository
+	lifecycle config.LifecycleConfig
+	docs      *DocumentGenerator
 }
 
-func NewConfiguratorService(repos *repository.Repository) *ConfiguratorService {
-	return &ConfiguratorService{repo: repos}
+func NewConfiguratorService(repos *repository.Repository, lifecycle config.LifecycleConfig, docs *DocumentGenerator) *ConfiguratorService {
+	return &ConfiguratorService{repo: repos, lifecycle: lifecycle, docs: docs}
 }
 
 func (s *ConfiguratorService) GetColors(ctx context.Context, isAvailable *bool) ([]model.Color, error) {
@@ -50,7 +54,7 @@ func (s *ConfiguratorService) CreateConfiguration(ctx context.Context, userID uu
 		}
 	}
 
-	config, err := s.repo.Configuration.Create(ctx, userID, create, totalPrice)
+	config, err := s.repo.Configuration.Create(ctx, userID, create, totalPrice, draftExpiresAt(time.Now(), s.lifecycle.DraftExpiryDays))
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
@@ -59,6 +63,7 @@ func (s *ConfiguratorService) CreateConfiguration(ctx context.Context, userID uu
 	if err != nil {
 		return nil, err
 	}
+	s.attachPricing(ctx, configWithDetails)
 
 	return configWithDetails, nil
 }
@@ -71,9 +76,33 @@ func (s *ConfiguratorService) GetConfiguration(ctx context.Context, configID uui
 	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
 		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
 	}
+	s.attachPricing(ctx, config)
 	return config, nil
 }
 
+// QuoteConfiguration prices a configuration with automatic promotions and an optional promo code.
+func (s *ConfiguratorService) QuoteConfiguration(ctx context.Context, configID uuid.UUID, requester uuid.UUID, role string, promoCode *string) (*model.PriceBreakdown, error) {
+	config, err := s.repo.Configuration.GetByID(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return quoteConfiguration(ctx, s.repo, config, promoCode, time.Now())
+}
+
+// attachPricing adds the current promotional price breakdown to editable configurations.
+// Pricing is informational here, so lookup failures leave the field empty.
+func (s *ConfiguratorService) attachPricing(ctx context.Context, config *model.ConfigurationWithDetails) {
+	if config.Status != "draft" && config.Status != "confirmed" {
+		return
+	}
+	if quote, err := quoteConfiguration(ctx, s.repo, config, nil, time.Now()); err == nil {
+		config.Pricing = quote
+	}
+}
+
 func (s *ConfiguratorService) GetUserConfigurations(ctx context.Context, userID uuid.UUID) ([]model.ConfigurationWithDetails, error) {
 	return s.repo.Configuration.GetByUserID(ctx, userID)
 }
@@ -84,7 +113,7 @@ func (s *ConfiguratorService) UpdateConfigurationStatus(ctx context.Context, con
 	}
 
 	validStatuses := map[string]bool{
-		"draft": true, "confirmed": true, "ordered": true, "cancelled": true, "purchased": true,
+		"draft": true, "confirmed": true, "ordered": true, "cancelled": true, "purchased": true, "expired": true,
 	}
 	if !validStatuses[status] {
 		return apperr.BadRequest("Invalid configuration status")
@@ -95,17 +124,28 @@ func (s *ConfiguratorService) UpdateConfigurationStatus(ctx context.Context, con
 		return err
 	}
 
-	if authz.CanManageConfigurationStatus(role) {
-		return s.repo.Configuration.UpdateStatus(ctx, configID, status)
+	if !authz.CanManageConfigurationStatus(role) {
+		if config.UserID != requester {
+			return fmt.Errorf("%w", apperr.ErrForbidden)
+		}
+		if !authz.CustomerMayChangeConfigurationStatus(config.Status, status) {
+			return fmt.Errorf("%w", apperr.ErrForbidden)
+		}
 	}
 
-	if config.UserID != requester {
-		return fmt.Errorf("%w", apperr.ErrForbidden)
-	}
-	if !authz.CustomerMayChangeConfigurationStatus(config.Status, status) {
-		return fmt.Errorf("%w", apperr.ErrForbidden)
+	// Confirming locks catalog prices and issues a commercial offer at them; returning to draft drops
+	// the lock and restarts the draft clock.
+	now := time.Now()
+	switch {
+	case status == "confirmed" && config.Status != "confirmed":
+		if err := s.repo.Configuration.Confirm(ctx, configID, priceLockUntil(now, s.lifecycle.PriceLockDays)); err != nil {
+			return err
+		}
+		issueDocument(ctx, documentTypeCommercialOffer, configID, s.docs.OfferForConfiguration)
+		return nil
+	case status == "draft" && config.Status != "draft":
+		return s.repo.Configuration.Reopen(ctx, configID, draftExpiresAt(now, s.lifecycle.DraftExpiryDays))
 	}
-
 	return s.repo.Configuration.UpdateStatus(ctx, configID, status)
 }
 
@@ -144,7 +184,7 @@ func (s *ConfiguratorService) UpdateConfiguration(ctx context.Context, configID
 		}
 	}
 
-	updatedConfig, err := s.repo.Configuration.Update(ctx, configID, update, totalPrice)
+	updatedConfig, err := s.repo.Configuration.Update(ctx, configID, update, totalPrice, draftExpiresAt(time.Now(), s.lifecycle.DraftExpiryDays))
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
@@ -153,6 +193,7 @@ func (s *ConfiguratorService) UpdateConfiguration(ctx context.Context, configID
 	if err != nil {
 		return nil, err
 	}
+	s.attachPricing(ctx, configWithDetails)
 
 	return configWithDetails, nil
 }
@@ -165,8 +206,8 @@ func (s *ConfiguratorService) DeleteConfiguration(ctx context.Context, configID
 	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
 		return fmt.Errorf("%w", apperr.ErrNotFound)
 	}
-	if config.Status != "draft" {
-		return apperr.BadRequest("Only draft configurations can be deleted")
+	if config.Status != "draft" && config.Status != "expired" {
+		return apperr.BadRequest("Only draft or expired configurations can be deleted")
 	}
 
 	return s.repo.Configuration.Delete(ctx, configID)
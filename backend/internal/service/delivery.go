@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	deliveryCalendarDefaultDays = 7
+	deliveryCalendarMaxDays     = 31
+)
+
+// DeliveryService schedules handovers of paid orders on branch calendars with separate handover bays.
+type DeliveryService struct {
+	repo *repository.Repository
+}
+
+func NewDeliveryService(repos *repository.Repository) *DeliveryService {
+	return &DeliveryService{repo: repos}
+}
+
+// Availability returns free delivery slot starts at the branch on a calendar day.
+func (s *DeliveryService) Availability(ctx context.Context, branchID uuid.UUID, dateStr string) (*model.BranchAvailability, error) {
+	branch, err := s.deliveryBranch(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadBranchLocation(branch.Timezone)
+	dayStart, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return nil, apperr.BadRequest("invalid date format")
+	}
+	days, err := loadBranchDays(ctx, s.repo, branchID, dayStart, 1)
+	if err != nil {
+		return nil, err
+	}
+	busy, err := s.repo.Delivery.ScheduledIntervals(ctx, branchID, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	slots := freeSlotStarts(branch, days[0], branch.HandoverDurationMinutes, time.Now(), capacityFits(busy, branch.HandoverBays))
+	return &model.BranchAvailability{
+		SlotStarts:      slots,
+		Timezone:        branch.Timezone,
+		DurationMinutes: branch.HandoverDurationMinutes,
+		Hours:           days[0].hours,
+	}, nil
+}
+
+// GetForOrder returns the order's current (or last) delivery appointment, or nil when none was booked.
+func (s *DeliveryService) GetForOrder(ctx context.Context, orderID, requester uuid.UUID, role string) (*model.DeliveryAppointment, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanViewOrder(order.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	d, err := s.repo.Delivery.GetLatestByOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// Schedule books a delivery slot for a paid order; booking again replaces the previous slot. The
+// customer books their own order, staff with orders.manage_status may book for anyone.
+func (s *DeliveryService) Schedule(ctx context.Context, orderID, requester uuid.UUID, role string, in model.DeliveryScheduleCreate) (*model.DeliveryAppointment, error) {
+	now := time.Now()
+	if err := validate.AppointmentDate(in.ScheduledAt, now); err != nil {
+		return nil, apperr.BadRequest(err.Error())
+	}
+	var msg string
+	if in.Notes, msg = validate.DeliveryNotes(in.Notes); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+
+	order, err := s.orderForDelivery(ctx, orderID, requester, role)
+	if err != nil {
+		return nil, err
+	}
+	if !orderAwaitingDelivery(order) {
+		return nil, apperr.BadRequest("Delivery can be scheduled only for a paid order whose car has not been handed over")
+	}
+
+	branch, err := s.deliveryBranch(ctx, in.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	in.DurationMinutes = branch.HandoverDurationMinutes
+	if err := checkBranchSlot(ctx, s.repo, branch, in.ScheduledAt, in.DurationMinutes, true); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Delivery.Schedule(ctx, orderID, in, branch.HandoverBays, requester)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Delivery.GetByID(ctx, id)
+}
+
+// Cancel drops the order's scheduled delivery.
+func (s *DeliveryService) Cancel(ctx context.Context, orderID, requester uuid.UUID, role string) error {
+	if _, err := s.orderForDelivery(ctx, orderID, requester, role); err != nil {
+		return err
+	}
+	return s.repo.Delivery.CancelScheduled(ctx, orderID)
+}
+
+// Calendar lists deliveries for staff between two dates (YYYY-MM-DD, "to" inclusive), optionally
+// for one branch whose timezone then defines the day boundaries.
+func (s *DeliveryService) Calendar(ctx context.Context, branchID *uuid.UUID, fromStr, toStr string) ([]model.DeliveryAppointment, error) {
+	loc := time.UTC
+	if branchID != nil {
+		branch, err := s.repo.Branch.GetByID(ctx, *branchID)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return nil, apperr.NotFoundErr("Branch not found")
+			}
+			return nil, err
+		}
+		loc = loadBranchLocation(branch.Timezone)
+	}
+	from, to, msg := deliveryCalendarWindow(fromStr, toStr, loc, time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	return s.repo.Delivery.ListCalendar(ctx, branchID, from, to)
+}
+
+func (s *DeliveryService) orderForDelivery(ctx context.Context, orderID, requester uuid.UUID, role string) (*model.OrderWithDetails, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanViewOrder(order.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	if !authz.IsOwnerOrHasPermission(order.UserID, requester, role, authz.PermOrdersManageStatus) {
+		return nil, fmt.Errorf("%w", apperr.ErrForbidden)
+	}
+	return order, nil
+}
+
+func (s *DeliveryService) deliveryBranch(ctx context.Context, branchID uuid.UUID) (*model.Branch, error) {
+	branch, err := s.repo.Branch.GetByID(ctx, branchID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Branch not found")
+		}
+		return nil, err
+	}
+	if !branch.IsActive {
+		return nil, apperr.BadRequest("branch is not active")
+	}
+	if branch.HandoverBays <= 0 || branch.HandoverDurationMinutes <= 0 {
+		return nil, apperr.BadRequest("branch does not deliver cars")
+	}
+	if err := validateBranchScheduleConfig(branch); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// orderAwaitingDelivery reports whether the order is paid but its car is not yet in the garage.
+func orderAwaitingDelivery(order *model.OrderWithDetails) bool {
+	switch order.Status {
+	case orderStatusPaid:
+		return true
+	case orderStatusCompleted:
+		return order.UserCarID == nil
+	default:
+		return false
+	}
+}
+
+// deliveryCalendarWindow resolves [from, to) in loc from inclusive YYYY-MM-DD dates; from defaults
+// to today and to to a week after from.
+func deliveryCalendarWindow(fromStr, toStr string, loc *time.Location, now time.Time) (time.Time, time.Time, string) {
+	return dateWindow(fromStr, toStr, loc, now, deliveryCalendarDefaultDays, deliveryCalendarMaxDays)
+}
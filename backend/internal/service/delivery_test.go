@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestOrderAwaitingDelivery(t *testing.T) {
+	carID := uuid.New()
+	cases := []struct {
+		name  string
+		order model.OrderWithDetails
+		want  bool
+	}{
+		{"paid", model.OrderWithDetails{Order: model.Order{Status: orderStatusPaid}}, true},
+		{"completed without car", model.OrderWithDetails{Order: model.Order{Status: orderStatusCompleted}}, true},
+		{"handed over", model.OrderWithDetails{Order: model.Order{Status: orderStatusCompleted}, UserCarID: &carID}, false},
+		{"approved", model.OrderWithDetails{Order: model.Order{Status: orderStatusApproved}}, false},
+	}
+	for _, c := range cases {
+		if got := orderAwaitingDelivery(&c.order); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDeliveryCalendarWindow(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 5, 18, 15, 0, 0, 0, loc)
+
+	from, to, msg := deliveryCalendarWindow("", "", loc, now)
+	if msg != "" || !from.Equal(time.Date(2026, 5, 18, 0, 0, 0, 0, loc)) || !to.Equal(from.AddDate(0, 0, deliveryCalendarDefaultDays)) {
+		t.Fatalf("default window: %v %v %q", from, to, msg)
+	}
+	from, to, msg = deliveryCalendarWindow("2026-05-20", "2026-05-20", loc, now)
+	if msg != "" || to.Sub(from) != 24*time.Hour {
+		t.Fatalf("single day should include the whole day: %v %v %q", from, to, msg)
+	}
+	if _, _, msg = deliveryCalendarWindow("2026-05-20", "2026-05-19", loc, now); msg == "" {
+		t.Error("expected error for reversed range")
+	}
+	if _, _, msg = deliveryCalendarWindow("2026-05-01", "2026-07-01", loc, now); msg == "" {
+		t.Error("expected error for too long range")
+	}
+	if _, _, msg = deliveryCalendarWindow("20.05.2026", "", loc, now); msg == "" {
+		t.Error("expected error for bad date")
+	}
+}
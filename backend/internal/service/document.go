@@ -228,6 +228,10 @@ func (s *DocumentService) Delete(ctx context.Context, documentID uuid.UUID, requ
 	if err := s.repo.Document.Delete(ctx, documentID); err != nil {
 		return err
 	}
+	// Files copied into a service book stay for its later owners.
+	if kept, err := s.repo.ServiceHistory.FileReferenced(ctx, key); err != nil || kept {
+		return nil
+	}
 	_ = s.store.Remove(ctx, key)
 	return nil
 }
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/pdfgen"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	documentTypeCommercialOffer = "commercial_offer"
+	documentTypeOrderContract   = "order_contract"
+	documentTypeServiceOrder    = "service_order"
+	documentTypeServiceAct      = "service_act"
+
+	// orderStatusApproved is the status on which the sale contract is issued.
+	orderStatusApproved = "approved"
+	pdfMimeType         = "application/pdf"
+)
+
+// DocumentGenerator renders PDF documents from templates, stores them and registers them in documents.
+type DocumentGenerator struct {
+	repo  *repository.Repository
+	store storage.FileStorage
+}
+
+func NewDocumentGenerator(repos *repository.Repository, store storage.FileStorage) *DocumentGenerator {
+	return &DocumentGenerator{repo: repos, store: store}
+}
+
+// Generate (re)issues a document on staff request; exactly one target matching the type is required.
+func (g *DocumentGenerator) Generate(ctx context.Context, in model.DocumentGenerate) (*model.Document, error) {
+	targets := 0
+	for _, id := range []*uuid.UUID{in.OrderID, in.ServiceAppointmentID, in.ConfigurationID} {
+		if id != nil {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, apperr.BadRequest("provide exactly one of order_id, service_appointment_id or configuration_id")
+	}
+
+	switch in.DocumentType {
+	case documentTypeCommercialOffer:
+		if in.ConfigurationID != nil {
+			return g.OfferForConfiguration(ctx, *in.ConfigurationID)
+		}
+		if in.OrderID != nil {
+			return g.offerFromOrder(ctx, *in.OrderID)
+		}
+		return nil, apperr.BadRequest("commercial_offer requires configuration_id or order_id")
+	case documentTypeOrderContract:
+		if in.OrderID == nil {
+			return nil, apperr.BadRequest("order_contract requires order_id")
+		}
+		return g.OrderContract(ctx, *in.OrderID)
+	case documentTypeServiceOrder, documentTypeServiceAct:
+		if in.ServiceAppointmentID == nil {
+			return nil, apperr.BadRequest(in.DocumentType + " requires service_appointment_id")
+		}
+		if in.DocumentType == documentTypeServiceAct {
+			return g.ServiceAct(ctx, *in.ServiceAppointmentID)
+		}
+		return g.ServiceOrder(ctx, *in.ServiceAppointmentID)
+	default:
+		return nil, apperr.BadRequest("invalid document_type")
+	}
+}
+
+// OfferForConfiguration issues a commercial offer at the configuration's current quote; for a
+// confirmed configuration the offer is valid until its price lock ends.
+func (g *DocumentGenerator) OfferForConfiguration(ctx context.Context, configID uuid.UUID) (*model.Document, error) {
+	config, err := g.repo.Configuration.GetByID(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := quoteConfiguration(ctx, g.repo, config, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	customer, vehicle, err := g.configurationParties(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []pdfgen.Line{
+		{Description: "Vehicle " + vehicle.Title, Amount: quote.TrimPrice},
+		{Description: "Color " + config.ColorName, Amount: quote.ColorPrice},
+	}
+	for _, opt := range config.Options {
+		lines = append(lines, pdfgen.Line{Description: "Option: " + opt.Name, Amount: opt.Price})
+	}
+	offer := pdfgen.Offer{
+		Number:        documentNumber(configID),
+		Date:          time.Now(),
+		ValidUntil:    quote.PriceLockedUntil,
+		Customer:      customer,
+		Vehicle:       vehicle,
+		Lines:         lines,
+		ListPrice:     quote.ListPrice,
+		Discounts:     promotionLines(quote.Promotions, quote.DiscountTotal),
+		DiscountTotal: quote.DiscountTotal,
+		FinalPrice:    quote.FinalPrice,
+	}
+	pdf, err := pdfgen.CommercialOffer(offer)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return g.save(ctx, model.Document{
+		UserID:          config.UserID,
+		ConfigurationID: &configID,
+		DocumentType:    documentTypeCommercialOffer,
+	}, offer.Number, pdf)
+}
+
+// OfferForOrder moves the offers issued for the order's configuration to the order; when there are
+// none (e.g. the configuration was never confirmed) an offer is issued from the order's prices.
+func (g *DocumentGenerator) OfferForOrder(ctx context.Context, orderID uuid.UUID) (*model.Document, error) {
+	order, err := g.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	attached, err := g.repo.Document.AttachConfigurationToOrder(ctx, order.ConfigurationID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if attached > 0 {
+		return nil, nil
+	}
+	return g.offerFromOrder(ctx, orderID)
+}
+
+func (g *DocumentGenerator) offerFromOrder(ctx context.Context, orderID uuid.UUID) (*model.Document, error) {
+	order, err := g.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	customer, vehicle, err := g.configurationParties(ctx, &order.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	lines, listPrice := orderPriceLines(order, vehicle.Title)
+	offer := pdfgen.Offer{
+		Number:        documentNumber(orderID),
+		Date:          time.Now(),
+		Customer:      customer,
+		Vehicle:       vehicle,
+		Lines:         lines,
+		ListPrice:     listPrice,
+		Discounts:     promotionLines(order.Promotions, order.DiscountTotal),
+		DiscountTotal: order.DiscountTotal,
+		FinalPrice:    order.FinalPrice,
+	}
+	pdf, err := pdfgen.CommercialOffer(offer)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return g.save(ctx, model.Document{
+		UserID:          order.UserID,
+		OrderID:         &orderID,
+		ConfigurationID: &order.ConfigurationID,
+		DocumentType:    documentTypeCommercialOffer,
+	}, offer.Number, pdf)
+}
+
+// OrderContract issues the sale contract with the prices fixed on the order.
+func (g *DocumentGenerator) OrderContract(ctx context.Context, orderID uuid.UUID) (*model.Document, error) {
+	order, err := g.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	customer, vehicle, err := g.configurationParties(ctx, &order.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	lines, listPrice := orderPriceLines(order, vehicle.Title)
+	contract := pdfgen.Contract{
+		Number:        documentNumber(orderID),
+		Date:          time.Now(),
+		Customer:      customer,
+		Manager:       derefString(order.ManagerName),
+		Vehicle:       vehicle,
+		Lines:         lines,
+		ListPrice:     listPrice,
+		Discounts:     promotionLines(order.Promotions, order.DiscountTotal),
+		DiscountTotal: order.DiscountTotal,
+		FinalPrice:    order.FinalPrice,
+		TradeInCredit: order.TradeInCredit,
+		AmountDue:     order.AmountDue,
+		Finance:       financeSummary(order.FinancePlan),
+	}
+	pdf, err := pdfgen.OrderContract(contract)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return g.save(ctx, model.Document{
+		UserID:       order.UserID,
+		OrderID:      &orderID,
+		DocumentType: documentTypeOrderContract,
+	}, contract.Number, pdf)
+}
+
+// ServiceOrder issues the work order for a booked appointment.
+func (g *DocumentGenerator) ServiceOrder(ctx context.Context, apptID uuid.UUID) (*model.Document, error) {
+	return g.serviceDocument(ctx, apptID, documentTypeServiceOrder, pdfgen.ServiceOrder)
+}
+
+// ServiceAct issues the certificate of completed work for an appointment.
+func (g *DocumentGenerator) ServiceAct(ctx context.Context, apptID uuid.UUID) (*model.Document, error) {
+	return g.serviceDocument(ctx, apptID, documentTypeServiceAct, pdfgen.ServiceAct)
+}
+
+func (g *DocumentGenerator) serviceDocument(ctx context.Context, apptID uuid.UUID, docType string, render func(pdfgen.ServiceDocument) ([]byte, error)) (*model.Document, error) {
+	appt, err := g.repo.ServiceAppointment.GetByID(ctx, apptID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := g.repo.User.GetByID(ctx, appt.OwnerUserID)
+	if err != nil {
+		return nil, err
+	}
+	car, err := g.repo.UserCar.GetByID(ctx, appt.UserCarID)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	if branch, err := g.repo.Branch.GetByID(ctx, appt.BranchID); err == nil {
+		loc = loadBranchLocation(branch.Timezone)
+	}
+
+	doc := pdfgen.ServiceDocument{
+		Number:          documentNumber(apptID),
+		Date:            time.Now().In(loc),
+		Customer:        userParty(owner),
+		Manager:         derefString(appt.ManagerName),
+		Vehicle:         userCarVehicle(car),
+		Branch:          appt.BranchName,
+		BranchAddress:   appt.BranchAddress,
+		AppointmentAt:   appt.AppointmentDate.In(loc),
+		DurationMinutes: appt.DurationMinutes,
+		Notes:           derefString(appt.Description),
+	}
+	if docType == documentTypeServiceAct && appt.OdometerKm != nil {
+		doc.Vehicle.Mileage = *appt.OdometerKm
+	}
+	// The act lists the work actually performed when the work order has lines; otherwise, and on
+	// the service order, the booked services.
+	if docType == documentTypeServiceAct && len(appt.WorkLines) > 0 {
+		doc.Works = workOrderLines(appt.WorkLines)
+		if appt.WorkSummary != nil {
+			doc.Notes = *appt.WorkSummary
+		}
+	} else {
+		for _, st := range appt.ServiceTypes {
+			doc.Works = append(doc.Works, pdfgen.Line{Description: st.Name, Amount: st.Price})
+		}
+	}
+	for _, w := range doc.Works {
+		doc.Total += w.Amount
+	}
+	doc.Total = roundMoney(doc.Total)
+
+	pdf, err := render(doc)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	return g.save(ctx, model.Document{
+		UserID:               appt.OwnerUserID,
+		ServiceAppointmentID: &apptID,
+		DocumentType:         docType,
+	}, doc.Number, pdf)
+}
+
+func (g *DocumentGenerator) configurationParties(ctx context.Context, config *model.ConfigurationWithDetails) (pdfgen.Party, pdfgen.Vehicle, error) {
+	owner, err := g.repo.User.GetByID(ctx, config.UserID)
+	if err != nil {
+		return pdfgen.Party{}, pdfgen.Vehicle{}, err
+	}
+	trim, err := g.repo.Trim.GetByID(ctx, config.TrimID)
+	if err != nil {
+		return pdfgen.Party{}, pdfgen.Vehicle{}, err
+	}
+	vehicle := pdfgen.Vehicle{
+		Title: joinNonEmpty(" ", trim.BrandName, trim.ModelName, trim.GenerationName, trim.Name),
+		Specs: joinNonEmpty(", ", trim.EngineType, trim.Transmission, trim.DriveType),
+		Color: config.ColorName,
+	}
+	return userParty(owner), vehicle, nil
+}
+
+// save stores the PDF under a fresh document ID and registers it; the blob is removed if the row
+// cannot be inserted.
+func (g *DocumentGenerator) save(ctx context.Context, doc model.Document, number string, pdf []byte) (*model.Document, error) {
+	doc.DocumentID = uuid.New()
+	doc.FilePath = doc.DocumentID.String()
+	size := int64(len(pdf))
+	doc.FileSize = &size
+	doc.FileName = stringPtr(fmt.Sprintf("%s_%s.pdf", doc.DocumentType, number))
+	doc.MimeType = stringPtr(pdfMimeType)
+
+	if err := g.store.Store(ctx, doc.FilePath, bytes.NewReader(pdf), size); err != nil {
+		return nil, apperr.Internal(fmt.Errorf("store file: %w", err))
+	}
+	if err := g.repo.Document.Insert(ctx, doc); err != nil {
+		_ = g.store.Remove(ctx, doc.FilePath)
+		return nil, err
+	}
+	out, err := g.repo.Document.GetByID(ctx, doc.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	out.FileAvailable = true
+	return out, nil
+}
+
+// issueDocument runs a lifecycle document hook; generation is best-effort and never fails the
+// action that triggered it.
+func issueDocument(ctx context.Context, docType string, id uuid.UUID, issue func(context.Context, uuid.UUID) (*model.Document, error)) {
+	if _, err := issue(ctx, id); err != nil {
+		var apiErr *apperr.APIError
+		if errors.As(err, &apiErr) && apiErr.Cause != nil {
+			err = apiErr.Cause
+		}
+		slog.Warn("document generation failed", "document_type", docType, "id", id, "err", err)
+	}
+}
+
+// orderPriceLines splits the order's list price (final price plus discounts) into the vehicle and
+// its options, using the option prices stored with the configuration.
+func orderPriceLines(order *model.OrderWithDetails, vehicleTitle string) ([]pdfgen.Line, float64) {
+	listPrice := roundMoney(order.FinalPrice + order.DiscountTotal)
+	options := make([]pdfgen.Line, 0, len(order.Configuration.Options))
+	base := listPrice
+	for _, opt := range order.Configuration.Options {
+		options = append(options, pdfgen.Line{Description: "Option: " + opt.Name, Amount: opt.Price})
+		base -= opt.Price
+	}
+	vehicle := "Vehicle " + vehicleTitle
+	if order.Configuration.ColorName != "" {
+		vehicle += ", color " + order.Configuration.ColorName
+	}
+	return append([]pdfgen.Line{{Description: vehicle, Amount: roundMoney(base)}}, options...), listPrice
+}
+
+// promotionLines lists applied promotions; a discount without promotion details is shown as one line.
+func promotionLines(promotions []model.AppliedPromotion, discountTotal float64) []pdfgen.Line {
+	var lines []pdfgen.Line
+	for _, p := range promotions {
+		name := p.Name
+		if p.Code != nil && *p.Code != "" {
+			name += " (code " + *p.Code + ")"
+		}
+		lines = append(lines, pdfgen.Line{Description: name, Amount: p.DiscountAmount})
+	}
+	if len(lines) == 0 && discountTotal > 0 {
+		lines = append(lines, pdfgen.Line{Description: "Discount", Amount: discountTotal})
+	}
+	return lines
+}
+
+func financeSummary(plan *model.OrderFinancePlan) string {
+	if plan == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s), %d months at %.2f%% a year, down payment %s RUB, total payments %s RUB",
+		plan.ProductName, plan.Provider, plan.TermMonths, plan.AnnualRate,
+		pdfgen.Money(plan.DownPayment), pdfgen.Money(plan.TotalPayment))
+}
+
+func userParty(u *model.User) pdfgen.Party {
+	return pdfgen.Party{
+		Name:  joinNonEmpty(" ", u.FirstName, u.LastName),
+		Email: u.Email,
+		Phone: derefString(u.Phone),
+	}
+}
+
+func userCarVehicle(car *model.UserCarWithDetails) pdfgen.Vehicle {
+	return pdfgen.Vehicle{
+		Title:   joinNonEmpty(" ", car.BrandName, car.ModelName, car.TrimName),
+		Color:   car.ColorName,
+		VIN:     car.VIN,
+		Year:    car.Year,
+		Mileage: car.CurrentMileage,
+	}
+}
+
+// workOrderLines renders work order lines as priced document rows: "Part: name (number) x qty".
+func workOrderLines(lines []model.ServiceWorkLine) []pdfgen.Line {
+	out := make([]pdfgen.Line, 0, len(lines))
+	for _, l := range lines {
+		desc := l.Description
+		if l.Kind == model.WorkLinePart {
+			desc = "Part: " + desc
+			if l.PartNumber != nil {
+				desc += " (" + *l.PartNumber + ")"
+			}
+		}
+		if l.Quantity != 1 {
+			desc += " x " + strconv.FormatFloat(l.Quantity, 'f', -1, 64)
+		}
+		out = append(out, pdfgen.Line{Description: desc, Amount: l.Amount})
+	}
+	return out
+}
+
+// documentNumber is the human-facing document number: the first block of the source entity's ID.
+func documentNumber(id uuid.UUID) string {
+	return strings.ToUpper(id.String()[:8])
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
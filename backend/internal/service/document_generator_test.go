@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestOrderPriceLines(t *testing.T) {
+	order := &model.OrderWithDetails{
+		Order: model.Order{FinalPrice: 1_950_000, DiscountTotal: 100_000},
+		Configuration: model.ConfigurationWithDetails{
+			ColorName: "White",
+			Options:   []model.Option{{Name: "Tow bar", Price: 30_000}, {Name: "Winter pack", Price: 20_000}},
+		},
+	}
+	lines, list := orderPriceLines(order, "Lada Vesta")
+	if list != 2_050_000 {
+		t.Fatalf("list price = %v, want 2050000", list)
+	}
+	if len(lines) != 3 || lines[0].Description != "Vehicle Lada Vesta, color White" || lines[0].Amount != 2_000_000 {
+		t.Fatalf("unexpected lines %+v", lines)
+	}
+	sum := 0.0
+	for _, l := range lines {
+		sum += l.Amount
+	}
+	if sum != list {
+		t.Errorf("lines sum to %v, want the list price %v", sum, list)
+	}
+}
+
+func TestPromotionLines(t *testing.T) {
+	code := "SPRING"
+	lines := promotionLines([]model.AppliedPromotion{
+		{Name: "Spring sale", Code: &code, DiscountAmount: 50_000},
+		{Name: "Loyalty", DiscountAmount: 10_000},
+	}, 60_000)
+	if len(lines) != 2 || lines[0].Description != "Spring sale (code SPRING)" || lines[1].Amount != 10_000 {
+		t.Errorf("unexpected lines %+v", lines)
+	}
+	if got := promotionLines(nil, 5_000); len(got) != 1 || got[0].Amount != 5_000 {
+		t.Errorf("discount without promotions: %+v", got)
+	}
+	if got := promotionLines(nil, 0); len(got) != 0 {
+		t.Errorf("no discount: %+v", got)
+	}
+}
+
+func TestDocumentNumber(t *testing.T) {
+	id := uuid.MustParse("1a2b3c4d-0000-4000-8000-000000000000")
+	if got := documentNumber(id); got != "1A2B3C4D" {
+		t.Errorf("documentNumber = %q", got)
+	}
+}
+
+func TestWorkOrderLines(t *testing.T) {
+	pn := "04152-YZZA1"
+	got := workOrderLines([]model.ServiceWorkLine{
+		{Kind: model.WorkLineLabour, Description: "Oil change", Quantity: 1, Amount: 1500},
+		{Kind: model.WorkLinePart, Description: "Oil filter", PartNumber: &pn, Quantity: 2, Amount: 1200},
+		{Kind: model.WorkLineLabour, Description: "Diagnostics", Quantity: 0.5, Amount: 750},
+	})
+	want := []string{"Oil change", "Part: Oil filter (04152-YZZA1) x 2", "Diagnostics x 0.5"}
+	for i, w := range want {
+		if got[i].Description != w {
+			t.Errorf("line %d: got %q, want %q", i, got[i].Description, w)
+		}
+	}
+	if got[1].Amount != 1200 {
+		t.Errorf("amount: %v", got[1].Amount)
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/carkeeper/backend/internal/export"
+	"github.com/carkeeper/backend/internal/model"
+)
+
+// ExportOrdersForStaff builds the report of orders matching the filters (caller must enforce permission).
+func (s *OrderService) ExportOrdersForStaff(ctx context.Context, filters model.OrderFilters) (export.Table, error) {
+	orders, err := s.repo.Order.Search(ctx, filters)
+	if err != nil {
+		return export.Table{}, err
+	}
+	return orderExportTable(orders), nil
+}
+
+// ExportAppointmentsForStaff builds the report of appointments matching the filters (caller must
+// enforce permission).
+func (s *ServiceService) ExportAppointmentsForStaff(ctx context.Context, filters model.AppointmentFilters) (export.Table, error) {
+	list, err := s.repo.ServiceAppointment.Search(ctx, filters)
+	if err != nil {
+		return export.Table{}, err
+	}
+	return appointmentExportTable(list), nil
+}
+
+func orderExportTable(orders []model.OrderWithDetails) export.Table {
+	t := export.Table{
+		Sheet: "Orders",
+		Columns: []string{
+			"Order", "Created (UTC)", "Status", "Customer", "Email", "Phone",
+			"Brand", "Model", "Trim", "Color", "Manager",
+			"Final price", "Discount", "Trade-in credit", "Amount due",
+		},
+	}
+	for _, o := range orders {
+		t.Rows = append(t.Rows, []any{
+			documentNumber(o.OrderID), o.CreatedAt.UTC(), o.StatusLabel, o.CustomerName, o.CustomerEmail, o.CustomerPhone,
+			o.BrandName, o.ModelName, o.Configuration.TrimName, o.Configuration.ColorName, o.ManagerName,
+			o.FinalPrice, o.DiscountTotal, o.TradeInCredit, o.AmountDue,
+		})
+	}
+	return t
+}
+
+func appointmentExportTable(list []model.ServiceAppointmentWithDetails) export.Table {
+	t := export.Table{
+		Sheet: "Appointments",
+		Columns: []string{
+			"Appointment", "Date (UTC)", "Duration, min", "Status", "Branch", "Customer", "Email", "Phone",
+			"VIN", "Services", "Manager", "Services total",
+		},
+	}
+	for _, a := range list {
+		names := make([]string, 0, len(a.ServiceTypes))
+		total := 0.0
+		for _, st := range a.ServiceTypes {
+			names = append(names, st.Name)
+			total += st.Price
+		}
+		t.Rows = append(t.Rows, []any{
+			documentNumber(a.ServiceAppointmentID), a.AppointmentDate.UTC(), a.DurationMinutes, a.Status, a.BranchName,
+			a.OwnerName, a.OwnerEmail, a.OwnerPhone, a.UserCarVIN, strings.Join(names, "; "), a.ManagerName, total,
+		})
+	}
+	return t
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestOrderExportTable(t *testing.T) {
+	phone := "+79990000000"
+	table := orderExportTable([]model.OrderWithDetails{{
+		Order:         model.Order{OrderID: uuid.MustParse("a1b2c3d4-0000-0000-0000-000000000000"), StatusLabel: "Оплачен", FinalPrice: 2_000_000, AmountDue: 1_500_000},
+		CustomerName:  "Ivan Petrov",
+		CustomerPhone: &phone,
+		BrandName:     "Lada",
+	}})
+	if len(table.Rows) != 1 || len(table.Rows[0]) != len(table.Columns) {
+		t.Fatalf("row width %d, columns %d", len(table.Rows[0]), len(table.Columns))
+	}
+	row := table.Rows[0]
+	if row[0] != "A1B2C3D4" || row[2] != "Оплачен" || row[6] != "Lada" || row[14] != 1_500_000.0 {
+		t.Errorf("unexpected row %v", row)
+	}
+}
+
+func TestAppointmentExportTable(t *testing.T) {
+	table := appointmentExportTable([]model.ServiceAppointmentWithDetails{{
+		ServiceTypes: []model.ServiceType{{Name: "Oil change", Price: 3000}, {Name: "Diagnostics", Price: 1500}},
+	}})
+	row := table.Rows[0]
+	if len(row) != len(table.Columns) {
+		t.Fatalf("row width %d, columns %d", len(row), len(table.Columns))
+	}
+	if row[9] != "Oil change; Diagnostics" || row[11] != 4500.0 {
+		t.Errorf("unexpected row %v", row)
+	}
+}
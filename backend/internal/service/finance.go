@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	scheduleAnnuity        = "annuity"
+	scheduleDifferentiated = "differentiated"
+)
+
+type FinanceService struct {
+	repo *repository.Repository
+}
+
+func NewFinanceService(repos *repository.Repository) *FinanceService {
+	return &FinanceService{repo: repos}
+}
+
+// ListProducts returns active programs for customers, or all programs for admin screens.
+func (s *FinanceService) ListProducts(ctx context.Context, activeOnly bool) ([]model.FinanceProduct, error) {
+	return s.repo.FinanceProduct.List(ctx, activeOnly)
+}
+
+func (s *FinanceService) CreateProduct(ctx context.Context, in model.FinanceProductInput) (*model.FinanceProduct, error) {
+	if err := normalizeFinanceProductInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.FinanceProduct.Create(ctx, in)
+}
+
+func (s *FinanceService) UpdateProduct(ctx context.Context, id uuid.UUID, in model.FinanceProductInput) (*model.FinanceProduct, error) {
+	if err := normalizeFinanceProductInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.FinanceProduct.Update(ctx, id, in)
+}
+
+func (s *FinanceService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	return s.repo.FinanceProduct.Delete(ctx, id)
+}
+
+// CalculateForConfiguration prices the configuration (promotions included) and evaluates every
+// active program, or only req.FinanceProductID, for the requested term and down payment.
+func (s *FinanceService) CalculateForConfiguration(ctx context.Context, configID, requester uuid.UUID, role string, req model.FinanceRequest) (*model.FinanceQuote, error) {
+	config, err := s.repo.Configuration.GetByID(ctx, configID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanAccessConfiguration(config.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	if msg := validate.FinanceTerm(req.TermMonths); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+
+	price, err := quoteConfiguration(ctx, s.repo, config, req.PromoCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	down, msg := resolveDownPayment(price.FinalPrice, req.DownPayment, req.DownPaymentPercent)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+
+	var products []model.FinanceProduct
+	if req.FinanceProductID != nil {
+		p, err := s.repo.FinanceProduct.GetByID(ctx, *req.FinanceProductID)
+		if err != nil {
+			return nil, err
+		}
+		if !p.IsActive {
+			return nil, apperr.BadRequest("Finance product is not available")
+		}
+		products = []model.FinanceProduct{*p}
+	} else {
+		if products, err = s.repo.FinanceProduct.List(ctx, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return compareFinanceOffers(products, price.FinalPrice, down, req.TermMonths, req.IncludeSchedule), nil
+}
+
+// buildOrderFinancePlan validates a customer's selection against the product and returns the snapshot.
+func buildOrderFinancePlan(p *model.FinanceProduct, price float64, sel model.OrderFinanceSelection) (*model.OrderFinancePlan, error) {
+	if !p.IsActive {
+		return nil, apperr.BadRequest("Finance product is not available")
+	}
+	if sel.ScheduleType != scheduleAnnuity && sel.ScheduleType != scheduleDifferentiated {
+		return nil, apperr.BadRequest("schedule_type must be annuity or differentiated")
+	}
+	if msg := validate.FinanceTerm(sel.TermMonths); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	down, msg := resolveDownPayment(price, &sel.DownPayment, nil)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	offer := evaluateFinanceProduct(*p, price, down, sel.TermMonths)
+	if !offer.Eligible {
+		return nil, apperr.BadRequest(offer.Reason)
+	}
+	plan := offer.Annuity
+	if sel.ScheduleType == scheduleDifferentiated {
+		plan = offer.Differentiated
+	}
+	productID := p.FinanceProductID
+	return &model.OrderFinancePlan{
+		FinanceProductID: &productID,
+		ProductName:      p.Name,
+		Provider:         p.Provider,
+		ProductType:      p.ProductType,
+		ScheduleType:     sel.ScheduleType,
+		AnnualRate:       p.AnnualRate,
+		TermMonths:       sel.TermMonths,
+		VehiclePrice:     price,
+		DownPayment:      down,
+		Principal:        roundMoney(price - down),
+		BalloonPayment:   plan.BalloonPayment,
+		FirstPayment:     plan.FirstPayment,
+		LastPayment:      plan.LastPayment,
+		TotalPayment:     plan.TotalPayment,
+		Overpayment:      plan.Overpayment,
+	}, nil
+}
+
+func resolveDownPayment(price float64, amount, percent *float64) (float64, string) {
+	down := 0.0
+	switch {
+	case amount != nil:
+		down = *amount
+	case percent != nil:
+		if *percent < 0 || *percent >= 100 {
+			return 0, "down_payment_percent must be between 0 and 100"
+		}
+		down = price * *percent / 100
+	}
+	down = roundMoney(down)
+	if down < 0 {
+		return 0, "down_payment cannot be negative"
+	}
+	if down >= price {
+		return 0, "down_payment must be less than the vehicle price"
+	}
+	return down, ""
+}
+
+func compareFinanceOffers(products []model.FinanceProduct, price, down float64, term int, includeSchedule bool) *model.FinanceQuote {
+	out := &model.FinanceQuote{
+		VehiclePrice: price,
+		DownPayment:  down,
+		Principal:    roundMoney(price - down),
+		TermMonths:   term,
+		Offers:       make([]model.FinanceOffer, 0, len(products)),
+	}
+	for _, p := range products {
+		offer := evaluateFinanceProduct(p, price, down, term)
+		if !includeSchedule && offer.Eligible {
+			offer.Annuity.Schedule = nil
+			offer.Differentiated.Schedule = nil
+		}
+		out.Offers = append(out.Offers, offer)
+	}
+	sort.SliceStable(out.Offers, func(i, j int) bool {
+		a, b := out.Offers[i], out.Offers[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if !a.Eligible {
+			return false
+		}
+		return a.Annuity.Overpayment < b.Annuity.Overpayment
+	})
+	return out
+}
+
+// evaluateFinanceProduct checks program limits and, when eligible, builds both schedules.
+func evaluateFinanceProduct(p model.FinanceProduct, price, down float64, term int) model.FinanceOffer {
+	offer := model.FinanceOffer{
+		FinanceProductID: p.FinanceProductID,
+		Name:             p.Name,
+		Provider:         p.Provider,
+		ProductType:      p.ProductType,
+		AnnualRate:       p.AnnualRate,
+	}
+	principal := roundMoney(price - down)
+	downPercent := 0.0
+	if price > 0 {
+		downPercent = down / price * 100
+	}
+	balloon := roundMoney(price * p.BalloonPercent / 100)
+
+	switch {
+	case term < p.MinTermMonths || term > p.MaxTermMonths:
+		offer.Reason = fmt.Sprintf("term must be between %d and %d months", p.MinTermMonths, p.MaxTermMonths)
+	case downPercent+1e-9 < p.MinDownPaymentPercent || downPercent-1e-9 > p.MaxDownPaymentPercent:
+		offer.Reason = fmt.Sprintf("down payment must be between %.2f%% and %.2f%% of the price", p.MinDownPaymentPercent, p.MaxDownPaymentPercent)
+	case p.MinAmount != nil && principal < *p.MinAmount:
+		offer.Reason = fmt.Sprintf("financed amount must be at least %.2f", *p.MinAmount)
+	case p.MaxAmount != nil && principal > *p.MaxAmount:
+		offer.Reason = fmt.Sprintf("financed amount must not exceed %.2f", *p.MaxAmount)
+	case balloon >= principal:
+		offer.Reason = "balloon payment exceeds the financed amount"
+	default:
+		offer.Eligible = true
+		annuity := annuitySchedule(principal, balloon, p.AnnualRate, term)
+		differentiated := differentiatedSchedule(principal, balloon, p.AnnualRate, term)
+		offer.Annuity = &annuity
+		offer.Differentiated = &differentiated
+	}
+	return offer
+}
+
+// annuitySchedule builds equal monthly payments; the balloon is repaid with the last payment.
+func annuitySchedule(principal, balloon, annualRate float64, term int) model.PaymentPlan {
+	r := annualRate / 100 / 12
+	var payment float64
+	if r == 0 {
+		payment = (principal - balloon) / float64(term)
+	} else {
+		discount := math.Pow(1+r, -float64(term))
+		payment = (principal - balloon*discount) * r / (1 - discount)
+	}
+	payment = roundMoney(payment)
+
+	balance := principal
+	rows := make([]model.PaymentScheduleRow, 0, term)
+	for m := 1; m <= term; m++ {
+		interest := roundMoney(balance * r)
+		principalPart := roundMoney(payment - interest)
+		if m == term {
+			principalPart = balance
+		}
+		balance = roundMoney(balance - principalPart)
+		rows = append(rows, model.PaymentScheduleRow{
+			Month:     m,
+			Payment:   roundMoney(principalPart + interest),
+			Principal: principalPart,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return summarizeSchedule(scheduleAnnuity, principal, balloon, rows)
+}
+
+// differentiatedSchedule repays equal principal parts plus interest on the remaining balance.
+func differentiatedSchedule(principal, balloon, annualRate float64, term int) model.PaymentPlan {
+	r := annualRate / 100 / 12
+	part := roundMoney((principal - balloon) / float64(term))
+
+	balance := principal
+	rows := make([]model.PaymentScheduleRow, 0, term)
+	for m := 1; m <= term; m++ {
+		interest := roundMoney(balance * r)
+		principalPart := part
+		if m == term {
+			principalPart = balance
+		}
+		balance = roundMoney(balance - principalPart)
+		rows = append(rows, model.PaymentScheduleRow{
+			Month:     m,
+			Payment:   roundMoney(principalPart + interest),
+			Principal: principalPart,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return summarizeSchedule(scheduleDifferentiated, principal, balloon, rows)
+}
+
+func summarizeSchedule(kind string, principal, balloon float64, rows []model.PaymentScheduleRow) model.PaymentPlan {
+	plan := model.PaymentPlan{ScheduleType: kind, BalloonPayment: balloon, Schedule: rows}
+	for _, row := range rows {
+		plan.TotalPayment += row.Payment
+	}
+	plan.TotalPayment = roundMoney(plan.TotalPayment)
+	plan.Overpayment = roundMoney(plan.TotalPayment - principal)
+	if len(rows) > 0 {
+		plan.FirstPayment = rows[0].Payment
+		plan.LastPayment = rows[len(rows)-1].Payment
+	}
+	return plan
+}
+
+func normalizeFinanceProductInput(in *model.FinanceProductInput) error {
+	name, msg := validate.FinanceProductName(in.Name)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	provider, msg := validate.FinanceProvider(in.Provider)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	productType, msg := validate.FinanceProductType(in.ProductType)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	desc, msg := validate.FinanceProductDescription(in.Description)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.FinanceProductTerms(in.AnnualRate, in.MinTermMonths, in.MaxTermMonths); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.FinanceDownPaymentRange(in.MinDownPaymentPercent, in.MaxDownPaymentPercent, in.BalloonPercent); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.FinanceAmountRange(in.MinAmount, in.MaxAmount); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	in.Name = name
+	in.Provider = provider
+	in.ProductType = productType
+	in.Description = desc
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestAnnuitySchedule_KnownPayment(t *testing.T) {
+	plan := annuitySchedule(1_000_000, 0, 12, 12)
+	if plan.FirstPayment != 88_848.79 {
+		t.Fatalf("first payment %v", plan.FirstPayment)
+	}
+	if math.Abs(plan.LastPayment-plan.FirstPayment) > 0.1 {
+		t.Fatalf("last payment %v should absorb only rounding", plan.LastPayment)
+	}
+	if got := plan.Schedule[len(plan.Schedule)-1].Balance; got != 0 {
+		t.Fatalf("final balance %v", got)
+	}
+	if math.Abs(plan.Overpayment-66_185.46) > 0.1 {
+		t.Fatalf("overpayment %v", plan.Overpayment)
+	}
+}
+
+func TestAnnuitySchedule_ZeroRate(t *testing.T) {
+	plan := annuitySchedule(120_000, 0, 0, 12)
+	if plan.FirstPayment != 10_000 || plan.Overpayment != 0 {
+		t.Fatalf("got %+v", plan)
+	}
+}
+
+func TestDifferentiatedSchedule_Decreasing(t *testing.T) {
+	plan := differentiatedSchedule(1_000_000, 0, 12, 12)
+	if plan.FirstPayment != 93_333.33 {
+		t.Fatalf("first payment %v", plan.FirstPayment)
+	}
+	if plan.LastPayment >= plan.FirstPayment {
+		t.Fatal("differentiated payments should decrease")
+	}
+	annuity := annuitySchedule(1_000_000, 0, 12, 12)
+	if plan.Overpayment >= annuity.Overpayment {
+		t.Fatal("differentiated overpayment should be lower than annuity")
+	}
+}
+
+func TestAnnuitySchedule_BalloonRepaidLast(t *testing.T) {
+	plan := annuitySchedule(1_000_000, 300_000, 10, 36)
+	last := plan.Schedule[len(plan.Schedule)-1]
+	if last.Principal < 300_000 || last.Balance != 0 {
+		t.Fatalf("last row %+v", last)
+	}
+	if plan.LastPayment <= plan.FirstPayment {
+		t.Fatal("balloon should make the last payment the largest")
+	}
+}
+
+func TestCompareFinanceOffers_EligibilityAndOrder(t *testing.T) {
+	maxAmount := 500_000.0
+	products := []model.FinanceProduct{
+		{FinanceProductID: uuid.New(), Name: "expensive", AnnualRate: 15, MinTermMonths: 12, MaxTermMonths: 60, MaxDownPaymentPercent: 90},
+		{FinanceProductID: uuid.New(), Name: "short", AnnualRate: 5, MinTermMonths: 6, MaxTermMonths: 24, MaxDownPaymentPercent: 90},
+		{FinanceProductID: uuid.New(), Name: "cheap", AnnualRate: 9, MinTermMonths: 12, MaxTermMonths: 84, MaxDownPaymentPercent: 90},
+		{FinanceProductID: uuid.New(), Name: "capped", AnnualRate: 1, MinTermMonths: 12, MaxTermMonths: 84, MaxDownPaymentPercent: 90, MaxAmount: &maxAmount},
+		{FinanceProductID: uuid.New(), Name: "big down", AnnualRate: 2, MinTermMonths: 12, MaxTermMonths: 84, MinDownPaymentPercent: 30, MaxDownPaymentPercent: 90},
+	}
+	quote := compareFinanceOffers(products, 2_000_000, 200_000, 36, false)
+	if quote.Principal != 1_800_000 || len(quote.Offers) != 5 {
+		t.Fatalf("got %+v", quote)
+	}
+	if quote.Offers[0].Name != "cheap" || quote.Offers[1].Name != "expensive" {
+		t.Fatalf("unexpected order: %s, %s", quote.Offers[0].Name, quote.Offers[1].Name)
+	}
+	for _, o := range quote.Offers[2:] {
+		if o.Eligible || o.Reason == "" {
+			t.Fatalf("%s should be ineligible with a reason", o.Name)
+		}
+	}
+	if quote.Offers[0].Annuity.Schedule != nil {
+		t.Fatal("schedule should be omitted unless requested")
+	}
+}
+
+func TestResolveDownPayment(t *testing.T) {
+	pct := 20.0
+	if got, msg := resolveDownPayment(1_000_000, nil, &pct); msg != "" || got != 200_000 {
+		t.Fatalf("got %v msg %q", got, msg)
+	}
+	full := 1_000_000.0
+	if _, msg := resolveDownPayment(1_000_000, &full, nil); msg == "" {
+		t.Fatal("down payment equal to price should fail")
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/config"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Reminder notification texts are shown to customers as-is.
+const (
+	maintenanceDueTitle = "Пора на плановое ТО"
+	maintenanceDueBody  = "%s: пора пройти «%s»%s. Запись уже заполнена — осталось выбрать время."
+)
+
+// maintenanceProjectionMaxDays bounds the mileage projection of rarely driven cars.
+const maintenanceProjectionMaxDays = 3650
+
+type MaintenanceService struct {
+	repo *repository.Repository
+	cfg  config.MaintenanceConfig
+}
+
+func NewMaintenanceService(repos *repository.Repository, cfg config.MaintenanceConfig) *MaintenanceService {
+	return &MaintenanceService{repo: repos, cfg: cfg}
+}
+
+// AdminListPlans returns maintenance plans, optionally of one model or generation.
+func (s *MaintenanceService) AdminListPlans(ctx context.Context, modelID, generationID *uuid.UUID) ([]model.MaintenancePlan, error) {
+	return s.repo.Maintenance.ListPlans(ctx, modelID, generationID)
+}
+
+// AdminCreatePlan adds a plan to a model or a generation.
+func (s *MaintenanceService) AdminCreatePlan(ctx context.Context, in model.MaintenancePlanInput) (*model.MaintenancePlan, error) {
+	if (in.ModelID == nil) == (in.GenerationID == nil) {
+		return nil, apperr.BadRequest("exactly one of model_id and generation_id is required")
+	}
+	if err := normalizeMaintenancePlan(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.Maintenance.CreatePlan(ctx, in)
+}
+
+// AdminUpdatePlan replaces a plan's name, intervals, services and active flag. Reminders already
+// raised keep their due date and booking.
+func (s *MaintenanceService) AdminUpdatePlan(ctx context.Context, id uuid.UUID, in model.MaintenancePlanInput) (*model.MaintenancePlan, error) {
+	cur, err := s.repo.Maintenance.GetPlan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if (in.ModelID != nil && (cur.ModelID == nil || *in.ModelID != *cur.ModelID)) ||
+		(in.GenerationID != nil && (cur.GenerationID == nil || *in.GenerationID != *cur.GenerationID)) {
+		return nil, apperr.BadRequest("the model or generation of a plan cannot be changed")
+	}
+	if err := normalizeMaintenancePlan(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.Maintenance.UpdatePlan(ctx, id, in)
+}
+
+func (s *MaintenanceService) AdminDeletePlan(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Maintenance.DeletePlan(ctx, id)
+}
+
+// normalizeMaintenancePlan validates the name and intervals and de-duplicates the service types.
+func normalizeMaintenancePlan(in *model.MaintenancePlanInput) error {
+	var msg string
+	if in.Name, msg = validate.MaintenancePlanName(in.Name); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.MaintenanceIntervals(in.IntervalKm, in.IntervalMonths); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	seen := make(map[uuid.UUID]bool, len(in.ServiceTypeIDs))
+	ids := make([]uuid.UUID, 0, len(in.ServiceTypeIDs))
+	for _, id := range in.ServiceTypeIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if msg := validate.MaintenancePlanServiceCount(len(ids)); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	in.ServiceTypeIDs = ids
+	return nil
+}
+
+// CarSchedule reports when each plan applying to the car is next due, with a prefilled booking.
+func (s *MaintenanceService) CarSchedule(ctx context.Context, userCarID, requester uuid.UUID, role string) ([]model.MaintenanceDue, error) {
+	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("User car not found")
+		}
+		return nil, err
+	}
+	ok, err := canViewCar(ctx, s.repo, car.UserCarID, car.UserID, requester, role, authz.PermGarageViewAny)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	candidates, err := s.repo.Maintenance.Candidates(ctx, &userCarID, false)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]model.MaintenanceDue, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, maintenanceDue(c, now, s.cfg.LeadDays, s.cfg.LeadKm))
+	}
+	return out, nil
+}
+
+// ListReminders returns the open service reminders for the user's cars.
+func (s *MaintenanceService) ListReminders(ctx context.Context, userID uuid.UUID) ([]model.MaintenanceReminder, error) {
+	return s.repo.Maintenance.ListOpenReminders(ctx, userID)
+}
+
+// DismissReminder hides a reminder; the next one is raised after the next service.
+func (s *MaintenanceService) DismissReminder(ctx context.Context, userID, reminderID uuid.UUID) (*model.MaintenanceReminder, error) {
+	m, err := s.repo.Maintenance.DismissReminder(ctx, userID, reminderID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Reminder not found")
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// RunReminders raises a reminder, with a notification, for every car whose service is due within
+// the lead time or overdue. A car gets one reminder per plan and last service, so runs are
+// idempotent.
+func (s *MaintenanceService) RunReminders(ctx context.Context, now time.Time) (int, error) {
+	candidates, err := s.repo.Maintenance.Candidates(ctx, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for _, c := range candidates {
+		due := maintenanceDue(c, now, s.cfg.LeadDays, s.cfg.LeadKm)
+		if due.Status != model.MaintenanceDueSoon && due.Status != model.MaintenanceOverdue {
+			continue
+		}
+		ok, err := s.repo.Maintenance.CreateReminder(ctx, model.MaintenanceReminderCreate{
+			UserID:            c.UserID,
+			UserCarID:         c.UserCarID,
+			PlanID:            c.Plan.PlanID,
+			LastAppointmentID: c.Baseline.AppointmentID,
+			DueDate:           due.DueDate,
+			DueMileage:        due.DueMileage,
+			Booking:           due.Booking,
+			Title:             maintenanceDueTitle,
+			Body:              fmt.Sprintf(maintenanceDueBody, c.CarTitle, c.Plan.Name, maintenanceDueText(due)),
+		})
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, nil
+}
+
+// maintenanceDue counts the plan intervals from the baseline. The service is overdue once the date
+// or the mileage is reached and due soon within leadDays of the estimated date or leadKm of the
+// due mileage.
+func maintenanceDue(c model.MaintenanceCandidate, now time.Time, leadDays, leadKm int) model.MaintenanceDue {
+	d := model.MaintenanceDue{
+		Plan:        c.Plan,
+		LastService: c.Baseline,
+		Status:      model.MaintenanceOK,
+		Booking:     maintenanceBooking(c),
+	}
+	if months := c.Plan.IntervalMonths; months != nil {
+		due := c.Baseline.Date.AddDate(0, *months, 0)
+		d.DueDate = &due
+		d.EstimatedDate = &due
+	}
+	if km := c.Plan.IntervalKm; km != nil && c.Baseline.MileageKm != nil {
+		dueKm := *c.Baseline.MileageKm + *km
+		d.DueMileage = &dueKm
+		if at, ok := projectMileageDate(c.Baseline, c.CurrentMileage, dueKm, now); ok && (d.EstimatedDate == nil || at.Before(*d.EstimatedDate)) {
+			d.EstimatedDate = &at
+		}
+	}
+	switch {
+	case d.DueDate == nil && d.DueMileage == nil:
+		d.Status = model.MaintenanceUnknown
+	case (d.DueDate != nil && !now.Before(*d.DueDate)) || (d.DueMileage != nil && c.CurrentMileage >= *d.DueMileage):
+		d.Status = model.MaintenanceOverdue
+	case (d.EstimatedDate != nil && d.EstimatedDate.Before(now.AddDate(0, 0, leadDays))) ||
+		(d.DueMileage != nil && *d.DueMileage-c.CurrentMileage <= leadKm):
+		d.Status = model.MaintenanceDueSoon
+	}
+	return d
+}
+
+// projectMileageDate extrapolates when the car reaches dueKm at its average daily mileage since the
+// baseline; it needs at least a day of driving and gives up beyond ten years.
+func projectMileageDate(b model.MaintenanceBaseline, current, dueKm int, now time.Time) (time.Time, bool) {
+	days := now.Sub(b.Date).Hours() / 24
+	driven := current - *b.MileageKm
+	if days < 1 || driven <= 0 {
+		return time.Time{}, false
+	}
+	left := math.Ceil(float64(dueKm-current) / (float64(driven) / days))
+	if left <= 0 {
+		return now, true
+	}
+	if left > maintenanceProjectionMaxDays {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, int(left)), true
+}
+
+// maintenanceBooking prefills an appointment with the plan services at the branch of the last visit.
+func maintenanceBooking(c model.MaintenanceCandidate) model.MaintenanceBooking {
+	ids := c.BookableServiceTypeIDs
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	return model.MaintenanceBooking{
+		UserCarID:      c.UserCarID,
+		BranchID:       c.Baseline.BranchID,
+		ServiceTypeIDs: ids,
+		Description:    c.Plan.Name,
+	}
+}
+
+// maintenanceDueText renders the due date and mileage for the reminder body.
+func maintenanceDueText(d model.MaintenanceDue) string {
+	var parts []string
+	if d.DueDate != nil {
+		parts = append(parts, "до "+d.DueDate.Format("02.01.2006"))
+	}
+	if d.DueMileage != nil {
+		parts = append(parts, fmt.Sprintf("при пробеге %d км", *d.DueMileage))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " или ")
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func maintenanceCandidate(km, months, baselineKm *int, baseline time.Time, current int) model.MaintenanceCandidate {
+	return model.MaintenanceCandidate{
+		UserCarID:      uuid.New(),
+		CurrentMileage: current,
+		Plan:           model.MaintenancePlan{PlanID: uuid.New(), Name: "ТО-1", IntervalKm: km, IntervalMonths: months},
+		Baseline:       model.MaintenanceBaseline{Date: baseline, MileageKm: baselineKm},
+	}
+}
+
+func TestMaintenanceDue_DateAndMileage(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	km, months, base := 15000, 12, 30000
+
+	// Serviced two months ago, barely driven since: due by date in ten months.
+	c := maintenanceCandidate(&km, &months, &base, now.AddDate(0, -2, 0), 30100)
+	d := maintenanceDue(c, now, 30, 1000)
+	if d.Status != model.MaintenanceOK {
+		t.Fatalf("status = %s, want ok", d.Status)
+	}
+	if d.DueDate == nil || !d.DueDate.Equal(now.AddDate(0, 10, 0)) || d.DueMileage == nil || *d.DueMileage != 45000 {
+		t.Fatalf("due = %v / %v", d.DueDate, d.DueMileage)
+	}
+
+	// Within the mileage lead.
+	c.CurrentMileage = 44200
+	if d := maintenanceDue(c, now, 30, 1000); d.Status != model.MaintenanceDueSoon {
+		t.Fatalf("status = %s, want due_soon", d.Status)
+	}
+
+	// Mileage reached before the date.
+	c.CurrentMileage = 45000
+	if d := maintenanceDue(c, now, 30, 1000); d.Status != model.MaintenanceOverdue {
+		t.Fatalf("status = %s, want overdue", d.Status)
+	}
+
+	// Date reached regardless of mileage.
+	c = maintenanceCandidate(&km, &months, &base, now.AddDate(-1, 0, 0), 31000)
+	if d := maintenanceDue(c, now, 30, 1000); d.Status != model.MaintenanceOverdue {
+		t.Fatalf("status = %s, want overdue", d.Status)
+	}
+}
+
+func TestMaintenanceDue_ProjectsMileageDate(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	km, months, base := 10000, 12, 0
+	// 9000 km in 90 days is 100 km a day: the remaining 1000 km take ten days.
+	c := maintenanceCandidate(&km, &months, &base, now.AddDate(0, 0, -90), 9000)
+	d := maintenanceDue(c, now, 30, 500)
+	if d.EstimatedDate == nil || !d.EstimatedDate.Equal(now.AddDate(0, 0, 10)) {
+		t.Fatalf("estimated = %v, want %v", d.EstimatedDate, now.AddDate(0, 0, 10))
+	}
+	if d.Status != model.MaintenanceDueSoon {
+		t.Fatalf("status = %s, want due_soon from the projection", d.Status)
+	}
+}
+
+func TestMaintenanceDue_UnknownMileage(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	km := 15000
+	d := maintenanceDue(maintenanceCandidate(&km, nil, nil, now.AddDate(-2, 0, 0), 80000), now, 30, 1000)
+	if d.Status != model.MaintenanceUnknown || d.DueMileage != nil {
+		t.Fatalf("status = %s, due mileage %v", d.Status, d.DueMileage)
+	}
+}
+
+func TestMaintenanceBooking(t *testing.T) {
+	branch := uuid.New()
+	c := maintenanceCandidate(nil, nil, nil, time.Now(), 0)
+	c.Baseline.BranchID = &branch
+	b := maintenanceBooking(c)
+	if b.UserCarID != c.UserCarID || b.BranchID != &branch || b.ServiceTypeIDs == nil || b.Description != "ТО-1" {
+		t.Fatalf("booking = %+v", b)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	messageThreadOrder       = "order"
+	messageThreadAppointment = "appointment"
+
+	notificationKindMessage = "message"
+)
+
+// Notification texts are shown as-is to the recipient of a new thread message.
+const (
+	orderMessageTitle       = "Новое сообщение по заказу"
+	appointmentMessageTitle = "Новое сообщение по записи на сервис"
+)
+
+type MessageService struct {
+	repo *repository.Repository
+}
+
+func NewMessageService(repos *repository.Repository) *MessageService {
+	return &MessageService{repo: repos}
+}
+
+// messageThreadInfo is what access checks and notifications need to know about a thread's subject.
+type messageThreadInfo struct {
+	ownerID   uuid.UUID
+	managerID *uuid.UUID
+	// staff is true when the requester sees the thread as staff, including internal notes.
+	staff bool
+}
+
+// threadInfo loads the order or appointment behind a thread; threads the requester may not see
+// are reported as not found.
+func (s *MessageService) threadInfo(ctx context.Context, thread string, id, requester uuid.UUID, role string) (*messageThreadInfo, error) {
+	switch thread {
+	case messageThreadOrder:
+		order, err := s.repo.Order.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !authz.CanViewOrder(order.UserID, requester, role) {
+			return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+		}
+		return &messageThreadInfo{
+			ownerID:   order.UserID,
+			managerID: order.ManagerID,
+			staff:     authz.HasPermission(role, authz.PermOrdersViewAny),
+		}, nil
+	case messageThreadAppointment:
+		a, err := s.repo.ServiceAppointment.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		staff := authz.HasPermission(role, authz.PermAppointmentsViewAny)
+		if !staff {
+			canBook, err := canBookCar(ctx, s.repo, a.UserCarID, a.OwnerUserID, requester)
+			if err != nil {
+				return nil, err
+			}
+			if !canBook {
+				return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+			}
+		}
+		return &messageThreadInfo{
+			ownerID:   a.OwnerUserID,
+			managerID: a.ManagerID,
+			staff:     staff,
+		}, nil
+	default:
+		return nil, apperr.BadRequest("Unknown message thread")
+	}
+}
+
+// List returns the thread as the requester sees it; customers never get internal notes.
+func (s *MessageService) List(ctx context.Context, thread string, id, requester uuid.UUID, role string) (*model.MessageThread, error) {
+	info, err := s.threadInfo(ctx, thread, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.Message.List(ctx, thread, id, requester, info.staff)
+	if err != nil {
+		return nil, err
+	}
+	return &model.MessageThread{Items: items, UnreadCount: countUnread(items)}, nil
+}
+
+// Post adds a message to the thread and notifies the other side. Internal notes are staff-only
+// and notify nobody.
+func (s *MessageService) Post(ctx context.Context, thread string, id, requester uuid.UUID, role string, in model.MessageCreate) (*model.Message, error) {
+	info, err := s.threadInfo(ctx, thread, id, requester, role)
+	if err != nil {
+		return nil, err
+	}
+	body, msg := validate.MessageBody(in.Body)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if in.Internal && !info.staff {
+		return nil, apperr.Forbidden("only staff can post internal notes")
+	}
+	docs := dedupeUUIDs(in.DocumentIDs)
+	if msg := validate.MessageAttachments(len(docs)); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+
+	messageID, err := s.repo.Message.Create(ctx, thread, id, requester, model.MessageCreate{
+		Body:        body,
+		Internal:    in.Internal,
+		DocumentIDs: docs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !in.Internal {
+		s.notifyRecipient(ctx, thread, id, requester, info, body)
+	}
+	return s.repo.Message.GetByID(ctx, messageID, requester)
+}
+
+// MarkRead records read receipts for everything in the thread the requester can see.
+func (s *MessageService) MarkRead(ctx context.Context, thread string, id, requester uuid.UUID, role string) error {
+	info, err := s.threadInfo(ctx, thread, id, requester, role)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.Message.MarkThreadRead(ctx, thread, id, requester, info.staff)
+	return err
+}
+
+// notifyRecipient tells the customer about a staff reply, or the assigned manager about a customer
+// message. Failures are logged and never fail the post.
+func (s *MessageService) notifyRecipient(ctx context.Context, thread string, id, author uuid.UUID, info *messageThreadInfo, body string) {
+	recipient := messageRecipient(author, info.ownerID, info.managerID)
+	if recipient == nil {
+		return
+	}
+	title := orderMessageTitle
+	if thread == messageThreadAppointment {
+		title = appointmentMessageTitle
+	}
+	entityType := thread
+	_, err := s.repo.Notification.Create(ctx, model.Notification{
+		UserID:     *recipient,
+		Kind:       notificationKindMessage,
+		Title:      title,
+		Body:       messagePreview(body),
+		EntityType: &entityType,
+		EntityID:   &id,
+	})
+	if err != nil {
+		slog.Warn("message notification failed", "thread", thread, "id", id, "err", err)
+	}
+}
+
+// messageRecipient picks who to notify about a new message: the customer when someone else writes,
+// otherwise the assigned manager (nil when the thread is unassigned).
+func messageRecipient(author, ownerID uuid.UUID, managerID *uuid.UUID) *uuid.UUID {
+	if author != ownerID {
+		return &ownerID
+	}
+	if managerID != nil && *managerID != author {
+		return managerID
+	}
+	return nil
+}
+
+const messagePreviewRunes = 140
+
+// messagePreview shortens a message body for the notification text.
+func messagePreview(body string) string {
+	r := []rune(body)
+	if len(r) <= messagePreviewRunes {
+		return body
+	}
+	return string(r[:messagePreviewRunes-1]) + "…"
+}
+
+func countUnread(items []model.Message) int {
+	n := 0
+	for _, m := range items {
+		if !m.IsRead {
+			n++
+		}
+	}
+	return n
+}
+
+// fillOrderUnread sets UnreadMessages on each order for the viewer.
+func fillOrderUnread(ctx context.Context, repo *repository.Repository, orders []model.OrderWithDetails, viewer uuid.UUID, includeInternal bool) error {
+	ids := make([]uuid.UUID, len(orders))
+	for i := range orders {
+		ids[i] = orders[i].OrderID
+	}
+	counts, err := repo.Message.UnreadCounts(ctx, messageThreadOrder, ids, viewer, includeInternal)
+	if err != nil {
+		return err
+	}
+	for i := range orders {
+		orders[i].UnreadMessages = counts[orders[i].OrderID]
+	}
+	return nil
+}
+
+// fillAppointmentUnread sets UnreadMessages on each appointment for the viewer.
+func fillAppointmentUnread(ctx context.Context, repo *repository.Repository, list []model.ServiceAppointmentWithDetails, viewer uuid.UUID, includeInternal bool) error {
+	ids := make([]uuid.UUID, len(list))
+	for i := range list {
+		ids[i] = list[i].ServiceAppointmentID
+	}
+	counts, err := repo.Message.UnreadCounts(ctx, messageThreadAppointment, ids, viewer, includeInternal)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].UnreadMessages = counts[list[i].ServiceAppointmentID]
+	}
+	return nil
+}
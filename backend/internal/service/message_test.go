@@ -0,0 +1,45 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestMessageRecipient(t *testing.T) {
+	owner, manager, other := uuid.New(), uuid.New(), uuid.New()
+
+	if got := messageRecipient(manager, owner, &manager); got == nil || *got != owner {
+		t.Errorf("staff reply should notify the customer, got %v", got)
+	}
+	if got := messageRecipient(other, owner, &manager); got == nil || *got != owner {
+		t.Errorf("reply by another staff member should notify the customer, got %v", got)
+	}
+	if got := messageRecipient(owner, owner, &manager); got == nil || *got != manager {
+		t.Errorf("customer message should notify the manager, got %v", got)
+	}
+	if got := messageRecipient(owner, owner, nil); got != nil {
+		t.Errorf("unassigned thread should notify nobody, got %v", got)
+	}
+}
+
+func TestMessagePreview(t *testing.T) {
+	if got := messagePreview("short"); got != "short" {
+		t.Fatalf("got %q", got)
+	}
+	long := strings.Repeat("я", messagePreviewRunes+10)
+	got := messagePreview(long)
+	if utf8.RuneCountInString(got) != messagePreviewRunes || !strings.HasSuffix(got, "…") {
+		t.Fatalf("got %d runes: %q", utf8.RuneCountInString(got), got)
+	}
+}
+
+func TestCountUnread(t *testing.T) {
+	items := []model.Message{{IsRead: true}, {IsRead: false}, {IsRead: false}}
+	if got := countUnread(items); got != 2 {
+		t.Fatalf("got %d", got)
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const notificationListLimit = 100
+
+type NotificationService struct {
+	repo *repository.Repository
+}
+
+func NewNotificationService(repos *repository.Repository) *NotificationService {
+	return &NotificationService{repo: repos}
+}
+
+// List returns the user's latest notifications together with the unread total.
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool) (*model.NotificationList, error) {
+	items, err := s.repo.Notification.ListByUser(ctx, userID, unreadOnly, notificationListLimit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.Notification.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NotificationList{Items: items, UnreadCount: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	return s.repo.Notification.MarkRead(ctx, userID, notificationID)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.Notification.MarkAllRead(ctx, userID)
+}
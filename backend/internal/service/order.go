@@ -5,20 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/carkeeper/backend/internal/apperr"
 	"github.com/carkeeper/backend/internal/authz"
 	"github.com/carkeeper/backend/internal/model"
 	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
 	"github.com/google/uuid"
 )
 
 type OrderService struct {
 	repo *repository.Repository
+	docs *DocumentGenerator
 }
 
-func NewOrderService(repos *repository.Repository) *OrderService {
-	return &OrderService{repo: repos}
+func NewOrderService(repos *repository.Repository, docs *DocumentGenerator) *OrderService {
+	return &OrderService{repo: repos, docs: docs}
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, create model.OrderCreate) (*model.OrderWithDetails, error) {
@@ -30,10 +33,32 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uuid.UUID, create
 		return nil, err
 	}
 
-	order, err := s.repo.Order.CreateAndMarkConfigurationOrdered(ctx, userID, create, config.TotalPrice)
+	if config.UserID != userID {
+		return nil, apperr.Forbidden("Configuration does not belong to your account")
+	}
+
+	quote, err := quoteConfiguration(ctx, s.repo, config, create.PromoCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var financePlan *model.OrderFinancePlan
+	if create.Finance != nil {
+		product, err := s.repo.FinanceProduct.GetByID(ctx, create.Finance.FinanceProductID)
+		if err != nil {
+			return nil, err
+		}
+		if financePlan, err = buildOrderFinancePlan(product, quote.FinalPrice, *create.Finance); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err := s.repo.Order.CreateAndMarkConfigurationOrdered(ctx, userID, create, *quote, financePlan)
 	if err != nil {
 		return nil, err
 	}
+	autoAssign(ctx, s.repo, assignmentEntityOrder, order.OrderID)
+	issueDocument(ctx, documentTypeCommercialOffer, order.OrderID, s.docs.OfferForOrder)
 
 	orderWithDetails, err := s.repo.Order.GetByID(ctx, order.OrderID)
 	if err != nil {
@@ -51,43 +76,143 @@ func (s *OrderService) GetOrder(ctx context.Context, orderID uuid.UUID, requeste
 	if !authz.CanViewOrder(order.UserID, requester, role) {
 		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
 	}
+	transitions, err := s.repo.OrderStatus.ListTransitionsFrom(ctx, order.Status)
+	if err != nil {
+		return nil, err
+	}
+	order.NextStatuses = []model.OrderNextStatus{}
+	for _, t := range availableOrderTransitions(transitions, order.UserID, requester, role) {
+		order.NextStatuses = append(order.NextStatuses, model.OrderNextStatus{
+			Code:            t.ToStatus,
+			CustomerLabelRu: t.ToCustomerLabelRu,
+			AdminLabelRu:    t.ToAdminLabelRu,
+			IsTerminal:      t.ToIsTerminal,
+		})
+	}
+	events, err := s.repo.Order.ListEvents(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.HasPermission(role, authz.PermOrdersViewAny) {
+		events = customerOrderTimeline(events)
+	}
+	order.Timeline = events
 	return order, nil
 }
 
+// customerOrderTimeline strips staff-only comments from the events shown to the customer.
+func customerOrderTimeline(events []model.OrderEvent) []model.OrderEvent {
+	out := make([]model.OrderEvent, 0, len(events))
+	for _, e := range events {
+		if e.CommentInternal {
+			e.Comment = nil
+			e.CommentInternal = false
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
 func (s *OrderService) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]model.OrderWithDetails, error) {
-	return s.repo.Order.GetByUserID(ctx, userID)
+	orders, err := s.repo.Order.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillOrderUnread(ctx, s.repo, orders, userID, false); err != nil {
+		return nil, err
+	}
+	return orders, nil
 }
 
-// ListAllOrdersForStaff returns all orders with details (caller must enforce permission).
-func (s *OrderService) ListAllOrdersForStaff(ctx context.Context) ([]model.OrderWithDetails, error) {
-	return s.repo.Order.ListAllWithDetails(ctx)
+// ListAllOrdersForStaff returns the orders matching the filters with the staff member's unread
+// message counts (caller must enforce permission).
+func (s *OrderService) ListAllOrdersForStaff(ctx context.Context, staffID uuid.UUID, filters model.OrderFilters) ([]model.OrderWithDetails, error) {
+	orders, err := s.repo.Order.Search(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillOrderUnread(ctx, s.repo, orders, staffID, true); err != nil {
+		return nil, err
+	}
+	return orders, nil
 }
 
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, status string, requester uuid.UUID, role string) error {
-	status = strings.TrimSpace(status)
+// UpdateOrderStatus applies a transition from the configured status graph and records it in the
+// order timeline.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uuid.UUID, change model.OrderStatusChange, requester uuid.UUID, role string) error {
+	status := strings.TrimSpace(change.Status)
 	if status == "" {
 		return apperr.BadRequest("status is required")
 	}
+	change.Status = status
+	var msg string
+	if change.Reason, msg = validate.OrderStatusReason(change.Reason); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if change.Comment, msg = validate.OrderStatusComment(change.Comment); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	// Only staff can leave notes hidden from the customer.
+	change.Internal = change.Internal && authz.HasPermission(role, authz.PermOrdersViewAny)
 
-	target, err := s.repo.OrderStatus.GetByCode(ctx, status)
-	if err != nil {
+	if _, err := s.repo.OrderStatus.GetByCode(ctx, status); err != nil {
 		if errors.Is(err, apperr.ErrNotFound) {
 			return apperr.BadRequest("Unknown order status")
 		}
 		return err
 	}
 
-	if !authz.HasPermission(role, authz.PermOrdersManageStatus) && !target.IsActive {
-		return apperr.BadRequest("This order status is not available")
-	}
-
 	order, err := s.repo.Order.GetByID(ctx, orderID)
 	if err != nil {
 		return err
 	}
-	if !authz.CanUpdateOrderStatus(order.UserID, requester, role, order.Status, status) {
+	if !authz.CanViewOrder(order.UserID, requester, role) {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	transitions, err := s.repo.OrderStatus.ListTransitionsFrom(ctx, order.Status)
+	if err != nil {
+		return err
+	}
+	transition := findOrderTransition(transitions, status)
+	if transition == nil {
+		return apperr.BadRequest(fmt.Sprintf("Order cannot move from %s to %s", order.Status, status))
+	}
+	if len(availableOrderTransitions([]model.OrderStatusTransition{*transition}, order.UserID, requester, role)) == 0 {
 		return fmt.Errorf("%w", apperr.ErrForbidden)
 	}
 
-	return s.repo.Order.UpdateStatus(ctx, orderID, status)
+	if err := s.repo.Order.UpdateStatus(ctx, orderID, order.Status, change, &requester); err != nil {
+		return err
+	}
+	if status == orderStatusApproved {
+		issueDocument(ctx, documentTypeOrderContract, orderID, s.docs.OrderContract)
+	}
+	// An order paid in full before it could move to "paid" (e.g. while still pending) gets there now.
+	advanceOrderIfFullyPaid(ctx, s.repo, orderID, &requester)
+	return nil
+}
+
+func findOrderTransition(transitions []model.OrderStatusTransition, to string) *model.OrderStatusTransition {
+	for i := range transitions {
+		if transitions[i].ToStatus == to {
+			return &transitions[i]
+		}
+	}
+	return nil
+}
+
+// availableOrderTransitions keeps the transitions the requester may trigger. Inactive target
+// statuses stay available to staff who manage order statuses only.
+func availableOrderTransitions(transitions []model.OrderStatusTransition, orderUserID, requester uuid.UUID, role string) []model.OrderStatusTransition {
+	var out []model.OrderStatusTransition
+	for _, t := range transitions {
+		if !authz.CanTriggerOrderTransition(orderUserID, requester, role, t.PermissionCode, t.CustomerMayTrigger) {
+			continue
+		}
+		if !t.ToIsActive && !authz.HasPermission(role, authz.PermOrdersManageStatus) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
 }
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	orderStatusCompleted = "completed"
+	orderHandoverNote    = "Автомобиль передан клиенту"
+)
+
+// HandOver delivers the ordered car: it appears in the customer's garage with the order's trim and
+// color, the configuration becomes purchased and the order moves to completed. The order must
+// already be completed or be allowed to move there by the status graph (e.g. from paid), and the
+// staff role must hold the permission that transition requires.
+func (s *OrderService) HandOver(ctx context.Context, orderID, staffID uuid.UUID, role string, in model.OrderHandover) (*model.UserCarWithDetails, error) {
+	now := time.Now()
+	vin := validate.NormalizeVIN(in.VIN)
+	if msg := validate.VIN(vin); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	delivered, msg := validate.DeliveryDate(in.DeliveryDate, now)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if msg := validate.HandoverMileage(in.Mileage); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	year := delivered.Year()
+	if in.Year != nil {
+		year = *in.Year
+	}
+	if year < 1900 || year > now.Year()+1 {
+		return nil, apperr.BadRequest("Invalid vehicle year")
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if delivered.Before(truncateToDate(order.CreatedAt)) {
+		return nil, apperr.BadRequest("delivery_date cannot be before the order was placed")
+	}
+	if order.Status != orderStatusCompleted {
+		transitions, err := s.repo.OrderStatus.ListTransitionsFrom(ctx, order.Status)
+		if err != nil {
+			return nil, err
+		}
+		transition := findOrderTransition(transitions, orderStatusCompleted)
+		if transition == nil {
+			return nil, apperr.BadRequest(fmt.Sprintf("Order in status %s cannot be handed over", order.Status))
+		}
+		if len(availableOrderTransitions([]model.OrderStatusTransition{*transition}, order.UserID, staffID, role)) == 0 {
+			return nil, fmt.Errorf("%w", apperr.ErrForbidden)
+		}
+	}
+
+	exists, err := s.repo.UserCar.VINExists(ctx, vin)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if exists {
+		return nil, apperr.Conflict("This VIN is already registered")
+	}
+
+	reason := orderHandoverNote
+	car := model.UserCarCreate{VIN: vin, Year: year, CurrentMileage: in.Mileage, PurchaseDate: &delivered}
+	created, err := s.repo.Order.HandOver(ctx, orderID, order.Status, orderStatusCompleted, car,
+		model.OrderStatusChange{Reason: &reason}, &staffID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UserCar.GetByID(ctx, created.UserCarID)
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
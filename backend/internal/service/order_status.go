@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
 	"github.com/carkeeper/backend/internal/model"
 	"github.com/carkeeper/backend/internal/repository"
 	"github.com/carkeeper/backend/internal/validate"
@@ -110,3 +113,55 @@ func (s *OrderStatusService) Update(ctx context.Context, id uuid.UUID, patch mod
 func (s *OrderStatusService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.OrderStatus.Delete(ctx, id)
 }
+
+func (s *OrderStatusService) ListTransitions(ctx context.Context) ([]model.OrderStatusTransition, error) {
+	return s.repo.OrderStatus.ListTransitions(ctx)
+}
+
+func (s *OrderStatusService) CreateTransition(ctx context.Context, in model.OrderStatusTransitionInput) (*model.OrderStatusTransition, error) {
+	if err := s.normalizeTransitionInput(ctx, &in); err != nil {
+		return nil, err
+	}
+	return s.repo.OrderStatus.CreateTransition(ctx, in)
+}
+
+func (s *OrderStatusService) UpdateTransition(ctx context.Context, id uuid.UUID, in model.OrderStatusTransitionInput) (*model.OrderStatusTransition, error) {
+	if err := s.normalizeTransitionInput(ctx, &in); err != nil {
+		return nil, err
+	}
+	return s.repo.OrderStatus.UpdateTransition(ctx, id, in)
+}
+
+func (s *OrderStatusService) DeleteTransition(ctx context.Context, id uuid.UUID) error {
+	return s.repo.OrderStatus.DeleteTransition(ctx, id)
+}
+
+func (s *OrderStatusService) normalizeTransitionInput(ctx context.Context, in *model.OrderStatusTransitionInput) error {
+	from, msg := validate.OrderStatusCode(in.FromStatus)
+	if msg != "" {
+		return apperr.BadRequest("from_status: " + msg)
+	}
+	to, msg := validate.OrderStatusCode(in.ToStatus)
+	if msg != "" {
+		return apperr.BadRequest("to_status: " + msg)
+	}
+	if from == to {
+		return apperr.BadRequest("A status cannot transition to itself")
+	}
+	def, err := s.repo.OrderStatus.GetByCode(ctx, from)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.BadRequest("Unknown from_status")
+		}
+		return err
+	}
+	if def.IsTerminal {
+		return apperr.BadRequest("Terminal statuses cannot have outgoing transitions")
+	}
+	perm := authz.PermOrdersManageStatus
+	if in.PermissionCode != nil && strings.TrimSpace(*in.PermissionCode) != "" {
+		perm = strings.TrimSpace(*in.PermissionCode)
+	}
+	in.FromStatus, in.ToStatus, in.PermissionCode = from, to, &perm
+	return nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func testOrderTransitions() []model.OrderStatusTransition {
+	return []model.OrderStatusTransition{
+		{ToStatus: "approved", PermissionCode: authz.PermOrdersManageStatus, ToIsActive: true},
+		{ToStatus: "cancelled", PermissionCode: authz.PermOrdersManageStatus, CustomerMayTrigger: true, ToIsActive: true},
+		{ToStatus: "on_hold", PermissionCode: authz.PermOrdersManageStatus, CustomerMayTrigger: true, ToIsActive: false},
+	}
+}
+
+func transitionTargets(ts []model.OrderStatusTransition) []string {
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, t.ToStatus)
+	}
+	return out
+}
+
+func TestAvailableOrderTransitions_Customer(t *testing.T) {
+	owner := uuid.New()
+	got := transitionTargets(availableOrderTransitions(testOrderTransitions(), owner, owner, "customer"))
+	if len(got) != 1 || got[0] != "cancelled" {
+		t.Fatalf("owner transitions = %v, want [cancelled]", got)
+	}
+	if got := availableOrderTransitions(testOrderTransitions(), owner, uuid.New(), "customer"); len(got) != 0 {
+		t.Fatalf("stranger transitions = %v, want none", transitionTargets(got))
+	}
+}
+
+func TestAvailableOrderTransitions_StaffSeesInactiveTargets(t *testing.T) {
+	got := transitionTargets(availableOrderTransitions(testOrderTransitions(), uuid.New(), uuid.New(), "manager"))
+	if len(got) != 3 {
+		t.Fatalf("manager transitions = %v, want all three", got)
+	}
+}
+
+func TestFindOrderTransition(t *testing.T) {
+	ts := testOrderTransitions()
+	if tr := findOrderTransition(ts, "approved"); tr == nil || tr.ToStatus != "approved" {
+		t.Fatalf("approved not found: %+v", tr)
+	}
+	if tr := findOrderTransition(ts, "paid"); tr != nil {
+		t.Fatalf("paid should not be reachable: %+v", tr)
+	}
+}
+
+func TestCustomerOrderTimeline_HidesInternalComments(t *testing.T) {
+	public, internal := "Ожидаем поставку", "Проверить кредитную историю"
+	events := []model.OrderEvent{
+		{ToStatus: "approved", Comment: &public},
+		{ToStatus: "paid", Comment: &internal, CommentInternal: true},
+	}
+	got := customerOrderTimeline(events)
+	if len(got) != 2 {
+		t.Fatalf("events = %d, want 2", len(got))
+	}
+	if got[0].Comment == nil || *got[0].Comment != public {
+		t.Fatalf("public comment lost: %+v", got[0])
+	}
+	if got[1].Comment != nil || got[1].CommentInternal {
+		t.Fatalf("internal comment leaked: %+v", got[1])
+	}
+	if events[1].Comment == nil {
+		t.Fatal("source events must not be modified")
+	}
+}
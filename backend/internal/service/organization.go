@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// OrganizationService manages households and company fleets: members with owner, driver or viewer
+// roles, the cars their owners share with them, the fleet's appointments and its service spend.
+type OrganizationService struct {
+	repo *repository.Repository
+}
+
+func NewOrganizationService(repos *repository.Repository) *OrganizationService {
+	return &OrganizationService{repo: repos}
+}
+
+// canViewCar reports whether the requester may read a car and its records: as the owner, as a
+// member of the organization the car is shared with, or as staff holding permission.
+func canViewCar(ctx context.Context, repos *repository.Repository, userCarID, ownerID, requester uuid.UUID, role, permission string) (bool, error) {
+	if authz.IsOwnerOrHasPermission(ownerID, requester, role, permission) {
+		return true, nil
+	}
+	orgRole, err := repos.Organization.CarMemberRole(ctx, userCarID, requester)
+	return orgRole != "", err
+}
+
+// canBookCar reports whether the requester may book and change service for a car: the owner and
+// the owners and drivers of the organization the car is shared with.
+func canBookCar(ctx context.Context, repos *repository.Repository, userCarID, ownerID, requester uuid.UUID) (bool, error) {
+	if ownerID == requester {
+		return true, nil
+	}
+	orgRole, err := repos.Organization.CarMemberRole(ctx, userCarID, requester)
+	return authz.OrgCanBook(orgRole), err
+}
+
+// memberRole returns the requester's role; organizations the requester is not in are reported as
+// not found.
+func (s *OrganizationService) memberRole(ctx context.Context, organizationID, requester uuid.UUID) (string, error) {
+	role, err := s.repo.Organization.MemberRole(ctx, organizationID, requester)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", apperr.NotFoundErr("Organization not found")
+	}
+	return role, nil
+}
+
+// requireManager checks that the requester is an owner of the organization.
+func (s *OrganizationService) requireManager(ctx context.Context, organizationID, requester uuid.UUID) error {
+	role, err := s.memberRole(ctx, organizationID, requester)
+	if err != nil {
+		return err
+	}
+	if !authz.OrgCanManage(role) {
+		return apperr.Forbidden("only organization owners can do this")
+	}
+	return nil
+}
+
+func (s *OrganizationService) List(ctx context.Context, requester uuid.UUID) ([]model.Organization, error) {
+	return s.repo.Organization.ListForUser(ctx, requester)
+}
+
+func (s *OrganizationService) Get(ctx context.Context, organizationID, requester uuid.UUID) (*model.Organization, error) {
+	o, err := s.repo.Organization.GetForUser(ctx, organizationID, requester)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Organization not found")
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// Create starts an organization with the requester as its owner.
+func (s *OrganizationService) Create(ctx context.Context, requester uuid.UUID, in model.OrganizationInput) (*model.Organization, error) {
+	name, kind, err := organizationInput(in)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.repo.Organization.Create(ctx, name, kind, requester)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, id, requester)
+}
+
+func (s *OrganizationService) Update(ctx context.Context, organizationID, requester uuid.UUID, in model.OrganizationInput) (*model.Organization, error) {
+	if err := s.requireManager(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	name, kind, err := organizationInput(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Organization.Update(ctx, organizationID, name, kind); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, organizationID, requester)
+}
+
+// Delete removes the organization; shared cars stay in their owners' garages.
+func (s *OrganizationService) Delete(ctx context.Context, organizationID, requester uuid.UUID) error {
+	if err := s.requireManager(ctx, organizationID, requester); err != nil {
+		return err
+	}
+	return s.repo.Organization.Delete(ctx, organizationID)
+}
+
+func organizationInput(in model.OrganizationInput) (string, string, error) {
+	name, msg := validate.OrganizationName(in.Name)
+	if msg != "" {
+		return "", "", apperr.BadRequest(msg)
+	}
+	kind, msg := validate.OrganizationKind(in.Kind)
+	if msg != "" {
+		return "", "", apperr.BadRequest(msg)
+	}
+	return name, kind, nil
+}
+
+func (s *OrganizationService) Members(ctx context.Context, organizationID, requester uuid.UUID) ([]model.OrganizationMember, error) {
+	if _, err := s.memberRole(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	return s.repo.Organization.ListMembers(ctx, organizationID)
+}
+
+// AddMember adds a registered customer by email.
+func (s *OrganizationService) AddMember(ctx context.Context, organizationID, requester uuid.UUID, in model.OrganizationMemberInput) ([]model.OrganizationMember, error) {
+	if err := s.requireManager(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	role, msg := validate.OrganizationRole(in.Role)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	email, msg := validate.Email(in.Email)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	exists, err := s.repo.User.EmailExists(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apperr.NotFoundErr("No customer with this email")
+	}
+	u, err := s.repo.User.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	if err := s.repo.Organization.AddMember(ctx, organizationID, u.UserID, role); err != nil {
+		return nil, err
+	}
+	return s.repo.Organization.ListMembers(ctx, organizationID)
+}
+
+func (s *OrganizationService) UpdateMember(ctx context.Context, organizationID, requester, memberID uuid.UUID, in model.OrganizationMemberUpdate) ([]model.OrganizationMember, error) {
+	if err := s.requireManager(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	role, msg := validate.OrganizationRole(in.Role)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if err := s.repo.Organization.UpdateMemberRole(ctx, organizationID, memberID, role); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Member not found")
+		}
+		return nil, err
+	}
+	return s.repo.Organization.ListMembers(ctx, organizationID)
+}
+
+// RemoveMember removes a member; any member may leave on their own.
+func (s *OrganizationService) RemoveMember(ctx context.Context, organizationID, requester, memberID uuid.UUID) error {
+	if memberID == requester {
+		if _, err := s.memberRole(ctx, organizationID, requester); err != nil {
+			return err
+		}
+	} else if err := s.requireManager(ctx, organizationID, requester); err != nil {
+		return err
+	}
+	if err := s.repo.Organization.RemoveMember(ctx, organizationID, memberID); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.NotFoundErr("Member not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// Cars lists the cars shared with the organization.
+func (s *OrganizationService) Cars(ctx context.Context, organizationID, requester uuid.UUID) ([]model.UserCarWithDetails, error) {
+	if _, err := s.memberRole(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	cars, err := s.repo.UserCar.ListByOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if cars == nil {
+		cars = []model.UserCarWithDetails{}
+	}
+	return cars, nil
+}
+
+// Appointments lists the service appointments of all the organization's cars.
+func (s *OrganizationService) Appointments(ctx context.Context, organizationID, requester uuid.UUID, filters model.AppointmentFilters) ([]model.ServiceAppointmentWithDetails, error) {
+	if _, err := s.memberRole(ctx, organizationID, requester); err != nil {
+		return nil, err
+	}
+	filters.OrganizationID = &organizationID
+	list, err := s.repo.ServiceAppointment.Search(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillAppointmentUnread(ctx, s.repo, list, requester, false); err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []model.ServiceAppointmentWithDetails{}
+	}
+	return list, nil
+}
+
+// Spend reports what the organization's cars cost in completed service visits, per car and per
+// month, using the service book totals (?from=&to=, the current month by default).
+func (s *OrganizationService) Spend(ctx context.Context, organizationID, requester uuid.UUID, fromStr, toStr string) (*model.OrganizationSpendReport, error) {
+	role, err := s.memberRole(ctx, organizationID, requester)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.OrgCanViewSpend(role) {
+		return nil, apperr.Forbidden("drivers cannot view the spend report")
+	}
+	from, to, msg := reportWindow(fromStr, toStr, time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	cars, err := s.repo.Organization.SpendByCar(ctx, organizationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	months, err := s.repo.Organization.SpendByMonth(ctx, organizationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := &model.OrganizationSpendReport{OrganizationID: organizationID, From: from, To: to, Cars: cars, Months: months}
+	report.Visits, report.Total = organizationSpendTotals(cars)
+	return report, nil
+}
+
+func organizationSpendTotals(cars []model.OrganizationCarSpend) (int, float64) {
+	visits, total := 0, 0.0
+	for _, c := range cars {
+		visits += c.Visits
+		total += c.Total
+	}
+	return visits, roundMoney(total)
+}
+
+// ShareCar shares the requester's car with one of the requester's organizations.
+func (s *OrganizationService) ShareCar(ctx context.Context, userCarID, requester uuid.UUID, in model.UserCarShare) (*model.UserCarWithDetails, error) {
+	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("User car not found")
+		}
+		return nil, err
+	}
+	if car.UserID != requester {
+		return nil, apperr.NotFoundErr("User car not found")
+	}
+	if _, err := s.memberRole(ctx, in.OrganizationID, requester); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UserCar.SetOrganization(ctx, userCarID, &in.OrganizationID); err != nil {
+		return nil, err
+	}
+	return s.repo.UserCar.GetByID(ctx, userCarID)
+}
+
+// UnshareCar stops sharing a car; the car owner or an owner of the organization may do it.
+func (s *OrganizationService) UnshareCar(ctx context.Context, userCarID, requester uuid.UUID) error {
+	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.NotFoundErr("User car not found")
+		}
+		return err
+	}
+	if car.UserID != requester {
+		orgRole, err := s.repo.Organization.CarMemberRole(ctx, userCarID, requester)
+		if err != nil {
+			return err
+		}
+		if orgRole == "" {
+			return apperr.NotFoundErr("User car not found")
+		}
+		if !authz.OrgCanManage(orgRole) {
+			return apperr.Forbidden("only organization owners can do this")
+		}
+	}
+	if car.OrganizationID == nil {
+		return apperr.BadRequest("car is not shared")
+	}
+	return s.repo.UserCar.SetOrganization(ctx, userCarID, nil)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestOrganizationSpendTotals(t *testing.T) {
+	cars := []model.OrganizationCarSpend{
+		{Visits: 2, Total: 10500.104},
+		{Visits: 1, Total: 3200.2},
+		{Visits: 0, Total: 0},
+	}
+	visits, total := organizationSpendTotals(cars)
+	if visits != 3 {
+		t.Fatalf("visits = %d, want 3", visits)
+	}
+	if total != 13700.3 {
+		t.Fatalf("total = %v, want 13700.3", total)
+	}
+	if visits, total := organizationSpendTotals(nil); visits != 0 || total != 0 {
+		t.Fatalf("empty report = %d, %v", visits, total)
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/carkeeper/backend/config"
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/payment"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	orderStatusPaid     = "paid"
+	orderFullyPaidNote  = "Заказ оплачен полностью"
+	paymentMethodOnline = "online"
+)
+
+type PaymentService struct {
+	repo     *repository.Repository
+	gateway  payment.Gateway
+	currency string
+}
+
+func NewPaymentService(repos *repository.Repository, gateway payment.Gateway, cfg config.PaymentConfig) *PaymentService {
+	return &PaymentService{repo: repos, gateway: gateway, currency: cfg.Currency}
+}
+
+// OrderPayments returns invoices, payments and totals of an order the requester can see.
+func (s *PaymentService) OrderPayments(ctx context.Context, orderID, requester uuid.UUID, role string) (*model.OrderPayments, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanViewOrder(order.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	invoices, err := s.repo.Payment.ListInvoicesByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeOrderPayments(order.AmountDue, invoices), nil
+}
+
+func (s *PaymentService) CreateInvoice(ctx context.Context, orderID, staffID uuid.UUID, in model.InvoiceCreate) (*model.Invoice, error) {
+	kind, msg := validate.InvoiceKind(in.Kind)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	dueDate, msg := validate.InvoiceDueDate(in.DueDate)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if msg := validate.PaymentAmount(in.Amount); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.repo.OrderStatus.GetByCode(ctx, order.Status)
+	if err != nil {
+		return nil, err
+	}
+	if status.IsTerminal {
+		return nil, apperr.BadRequest("Order is closed and cannot be invoiced")
+	}
+
+	inv, err := s.repo.Payment.CreateInvoice(ctx, orderID, kind, dueDate, staffID, func(amountDue, invoiced float64) (float64, error) {
+		return planInvoiceAmount(kind, in.Amount, amountDue, invoiced)
+	})
+	if err != nil {
+		return nil, err
+	}
+	inv.Payments = []model.Payment{}
+	return inv, nil
+}
+
+func (s *PaymentService) CancelInvoice(ctx context.Context, invoiceID uuid.UUID) error {
+	return s.repo.Payment.CancelInvoice(ctx, invoiceID)
+}
+
+// PayOnline starts a gateway payment for an invoice of the order, in full or in part. The payment
+// stays pending until the gateway confirms it, synchronously or through the webhook.
+func (s *PaymentService) PayOnline(ctx context.Context, orderID, invoiceID, requester uuid.UUID, role string, in model.PaymentCreate) (*model.Payment, error) {
+	if msg := validate.PaymentAmount(in.Amount); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !authz.CanViewOrder(order.UserID, requester, role) {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	inv, err := s.repo.Payment.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.OrderID != orderID {
+		return nil, apperr.NotFoundErr("Invoice not found")
+	}
+
+	gatewayName := s.gateway.Name()
+	p, err := s.repo.Payment.CreatePayment(ctx, invoiceID, paymentMethodOnline, &gatewayName, requester, func(inv model.Invoice) (float64, error) {
+		return paymentAmount(inv, in.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.gateway.CreatePayment(ctx, payment.PaymentRequest{
+		PaymentID:   p.PaymentID,
+		Amount:      p.Amount,
+		Currency:    s.currency,
+		Description: fmt.Sprintf("Order %s, %s invoice", orderID, inv.Kind),
+	})
+	if err != nil {
+		reason := "gateway error"
+		if _, _, settleErr := s.repo.Payment.SettlePayment(ctx, p.PaymentID, payment.StatusFailed, &reason); settleErr != nil {
+			slog.Error("payment: mark failed after gateway error", "payment_id", p.PaymentID, "err", settleErr)
+		}
+		return nil, apperr.Wrap(err, http.StatusBadGateway, "Payment gateway is unavailable, please try again later")
+	}
+	if err := s.repo.Payment.SetPaymentGatewayRef(ctx, p.PaymentID, res.Ref, nonEmpty(res.PaymentURL)); err != nil {
+		return nil, err
+	}
+	if res.Status != payment.StatusPending {
+		if err := s.applyPaymentResult(ctx, p.PaymentID, res.Status, nonEmpty(res.FailureReason), &requester); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Payment.GetPayment(ctx, p.PaymentID)
+}
+
+// RecordOfflinePayment registers money staff received outside the gateway; it settles at once.
+func (s *PaymentService) RecordOfflinePayment(ctx context.Context, invoiceID, staffID uuid.UUID, in model.PaymentCreate) (*model.Payment, error) {
+	method, msg := validate.OfflinePaymentMethod(in.Method)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if msg := validate.PaymentAmount(in.Amount); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	p, err := s.repo.Payment.CreatePayment(ctx, invoiceID, method, nil, staffID, func(inv model.Invoice) (float64, error) {
+		return paymentAmount(inv, in.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	advanceOrderIfFullyPaid(ctx, s.repo, p.OrderID, &staffID)
+	return p, nil
+}
+
+// Refund returns part or all of a succeeded payment; gateway payments are refunded through the
+// gateway, offline ones are recorded as paid back at once.
+func (s *PaymentService) Refund(ctx context.Context, paymentID, staffID uuid.UUID, in model.RefundCreate) (*model.Refund, error) {
+	reason, msg := validate.RefundReason(in.Reason)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if msg := validate.PaymentAmount(in.Amount); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	rf, p, err := s.repo.Payment.CreateRefund(ctx, paymentID, reason, staffID, func(p model.Payment, refunded float64) (float64, error) {
+		return refundAmount(p, refunded, in.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.Gateway == nil {
+		return rf, nil
+	}
+	if *p.Gateway != s.gateway.Name() || p.GatewayRef == nil {
+		failure := "payment was taken by another gateway"
+		return s.repo.Payment.SettleRefund(ctx, rf.RefundID, nil, payment.StatusFailed, &failure)
+	}
+
+	res, err := s.gateway.Refund(ctx, payment.RefundRequest{RefundID: rf.RefundID, PaymentRef: *p.GatewayRef, Amount: rf.Amount})
+	if err != nil {
+		failure := "gateway error"
+		if _, settleErr := s.repo.Payment.SettleRefund(ctx, rf.RefundID, nil, payment.StatusFailed, &failure); settleErr != nil {
+			slog.Error("payment: mark refund failed after gateway error", "refund_id", rf.RefundID, "err", settleErr)
+		}
+		return nil, apperr.Wrap(err, http.StatusBadGateway, "Payment gateway is unavailable, please try again later")
+	}
+	return s.repo.Payment.SettleRefund(ctx, rf.RefundID, nonEmpty(res.Ref), res.Status, nonEmpty(res.FailureReason))
+}
+
+// HandleWebhook applies an asynchronous payment or refund confirmation from the gateway.
+// Repeated notifications are harmless.
+func (s *PaymentService) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	ev, err := s.gateway.ParseWebhook(header, body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return apperr.Unauthorized("Invalid webhook signature")
+		}
+		return apperr.Wrap(err, http.StatusBadRequest, "Invalid webhook payload")
+	}
+	switch ev.Type {
+	case payment.EventPayment:
+		p, err := s.repo.Payment.GetPaymentByGatewayRef(ctx, s.gateway.Name(), ev.Ref)
+		if err != nil {
+			return err
+		}
+		return s.applyPaymentResult(ctx, p.PaymentID, ev.Status, nonEmpty(ev.FailureReason), nil)
+	default:
+		rf, err := s.repo.Payment.GetRefundByGatewayRef(ctx, ev.Ref)
+		if err != nil {
+			return err
+		}
+		_, err = s.repo.Payment.SettleRefund(ctx, rf.RefundID, nil, ev.Status, nonEmpty(ev.FailureReason))
+		return err
+	}
+}
+
+func (s *PaymentService) applyPaymentResult(ctx context.Context, paymentID uuid.UUID, status string, failureReason *string, actorID *uuid.UUID) error {
+	p, _, err := s.repo.Payment.SettlePayment(ctx, paymentID, status, failureReason)
+	if err != nil {
+		return err
+	}
+	if p.Status == payment.StatusSucceeded {
+		advanceOrderIfFullyPaid(ctx, s.repo, p.OrderID, actorID)
+	}
+	return nil
+}
+
+// advanceOrderIfFullyPaid moves a fully paid order to "paid" when the status graph allows it from
+// its current status. Failures are logged: the money is already recorded and the move is retried on
+// the next payment event or status change.
+func advanceOrderIfFullyPaid(ctx context.Context, repo *repository.Repository, orderID uuid.UUID, actorID *uuid.UUID) {
+	order, err := repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		slog.Warn("payment: load order", "order_id", orderID, "err", err)
+		return
+	}
+	if order.Status == orderStatusPaid {
+		return
+	}
+	net, err := repo.Payment.NetPaidByOrder(ctx, orderID)
+	if err != nil {
+		slog.Warn("payment: order totals", "order_id", orderID, "err", err)
+		return
+	}
+	if !orderFullyPaid(order.AmountDue, net) {
+		return
+	}
+	transitions, err := repo.OrderStatus.ListTransitionsFrom(ctx, order.Status)
+	if err != nil {
+		slog.Warn("payment: order transitions", "order_id", orderID, "err", err)
+		return
+	}
+	if findOrderTransition(transitions, orderStatusPaid) == nil {
+		slog.Info("payment: order fully paid but cannot move to paid yet", "order_id", orderID, "status", order.Status)
+		return
+	}
+	reason := orderFullyPaidNote
+	change := model.OrderStatusChange{Status: orderStatusPaid, Reason: &reason}
+	if err := repo.Order.UpdateStatus(ctx, orderID, order.Status, change, actorID); err != nil {
+		slog.Warn("payment: move order to paid", "order_id", orderID, "err", err)
+	}
+}
+
+func summarizeOrderPayments(amountDue float64, invoices []model.Invoice) *model.OrderPayments {
+	out := &model.OrderPayments{AmountDue: amountDue, Invoices: invoices}
+	for _, inv := range invoices {
+		out.Paid += inv.AmountPaid - inv.AmountRefunded
+		if inv.Status != "cancelled" {
+			out.Invoiced += inv.Amount
+		}
+	}
+	out.Invoiced = roundMoney(out.Invoiced)
+	out.Paid = roundMoney(out.Paid)
+	out.Outstanding = math.Max(0, roundMoney(amountDue-out.Paid))
+	return out
+}
+
+// planInvoiceAmount resolves the invoice amount against what is left to bill on the order. A deposit
+// needs an explicit amount; a balance defaults to the whole remainder.
+func planInvoiceAmount(kind string, requested *float64, amountDue, invoiced float64) (float64, error) {
+	remaining := roundMoney(amountDue - invoiced)
+	if remaining <= 0 {
+		return 0, apperr.BadRequest("The order is already fully invoiced")
+	}
+	if requested == nil {
+		if kind == "deposit" {
+			return 0, apperr.BadRequest("amount is required for a deposit invoice")
+		}
+		return remaining, nil
+	}
+	amount := roundMoney(*requested)
+	if amount <= 0 || amount > remaining {
+		return 0, apperr.BadRequest(fmt.Sprintf("amount must be between 0.01 and %.2f", remaining))
+	}
+	return amount, nil
+}
+
+// paymentAmount resolves a payment against the unpaid part of the invoice, counting payments still
+// awaiting confirmation as paid so the invoice cannot be overpaid.
+func paymentAmount(inv model.Invoice, requested *float64) (float64, error) {
+	if inv.Status == "cancelled" {
+		return 0, apperr.BadRequest("Invoice is cancelled")
+	}
+	remaining := roundMoney(inv.Amount - (inv.AmountPaid - inv.AmountRefunded) - inv.PendingAmount)
+	if remaining <= 0 {
+		if inv.PendingAmount > 0 {
+			return 0, apperr.Conflict("A payment for this invoice is awaiting confirmation")
+		}
+		return 0, apperr.BadRequest("Invoice is already paid")
+	}
+	if requested == nil {
+		return remaining, nil
+	}
+	amount := roundMoney(*requested)
+	if amount <= 0 || amount > remaining {
+		return 0, apperr.BadRequest(fmt.Sprintf("amount must be between 0.01 and %.2f", remaining))
+	}
+	return amount, nil
+}
+
+// refundAmount resolves a refund against the part of a succeeded payment not yet refunded (pending
+// refunds included).
+func refundAmount(p model.Payment, refunded float64, requested *float64) (float64, error) {
+	if p.Status != payment.StatusSucceeded {
+		return 0, apperr.BadRequest("Only succeeded payments can be refunded")
+	}
+	remaining := roundMoney(p.Amount - refunded)
+	if remaining <= 0 {
+		return 0, apperr.BadRequest("Payment is already fully refunded")
+	}
+	if requested == nil {
+		return remaining, nil
+	}
+	amount := roundMoney(*requested)
+	if amount <= 0 || amount > remaining {
+		return 0, apperr.BadRequest(fmt.Sprintf("amount must be between 0.01 and %.2f", remaining))
+	}
+	return amount, nil
+}
+
+func orderFullyPaid(amountDue, paid float64) bool {
+	return amountDue > 0 && math.Round(paid*100) >= math.Round(amountDue*100)
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func amountPtr(v float64) *float64 { return &v }
+
+func apiStatus(err error) int {
+	var ae *apperr.APIError
+	if errors.As(err, &ae) {
+		return ae.Status
+	}
+	return 0
+}
+
+func TestPlanInvoiceAmount(t *testing.T) {
+	if got, err := planInvoiceAmount("balance", nil, 1_000_000, 100_000); err != nil || got != 900_000 {
+		t.Fatalf("balance remainder = %v, %v", got, err)
+	}
+	if _, err := planInvoiceAmount("deposit", nil, 1_000_000, 0); err == nil {
+		t.Fatal("deposit without amount accepted")
+	}
+	if got, err := planInvoiceAmount("deposit", amountPtr(50_000.004), 1_000_000, 0); err != nil || got != 50_000 {
+		t.Fatalf("deposit = %v, %v", got, err)
+	}
+	if _, err := planInvoiceAmount("deposit", amountPtr(900_000.01), 1_000_000, 100_000); err == nil {
+		t.Fatal("amount above the uninvoiced remainder accepted")
+	}
+	if _, err := planInvoiceAmount("balance", nil, 1_000_000, 1_000_000); err == nil {
+		t.Fatal("fully invoiced order accepted another invoice")
+	}
+}
+
+func TestPaymentAmount(t *testing.T) {
+	inv := model.Invoice{Amount: 100_000, AmountPaid: 30_000, AmountRefunded: 10_000, PendingAmount: 20_000, Status: "partially_paid"}
+	if got, err := paymentAmount(inv, nil); err != nil || got != 60_000 {
+		t.Fatalf("remainder = %v, %v", got, err)
+	}
+	if got, err := paymentAmount(inv, amountPtr(25_000)); err != nil || got != 25_000 {
+		t.Fatalf("partial = %v, %v", got, err)
+	}
+	if _, err := paymentAmount(inv, amountPtr(60_000.01)); apiStatus(err) != http.StatusBadRequest {
+		t.Fatalf("overpayment err = %v", err)
+	}
+
+	inv.PendingAmount = 80_000
+	if _, err := paymentAmount(inv, nil); apiStatus(err) != http.StatusConflict {
+		t.Fatalf("pending cover err = %v, want conflict", err)
+	}
+	inv.Status = "cancelled"
+	if _, err := paymentAmount(inv, nil); err == nil {
+		t.Fatal("cancelled invoice accepted a payment")
+	}
+}
+
+func TestRefundAmount(t *testing.T) {
+	p := model.Payment{Amount: 50_000, Status: "succeeded"}
+	if got, err := refundAmount(p, 20_000, nil); err != nil || got != 30_000 {
+		t.Fatalf("remainder = %v, %v", got, err)
+	}
+	if _, err := refundAmount(p, 20_000, amountPtr(30_000.01)); err == nil {
+		t.Fatal("over-refund accepted")
+	}
+	if _, err := refundAmount(p, 50_000, nil); err == nil {
+		t.Fatal("refund of a fully refunded payment accepted")
+	}
+	p.Status = "pending"
+	if _, err := refundAmount(p, 0, nil); err == nil {
+		t.Fatal("refund of a pending payment accepted")
+	}
+}
+
+func TestSummarizeOrderPayments(t *testing.T) {
+	invoices := []model.Invoice{
+		{Kind: "deposit", Amount: 100_000, AmountPaid: 100_000, Status: "paid"},
+		{Kind: "balance", Amount: 50_000, AmountPaid: 20_000, AmountRefunded: 20_000, Status: "cancelled"},
+		{Kind: "balance", Amount: 400_000, AmountPaid: 150_000, AmountRefunded: 50_000, Status: "partially_paid"},
+	}
+	got := summarizeOrderPayments(500_000, invoices)
+	if got.Invoiced != 500_000 || got.Paid != 200_000 || got.Outstanding != 300_000 {
+		t.Fatalf("got %+v", got)
+	}
+	if !orderFullyPaid(500_000, 500_000.001) || orderFullyPaid(500_000, 499_999.99) || orderFullyPaid(0, 0) {
+		t.Fatal("orderFullyPaid thresholds")
+	}
+}
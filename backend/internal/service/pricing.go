@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	customerSegmentNew       = "new_customer"
+	customerSegmentReturning = "returning_customer"
+)
+
+// pricingInput is everything applyPromotions needs; it is filled from the DB by quoteConfiguration.
+type pricingInput struct {
+	Scope           model.PricingScope
+	ColorPrice      float64
+	Options         []model.Option
+	CustomerSegment string
+	UserUses        map[uuid.UUID]int
+	Code            *model.PromotionCode
+	Now             time.Time
+}
+
+// quoteConfiguration prices a configuration at current catalog prices for its owner and applies
+// eligible promotions. promoCode is optional; an unusable code is reported as a bad request.
+func quoteConfiguration(ctx context.Context, repo *repository.Repository, config *model.ConfigurationWithDetails, promoCode *string, now time.Time) (*model.PriceBreakdown, error) {
+	scope, err := repo.Trim.GetPricingScope(ctx, config.TrimID)
+	if err != nil {
+		return nil, apperr.Wrap(err, 400, "Invalid trim or catalog data")
+	}
+	color, err := repo.Color.GetByID(ctx, config.ColorID)
+	if err != nil {
+		return nil, apperr.Wrap(err, 400, "Invalid color or catalog data")
+	}
+
+	in := pricingInput{
+		Scope:      *scope,
+		ColorPrice: color.PriceDelta,
+		Options:    config.Options,
+		Now:        now,
+	}
+	lockedUntil := applyPriceLock(&in, &config.Configuration)
+
+	completed, err := repo.Order.CountCompletedByUser(ctx, config.UserID)
+	if err != nil {
+		return nil, err
+	}
+	in.CustomerSegment = customerSegmentNew
+	if completed > 0 {
+		in.CustomerSegment = customerSegmentReturning
+	}
+	if in.UserUses, err = repo.Promotion.CountUsesByUser(ctx, config.UserID); err != nil {
+		return nil, err
+	}
+
+	if promoCode != nil && strings.TrimSpace(*promoCode) != "" {
+		code, msg := validate.PromoCode(*promoCode)
+		if msg != "" {
+			return nil, apperr.BadRequest(msg)
+		}
+		pc, err := repo.Promotion.GetCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return nil, apperr.BadRequest("Unknown promo code")
+			}
+			return nil, err
+		}
+		if pc.IsSingleUse && pc.RedeemedAt != nil {
+			return nil, apperr.BadRequest("Promo code has already been used")
+		}
+		in.Code = pc
+	}
+
+	promotions, err := repo.Promotion.ListActiveForTrim(ctx, *scope)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := applyPromotions(in, promotions)
+	quote.PriceLockedUntil = lockedUntil
+	if in.Code != nil && !quoteUsesCode(quote, in.Code.PromotionCodeID) {
+		return nil, apperr.BadRequest("Promo code is not applicable to this configuration")
+	}
+	return &quote, nil
+}
+
+// applyPriceLock swaps in the trim and color prices captured on confirmation while the lock is
+// active; option prices already come locked from the repository. It returns the lock expiry.
+func applyPriceLock(in *pricingInput, config *model.Configuration) *time.Time {
+	if !config.PriceLockActive(in.Now) {
+		return nil
+	}
+	in.Scope.BasePrice = *config.LockedTrimPrice
+	in.ColorPrice = *config.LockedColorPrice
+	until := *config.PriceLockedUntil
+	return &until
+}
+
+func quoteUsesCode(quote model.PriceBreakdown, codeID uuid.UUID) bool {
+	for _, p := range quote.Promotions {
+		if p.PromotionCodeID != nil && *p.PromotionCodeID == codeID {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPromotions computes the price breakdown. Each eligible promotion is evaluated against the
+// undiscounted price; the result is either the best single non-stackable promotion or the sum of
+// all stackable ones, whichever saves more. The discount never exceeds the list price.
+func applyPromotions(in pricingInput, promotions []model.Promotion) model.PriceBreakdown {
+	out := model.PriceBreakdown{
+		TrimPrice:  roundMoney(in.Scope.BasePrice),
+		ColorPrice: roundMoney(in.ColorPrice),
+		Promotions: []model.AppliedPromotion{},
+	}
+	optionPrices := make(map[uuid.UUID]float64, len(in.Options))
+	for _, opt := range in.Options {
+		out.OptionsPrice += opt.Price
+		optionPrices[opt.OptionID] = opt.Price
+	}
+	out.OptionsPrice = roundMoney(out.OptionsPrice)
+	out.ListPrice = roundMoney(out.TrimPrice + out.ColorPrice + out.OptionsPrice)
+
+	type candidate struct {
+		promo   model.Promotion
+		applied model.AppliedPromotion
+	}
+	var stackable []candidate
+	var best *candidate
+	for _, p := range promotions {
+		if !promotionEligible(p, in, optionPrices) {
+			continue
+		}
+		base := out.ListPrice
+		if p.OptionID != nil {
+			base = optionPrices[*p.OptionID]
+		}
+		amount := promotionAmount(p.DiscountType, p.DiscountValue, base)
+		if amount <= 0 {
+			continue
+		}
+		c := candidate{promo: p, applied: model.AppliedPromotion{
+			PromotionID:    &p.PromotionID,
+			Name:           p.Name,
+			DiscountType:   p.DiscountType,
+			DiscountValue:  p.DiscountValue,
+			DiscountAmount: amount,
+		}}
+		if in.Code != nil && in.Code.PromotionID == p.PromotionID {
+			codeID, code := in.Code.PromotionCodeID, in.Code.Code
+			c.applied.PromotionCodeID = &codeID
+			c.applied.Code = &code
+		}
+		if p.IsStackable {
+			stackable = append(stackable, c)
+			continue
+		}
+		if best == nil || amount > best.applied.DiscountAmount ||
+			(amount == best.applied.DiscountAmount && p.Priority > best.promo.Priority) {
+			cc := c
+			best = &cc
+		}
+	}
+
+	stackSum := 0.0
+	for _, c := range stackable {
+		stackSum += c.applied.DiscountAmount
+	}
+	var chosen []candidate
+	if best != nil && best.applied.DiscountAmount >= stackSum {
+		chosen = []candidate{*best}
+	} else {
+		chosen = stackable
+	}
+	sort.SliceStable(chosen, func(i, j int) bool { return chosen[i].promo.Priority > chosen[j].promo.Priority })
+
+	remaining := out.ListPrice
+	for _, c := range chosen {
+		if remaining <= 0 {
+			break
+		}
+		if c.applied.DiscountAmount > remaining {
+			c.applied.DiscountAmount = remaining
+		}
+		remaining = roundMoney(remaining - c.applied.DiscountAmount)
+		out.DiscountTotal += c.applied.DiscountAmount
+		out.Promotions = append(out.Promotions, c.applied)
+	}
+	out.DiscountTotal = roundMoney(out.DiscountTotal)
+	out.FinalPrice = roundMoney(out.ListPrice - out.DiscountTotal)
+	return out
+}
+
+func promotionEligible(p model.Promotion, in pricingInput, optionPrices map[uuid.UUID]float64) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && in.Now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidTo != nil && !in.Now.Before(*p.ValidTo) {
+		return false
+	}
+	if p.MaxUses != nil && p.UsesCount >= *p.MaxUses {
+		return false
+	}
+	if p.MaxUsesPerUser != nil && in.UserUses[p.PromotionID] >= *p.MaxUsesPerUser {
+		return false
+	}
+	if p.BrandID != nil && *p.BrandID != in.Scope.BrandID {
+		return false
+	}
+	if p.ModelID != nil && *p.ModelID != in.Scope.ModelID {
+		return false
+	}
+	if p.TrimID != nil && *p.TrimID != in.Scope.TrimID {
+		return false
+	}
+	if p.VehicleSegment != nil && (in.Scope.Segment == nil || !strings.EqualFold(*p.VehicleSegment, *in.Scope.Segment)) {
+		return false
+	}
+	if p.OptionID != nil {
+		if _, ok := optionPrices[*p.OptionID]; !ok {
+			return false
+		}
+	}
+	if p.CustomerSegment != nil && *p.CustomerSegment != in.CustomerSegment {
+		return false
+	}
+	if p.RequiresCode && (in.Code == nil || in.Code.PromotionID != p.PromotionID) {
+		return false
+	}
+	return true
+}
+
+func promotionAmount(discountType string, value, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	var amount float64
+	switch discountType {
+	case "percent":
+		amount = base * value / 100
+	case "fixed":
+		amount = value
+	}
+	return roundMoney(math.Min(amount, base))
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func testPricingInput() pricingInput {
+	segment := "crossover"
+	return pricingInput{
+		Scope: model.PricingScope{
+			TrimID:    uuid.New(),
+			ModelID:   uuid.New(),
+			BrandID:   uuid.New(),
+			Segment:   &segment,
+			BasePrice: 1_000_000,
+		},
+		ColorPrice:      50_000,
+		Options:         []model.Option{{OptionID: uuid.New(), Price: 100_000}},
+		CustomerSegment: customerSegmentNew,
+		UserUses:        map[uuid.UUID]int{},
+		Now:             time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func testPromotion(discountType string, value float64) model.Promotion {
+	return model.Promotion{
+		PromotionID:   uuid.New(),
+		Name:          "promo",
+		DiscountType:  discountType,
+		DiscountValue: value,
+		IsActive:      true,
+	}
+}
+
+func TestApplyPromotions_NoPromotions(t *testing.T) {
+	got := applyPromotions(testPricingInput(), nil)
+	if got.ListPrice != 1_150_000 || got.FinalPrice != 1_150_000 || got.DiscountTotal != 0 {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestApplyPromotions_SegmentPercent(t *testing.T) {
+	in := testPricingInput()
+	p := testPromotion("percent", 5)
+	seg := "Crossover"
+	p.VehicleSegment = &seg
+	got := applyPromotions(in, []model.Promotion{p})
+	if got.DiscountTotal != 57_500 || got.FinalPrice != 1_092_500 {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestApplyPromotions_BestNonStackableVsStackableSum(t *testing.T) {
+	in := testPricingInput()
+	big := testPromotion("fixed", 80_000)
+	s1 := testPromotion("fixed", 30_000)
+	s1.IsStackable = true
+	s2 := testPromotion("fixed", 40_000)
+	s2.IsStackable = true
+	got := applyPromotions(in, []model.Promotion{big, s1, s2})
+	if got.DiscountTotal != 80_000 || len(got.Promotions) != 1 {
+		t.Fatalf("expected single best promotion, got %+v", got)
+	}
+
+	s2.DiscountValue = 60_000
+	got = applyPromotions(in, []model.Promotion{big, s1, s2})
+	if got.DiscountTotal != 90_000 || len(got.Promotions) != 2 {
+		t.Fatalf("expected stacked promotions, got %+v", got)
+	}
+}
+
+func TestApplyPromotions_OptionScopedUsesOptionPrice(t *testing.T) {
+	in := testPricingInput()
+	p := testPromotion("percent", 50)
+	p.OptionID = &in.Options[0].OptionID
+	got := applyPromotions(in, []model.Promotion{p})
+	if got.DiscountTotal != 50_000 {
+		t.Fatalf("got %+v", got)
+	}
+
+	other := uuid.New()
+	p.OptionID = &other
+	if got := applyPromotions(in, []model.Promotion{p}); got.DiscountTotal != 0 {
+		t.Fatalf("option not in configuration, got %+v", got)
+	}
+}
+
+func TestApplyPromotions_EligibilityRules(t *testing.T) {
+	in := testPricingInput()
+	past := in.Now.Add(-48 * time.Hour)
+	yesterday := in.Now.Add(-24 * time.Hour)
+	returning := customerSegmentReturning
+	one := 1
+	otherBrand := uuid.New()
+
+	expired := testPromotion("fixed", 1000)
+	expired.ValidFrom, expired.ValidTo = &past, &yesterday
+	exhausted := testPromotion("fixed", 1000)
+	exhausted.MaxUses, exhausted.UsesCount = &one, 1
+	wrongSegment := testPromotion("fixed", 1000)
+	wrongSegment.CustomerSegment = &returning
+	wrongBrand := testPromotion("fixed", 1000)
+	wrongBrand.BrandID = &otherBrand
+	perUser := testPromotion("fixed", 1000)
+	perUser.MaxUsesPerUser = &one
+	in.UserUses[perUser.PromotionID] = 1
+	needsCode := testPromotion("fixed", 1000)
+	needsCode.RequiresCode = true
+	inactive := testPromotion("fixed", 1000)
+	inactive.IsActive = false
+
+	got := applyPromotions(in, []model.Promotion{expired, exhausted, wrongSegment, wrongBrand, perUser, needsCode, inactive})
+	if got.DiscountTotal != 0 {
+		t.Fatalf("no promotion should apply, got %+v", got)
+	}
+}
+
+func TestApplyPromotions_CodeUnlocksPromotion(t *testing.T) {
+	in := testPricingInput()
+	p := testPromotion("fixed", 25_000)
+	p.RequiresCode = true
+	in.Code = &model.PromotionCode{PromotionCodeID: uuid.New(), PromotionID: p.PromotionID, Code: "VIP-2025"}
+	got := applyPromotions(in, []model.Promotion{p})
+	if got.DiscountTotal != 25_000 || len(got.Promotions) != 1 {
+		t.Fatalf("got %+v", got)
+	}
+	if !quoteUsesCode(got, in.Code.PromotionCodeID) || got.Promotions[0].Code == nil {
+		t.Fatal("applied line should carry the code")
+	}
+}
+
+func TestApplyPromotions_DiscountCappedAtListPrice(t *testing.T) {
+	in := testPricingInput()
+	a := testPromotion("percent", 100)
+	a.IsStackable = true
+	b := testPromotion("fixed", 500_000)
+	b.IsStackable = true
+	got := applyPromotions(in, []model.Promotion{a, b})
+	if got.FinalPrice != 0 || got.DiscountTotal != got.ListPrice {
+		t.Fatalf("got %+v", got)
+	}
+}
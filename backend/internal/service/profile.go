@@ -27,6 +27,11 @@ func (s *ProfileService) GetUserCars(ctx context.Context, userID uuid.UUID) ([]m
 }
 
 func (s *ProfileService) CreateUserCar(ctx context.Context, userID uuid.UUID, create model.UserCarCreate) (*model.UserCarWithDetails, error) {
+	return createUserCar(ctx, s.repo, userID, create)
+}
+
+// createUserCar validates and registers a car in the user's garage; the VIN must be new.
+func createUserCar(ctx context.Context, repos *repository.Repository, userID uuid.UUID, create model.UserCarCreate) (*model.UserCarWithDetails, error) {
 	create.VIN = validate.NormalizeVIN(create.VIN)
 	if msg := validate.VIN(create.VIN); msg != "" {
 		return nil, apperr.BadRequest(msg)
@@ -39,7 +44,7 @@ func (s *ProfileService) CreateUserCar(ctx context.Context, userID uuid.UUID, cr
 		return nil, apperr.BadRequest("Mileage must be non-negative")
 	}
 
-	exists, err := s.repo.UserCar.VINExists(ctx, create.VIN)
+	exists, err := repos.UserCar.VINExists(ctx, create.VIN)
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
@@ -47,12 +52,12 @@ func (s *ProfileService) CreateUserCar(ctx context.Context, userID uuid.UUID, cr
 		return nil, apperr.Conflict("This VIN is already registered")
 	}
 
-	userCar, err := s.repo.UserCar.Create(ctx, userID, create)
+	userCar, err := repos.UserCar.Create(ctx, userID, create)
 	if err != nil {
 		return nil, apperr.Internal(err)
 	}
 
-	userCarWithDetails, err := s.repo.UserCar.GetByID(ctx, userCar.UserCarID)
+	userCarWithDetails, err := repos.UserCar.GetByID(ctx, userCar.UserCarID)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +65,8 @@ func (s *ProfileService) CreateUserCar(ctx context.Context, userID uuid.UUID, cr
 	return userCarWithDetails, nil
 }
 
+// GetUserCar returns a car with its recall and service campaigns to the owner, members of the
+// organization it is shared with and staff.
 func (s *ProfileService) GetUserCar(ctx context.Context, userCarID uuid.UUID, requester uuid.UUID, role string) (*model.UserCarWithDetails, error) {
 	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
 	if err != nil {
@@ -68,9 +75,16 @@ func (s *ProfileService) GetUserCar(ctx context.Context, userCarID uuid.UUID, re
 		}
 		return nil, err
 	}
-	if !authz.IsOwnerOrHasPermission(car.UserID, requester, role, authz.PermGarageViewAny) {
+	ok, err := canViewCar(ctx, s.repo, car.UserCarID, car.UserID, requester, role, authz.PermGarageViewAny)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
 	}
+	if car.Campaigns, err = s.repo.ServiceCampaign.ListForCar(ctx, car.UserCarID); err != nil {
+		return nil, err
+	}
 	return car, nil
 }
 
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	promoCodeBatchMax     = 500
+	generatedPromoCodeLen = 10
+	promoCodeAlphabet     = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+type PromotionService struct {
+	repo *repository.Repository
+}
+
+func NewPromotionService(repos *repository.Repository) *PromotionService {
+	return &PromotionService{repo: repos}
+}
+
+func (s *PromotionService) ListAll(ctx context.Context) ([]model.Promotion, error) {
+	return s.repo.Promotion.ListAll(ctx)
+}
+
+func (s *PromotionService) Get(ctx context.Context, id uuid.UUID) (*model.Promotion, error) {
+	return s.repo.Promotion.GetByID(ctx, id)
+}
+
+func (s *PromotionService) Create(ctx context.Context, in model.PromotionInput) (*model.Promotion, error) {
+	if err := normalizePromotionInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.Promotion.Create(ctx, in)
+}
+
+func (s *PromotionService) Update(ctx context.Context, id uuid.UUID, in model.PromotionInput) (*model.Promotion, error) {
+	if err := normalizePromotionInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.Promotion.Update(ctx, id, in)
+}
+
+func (s *PromotionService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Promotion.Delete(ctx, id)
+}
+
+func (s *PromotionService) ListCodes(ctx context.Context, promotionID uuid.UUID) ([]model.PromotionCode, error) {
+	if _, err := s.repo.Promotion.GetByID(ctx, promotionID); err != nil {
+		return nil, err
+	}
+	return s.repo.Promotion.ListCodes(ctx, promotionID)
+}
+
+// CreateCodes issues one explicit code or a batch of random codes for a promotion.
+func (s *PromotionService) CreateCodes(ctx context.Context, promotionID uuid.UUID, in model.PromotionCodesCreate) ([]model.PromotionCode, error) {
+	var codes []string
+	if in.Code != nil {
+		code, msg := validate.PromoCode(*in.Code)
+		if msg != "" {
+			return nil, apperr.BadRequest(msg)
+		}
+		codes = []string{code}
+	} else {
+		if in.Count <= 0 || in.Count > promoCodeBatchMax {
+			return nil, apperr.BadRequest("count must be between 1 and 500")
+		}
+		seen := make(map[string]bool, in.Count)
+		for len(codes) < in.Count {
+			code, err := randomPromoCode()
+			if err != nil {
+				return nil, apperr.Internal(err)
+			}
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return s.repo.Promotion.InsertCodes(ctx, promotionID, codes, in.IsSingleUse)
+}
+
+func randomPromoCode() (string, error) {
+	buf := make([]byte, generatedPromoCodeLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = promoCodeAlphabet[int(b)%len(promoCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+func normalizePromotionInput(in *model.PromotionInput) error {
+	name, msg := validate.PromotionName(in.Name)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	desc, msg := validate.PromotionDescription(in.Description)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	discountType, msg := validate.PromotionDiscount(in.DiscountType, in.DiscountValue)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	vehicleSegment, msg := validate.PromotionVehicleSegment(in.VehicleSegment)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	customerSegment, msg := validate.PromotionCustomerSegment(in.CustomerSegment)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.PromotionWindow(in.ValidFrom, in.ValidTo); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.PromotionLimits(in.MaxUses, in.MaxUsesPerUser, in.Priority); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	in.Name = name
+	in.Description = desc
+	in.DiscountType = discountType
+	in.DiscountValue = roundMoney(in.DiscountValue)
+	in.VehicleSegment = vehicleSegment
+	in.CustomerSegment = customerSegment
+	return nil
+}
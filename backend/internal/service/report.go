@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	reportDefaultGroupBy = "period"
+	reportDefaultPeriod  = "month"
+	reportMaxDays        = 731
+)
+
+var reportGroupings = map[string]struct{}{"period": {}, "brand": {}, "model": {}, "manager": {}}
+
+// reportPeriods are the accepted date_trunc units for period buckets.
+var reportPeriods = map[string]struct{}{"day": {}, "week": {}, "month": {}, "quarter": {}, "year": {}}
+
+type ReportService struct {
+	repo *repository.Repository
+}
+
+func NewReportService(repos *repository.Repository) *ReportService {
+	return &ReportService{repo: repos}
+}
+
+// Orders reports orders and revenue grouped by period, brand, model or manager.
+func (s *ReportService) Orders(ctx context.Context, fromStr, toStr, groupBy, period string) (*model.OrderReport, error) {
+	from, to, msg := reportWindow(fromStr, toStr, time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	groupBy = strings.TrimSpace(groupBy)
+	if groupBy == "" {
+		groupBy = reportDefaultGroupBy
+	}
+	if _, ok := reportGroupings[groupBy]; !ok {
+		return nil, apperr.BadRequest("group_by must be one of period, brand, model, manager")
+	}
+	period = strings.TrimSpace(period)
+	if groupBy != "period" {
+		period = ""
+	} else if period == "" {
+		period = reportDefaultPeriod
+	} else if _, ok := reportPeriods[period]; !ok {
+		return nil, apperr.BadRequest("period must be one of day, week, month, quarter, year")
+	}
+
+	rows, err := s.repo.Report.OrderStats(ctx, from, to, groupBy, period)
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := s.repo.Report.RefreshedAt(ctx, repository.ReportViewOrders)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		withOrderAverages(&rows[i])
+	}
+	return &model.OrderReport{
+		From:        from,
+		To:          to,
+		GroupBy:     groupBy,
+		Period:      period,
+		Rows:        rows,
+		Totals:      orderReportTotals(rows),
+		RefreshedAt: refreshed,
+	}, nil
+}
+
+// Funnel reports conversion from configuration to paid order for configurations created in the range.
+func (s *ReportService) Funnel(ctx context.Context, fromStr, toStr string) (*model.FunnelReport, error) {
+	from, to, msg := reportWindow(fromStr, toStr, time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	counts, err := s.repo.Report.FunnelCounts(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := s.repo.Report.RefreshedAt(ctx, repository.ReportViewFunnel)
+	if err != nil {
+		return nil, err
+	}
+	return &model.FunnelReport{From: from, To: to, Stages: funnelStages(counts), RefreshedAt: refreshed}, nil
+}
+
+// Service reports bay utilisation, cancellation and no-show rates per branch.
+func (s *ReportService) Service(ctx context.Context, fromStr, toStr string, branchID *uuid.UUID) (*model.ServiceReport, error) {
+	from, to, msg := reportWindow(fromStr, toStr, time.Now())
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	branches, err := s.repo.Report.ServiceStats(ctx, from, to, branchID)
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := s.repo.Report.RefreshedAt(ctx, repository.ReportViewService)
+	if err != nil {
+		return nil, err
+	}
+	for i := range branches {
+		withServiceRates(&branches[i])
+	}
+	return &model.ServiceReport{From: from, To: to, Branches: branches, RefreshedAt: refreshed}, nil
+}
+
+// Refresh rebuilds the analytics views; it reports false when another instance is already refreshing.
+func (s *ReportService) Refresh(ctx context.Context) (bool, error) {
+	return s.repo.Report.Refresh(ctx)
+}
+
+// reportWindow resolves inclusive YYYY-MM-DD dates into a UTC day range [from, to). By default it
+// covers the current month up to today.
+func reportWindow(fromStr, toStr string, now time.Time) (time.Time, time.Time, string) {
+	today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	from := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := today.AddDate(0, 0, 1)
+	if s := strings.TrimSpace(fromStr); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, "from must be YYYY-MM-DD"
+		}
+		from = d
+	}
+	if s := strings.TrimSpace(toStr); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return time.Time{}, time.Time{}, "to must be YYYY-MM-DD"
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, "to must not be before from"
+	}
+	if to.After(from.AddDate(0, 0, reportMaxDays)) {
+		return time.Time{}, time.Time{}, fmt.Sprintf("report range cannot exceed %d days", reportMaxDays)
+	}
+	return from, to, ""
+}
+
+func withOrderAverages(r *model.OrderReportRow) {
+	r.AverageOrderValue = ratio(r.OrdersValue, float64(r.Orders))
+	r.AveragePaidValue = ratio(r.Revenue, float64(r.PaidOrders))
+}
+
+func orderReportTotals(rows []model.OrderReportRow) model.OrderReportRow {
+	var t model.OrderReportRow
+	for _, r := range rows {
+		t.Orders += r.Orders
+		t.OrdersValue += r.OrdersValue
+		t.PaidOrders += r.PaidOrders
+		t.Revenue += r.Revenue
+		t.CancelledOrders += r.CancelledOrders
+	}
+	withOrderAverages(&t)
+	return t
+}
+
+func funnelStages(c model.FunnelCounts) []model.FunnelStage {
+	counts := []struct {
+		stage string
+		n     int
+	}{
+		{"configured", c.Configurations},
+		{"confirmed", c.Confirmed},
+		{"ordered", c.Ordered},
+		{"paid", c.Paid},
+	}
+	out := make([]model.FunnelStage, len(counts))
+	for i, s := range counts {
+		prev := s.n
+		if i > 0 {
+			prev = counts[i-1].n
+		}
+		out[i] = model.FunnelStage{
+			Stage:              s.stage,
+			Count:              s.n,
+			FromPreviousStage:  ratio(float64(s.n), float64(prev)),
+			FromConfigurations: ratio(float64(s.n), float64(c.Configurations)),
+		}
+	}
+	return out
+}
+
+func withServiceRates(s *model.BranchServiceStats) {
+	s.Utilisation = ratio(float64(s.BookedMinutes), float64(s.CapacityMinutes))
+	s.CancellationRate = ratio(float64(s.Cancelled), float64(s.Appointments))
+	s.NoShowRate = ratio(float64(s.NoShow), float64(s.Appointments))
+}
+
+// ratio divides and rounds to four decimals; an empty denominator yields 0.
+func ratio(num, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return math.Round(num/den*10000) / 10000
+}
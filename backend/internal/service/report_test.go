@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestReportWindow(t *testing.T) {
+	now := time.Date(2026, 5, 18, 22, 0, 0, 0, time.UTC)
+	from, to, msg := reportWindow("", "", now)
+	if msg != "" || !from.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 5, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("default window: %v %v %q", from, to, msg)
+	}
+	if _, _, msg := reportWindow("2024-01-01", "2026-05-01", now); msg == "" {
+		t.Error("too long range should fail")
+	}
+	if _, _, msg := reportWindow("2026-05-02", "2026-05-01", now); msg == "" {
+		t.Error("reversed range should fail")
+	}
+}
+
+func TestOrderReportTotals(t *testing.T) {
+	rows := []model.OrderReportRow{
+		{Orders: 2, OrdersValue: 4_000_000, PaidOrders: 1, Revenue: 2_500_000},
+		{Orders: 1, OrdersValue: 2_000_000, CancelledOrders: 1},
+	}
+	got := orderReportTotals(rows)
+	if got.Orders != 3 || got.OrdersValue != 6_000_000 || got.PaidOrders != 1 || got.CancelledOrders != 1 {
+		t.Fatalf("unexpected totals %+v", got)
+	}
+	if got.AverageOrderValue != 2_000_000 || got.AveragePaidValue != 2_500_000 {
+		t.Errorf("averages %v / %v", got.AverageOrderValue, got.AveragePaidValue)
+	}
+}
+
+func TestFunnelStages(t *testing.T) {
+	stages := funnelStages(model.FunnelCounts{Configurations: 200, Confirmed: 80, Ordered: 20, Paid: 15})
+	if len(stages) != 4 || stages[0].FromPreviousStage != 1 {
+		t.Fatalf("unexpected stages %+v", stages)
+	}
+	if stages[2].FromPreviousStage != 0.25 || stages[3].FromPreviousStage != 0.75 || stages[3].FromConfigurations != 0.075 {
+		t.Errorf("unexpected conversion %+v", stages)
+	}
+	empty := funnelStages(model.FunnelCounts{})
+	if empty[0].FromPreviousStage != 0 || empty[3].FromConfigurations != 0 {
+		t.Errorf("empty funnel should have zero rates: %+v", empty)
+	}
+}
+
+func TestWithServiceRates(t *testing.T) {
+	s := model.BranchServiceStats{Appointments: 8, Cancelled: 2, NoShow: 1, BookedMinutes: 600, CapacityMinutes: 1800}
+	withServiceRates(&s)
+	if s.Utilisation != 0.3333 || s.CancellationRate != 0.25 || s.NoShowRate != 0.125 {
+		t.Fatalf("unexpected rates %+v", s)
+	}
+}
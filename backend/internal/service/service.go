@@ -2,34 +2,66 @@ package service
 
 import (
 	"github.com/carkeeper/backend/config"
+	"github.com/carkeeper/backend/internal/payment"
 	"github.com/carkeeper/backend/internal/repository"
 	"github.com/carkeeper/backend/internal/storage"
 )
 
 type Service struct {
-	Auth         *AuthService
-	Catalog      *CatalogService
-	Configurator *ConfiguratorService
-	Order        *OrderService
-	OrderStatus  *OrderStatusService
-	Role         *RoleService
-	Service      *ServiceService
-	News         *NewsService
-	Profile      *ProfileService
-	Document     *DocumentService
+	Auth            *AuthService
+	Catalog         *CatalogService
+	Configurator    *ConfiguratorService
+	Order           *OrderService
+	OrderStatus     *OrderStatusService
+	Role            *RoleService
+	Service         *ServiceService
+	News            *NewsService
+	Profile         *ProfileService
+	Document        *DocumentService
+	Promotion       *PromotionService
+	Finance         *FinanceService
+	TradeIn         *TradeInService
+	Notification    *NotificationService
+	Assignment      *AssignmentService
+	Payment         *PaymentService
+	Generator       *DocumentGenerator
+	Delivery        *DeliveryService
+	Message         *MessageService
+	Report          *ReportService
+	Maintenance     *MaintenanceService
+	ServiceHistory  *ServiceHistoryService
+	VehicleTransfer *VehicleTransferService
+	Organization    *OrganizationService
+	ServiceCampaign *ServiceCampaignService
 }
 
-func New(repos *repository.Repository, cfg *config.Config, fileStore storage.FileStorage) *Service {
+func New(repos *repository.Repository, cfg *config.Config, fileStore storage.FileStorage, gateway payment.Gateway) *Service {
+	generator := NewDocumentGenerator(repos, fileStore)
 	return &Service{
-		Auth:         NewAuthService(repos, cfg),
-		Catalog:      NewCatalogService(repos, fileStore, cfg.Storage.MaxUploadBytes),
-		Configurator: NewConfiguratorService(repos),
-		Order:        NewOrderService(repos),
-		OrderStatus:  NewOrderStatusService(repos),
-		Role:         NewRoleService(repos),
-		Service:      NewServiceService(repos),
-		News:         NewNewsService(repos),
-		Profile:      NewProfileService(repos),
-		Document:     NewDocumentService(repos, fileStore, cfg.Storage.MaxUploadBytes),
+		Auth:            NewAuthService(repos, cfg),
+		Catalog:         NewCatalogService(repos, fileStore, cfg.Storage.MaxUploadBytes),
+		Configurator:    NewConfiguratorService(repos, cfg.Lifecycle, generator),
+		Order:           NewOrderService(repos, generator),
+		OrderStatus:     NewOrderStatusService(repos),
+		Role:            NewRoleService(repos),
+		Service:         NewServiceService(repos, generator),
+		News:            NewNewsService(repos),
+		Profile:         NewProfileService(repos),
+		Document:        NewDocumentService(repos, fileStore, cfg.Storage.MaxUploadBytes),
+		Promotion:       NewPromotionService(repos),
+		Finance:         NewFinanceService(repos),
+		TradeIn:         NewTradeInService(repos),
+		Notification:    NewNotificationService(repos),
+		Assignment:      NewAssignmentService(repos),
+		Payment:         NewPaymentService(repos, gateway, cfg.Payment),
+		Generator:       generator,
+		Delivery:        NewDeliveryService(repos),
+		Message:         NewMessageService(repos),
+		Report:          NewReportService(repos),
+		Maintenance:     NewMaintenanceService(repos, cfg.Maintenance),
+		ServiceHistory:  NewServiceHistoryService(repos, fileStore),
+		VehicleTransfer: NewVehicleTransferService(repos),
+		Organization:    NewOrganizationService(repos),
+		ServiceCampaign: NewServiceCampaignService(repos),
 	}
 }
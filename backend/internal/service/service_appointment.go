@@ -16,10 +16,11 @@ import (
 
 type ServiceService struct {
 	repo *repository.Repository
+	docs *DocumentGenerator
 }
 
-func NewServiceService(repos *repository.Repository) *ServiceService {
-	return &ServiceService{repo: repos}
+func NewServiceService(repos *repository.Repository, docs *DocumentGenerator) *ServiceService {
+	return &ServiceService{repo: repos, docs: docs}
 }
 
 func (s *ServiceService) GetServiceTypes(ctx context.Context, category *string, isAvailable *bool) ([]model.ServiceType, error) {
@@ -41,7 +42,7 @@ func (s *ServiceService) CreateAppointment(ctx context.Context, userID uuid.UUID
 
 	create.ServiceTypeIDs = dedupeUUIDs(create.ServiceTypeIDs)
 
-	// Verify user car belongs to user
+	// Verify the user may book the car: its owner or a driver of the organization it is shared with
 	userCar, err := s.repo.UserCar.GetByID(ctx, create.UserCarID)
 	if err != nil {
 		if errors.Is(err, apperr.ErrNotFound) {
@@ -50,7 +51,11 @@ func (s *ServiceService) CreateAppointment(ctx context.Context, userID uuid.UUID
 		return nil, fmt.Errorf("failed to get user car: %w", err)
 	}
 
-	if userCar.UserID != userID {
+	canBook, err := canBookCar(ctx, s.repo, userCar.UserCarID, userCar.UserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canBook {
 		return nil, apperr.Forbidden("user car does not belong to user")
 	}
 
@@ -80,11 +85,15 @@ func (s *ServiceService) CreateAppointment(ctx context.Context, userID uuid.UUID
 	}
 
 	create.DurationMinutes = totalDurationMinutes(selectedTypes)
-	if err := validateAppointmentSlot(branch, create.AppointmentDate, create.DurationMinutes); err != nil {
+	if err := checkBranchSlot(ctx, s.repo, branch, create.AppointmentDate, create.DurationMinutes, true); err != nil {
+		return nil, err
+	}
+	plan, err := loadResourcePlan(ctx, s.repo, branch, selectedTypes)
+	if err != nil {
 		return nil, err
 	}
 
-	appointment, err := s.repo.ServiceAppointment.Create(ctx, create, branch.ConcurrentBays)
+	appointment, err := s.repo.ServiceAppointment.Create(ctx, create, plan.allocate)
 	if err != nil {
 		var apiErr *apperr.APIError
 		if errors.As(err, &apiErr) {
@@ -92,6 +101,8 @@ func (s *ServiceService) CreateAppointment(ctx context.Context, userID uuid.UUID
 		}
 		return nil, fmt.Errorf("failed to create appointment: %w", err)
 	}
+	autoAssign(ctx, s.repo, assignmentEntityAppointment, appointment.ServiceAppointmentID)
+	issueDocument(ctx, documentTypeServiceOrder, appointment.ServiceAppointmentID, s.docs.ServiceOrder)
 
 	// Get full details
 	appointmentWithDetails, err := s.repo.ServiceAppointment.GetByID(ctx, appointment.ServiceAppointmentID)
@@ -107,19 +118,38 @@ func (s *ServiceService) GetAppointment(ctx context.Context, appointmentID uuid.
 	if err != nil {
 		return nil, err
 	}
-	if !authz.IsOwnerOrHasPermission(a.OwnerUserID, requester, role, authz.PermAppointmentsViewAny) {
+	ok, err := canViewCar(ctx, s.repo, a.UserCarID, a.OwnerUserID, requester, role, authz.PermAppointmentsViewAny)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
 	}
 	return a, nil
 }
 
 func (s *ServiceService) GetUserAppointments(ctx context.Context, userID uuid.UUID) ([]model.ServiceAppointmentWithDetails, error) {
-	return s.repo.ServiceAppointment.GetByUserID(ctx, userID)
+	list, err := s.repo.ServiceAppointment.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillAppointmentUnread(ctx, s.repo, list, userID, false); err != nil {
+		return nil, err
+	}
+	return list, nil
 }
 
-// ListAllAppointmentsForStaff returns all appointments (caller must enforce permission).
-func (s *ServiceService) ListAllAppointmentsForStaff(ctx context.Context) ([]model.ServiceAppointmentWithDetails, error) {
-	return s.repo.ServiceAppointment.ListAllWithDetails(ctx)
+// ListAllAppointmentsForStaff returns the appointments matching the filters with the staff
+// member's unread message counts (caller must enforce permission).
+func (s *ServiceService) ListAllAppointmentsForStaff(ctx context.Context, staffID uuid.UUID, filters model.AppointmentFilters) ([]model.ServiceAppointmentWithDetails, error) {
+	list, err := s.repo.ServiceAppointment.Search(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillAppointmentUnread(ctx, s.repo, list, staffID, true); err != nil {
+		return nil, err
+	}
+	return list, nil
 }
 
 func (s *ServiceService) CancelAppointment(ctx context.Context, appointmentID uuid.UUID, requester uuid.UUID, role string) error {
@@ -127,14 +157,24 @@ func (s *ServiceService) CancelAppointment(ctx context.Context, appointmentID uu
 	if err != nil {
 		return err
 	}
-	if !authz.IsOwnerOrHasPermission(a.OwnerUserID, requester, role, authz.PermAppointmentsViewAny) {
-		return fmt.Errorf("%w", apperr.ErrForbidden)
+	if !authz.HasPermission(role, authz.PermAppointmentsViewAny) {
+		canBook, err := canBookCar(ctx, s.repo, a.UserCarID, a.OwnerUserID, requester)
+		if err != nil {
+			return err
+		}
+		if !canBook {
+			return fmt.Errorf("%w", apperr.ErrForbidden)
+		}
 	}
 	switch a.Status {
 	case "cancelled":
 		return apperr.BadRequest("appointment already cancelled")
 	case "completed":
 		return apperr.BadRequest("cannot cancel completed appointment")
+	case "in_progress":
+		return apperr.BadRequest("cannot cancel appointment in progress")
+	case "no_show":
+		return apperr.BadRequest("cannot cancel no-show appointment")
 	}
 	ok, err := s.repo.ServiceAppointment.UpdateStatusIfCurrent(ctx, appointmentID, "scheduled", "cancelled")
 	if err != nil {
@@ -146,7 +186,8 @@ func (s *ServiceService) CancelAppointment(ctx context.Context, appointmentID uu
 	return nil
 }
 
-// RescheduleAppointment updates appointment_date for the car owner; services and duration stay unchanged.
+// RescheduleAppointment updates appointment_date for the car owner or an organization driver; services
+// and duration stay unchanged.
 func (s *ServiceService) RescheduleAppointment(ctx context.Context, userID uuid.UUID, appointmentID uuid.UUID, newDate time.Time) (*model.ServiceAppointmentWithDetails, error) {
 	now := time.Now()
 	if err := validate.AppointmentDate(newDate, now); err != nil {
@@ -156,7 +197,11 @@ func (s *ServiceService) RescheduleAppointment(ctx context.Context, userID uuid.
 	if err != nil {
 		return nil, err
 	}
-	if a.OwnerUserID != userID {
+	canBook, err := canBookCar(ctx, s.repo, a.UserCarID, a.OwnerUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canBook {
 		return nil, fmt.Errorf("%w", apperr.ErrForbidden)
 	}
 	if a.Status != "scheduled" {
@@ -170,11 +215,15 @@ func (s *ServiceService) RescheduleAppointment(ctx context.Context, userID uuid.
 	if !branch.IsActive {
 		return nil, apperr.BadRequest("branch is not active")
 	}
-	if err := validateAppointmentSlot(branch, newDate, a.DurationMinutes); err != nil {
+	if err := checkBranchSlot(ctx, s.repo, branch, newDate, a.DurationMinutes, true); err != nil {
+		return nil, err
+	}
+	plan, err := loadResourcePlan(ctx, s.repo, branch, a.ServiceTypes)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := s.repo.ServiceAppointment.RescheduleOwned(ctx, appointmentID, userID, a.BranchID, newDate, a.DurationMinutes, branch.ConcurrentBays); err != nil {
+	if err := s.repo.ServiceAppointment.RescheduleOwned(ctx, appointmentID, a.OwnerUserID, a.BranchID, newDate, a.DurationMinutes, plan.allocate); err != nil {
 		return nil, err
 	}
 
@@ -186,7 +235,7 @@ func boolPtr(b bool) *bool {
 }
 
 // AdminCreateServiceType creates a catalog service offering.
-func (s *ServiceService) AdminCreateServiceType(ctx context.Context, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool) (*model.ServiceType, error) {
+func (s *ServiceService) AdminCreateServiceType(ctx context.Context, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool, baySkills, technicianSkills []string) (*model.ServiceType, error) {
 	var msg string
 	name, msg = validate.ServiceTypeName(name)
 	if msg != "" {
@@ -206,11 +255,17 @@ func (s *ServiceService) AdminCreateServiceType(ctx context.Context, name, categ
 	if msg := validate.ServiceDurationMinutes(durationMinutes); msg != "" {
 		return nil, apperr.BadRequest(msg)
 	}
-	return s.repo.ServiceType.Create(ctx, name, category, description, price, durationMinutes, isAvailable)
+	if baySkills, msg = validate.ResourceSkills(baySkills); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if technicianSkills, msg = validate.ResourceSkills(technicianSkills); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	return s.repo.ServiceType.Create(ctx, name, category, description, price, durationMinutes, isAvailable, baySkills, technicianSkills)
 }
 
 // AdminUpdateServiceType updates a service type.
-func (s *ServiceService) AdminUpdateServiceType(ctx context.Context, id uuid.UUID, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool) error {
+func (s *ServiceService) AdminUpdateServiceType(ctx context.Context, id uuid.UUID, name, category string, description *string, price float64, durationMinutes *int, isAvailable bool, baySkills, technicianSkills []string) error {
 	var msg string
 	name, msg = validate.ServiceTypeName(name)
 	if msg != "" {
@@ -230,7 +285,13 @@ func (s *ServiceService) AdminUpdateServiceType(ctx context.Context, id uuid.UUI
 	if msg := validate.ServiceDurationMinutes(durationMinutes); msg != "" {
 		return apperr.BadRequest(msg)
 	}
-	return s.repo.ServiceType.Update(ctx, id, name, category, description, price, durationMinutes, isAvailable)
+	if baySkills, msg = validate.ResourceSkills(baySkills); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if technicianSkills, msg = validate.ResourceSkills(technicianSkills); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	return s.repo.ServiceType.Update(ctx, id, name, category, description, price, durationMinutes, isAvailable, baySkills, technicianSkills)
 }
 
 // AdminDeleteServiceType removes a service type.
@@ -239,7 +300,7 @@ func (s *ServiceService) AdminDeleteServiceType(ctx context.Context, id uuid.UUI
 }
 
 // AdminUpdateBranch updates branch operational fields.
-func (s *ServiceService) AdminUpdateBranch(ctx context.Context, id uuid.UUID, name, address *string, phone, email *string, isActive *bool) error {
+func (s *ServiceService) AdminUpdateBranch(ctx context.Context, id uuid.UUID, name, address *string, phone, email *string, isActive *bool, handoverBays, handoverDuration *int) error {
 	if name != nil {
 		n, msg := validate.BranchName(*name)
 		if msg != "" {
@@ -262,5 +323,11 @@ func (s *ServiceService) AdminUpdateBranch(ctx context.Context, id uuid.UUID, na
 	if msg != "" {
 		return apperr.BadRequest(msg)
 	}
-	return s.repo.Branch.UpdateBranch(ctx, id, name, address, phone, email, isActive)
+	if msg := validate.HandoverBays(handoverBays); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.HandoverDurationMinutes(handoverDuration); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	return s.repo.Branch.UpdateBranch(ctx, id, name, address, phone, email, isActive, handoverBays, handoverDuration)
 }
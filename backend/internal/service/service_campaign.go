@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// Campaign notification texts are shown to customers as-is.
+const (
+	notificationKindServiceCampaign = "service_campaign"
+	campaignRecallTitle             = "Отзывная кампания по вашему автомобилю"
+	campaignServiceTitle            = "Сервисная кампания по вашему автомобилю"
+	campaignBody                    = "%s (VIN %s) попадает под кампанию «%s». Подробности — в карточке автомобиля; запишитесь на сервис, чтобы выполнить работы."
+)
+
+// ServiceCampaignService manages manufacturer recall and service campaigns: staff define the
+// affected vehicles, matching garage cars are added and their owners notified, and the work is
+// closed by a completed appointment.
+type ServiceCampaignService struct {
+	repo *repository.Repository
+}
+
+func NewServiceCampaignService(repos *repository.Repository) *ServiceCampaignService {
+	return &ServiceCampaignService{repo: repos}
+}
+
+// AdminList returns campaigns, optionally only active or closed ones.
+func (s *ServiceCampaignService) AdminList(ctx context.Context, isActive *bool) ([]model.ServiceCampaign, error) {
+	return s.repo.ServiceCampaign.List(ctx, isActive)
+}
+
+func (s *ServiceCampaignService) AdminGet(ctx context.Context, id uuid.UUID) (*model.ServiceCampaign, error) {
+	return s.repo.ServiceCampaign.Get(ctx, id)
+}
+
+// AdminCreate adds a campaign and, when it is active, matches garage cars and notifies their
+// owners right away.
+func (s *ServiceCampaignService) AdminCreate(ctx context.Context, staffID uuid.UUID, in model.ServiceCampaignInput) (*model.ServiceCampaign, error) {
+	if err := normalizeServiceCampaign(&in); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.ServiceCampaign.Create(ctx, in, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.match(ctx, &id); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceCampaign.Get(ctx, id)
+}
+
+// AdminUpdate replaces a campaign. Open cars that no longer meet the criteria are dropped and newly
+// matching cars are added and notified.
+func (s *ServiceCampaignService) AdminUpdate(ctx context.Context, id uuid.UUID, in model.ServiceCampaignInput) (*model.ServiceCampaign, error) {
+	if err := normalizeServiceCampaign(&in); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ServiceCampaign.Update(ctx, id, in); err != nil {
+		return nil, err
+	}
+	if _, err := s.match(ctx, &id); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceCampaign.Get(ctx, id)
+}
+
+func (s *ServiceCampaignService) AdminDelete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.ServiceCampaign.Delete(ctx, id)
+}
+
+// AdminMatch re-runs matching for one campaign and reports how many cars were added.
+func (s *ServiceCampaignService) AdminMatch(ctx context.Context, id uuid.UUID) (int, error) {
+	c, err := s.repo.ServiceCampaign.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if !c.IsActive {
+		return 0, apperr.BadRequest("the campaign is closed")
+	}
+	return s.match(ctx, &id)
+}
+
+// AdminVehicles lists the cars affected by a campaign (?status=open|done).
+func (s *ServiceCampaignService) AdminVehicles(ctx context.Context, id uuid.UUID, status string) ([]model.ServiceCampaignVehicle, error) {
+	if status != "" && status != model.CampaignVehicleOpen && status != model.CampaignVehicleDone {
+		return nil, apperr.BadRequest("status must be open or done")
+	}
+	if _, err := s.repo.ServiceCampaign.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceCampaign.ListVehicles(ctx, id, model.ServiceCampaignVehicleFilters{Status: status})
+}
+
+// AdminCompleteVehicle records campaign work done on a car by a completed appointment that did
+// not include the campaign services.
+func (s *ServiceCampaignService) AdminCompleteVehicle(ctx context.Context, campaignID, userCarID uuid.UUID, in model.ServiceCampaignCompletion) error {
+	if in.ServiceAppointmentID == uuid.Nil {
+		return apperr.BadRequest("service_appointment_id is required")
+	}
+	if err := s.repo.ServiceCampaign.CompleteVehicle(ctx, campaignID, userCarID, in.ServiceAppointmentID); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.NotFoundErr("The car is not affected by this campaign")
+		}
+		return err
+	}
+	return nil
+}
+
+// RunMatching adds newly registered cars to active campaigns and notifies their owners; cars
+// already on a campaign are skipped, so runs are idempotent.
+func (s *ServiceCampaignService) RunMatching(ctx context.Context) (int, error) {
+	return s.match(ctx, nil)
+}
+
+func (s *ServiceCampaignService) match(ctx context.Context, campaignID *uuid.UUID) (int, error) {
+	matches, err := s.repo.ServiceCampaign.Match(ctx, campaignID)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range matches {
+		s.notifyMatch(ctx, m)
+	}
+	return len(matches), nil
+}
+
+// notifyMatch tells the owner that the car is affected. Failures are logged; the car stays on the
+// campaign and is shown on the car detail.
+func (s *ServiceCampaignService) notifyMatch(ctx context.Context, m model.ServiceCampaignMatch) {
+	title, body := campaignNotice(m)
+	entityType := "user_car"
+	_, err := s.repo.Notification.Create(ctx, model.Notification{
+		UserID:     m.UserID,
+		Kind:       notificationKindServiceCampaign,
+		Title:      title,
+		Body:       body,
+		EntityType: &entityType,
+		EntityID:   &m.UserCarID,
+	})
+	if err != nil {
+		slog.Warn("campaign notification failed", "campaign", m.CampaignID, "user_car", m.UserCarID, "err", err)
+	}
+}
+
+func campaignNotice(m model.ServiceCampaignMatch) (string, string) {
+	title := campaignRecallTitle
+	if m.CampaignKind == model.ServiceCampaignService {
+		title = campaignServiceTitle
+	}
+	return title, fmt.Sprintf(campaignBody, m.CarTitle, m.VIN, m.CampaignTitle)
+}
+
+// normalizeServiceCampaign validates the campaign fields and criteria and de-duplicates the
+// service types.
+func normalizeServiceCampaign(in *model.ServiceCampaignInput) error {
+	var msg string
+	if in.Code, msg = validate.CampaignCode(in.Code); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.Kind, msg = validate.CampaignKind(in.Kind); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.Title, msg = validate.CampaignTitle(in.Title); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.Description, msg = validate.CampaignDescription(in.Description); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if msg := validate.CampaignYears(in.YearFrom, in.YearTo); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.VINPattern, msg = validate.CampaignVINPattern(in.VINPattern); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.VINFrom, in.VINTo, msg = validate.CampaignVINRange(in.VINFrom, in.VINTo); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.GenerationID == nil && in.TrimID == nil && in.YearFrom == nil && in.YearTo == nil &&
+		in.VINPattern == nil && in.VINFrom == nil {
+		return apperr.BadRequest("at least one of generation_id, trim_id, year_from, year_to, vin_pattern or vin_from is required")
+	}
+	seen := make(map[uuid.UUID]bool, len(in.ServiceTypeIDs))
+	ids := make([]uuid.UUID, 0, len(in.ServiceTypeIDs))
+	for _, id := range in.ServiceTypeIDs {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if msg := validate.CampaignServiceCount(len(ids)); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	in.ServiceTypeIDs = ids
+	return nil
+}
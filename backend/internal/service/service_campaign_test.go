@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func TestNormalizeServiceCampaign(t *testing.T) {
+	st := uuid.New()
+	pattern := " wdd213* "
+	in := model.ServiceCampaignInput{
+		Code:           " 24V-123 ",
+		Title:          "Замена подушки безопасности водителя",
+		VINPattern:     &pattern,
+		ServiceTypeIDs: []uuid.UUID{st, st, uuid.Nil},
+	}
+	if err := normalizeServiceCampaign(&in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Code != "24V-123" || in.Kind != model.ServiceCampaignRecall || *in.VINPattern != "WDD213*" {
+		t.Fatalf("unexpected normalization: %+v", in)
+	}
+	if len(in.ServiceTypeIDs) != 1 || in.ServiceTypeIDs[0] != st {
+		t.Fatalf("service types = %v", in.ServiceTypeIDs)
+	}
+
+	noCriteria := model.ServiceCampaignInput{Code: "X1", Title: "Проверка", ServiceTypeIDs: []uuid.UUID{st}}
+	if err := normalizeServiceCampaign(&noCriteria); err == nil {
+		t.Fatal("a campaign without criteria should fail")
+	}
+	year := 2020
+	noServices := model.ServiceCampaignInput{Code: "X1", Title: "Проверка", YearFrom: &year}
+	if err := normalizeServiceCampaign(&noServices); err == nil {
+		t.Fatal("a campaign without services should fail")
+	}
+}
+
+func TestCampaignNotice(t *testing.T) {
+	m := model.ServiceCampaignMatch{
+		CampaignKind:  model.ServiceCampaignService,
+		CampaignTitle: "Обновление ПО",
+		CarTitle:      "Mercedes-Benz E-Class",
+		VIN:           "WDD2130421A000100",
+	}
+	title, body := campaignNotice(m)
+	if title != campaignServiceTitle {
+		t.Fatalf("title = %q", title)
+	}
+	if !strings.Contains(body, "WDD2130421A000100") || !strings.Contains(body, "«Обновление ПО»") {
+		t.Fatalf("body = %q", body)
+	}
+	m.CampaignKind = model.ServiceCampaignRecall
+	if title, _ := campaignNotice(m); title != campaignRecallTitle {
+		t.Fatalf("recall title = %q", title)
+	}
+}
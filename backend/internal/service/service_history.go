@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/pdfgen"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/storage"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// ServiceHistoryService serves the per-VIN service book. Customers reach it through a car in their
+// garage, so only the current owner sees it; staff with garage access look it up by VIN.
+type ServiceHistoryService struct {
+	repo  *repository.Repository
+	store storage.FileStorage
+}
+
+func NewServiceHistoryService(repos *repository.Repository, store storage.FileStorage) *ServiceHistoryService {
+	return &ServiceHistoryService{repo: repos, store: store}
+}
+
+// CarVIN returns the VIN of a car the requester owns, shares through an organization or may view.
+func (s *ServiceHistoryService) CarVIN(ctx context.Context, userCarID, requester uuid.UUID, role string) (string, error) {
+	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return "", apperr.NotFoundErr("User car not found")
+		}
+		return "", err
+	}
+	ok, err := canViewCar(ctx, s.repo, car.UserCarID, car.UserID, requester, role, authz.PermGarageViewAny)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	return car.VIN, nil
+}
+
+// ServiceBook returns all completed visits recorded for a VIN.
+func (s *ServiceHistoryService) ServiceBook(ctx context.Context, vin string) (*model.ServiceBook, error) {
+	if msg := validate.VIN(vin); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	vin = validate.NormalizeVIN(vin)
+	records, err := s.repo.ServiceHistory.ListByVIN(ctx, vin)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ServiceBook{VIN: vin, Records: records}, nil
+}
+
+// ServiceBookPDF renders the digital service book of a VIN.
+func (s *ServiceHistoryService) ServiceBookPDF(ctx context.Context, vin string) ([]byte, string, error) {
+	book, err := s.ServiceBook(ctx, vin)
+	if err != nil {
+		return nil, "", err
+	}
+	pdf, err := pdfgen.DigitalServiceBook(serviceBookDocument(book, time.Now()))
+	if err != nil {
+		return nil, "", apperr.Internal(err)
+	}
+	return pdf, fmt.Sprintf("service_book_%s.pdf", book.VIN), nil
+}
+
+// OpenDocument opens a file kept in the service book of a VIN.
+func (s *ServiceHistoryService) OpenDocument(ctx context.Context, vin string, documentID uuid.UUID) (io.ReadCloser, *model.ServiceHistoryDocument, error) {
+	d, err := s.repo.ServiceHistory.GetDocument(ctx, validate.NormalizeVIN(vin), documentID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil, apperr.NotFoundErr("Document not found")
+		}
+		return nil, nil, err
+	}
+	rc, err := s.store.Open(ctx, d.FilePath)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, apperr.NotFoundErr("file is not available on the server")
+		}
+		return nil, nil, apperr.Internal(fmt.Errorf("open file: %w", err))
+	}
+	return rc, d, nil
+}
+
+// serviceBookDocument lays the records out for the PDF; the vehicle is described as of the latest
+// visit. Owners are deliberately left out: the book follows the car.
+func serviceBookDocument(book *model.ServiceBook, now time.Time) pdfgen.ServiceBook {
+	doc := pdfgen.ServiceBook{Date: now, Vehicle: pdfgen.Vehicle{VIN: book.VIN}}
+	for _, rec := range book.Records {
+		doc.Vehicle.Title = rec.VehicleTitle
+		doc.Vehicle.Year = rec.VehicleYear
+		entry := pdfgen.ServiceBookEntry{
+			Date:   rec.PerformedAt,
+			Branch: rec.BranchName,
+			Total:  rec.Total,
+			Notes:  derefString(rec.WorkSummary),
+		}
+		if rec.OdometerKm != nil {
+			entry.Mileage = *rec.OdometerKm
+		}
+		lines := make([]model.ServiceWorkLine, 0, len(rec.Lines))
+		for _, l := range rec.Lines {
+			lines = append(lines, model.ServiceWorkLine{
+				Kind: l.Kind, Description: l.Description, PartNumber: l.PartNumber, Quantity: l.Quantity, Amount: l.Amount,
+			})
+		}
+		entry.Works = workOrderLines(lines)
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestServiceBookDocument(t *testing.T) {
+	odo, summary, part := 15100, "Front axle", "04E115561H"
+	book := &model.ServiceBook{
+		VIN: "XTA21099012345678",
+		Records: []model.ServiceHistoryRecord{
+			{VehicleTitle: "Lada Vesta", VehicleYear: 2023, BranchName: "Центр", PerformedAt: time.Now(), Total: 100,
+				Lines: []model.ServiceHistoryLine{{Kind: model.WorkLineLabour, Description: "Diagnostics", Quantity: 1, Amount: 100}}},
+			{VehicleTitle: "Lada Vesta Sport", VehicleYear: 2023, BranchName: "Север", PerformedAt: time.Now(), OdometerKm: &odo,
+				WorkSummary: &summary, Total: 900,
+				Lines: []model.ServiceHistoryLine{{Kind: model.WorkLinePart, Description: "Oil filter", PartNumber: &part, Quantity: 2, Amount: 900}}},
+		},
+	}
+	doc := serviceBookDocument(book, time.Now())
+	if doc.Vehicle.VIN != book.VIN || doc.Vehicle.Title != "Lada Vesta Sport" {
+		t.Fatalf("vehicle = %+v, want the latest description", doc.Vehicle)
+	}
+	if len(doc.Entries) != 2 || doc.Entries[0].Mileage != 0 || doc.Entries[1].Mileage != odo || doc.Entries[1].Notes != summary {
+		t.Fatalf("entries = %+v", doc.Entries)
+	}
+	if got := doc.Entries[1].Works[0].Description; got != "Part: Oil filter (04E115561H) x 2" {
+		t.Fatalf("work line = %q", got)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// AdminListBranchResources returns all bays and technicians of a branch, inactive included.
+func (s *ServiceService) AdminListBranchResources(ctx context.Context, branchID uuid.UUID) ([]model.ServiceResource, error) {
+	if _, err := s.scheduleBranch(ctx, branchID); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceResource.ListByBranch(ctx, branchID, false)
+}
+
+// AdminCreateBranchResource adds a bay or a technician to a branch.
+func (s *ServiceService) AdminCreateBranchResource(ctx context.Context, branchID uuid.UUID, in model.ServiceResourceInput) (*model.ServiceResource, error) {
+	if _, err := s.scheduleBranch(ctx, branchID); err != nil {
+		return nil, err
+	}
+	kind, msg := validate.ServiceResourceKind(in.Kind)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	in.Kind = kind
+	if err := s.normalizeResource(ctx, &in); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceResource.Create(ctx, branchID, in)
+}
+
+// AdminUpdateResource replaces a resource's name, staff account, skills and active flag; the kind
+// cannot change. Existing bookings keep their assignment.
+func (s *ServiceService) AdminUpdateResource(ctx context.Context, id uuid.UUID, in model.ServiceResourceInput) (*model.ServiceResource, error) {
+	cur, err := s.repo.ServiceResource.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Resource not found")
+		}
+		return nil, err
+	}
+	if in.Kind != "" && in.Kind != cur.Kind {
+		return nil, apperr.BadRequest("resource kind cannot be changed")
+	}
+	in.Kind = cur.Kind
+	if err := s.normalizeResource(ctx, &in); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceResource.Update(ctx, id, in)
+}
+
+// normalizeResource validates the name and skills; only technicians may link a staff account.
+func (s *ServiceService) normalizeResource(ctx context.Context, in *model.ServiceResourceInput) error {
+	var msg string
+	if in.Name, msg = validate.ServiceResourceName(in.Name); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.Skills, msg = validate.ResourceSkills(in.Skills); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.UserID == nil {
+		return nil
+	}
+	if in.Kind != model.ResourceKindTechnician {
+		return apperr.BadRequest("only technicians can be linked to a user")
+	}
+	u, err := s.repo.User.GetByID(ctx, *in.UserID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.BadRequest("user not found")
+		}
+		return err
+	}
+	if !authz.IsStaff(u.Role) {
+		return apperr.BadRequest("technician must be a staff user")
+	}
+	return nil
+}
+
+// WorkshopSchedule lays out the branch-local day's appointments by bay and technician; bookings
+// without an assignment are listed separately.
+func (s *ServiceService) WorkshopSchedule(ctx context.Context, branchID uuid.UUID, dateStr string) (*model.WorkshopSchedule, error) {
+	branch, err := s.scheduleBranch(ctx, branchID)
+	if err != nil {
+		return nil, err
+	}
+	loc := loadBranchLocation(branch.Timezone)
+	day, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return nil, apperr.BadRequest("invalid date format")
+	}
+	next := day.AddDate(0, 0, 1)
+	appointments, err := s.repo.ServiceAppointment.Search(ctx, model.AppointmentFilters{
+		BranchID: &branchID,
+		Status:   []string{"scheduled", "in_progress", "completed"},
+		From:     &day,
+		To:       &next,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.repo.ServiceResource.ListByBranch(ctx, branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	return buildWorkshopSchedule(branch, dateStr, resources, appointments), nil
+}
+
+// buildWorkshopSchedule groups appointments into resource lanes in start order; inactive resources
+// are shown only while they still hold bookings.
+func buildWorkshopSchedule(branch *model.Branch, date string, resources []model.ServiceResource, appointments []model.ServiceAppointmentWithDetails) *model.WorkshopSchedule {
+	sort.SliceStable(appointments, func(i, j int) bool {
+		return appointments[i].AppointmentDate.Before(appointments[j].AppointmentDate)
+	})
+	byResource := make(map[uuid.UUID][]model.ServiceAppointmentWithDetails)
+	out := &model.WorkshopSchedule{
+		BranchID:    branch.BranchID,
+		Date:        date,
+		Timezone:    branch.Timezone,
+		Bays:        []model.WorkshopLane{},
+		Technicians: []model.WorkshopLane{},
+		Unassigned:  []model.ServiceAppointmentWithDetails{},
+	}
+	for _, a := range appointments {
+		if a.BayID == nil && a.TechnicianID == nil {
+			out.Unassigned = append(out.Unassigned, a)
+			continue
+		}
+		if a.BayID != nil {
+			byResource[*a.BayID] = append(byResource[*a.BayID], a)
+		}
+		if a.TechnicianID != nil {
+			byResource[*a.TechnicianID] = append(byResource[*a.TechnicianID], a)
+		}
+	}
+	for _, r := range resources {
+		list := byResource[r.ResourceID]
+		if !r.IsActive && len(list) == 0 {
+			continue
+		}
+		if list == nil {
+			list = []model.ServiceAppointmentWithDetails{}
+		}
+		lane := model.WorkshopLane{Resource: r, Appointments: list}
+		if r.Kind == model.ResourceKindBay {
+			out.Bays = append(out.Bays, lane)
+		} else {
+			out.Technicians = append(out.Technicians, lane)
+		}
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+// CheckInAppointment receives the car: a scheduled appointment goes in progress, optionally with
+// the odometer reading at check-in.
+func (s *ServiceService) CheckInAppointment(ctx context.Context, appointmentID uuid.UUID, odometerKm *int) (*model.ServiceAppointmentWithDetails, error) {
+	a, err := s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != "scheduled" {
+		return nil, apperr.BadRequest("only scheduled appointments can be checked in")
+	}
+	if err := s.checkOdometer(ctx, a, odometerKm); err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.ServiceAppointment.CheckIn(ctx, appointmentID, odometerKm)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperr.Conflict("appointment status changed, refresh and retry")
+	}
+	return s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+}
+
+// UpdateWorkOrder records the odometer reading, the work performed and the parts and labour lines
+// of an in-progress appointment.
+func (s *ServiceService) UpdateWorkOrder(ctx context.Context, appointmentID, staffID uuid.UUID, in model.ServiceWorkOrderUpdate) (*model.ServiceAppointmentWithDetails, error) {
+	a, err := s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != "in_progress" {
+		return nil, apperr.BadRequest("work can only be recorded on an appointment in progress")
+	}
+	if err := s.checkOdometer(ctx, a, in.OdometerKm); err != nil {
+		return nil, err
+	}
+	var msg string
+	if in.WorkSummary, msg = validate.WorkSummary(in.WorkSummary); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if in.Lines, msg = normalizeWorkLines(in.Lines); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if err := s.repo.ServiceAppointment.UpdateWorkOrder(ctx, appointmentID, staffID, in); err != nil {
+		return nil, err
+	}
+	return s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+}
+
+// CompleteAppointment closes an in-progress appointment, updates the car's mileage from the work
+// order and issues the service act.
+func (s *ServiceService) CompleteAppointment(ctx context.Context, appointmentID uuid.UUID, odometerKm *int) (*model.ServiceAppointmentWithDetails, error) {
+	a, err := s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != "in_progress" {
+		return nil, apperr.BadRequest("only appointments in progress can be completed")
+	}
+	if err := s.checkOdometer(ctx, a, odometerKm); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ServiceAppointment.Complete(ctx, appointmentID, odometerKm); err != nil {
+		return nil, err
+	}
+	issueDocument(ctx, documentTypeServiceAct, appointmentID, s.docs.ServiceAct)
+	return s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+}
+
+// MarkAppointmentNoShow records that the customer did not arrive; allowed once the slot has started.
+func (s *ServiceService) MarkAppointmentNoShow(ctx context.Context, appointmentID uuid.UUID) (*model.ServiceAppointmentWithDetails, error) {
+	a, err := s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != "scheduled" {
+		return nil, apperr.BadRequest("only scheduled appointments can be marked as no-show")
+	}
+	if time.Now().Before(a.AppointmentDate) {
+		return nil, apperr.BadRequest("appointment has not started yet")
+	}
+	ok, err := s.repo.ServiceAppointment.UpdateStatusIfCurrent(ctx, appointmentID, "scheduled", "no_show")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperr.Conflict("appointment status changed, refresh and retry")
+	}
+	return s.repo.ServiceAppointment.GetByID(ctx, appointmentID)
+}
+
+// checkOdometer validates an optional reading against the car's recorded mileage.
+func (s *ServiceService) checkOdometer(ctx context.Context, a *model.ServiceAppointmentWithDetails, km *int) error {
+	if km == nil {
+		return nil
+	}
+	car, err := s.repo.UserCar.GetByID(ctx, a.UserCarID)
+	if err != nil {
+		return err
+	}
+	if msg := validate.Odometer(*km, car.CurrentMileage); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	return nil
+}
+
+// normalizeWorkLines validates and trims work order lines; nil (lines left unchanged) stays nil.
+func normalizeWorkLines(lines []model.ServiceWorkLineInput) ([]model.ServiceWorkLineInput, string) {
+	if lines == nil {
+		return nil, ""
+	}
+	if len(lines) > validate.WorkLinesMax {
+		return nil, fmt.Sprintf("too many work lines (max %d)", validate.WorkLinesMax)
+	}
+	out := make([]model.ServiceWorkLineInput, 0, len(lines))
+	for _, l := range lines {
+		var msg string
+		if msg = validate.WorkLineKind(l.Kind); msg != "" {
+			return nil, msg
+		}
+		if l.Description, msg = validate.WorkLineDescription(l.Description); msg != "" {
+			return nil, msg
+		}
+		if l.PartNumber, msg = validate.WorkLinePartNumber(l.PartNumber); msg != "" {
+			return nil, msg
+		}
+		if l.Kind == model.WorkLineLabour && l.PartNumber != nil {
+			return nil, "part_number is only allowed on part lines"
+		}
+		if l.Quantity, msg = validate.WorkLineQuantity(l.Quantity); msg != "" {
+			return nil, msg
+		}
+		if l.UnitPrice, msg = validate.WorkLineUnitPrice(l.UnitPrice); msg != "" {
+			return nil, msg
+		}
+		out = append(out, l)
+	}
+	return out, ""
+}
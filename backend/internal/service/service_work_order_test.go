@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestNormalizeWorkLines(t *testing.T) {
+	if got, msg := normalizeWorkLines(nil); got != nil || msg != "" {
+		t.Fatalf("nil keeps lines: %v %q", got, msg)
+	}
+	if got, msg := normalizeWorkLines([]model.ServiceWorkLineInput{}); got == nil || len(got) != 0 || msg != "" {
+		t.Fatalf("empty clears lines: %v %q", got, msg)
+	}
+
+	pn := " 04152 "
+	got, msg := normalizeWorkLines([]model.ServiceWorkLineInput{
+		{Kind: "part", Description: " Oil filter ", PartNumber: &pn, Quantity: 1, UnitPrice: 600.004},
+	})
+	if msg != "" {
+		t.Fatal(msg)
+	}
+	if got[0].Description != "Oil filter" || *got[0].PartNumber != "04152" || got[0].UnitPrice != 600 {
+		t.Fatalf("not normalized: %+v", got[0])
+	}
+
+	bad := []model.ServiceWorkLineInput{
+		{Kind: "labour", Description: "Oil change", PartNumber: &pn, Quantity: 1},
+		{Kind: "tyres", Description: "Oil change", Quantity: 1},
+		{Kind: "labour", Description: " ", Quantity: 1},
+		{Kind: "labour", Description: "Oil change", Quantity: 0},
+		{Kind: "labour", Description: "Oil change", Quantity: 1, UnitPrice: -5},
+	}
+	for _, l := range bad {
+		if _, msg := normalizeWorkLines([]model.ServiceWorkLineInput{l}); msg == "" {
+			t.Errorf("%+v should fail", l)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	bookingSourceStaff  = "staff"
+	bookingSourceWalkIn = "walk_in"
+)
+
+// StaffCreateAppointment books a visit on behalf of a customer (call centre or front desk). The
+// slot is checked against the branch calendar and resources like an online booking, but staff may
+// start off the slot grid; a walk-in starts now and goes straight to check-in.
+func (s *ServiceService) StaffCreateAppointment(ctx context.Context, staffID uuid.UUID, in model.StaffAppointmentCreate) (*model.ServiceAppointmentWithDetails, error) {
+	now := time.Now()
+	if err := validate.AppointmentDescription(in.Description); err != nil {
+		return nil, apperr.BadRequest(err.Error())
+	}
+	start, msg := staffBookingStart(in, now)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if !in.WalkIn && in.OdometerKm != nil {
+		return nil, apperr.BadRequest("odometer_km is recorded at check-in")
+	}
+
+	branch, types, err := s.availabilityTarget(ctx, in.BranchID, in.ServiceTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+	duration := totalDurationMinutes(types)
+	if err := checkBranchSlot(ctx, s.repo, branch, start, duration, !in.OffGrid && !in.WalkIn); err != nil {
+		return nil, err
+	}
+	plan, err := loadResourcePlan(ctx, s.repo, branch, types)
+	if err != nil {
+		return nil, err
+	}
+	// Check capacity before a new customer or car is registered; Create re-checks under the lock.
+	end := start.Add(time.Duration(duration) * time.Minute)
+	busy, err := s.repo.ServiceAppointment.ScheduledIntervals(ctx, branch.BranchID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.fits(busy)(start, end) {
+		return nil, apperr.Conflict("This time slot is no longer available")
+	}
+
+	car, err := s.staffBookingCar(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if in.OdometerKm != nil {
+		if msg := validate.Odometer(*in.OdometerKm, car.CurrentMileage); msg != "" {
+			return nil, apperr.BadRequest(msg)
+		}
+	}
+
+	create := model.ServiceAppointmentCreate{
+		UserCarID:       car.UserCarID,
+		BranchID:        branch.BranchID,
+		ServiceTypeIDs:  dedupeUUIDs(in.ServiceTypeIDs),
+		AppointmentDate: start,
+		Description:     in.Description,
+		DurationMinutes: duration,
+		Source:          bookingSourceStaff,
+		CreatedBy:       &staffID,
+		CheckIn:         in.WalkIn,
+		OdometerKm:      in.OdometerKm,
+	}
+	if in.WalkIn {
+		create.Source = bookingSourceWalkIn
+	}
+	appointment, err := s.repo.ServiceAppointment.Create(ctx, create, plan.allocate)
+	if err != nil {
+		var apiErr *apperr.APIError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create appointment: %w", err)
+	}
+	autoAssign(ctx, s.repo, assignmentEntityAppointment, appointment.ServiceAppointmentID)
+	issueDocument(ctx, documentTypeServiceOrder, appointment.ServiceAppointmentID, s.docs.ServiceOrder)
+
+	return s.repo.ServiceAppointment.GetByID(ctx, appointment.ServiceAppointmentID)
+}
+
+// staffBookingStart returns the visit start: now (to the minute) for a walk-in, otherwise the
+// requested future time.
+func staffBookingStart(in model.StaffAppointmentCreate, now time.Time) (time.Time, string) {
+	if in.WalkIn {
+		if in.AppointmentDate != nil {
+			return time.Time{}, "a walk-in starts now; omit appointment_date"
+		}
+		return now.Truncate(time.Minute), ""
+	}
+	if in.AppointmentDate == nil {
+		return time.Time{}, "appointment_date is required"
+	}
+	if err := validate.AppointmentDate(*in.AppointmentDate, now); err != nil {
+		return time.Time{}, err.Error()
+	}
+	return *in.AppointmentDate, ""
+}
+
+// staffBookingCar returns the selected car, or resolves the customer and adds the new car to
+// their garage.
+func (s *ServiceService) staffBookingCar(ctx context.Context, in model.StaffAppointmentCreate) (*model.UserCarWithDetails, error) {
+	if (in.UserCarID == nil) == (in.NewCar == nil) {
+		return nil, apperr.BadRequest("provide exactly one of user_car_id or new_car")
+	}
+	if in.UserCarID != nil {
+		car, err := s.repo.UserCar.GetByID(ctx, *in.UserCarID)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return nil, apperr.NotFoundErr("User car not found")
+			}
+			return nil, err
+		}
+		if in.Customer != nil && in.Customer.UserID != nil && *in.Customer.UserID != car.UserID {
+			return nil, apperr.BadRequest("car does not belong to the customer")
+		}
+		return car, nil
+	}
+	if in.Customer == nil {
+		return nil, apperr.BadRequest("customer is required for a new car")
+	}
+	customerID, err := s.staffBookingCustomer(ctx, *in.Customer)
+	if err != nil {
+		return nil, err
+	}
+	return createUserCar(ctx, s.repo, customerID, *in.NewCar)
+}
+
+// staffBookingCustomer finds the customer by ID or email, creating an account from the contact
+// details when the email is unknown. The account gets a random password the customer never sees.
+func (s *ServiceService) staffBookingCustomer(ctx context.Context, c model.StaffBookingCustomer) (uuid.UUID, error) {
+	if c.UserID != nil {
+		u, err := s.repo.User.GetByID(ctx, *c.UserID)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return uuid.Nil, apperr.NotFoundErr("Customer not found")
+			}
+			return uuid.Nil, err
+		}
+		return u.UserID, nil
+	}
+	email, msg := validate.Email(c.Email)
+	if msg != "" {
+		return uuid.Nil, apperr.BadRequest(msg)
+	}
+	exists, err := s.repo.User.EmailExists(ctx, email)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if exists {
+		u, err := s.repo.User.GetByEmail(ctx, email)
+		if err != nil {
+			return uuid.Nil, apperr.Internal(err)
+		}
+		return u.UserID, nil
+	}
+
+	firstName, lastName, msg := validate.Names(c.FirstName, c.LastName)
+	if msg != "" {
+		return uuid.Nil, apperr.BadRequest(msg)
+	}
+	phone, msg := validate.PhonePtr(c.Phone)
+	if msg != "" {
+		return uuid.Nil, apperr.BadRequest(msg)
+	}
+	password, err := randomPassword()
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	u, err := s.repo.User.Create(ctx, model.UserCreate{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     email,
+		Phone:     phone,
+		Password:  password,
+		Role:      "customer",
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return u.UserID, nil
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carkeeper/backend/internal/model"
+)
+
+func TestStaffBookingStart(t *testing.T) {
+	now := time.Date(2026, 5, 18, 9, 7, 42, 0, time.UTC)
+	got, msg := staffBookingStart(model.StaffAppointmentCreate{WalkIn: true}, now)
+	if msg != "" || !got.Equal(time.Date(2026, 5, 18, 9, 7, 0, 0, time.UTC)) {
+		t.Fatalf("walk-in starts now: %v %q", got, msg)
+	}
+	later := now.Add(3 * time.Hour)
+	if _, msg := staffBookingStart(model.StaffAppointmentCreate{WalkIn: true, AppointmentDate: &later}, now); msg == "" {
+		t.Fatal("walk-in with a date should fail")
+	}
+	if _, msg := staffBookingStart(model.StaffAppointmentCreate{}, now); msg == "" {
+		t.Fatal("booking without a date should fail")
+	}
+	past := now.Add(-time.Hour)
+	if _, msg := staffBookingStart(model.StaffAppointmentCreate{AppointmentDate: &past}, now); msg == "" {
+		t.Fatal("booking in the past should fail")
+	}
+	if got, msg := staffBookingStart(model.StaffAppointmentCreate{AppointmentDate: &later}, now); msg != "" || !got.Equal(later) {
+		t.Fatalf("future booking: %v %q", got, msg)
+	}
+}
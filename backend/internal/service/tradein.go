@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/authz"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	tradeInDefaultValidDays = 14
+	tradeInMaxValidDays     = 90
+	// Mileage deviation is priced per 10 000 km and capped so a single factor cannot dominate.
+	tradeInMileageStepKm   = 10_000
+	tradeInMileagePenalty  = 0.5
+	tradeInMileageBonusMax = 0.1
+)
+
+type TradeInService struct {
+	repo *repository.Repository
+}
+
+func NewTradeInService(repos *repository.Repository) *TradeInService {
+	return &TradeInService{repo: repos}
+}
+
+func (s *TradeInService) ListRules(ctx context.Context) ([]model.DepreciationRule, error) {
+	return s.repo.TradeIn.ListRules(ctx, false)
+}
+
+func (s *TradeInService) CreateRule(ctx context.Context, in model.DepreciationRuleInput) (*model.DepreciationRule, error) {
+	if err := normalizeDepreciationRuleInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.TradeIn.CreateRule(ctx, in)
+}
+
+func (s *TradeInService) UpdateRule(ctx context.Context, id uuid.UUID, in model.DepreciationRuleInput) (*model.DepreciationRule, error) {
+	if err := normalizeDepreciationRuleInput(&in); err != nil {
+		return nil, err
+	}
+	return s.repo.TradeIn.UpdateRule(ctx, id, in)
+}
+
+func (s *TradeInService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return s.repo.TradeIn.DeleteRule(ctx, id)
+}
+
+// Estimate values a garage car without issuing an offer.
+func (s *TradeInService) Estimate(ctx context.Context, in model.TradeInAppraisalCreate) (*model.TradeInValuation, error) {
+	_, v, err := s.appraise(ctx, in, time.Now())
+	return v, err
+}
+
+// CreateOffer values a garage car and issues the offer to its owner.
+func (s *TradeInService) CreateOffer(ctx context.Context, staffID uuid.UUID, in model.TradeInAppraisalCreate) (*model.TradeInOfferWithDetails, error) {
+	now := time.Now()
+	car, v, err := s.appraise(ctx, in, now)
+	if err != nil {
+		return nil, err
+	}
+	notes, msg := validate.TradeInNotes(in.Notes)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	days := in.ValidDays
+	if days == 0 {
+		days = tradeInDefaultValidDays
+	}
+	if days < 0 || days > tradeInMaxValidDays {
+		return nil, apperr.BadRequest("valid_days must be between 1 and 90")
+	}
+	return s.repo.TradeIn.CreateOffer(ctx, car, staffID, *v, notes, now.AddDate(0, 0, days))
+}
+
+func (s *TradeInService) ListOffers(ctx context.Context) ([]model.TradeInOfferWithDetails, error) {
+	return s.repo.TradeIn.ListOffers(ctx)
+}
+
+func (s *TradeInService) ListUserOffers(ctx context.Context, userID uuid.UUID) ([]model.TradeInOfferWithDetails, error) {
+	return s.repo.TradeIn.ListOffersByUser(ctx, userID)
+}
+
+// DecideOffer lets the car owner accept or reject an open offer.
+func (s *TradeInService) DecideOffer(ctx context.Context, offerID, userID uuid.UUID, accept bool) (*model.TradeInOfferWithDetails, error) {
+	offer, err := s.repo.TradeIn.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.UserID != userID {
+		return nil, fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	status := "rejected"
+	if accept {
+		status = "accepted"
+	}
+	if err := s.repo.TradeIn.DecideOffer(ctx, offerID, userID, status); err != nil {
+		return nil, err
+	}
+	return s.repo.TradeIn.GetOffer(ctx, offerID)
+}
+
+func (s *TradeInService) CancelOffer(ctx context.Context, offerID uuid.UUID) error {
+	return s.repo.TradeIn.CancelOffer(ctx, offerID)
+}
+
+// AttachToOrder applies an accepted offer to an order that has not been paid yet.
+func (s *TradeInService) AttachToOrder(ctx context.Context, orderID, requester uuid.UUID, role string, in model.TradeInAttach) (*model.OrderWithDetails, error) {
+	if err := s.checkOrderEditable(ctx, orderID, requester, role); err != nil {
+		return nil, err
+	}
+	if err := s.repo.TradeIn.AttachToOrder(ctx, orderID, in.TradeInOfferID); err != nil {
+		return nil, err
+	}
+	return s.repo.Order.GetByID(ctx, orderID)
+}
+
+func (s *TradeInService) DetachFromOrder(ctx context.Context, orderID, requester uuid.UUID, role string) (*model.OrderWithDetails, error) {
+	if err := s.checkOrderEditable(ctx, orderID, requester, role); err != nil {
+		return nil, err
+	}
+	if err := s.repo.TradeIn.DetachFromOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.repo.Order.GetByID(ctx, orderID)
+}
+
+func (s *TradeInService) checkOrderEditable(ctx context.Context, orderID, requester uuid.UUID, role string) error {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if !authz.IsOwnerOrHasPermission(order.UserID, requester, role, authz.PermOrdersManageStatus) {
+		return fmt.Errorf("%w", apperr.ErrNotFound)
+	}
+	if order.Status != "pending" && order.Status != "approved" {
+		return apperr.BadRequest("Trade-in can only be changed before the order is paid")
+	}
+	return nil
+}
+
+func (s *TradeInService) appraise(ctx context.Context, in model.TradeInAppraisalCreate, now time.Time) (*model.UserCarWithDetails, *model.TradeInValuation, error) {
+	if len(in.Adjustments) > validate.TradeInAdjustmentsMax {
+		return nil, nil, apperr.BadRequest("too many adjustments (max 20)")
+	}
+	adjustments := make([]model.TradeInAdjustment, 0, len(in.Adjustments))
+	for _, a := range in.Adjustments {
+		label, msg := validate.TradeInAdjustment(a.Label, a.Amount)
+		if msg != "" {
+			return nil, nil, apperr.BadRequest(msg)
+		}
+		adjustments = append(adjustments, model.TradeInAdjustment{Label: label, Amount: roundMoney(a.Amount)})
+	}
+	car, err := s.repo.UserCar.GetByID(ctx, in.UserCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, nil, apperr.NotFoundErr("Car not found")
+		}
+		return nil, nil, err
+	}
+	scope, err := s.repo.Trim.GetPricingScope(ctx, car.TrimID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := s.repo.TradeIn.ListRules(ctx, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	rule := selectDepreciationRule(rules, scope.BrandID, scope.Segment)
+	if rule == nil {
+		return nil, nil, apperr.BadRequest("No depreciation rule matches this car")
+	}
+	age := now.Year() - car.Year
+	if age < 0 {
+		age = 0
+	}
+	v := valueTradeIn(*rule, scope.BasePrice, age, car.CurrentMileage, adjustments)
+	return car, &v, nil
+}
+
+// selectDepreciationRule picks the most specific active rule: brand and segment, then brand,
+// then segment, then the catch-all rule.
+func selectDepreciationRule(rules []model.DepreciationRule, brandID uuid.UUID, segment *string) *model.DepreciationRule {
+	var best *model.DepreciationRule
+	bestScore := -1
+	for i := range rules {
+		rule := &rules[i]
+		score := 0
+		if rule.BrandID != nil {
+			if *rule.BrandID != brandID {
+				continue
+			}
+			score += 2
+		}
+		if rule.VehicleSegment != nil {
+			if segment == nil || !strings.EqualFold(*rule.VehicleSegment, *segment) {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
+
+// valueTradeIn applies first-year and annual depreciation to the current new-car price, then a
+// mileage correction against the expected mileage for the car's age, floored at the residual value.
+func valueTradeIn(rule model.DepreciationRule, referencePrice float64, ageYears, mileage int, adjustments []model.TradeInAdjustment) model.TradeInValuation {
+	years := ageYears
+	if years < 1 {
+		years = 1
+	}
+	residual := (1 - rule.FirstYearDepreciationPercent/100) * math.Pow(1-rule.AnnualDepreciationPercent/100, float64(years-1))
+
+	expected := rule.ExpectedAnnualMileage * years
+	deviation := float64(mileage-expected) / tradeInMileageStepKm
+	mileageFactor := -deviation * rule.MileageAdjustmentPercent / 100
+	mileageFactor = math.Max(-tradeInMileagePenalty, math.Min(tradeInMileageBonusMax, mileageFactor))
+
+	value := referencePrice * residual * (1 + mileageFactor)
+	floor := referencePrice * rule.MinResidualPercent / 100
+	if value < floor {
+		value = floor
+	}
+
+	ruleID := rule.RuleID
+	out := model.TradeInValuation{
+		RuleID:         &ruleID,
+		RuleName:       rule.Name,
+		ReferencePrice: roundMoney(referencePrice),
+		AgeYears:       ageYears,
+		Mileage:        mileage,
+		ModelValue:     roundMoney(value),
+		Adjustments:    adjustments,
+	}
+	offer := out.ModelValue
+	for _, a := range adjustments {
+		offer += a.Amount
+	}
+	out.OfferAmount = roundMoney(math.Max(0, offer))
+	return out
+}
+
+func normalizeDepreciationRuleInput(in *model.DepreciationRuleInput) error {
+	name, msg := validate.DepreciationRuleName(in.Name)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	segment, msg := validate.DepreciationRuleSegment(in.VehicleSegment)
+	if msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	if in.ExpectedAnnualMileage == 0 {
+		in.ExpectedAnnualMileage = 15000
+	}
+	if msg := validate.DepreciationRuleRates(in.FirstYearDepreciationPercent, in.AnnualDepreciationPercent, in.MileageAdjustmentPercent, in.MinResidualPercent, in.ExpectedAnnualMileage); msg != "" {
+		return apperr.BadRequest(msg)
+	}
+	in.Name = name
+	in.VehicleSegment = segment
+	return nil
+}
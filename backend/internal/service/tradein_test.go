@@ -0,0 +1,79 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+func testDepreciationRule() model.DepreciationRule {
+	return model.DepreciationRule{
+		RuleID:                       uuid.New(),
+		Name:                         "default",
+		FirstYearDepreciationPercent: 20,
+		AnnualDepreciationPercent:    10,
+		ExpectedAnnualMileage:        15000,
+		MileageAdjustmentPercent:     2,
+		MinResidualPercent:           10,
+		IsActive:                     true,
+	}
+}
+
+func TestValueTradeIn_AgeDepreciation(t *testing.T) {
+	rule := testDepreciationRule()
+	// 3 years, exactly expected mileage: 1 000 000 * 0.8 * 0.9^2
+	v := valueTradeIn(rule, 1_000_000, 3, 45000, nil)
+	if math.Abs(v.ModelValue-648_000) > 0.01 || v.OfferAmount != v.ModelValue {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestValueTradeIn_MileageAndAdjustments(t *testing.T) {
+	rule := testDepreciationRule()
+	// 20 000 km over expected => -4%.
+	high := valueTradeIn(rule, 1_000_000, 1, 35000, nil)
+	if math.Abs(high.ModelValue-768_000) > 0.01 {
+		t.Fatalf("high mileage %+v", high)
+	}
+	adj := []model.TradeInAdjustment{{Label: "scratches", Amount: -18_000}}
+	withAdj := valueTradeIn(rule, 1_000_000, 1, 35000, adj)
+	if withAdj.OfferAmount != 750_000 {
+		t.Fatalf("adjusted %+v", withAdj)
+	}
+}
+
+func TestValueTradeIn_ResidualFloor(t *testing.T) {
+	rule := testDepreciationRule()
+	v := valueTradeIn(rule, 1_000_000, 40, 900_000, nil)
+	if v.ModelValue != 100_000 {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestSelectDepreciationRule_MostSpecific(t *testing.T) {
+	brand := uuid.New()
+	segment := "SUV"
+	generic := testDepreciationRule()
+	bySegment := testDepreciationRule()
+	bySegment.VehicleSegment = &segment
+	byBrand := testDepreciationRule()
+	byBrand.BrandID = &brand
+	otherBrand := uuid.New()
+	foreign := testDepreciationRule()
+	foreign.BrandID = &otherBrand
+	foreign.VehicleSegment = &segment
+
+	rules := []model.DepreciationRule{generic, bySegment, byBrand, foreign}
+	if got := selectDepreciationRule(rules, brand, &segment); got == nil || got.RuleID != byBrand.RuleID {
+		t.Fatalf("expected brand rule, got %+v", got)
+	}
+	sedan := "sedan"
+	if got := selectDepreciationRule(rules[:2], uuid.New(), &sedan); got == nil || got.RuleID != generic.RuleID {
+		t.Fatalf("expected generic rule, got %+v", got)
+	}
+	if got := selectDepreciationRule([]model.DepreciationRule{foreign}, brand, &segment); got != nil {
+		t.Fatal("no rule should match")
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/carkeeper/backend/internal/apperr"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/repository"
+	"github.com/carkeeper/backend/internal/validate"
+	"github.com/google/uuid"
+)
+
+const (
+	transferCodeDays          = 7
+	transferMaxFailedAttempts = 5
+
+	notificationKindTransferSent     = "car_transfer_completed"
+	notificationKindTransferReceived = "car_transfer_received"
+	transferSentTitle                = "Автомобиль передан"
+	transferSentBody                 = "%s передан новому владельцу."
+	transferReceivedTitle            = "Автомобиль в вашем гараже"
+	transferReceivedBody             = "%s добавлен в ваш гараж вместе с сервисной историей."
+)
+
+// VehicleTransferService moves a garage car to another customer: the seller starts a transfer and
+// passes the one-time code to the buyer, who confirms it with the VIN. Staff may force a transfer.
+type VehicleTransferService struct {
+	repo *repository.Repository
+}
+
+func NewVehicleTransferService(repos *repository.Repository) *VehicleTransferService {
+	return &VehicleTransferService{repo: repos}
+}
+
+// ownedCar returns the requester's car; other customers' cars are reported as not found.
+func (s *VehicleTransferService) ownedCar(ctx context.Context, userCarID, requester uuid.UUID) (*model.UserCarWithDetails, error) {
+	car, err := s.repo.UserCar.GetByID(ctx, userCarID)
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("User car not found")
+		}
+		return nil, err
+	}
+	if car.UserID != requester {
+		return nil, apperr.NotFoundErr("User car not found")
+	}
+	return car, nil
+}
+
+// Start issues a transfer code for the seller's car, replacing an earlier pending transfer. The
+// code is returned only here; the database keeps its hash.
+func (s *VehicleTransferService) Start(ctx context.Context, userCarID, seller uuid.UUID, in model.VehicleTransferCreate) (*model.VehicleTransfer, error) {
+	if _, err := s.ownedCar(ctx, userCarID, seller); err != nil {
+		return nil, err
+	}
+	policy, msg := validate.TransferAppointmentPolicy(in.AppointmentPolicy)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	code, err := newTransferCode()
+	if err != nil {
+		return nil, apperr.Internal(err)
+	}
+	t, err := s.repo.VehicleTransfer.Start(ctx, userCarID, seller, hashTransferCode(code), policy, time.Now().AddDate(0, 0, transferCodeDays))
+	if err != nil {
+		return nil, err
+	}
+	t.Code = formatTransferCode(code)
+	return t, nil
+}
+
+// Pending returns the seller's pending transfer of a car.
+func (s *VehicleTransferService) Pending(ctx context.Context, userCarID, seller uuid.UUID) (*model.VehicleTransfer, error) {
+	if _, err := s.ownedCar(ctx, userCarID, seller); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.VehicleTransfer.GetPending(ctx, userCarID, time.Now())
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("No pending transfer")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *VehicleTransferService) Cancel(ctx context.Context, userCarID, seller uuid.UUID) error {
+	if _, err := s.ownedCar(ctx, userCarID, seller); err != nil {
+		return err
+	}
+	if err := s.repo.VehicleTransfer.CancelPending(ctx, userCarID, seller); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return apperr.NotFoundErr("No pending transfer")
+		}
+		return err
+	}
+	return nil
+}
+
+// Accept moves the car with the given VIN into the buyer's garage when the code matches.
+func (s *VehicleTransferService) Accept(ctx context.Context, buyer uuid.UUID, in model.VehicleTransferAccept) (*model.VehicleTransfer, error) {
+	if msg := validate.VIN(in.VIN); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	if msg := validate.TransferCode(in.Code); msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	code := validate.NormalizeTransferCode(in.Code)
+	t, err := s.repo.VehicleTransfer.Accept(ctx, validate.NormalizeVIN(in.VIN), hashTransferCode(code), buyer, transferMaxFailedAttempts, time.Now())
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("Transfer code is invalid or has expired")
+		}
+		return nil, err
+	}
+	s.notifyTransfer(ctx, t)
+	return t, nil
+}
+
+// AdminForce transfers a car to another customer without the seller's code.
+func (s *VehicleTransferService) AdminForce(ctx context.Context, userCarID, staffID uuid.UUID, in model.VehicleTransferForce) (*model.VehicleTransfer, error) {
+	reason, msg := validate.TransferReason(in.Reason)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	policy, msg := validate.TransferAppointmentPolicy(in.AppointmentPolicy)
+	if msg != "" {
+		return nil, apperr.BadRequest(msg)
+	}
+	toUserID, err := s.transferRecipient(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.repo.VehicleTransfer.Force(ctx, userCarID, toUserID, staffID, reason, policy, time.Now())
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("User car not found")
+		}
+		return nil, err
+	}
+	s.notifyTransfer(ctx, t)
+	return t, nil
+}
+
+// AdminList returns the transfer log of a car.
+func (s *VehicleTransferService) AdminList(ctx context.Context, userCarID uuid.UUID) ([]model.VehicleTransfer, error) {
+	if _, err := s.repo.UserCar.GetByID(ctx, userCarID); err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil, apperr.NotFoundErr("User car not found")
+		}
+		return nil, err
+	}
+	return s.repo.VehicleTransfer.ListByCar(ctx, userCarID)
+}
+
+// transferRecipient resolves the new owner of a forced transfer by ID or email; unlike staff
+// booking, no account is created.
+func (s *VehicleTransferService) transferRecipient(ctx context.Context, in model.VehicleTransferForce) (uuid.UUID, error) {
+	if in.ToUserID != nil {
+		u, err := s.repo.User.GetByID(ctx, *in.ToUserID)
+		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				return uuid.Nil, apperr.NotFoundErr("Customer not found")
+			}
+			return uuid.Nil, err
+		}
+		return u.UserID, nil
+	}
+	if in.ToEmail == nil {
+		return uuid.Nil, apperr.BadRequest("to_user_id or to_email is required")
+	}
+	email, msg := validate.Email(*in.ToEmail)
+	if msg != "" {
+		return uuid.Nil, apperr.BadRequest(msg)
+	}
+	exists, err := s.repo.User.EmailExists(ctx, email)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !exists {
+		return uuid.Nil, apperr.NotFoundErr("Customer not found")
+	}
+	u, err := s.repo.User.GetByEmail(ctx, email)
+	if err != nil {
+		return uuid.Nil, apperr.Internal(err)
+	}
+	return u.UserID, nil
+}
+
+// notifyTransfer tells both owners that the car changed hands. Failures are logged and never fail
+// the transfer.
+func (s *VehicleTransferService) notifyTransfer(ctx context.Context, t *model.VehicleTransfer) {
+	carTitle := "VIN " + t.VIN
+	if car, err := s.repo.UserCar.GetByID(ctx, t.UserCarID); err == nil {
+		carTitle = fmt.Sprintf("%s %s (VIN %s)", car.BrandName, car.ModelName, car.VIN)
+	}
+	entityType := "user_car"
+	send := func(userID *uuid.UUID, kind, title, body string) {
+		if userID == nil {
+			return
+		}
+		_, err := s.repo.Notification.Create(ctx, model.Notification{
+			UserID:     *userID,
+			Kind:       kind,
+			Title:      title,
+			Body:       fmt.Sprintf(body, carTitle),
+			EntityType: &entityType,
+			EntityID:   &t.UserCarID,
+		})
+		if err != nil {
+			slog.Warn("transfer notification failed", "transfer", t.TransferID, "err", err)
+		}
+	}
+	send(t.FromUserID, notificationKindTransferSent, transferSentTitle, transferSentBody)
+	send(t.ToUserID, notificationKindTransferReceived, transferReceivedTitle, transferReceivedBody)
+}
+
+// newTransferCode returns a random code from the transfer code alphabet; its 32 letters divide 256,
+// so taking bytes modulo its length keeps the choice uniform.
+func newTransferCode() (string, error) {
+	b := make([]byte, validate.TransferCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	alphabet := validate.TransferCodeAlphabet
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b), nil
+}
+
+// formatTransferCode splits a code into two groups for reading it out: ABCD-EFGH.
+func formatTransferCode(code string) string {
+	half := len(code) / 2
+	return code[:half] + "-" + code[half:]
+}
+
+// hashTransferCode hashes a normalized code. The code is short-lived and attempts are limited, so a
+// plain SHA-256 is enough.
+func hashTransferCode(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
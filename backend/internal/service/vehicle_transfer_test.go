@@ -0,0 +1,43 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/carkeeper/backend/internal/validate"
+)
+
+func TestNewTransferCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		code, err := newTransferCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		formatted := formatTransferCode(code)
+		if len(formatted) != validate.TransferCodeLength+1 || formatted[4] != '-' {
+			t.Fatalf("unexpected format %q", formatted)
+		}
+		if msg := validate.TransferCode(formatted); msg != "" {
+			t.Fatalf("generated code %q rejected: %s", formatted, msg)
+		}
+		seen[code] = true
+	}
+	if len(seen) < 50 {
+		t.Fatal("transfer codes repeat")
+	}
+}
+
+func TestHashTransferCodeMatchesTypedCode(t *testing.T) {
+	code, err := newTransferCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	typed := " " + formatTransferCode(code) + " "
+	if !bytes.Equal(hashTransferCode(code), hashTransferCode(validate.NormalizeTransferCode(typed))) {
+		t.Fatal("normalized typed code should hash like the issued one")
+	}
+	if bytes.Equal(hashTransferCode("AAAAAAAA"), hashTransferCode("AAAAAAAB")) {
+		t.Fatal("different codes must hash differently")
+	}
+}
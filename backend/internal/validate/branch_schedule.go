@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	BranchExceptionNoteMaxRunes = 200
+	// BranchExceptionMaxDaysAhead bounds how far ahead calendar exceptions may be planned.
+	BranchExceptionMaxDaysAhead = 731
+)
+
+// BranchHours validates working hours in minutes from local midnight with an optional break
+// that must lie strictly inside them.
+func BranchHours(open, close int, breakStart, breakEnd *int) string {
+	if open < 0 || open >= 1440 || close <= 0 || close > 1440 {
+		return "working hours must be within a day (0..1440 minutes)"
+	}
+	if open >= close {
+		return "opening time must be before closing time"
+	}
+	if (breakStart == nil) != (breakEnd == nil) {
+		return "break needs both start and end"
+	}
+	if breakStart != nil && !(open < *breakStart && *breakStart < *breakEnd && *breakEnd < close) {
+		return "break must lie inside working hours"
+	}
+	return ""
+}
+
+// Weekday validates a day of week where 0 is Sunday.
+func Weekday(d int) string {
+	if d < 0 || d > 6 {
+		return "weekday must be 0 (Sunday) .. 6 (Saturday)"
+	}
+	return ""
+}
+
+// BranchExceptionDay parses the YYYY-MM-DD date of a calendar exception; it may not be in the
+// past relative to today in loc and not further than BranchExceptionMaxDaysAhead.
+func BranchExceptionDay(day string, loc *time.Location, now time.Time) (string, string) {
+	d, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(day), loc)
+	if err != nil {
+		return "", "day must be YYYY-MM-DD"
+	}
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if d.Before(today) {
+		return "", "day cannot be in the past"
+	}
+	if d.After(today.AddDate(0, 0, BranchExceptionMaxDaysAhead)) {
+		return "", "day is too far ahead"
+	}
+	return d.Format("2006-01-02"), ""
+}
+
+// BranchExceptionNote trims the optional exception note (e.g. holiday name); blank becomes nil.
+func BranchExceptionNote(note *string) (*string, string) {
+	if note == nil {
+		return nil, ""
+	}
+	s := strings.TrimSpace(*note)
+	if s == "" {
+		return nil, ""
+	}
+	if len([]rune(s)) > BranchExceptionNoteMaxRunes {
+		return nil, "note is too long"
+	}
+	return &s, ""
+}
@@ -0,0 +1,69 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBranchHours(t *testing.T) {
+	at := func(m int) *int { return &m }
+	cases := []struct {
+		name        string
+		open, close int
+		bs, be      *int
+		ok          bool
+	}{
+		{"plain day", 540, 1080, nil, nil, true},
+		{"with lunch", 540, 1080, at(780), at(840), true},
+		{"until midnight", 0, 1440, nil, nil, true},
+		{"reversed", 1080, 540, nil, nil, false},
+		{"past midnight", 540, 1500, nil, nil, false},
+		{"half a break", 540, 1080, at(780), nil, false},
+		{"break at opening", 540, 1080, at(540), at(600), false},
+		{"break past closing", 540, 1080, at(1000), at(1080), false},
+	}
+	for _, c := range cases {
+		if got := BranchHours(c.open, c.close, c.bs, c.be) == ""; got != c.ok {
+			t.Errorf("%s: ok = %v, want %v", c.name, got, c.ok)
+		}
+	}
+}
+
+func TestWeekday(t *testing.T) {
+	if Weekday(0) != "" || Weekday(6) != "" {
+		t.Fatal("0..6 are valid")
+	}
+	if Weekday(7) == "" || Weekday(-1) == "" {
+		t.Fatal("out of range weekday should fail")
+	}
+}
+
+func TestBranchExceptionDay(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Moscow")
+	// 22:30 UTC is already the next day in Moscow.
+	now := time.Date(2026, 12, 30, 22, 30, 0, 0, time.UTC)
+	if d, msg := BranchExceptionDay(" 2026-12-31 ", loc, now); msg != "" || d != "2026-12-31" {
+		t.Fatalf("today in branch zone: %q %q", d, msg)
+	}
+	if _, msg := BranchExceptionDay("2026-12-30", loc, now); msg == "" {
+		t.Fatal("past day should fail")
+	}
+	if _, msg := BranchExceptionDay("2030-01-01", loc, now); msg == "" {
+		t.Fatal("far future should fail")
+	}
+	if _, msg := BranchExceptionDay("31.12.2026", loc, now); msg == "" {
+		t.Fatal("bad format should fail")
+	}
+}
+
+func TestBranchExceptionNote(t *testing.T) {
+	blank := "  "
+	if n, msg := BranchExceptionNote(&blank); n != nil || msg != "" {
+		t.Fatalf("blank: %v %q", n, msg)
+	}
+	long := strings.Repeat("я", BranchExceptionNoteMaxRunes+1)
+	if _, msg := BranchExceptionNote(&long); msg == "" {
+		t.Fatal("long note should fail")
+	}
+}
@@ -0,0 +1,94 @@
+package validate
+
+import "strings"
+
+const (
+	FinanceNameMax        = 200
+	FinanceDescriptionMax = 4000
+	FinanceTermMax        = 360
+	FinanceRateMax        = 100
+)
+
+// FinanceProductName validates required program name.
+func FinanceProductName(name string) (string, string) {
+	return requiredSingleLine("name", name, FinanceNameMax)
+}
+
+// FinanceProvider validates required bank / leasing company name.
+func FinanceProvider(provider string) (string, string) {
+	return requiredSingleLine("provider", provider, FinanceNameMax)
+}
+
+// FinanceProductDescription validates optional program description.
+func FinanceProductDescription(description *string) (*string, string) {
+	return optionalMultiline("description", description, FinanceDescriptionMax)
+}
+
+// FinanceProductType validates loan or lease (empty defaults to loan).
+func FinanceProductType(productType string) (string, string) {
+	s := strings.TrimSpace(strings.ToLower(productType))
+	switch s {
+	case "":
+		return "loan", ""
+	case "loan", "lease":
+		return s, ""
+	default:
+		return "", "product_type must be loan or lease"
+	}
+}
+
+// FinanceTerm validates a requested term in months.
+func FinanceTerm(months int) string {
+	if months <= 0 || months > FinanceTermMax {
+		return "term_months must be between 1 and 360"
+	}
+	return ""
+}
+
+// FinanceProductTerms validates annual rate and term range.
+func FinanceProductTerms(annualRate float64, minTerm, maxTerm int) string {
+	if annualRate < 0 || annualRate > FinanceRateMax {
+		return "annual_rate must be between 0 and 100"
+	}
+	if msg := FinanceTerm(minTerm); msg != "" {
+		return "invalid min_term_months"
+	}
+	if msg := FinanceTerm(maxTerm); msg != "" {
+		return "invalid max_term_months"
+	}
+	if maxTerm < minTerm {
+		return "max_term_months must not be less than min_term_months"
+	}
+	return ""
+}
+
+// FinanceDownPaymentRange validates down payment percent range and balloon percent.
+func FinanceDownPaymentRange(minPercent, maxPercent, balloonPercent float64) string {
+	if minPercent < 0 || minPercent >= 100 || maxPercent < 0 || maxPercent >= 100 {
+		return "down payment percent must be between 0 and 100"
+	}
+	if maxPercent < minPercent {
+		return "max_down_payment_percent must not be less than min_down_payment_percent"
+	}
+	if balloonPercent < 0 || balloonPercent >= 100 {
+		return "balloon_percent must be between 0 and 100"
+	}
+	if minPercent+balloonPercent >= 100 {
+		return "down payment and balloon leave nothing to finance"
+	}
+	return ""
+}
+
+// FinanceAmountRange validates optional financed amount limits.
+func FinanceAmountRange(minAmount, maxAmount *float64) string {
+	if minAmount != nil && (*minAmount < 0 || *minAmount > ServicePriceMax) {
+		return "invalid min_amount"
+	}
+	if maxAmount != nil && (*maxAmount <= 0 || *maxAmount > ServicePriceMax) {
+		return "invalid max_amount"
+	}
+	if minAmount != nil && maxAmount != nil && *maxAmount < *minAmount {
+		return "max_amount must not be less than min_amount"
+	}
+	return ""
+}
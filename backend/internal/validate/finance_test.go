@@ -0,0 +1,36 @@
+package validate
+
+import "testing"
+
+func TestFinanceProductType(t *testing.T) {
+	if got, msg := FinanceProductType(""); msg != "" || got != "loan" {
+		t.Fatalf("got %q msg %q", got, msg)
+	}
+	if got, msg := FinanceProductType(" Lease "); msg != "" || got != "lease" {
+		t.Fatalf("got %q msg %q", got, msg)
+	}
+	if _, msg := FinanceProductType("rent"); msg == "" {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFinanceProductTerms(t *testing.T) {
+	if msg := FinanceProductTerms(12.5, 12, 84); msg != "" {
+		t.Fatalf("unexpected %q", msg)
+	}
+	if msg := FinanceProductTerms(12.5, 60, 12); msg == "" {
+		t.Fatal("reversed range should fail")
+	}
+	if msg := FinanceProductTerms(-1, 12, 24); msg == "" {
+		t.Fatal("negative rate should fail")
+	}
+}
+
+func TestFinanceDownPaymentRange(t *testing.T) {
+	if msg := FinanceDownPaymentRange(10, 50, 30); msg != "" {
+		t.Fatalf("unexpected %q", msg)
+	}
+	if msg := FinanceDownPaymentRange(60, 80, 40); msg == "" {
+		t.Fatal("down payment plus balloon over 100% should fail")
+	}
+}
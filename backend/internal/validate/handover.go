@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// HandoverMileageMax bounds the odometer reading of a car delivered from a dealer order.
+	HandoverMileageMax    = 10000
+	HandoverBaysMax       = 16
+	HandoverDurationMin   = 15
+	HandoverDurationMax   = 240
+	DeliveryNotesMaxRunes = 1000
+)
+
+// DeliveryDate parses the required YYYY-MM-DD handover date; it cannot be in the future.
+func DeliveryDate(date string, now time.Time) (time.Time, string) {
+	d, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return time.Time{}, "delivery_date must be YYYY-MM-DD"
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if d.After(today) {
+		return time.Time{}, "delivery_date cannot be in the future"
+	}
+	return d, ""
+}
+
+// HandoverMileage validates the starting odometer reading of a delivered car.
+func HandoverMileage(km int) string {
+	if km < 0 || km > HandoverMileageMax {
+		return "mileage must be between 0 and " + itoa(HandoverMileageMax)
+	}
+	return ""
+}
+
+// HandoverBays validates an optional number of parallel deliveries at a branch (0 disables them).
+func HandoverBays(n *int) string {
+	if n != nil && (*n < 0 || *n > HandoverBaysMax) {
+		return "handover_bays must be between 0 and " + itoa(HandoverBaysMax)
+	}
+	return ""
+}
+
+// HandoverDurationMinutes validates an optional delivery slot length.
+func HandoverDurationMinutes(n *int) string {
+	if n != nil && (*n < HandoverDurationMin || *n > HandoverDurationMax) {
+		return "handover_duration_minutes must be between " + itoa(HandoverDurationMin) + " and " + itoa(HandoverDurationMax)
+	}
+	return ""
+}
+
+// DeliveryNotes validates optional customer notes for a delivery appointment.
+func DeliveryNotes(notes *string) (*string, string) {
+	return optionalMultiline("notes", notes, DeliveryNotesMaxRunes)
+}
@@ -0,0 +1,44 @@
+package validate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryDate(t *testing.T) {
+	now := time.Date(2026, 5, 10, 23, 30, 0, 0, time.UTC)
+	if d, msg := DeliveryDate(" 2026-05-10 ", now); msg != "" || d.Format("2006-01-02") != "2026-05-10" {
+		t.Fatalf("today: got %v %q", d, msg)
+	}
+	if _, msg := DeliveryDate("2026-05-11", now); msg == "" {
+		t.Fatal("future date should fail")
+	}
+	if _, msg := DeliveryDate("", now); msg == "" {
+		t.Fatal("empty date should fail")
+	}
+}
+
+func TestHandoverMileage(t *testing.T) {
+	for km, ok := range map[int]bool{0: true, 15: true, HandoverMileageMax: true, -1: false, HandoverMileageMax + 1: false} {
+		if got := HandoverMileage(km) == ""; got != ok {
+			t.Errorf("HandoverMileage(%d) ok = %v, want %v", km, got, ok)
+		}
+	}
+}
+
+func TestHandoverBranchSettings(t *testing.T) {
+	zero, big := 0, HandoverBaysMax+1
+	if msg := HandoverBays(&zero); msg != "" {
+		t.Fatalf("0 bays disables deliveries: %q", msg)
+	}
+	if msg := HandoverBays(&big); msg == "" {
+		t.Fatal("too many bays should fail")
+	}
+	short := HandoverDurationMin - 1
+	if msg := HandoverDurationMinutes(&short); msg == "" {
+		t.Fatal("too short duration should fail")
+	}
+	if msg := HandoverDurationMinutes(nil); msg != "" {
+		t.Fatalf("nil duration: %q", msg)
+	}
+}
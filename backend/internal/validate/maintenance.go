@@ -0,0 +1,38 @@
+package validate
+
+const (
+	MaintenancePlanNameMax      = 200
+	MaintenanceIntervalKmMax    = 200000
+	MaintenanceIntervalMonthMax = 120
+	MaintenancePlanServicesMax  = 20
+)
+
+// MaintenancePlanName validates a maintenance plan title.
+func MaintenancePlanName(name string) (string, string) {
+	return requiredSingleLine("name", name, MaintenancePlanNameMax)
+}
+
+// MaintenanceIntervals checks the mileage and time intervals of a plan; at least one is required.
+func MaintenanceIntervals(km, months *int) string {
+	if km == nil && months == nil {
+		return "interval_km or interval_months is required"
+	}
+	if km != nil && (*km < 1 || *km > MaintenanceIntervalKmMax) {
+		return "interval_km must be between 1 and 200000"
+	}
+	if months != nil && (*months < 1 || *months > MaintenanceIntervalMonthMax) {
+		return "interval_months must be between 1 and 120"
+	}
+	return ""
+}
+
+// MaintenancePlanServiceCount checks how many service types a plan lists.
+func MaintenancePlanServiceCount(n int) string {
+	if n == 0 {
+		return "at least one service type is required"
+	}
+	if n > MaintenancePlanServicesMax {
+		return "too many service types"
+	}
+	return ""
+}
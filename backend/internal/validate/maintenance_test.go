@@ -0,0 +1,28 @@
+package validate
+
+import "testing"
+
+func TestMaintenanceIntervals(t *testing.T) {
+	km, months, zero := 15000, 12, 0
+	if msg := MaintenanceIntervals(&km, &months); msg != "" {
+		t.Fatalf("valid intervals rejected: %q", msg)
+	}
+	if msg := MaintenanceIntervals(nil, &months); msg != "" {
+		t.Fatalf("time-only plan rejected: %q", msg)
+	}
+	if msg := MaintenanceIntervals(nil, nil); msg == "" {
+		t.Fatal("a plan without intervals should fail")
+	}
+	if msg := MaintenanceIntervals(&zero, nil); msg == "" {
+		t.Fatal("zero mileage interval should fail")
+	}
+}
+
+func TestMaintenancePlanServiceCount(t *testing.T) {
+	if msg := MaintenancePlanServiceCount(0); msg == "" {
+		t.Fatal("empty service list should fail")
+	}
+	if msg := MaintenancePlanServiceCount(MaintenancePlanServicesMax + 1); msg == "" {
+		t.Fatal("too many services should fail")
+	}
+}
@@ -0,0 +1,26 @@
+package validate
+
+const (
+	MessageBodyMaxRunes   = 4000
+	MessageAttachmentsMax = 10
+)
+
+// MessageBody validates the required text of a thread message.
+func MessageBody(body string) (string, string) {
+	s, msg := optionalMultiline("body", &body, MessageBodyMaxRunes)
+	if msg != "" {
+		return "", msg
+	}
+	if s == nil {
+		return "", "body is required"
+	}
+	return *s, ""
+}
+
+// MessageAttachments bounds the number of documents linked from one message.
+func MessageAttachments(n int) string {
+	if n > MessageAttachmentsMax {
+		return "a message can link at most " + itoa(MessageAttachmentsMax) + " documents"
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageBody(t *testing.T) {
+	if s, msg := MessageBody("  Hello,\nwhen is delivery?  "); msg != "" || s != "Hello,\nwhen is delivery?" {
+		t.Fatalf("got %q %q", s, msg)
+	}
+	if _, msg := MessageBody(" \n "); msg == "" {
+		t.Fatal("blank body should fail")
+	}
+	if _, msg := MessageBody(strings.Repeat("a", MessageBodyMaxRunes+1)); msg == "" {
+		t.Fatal("too long body should fail")
+	}
+	if _, msg := MessageBody("bell\a"); msg == "" {
+		t.Fatal("control characters should fail")
+	}
+}
+
+func TestMessageAttachments(t *testing.T) {
+	if msg := MessageAttachments(MessageAttachmentsMax); msg != "" {
+		t.Fatalf("limit should pass: %q", msg)
+	}
+	if msg := MessageAttachments(MessageAttachmentsMax + 1); msg == "" {
+		t.Fatal("too many attachments should fail")
+	}
+}
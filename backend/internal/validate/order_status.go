@@ -11,6 +11,8 @@ const (
 	OrderStatusDescriptionMax   = 2000
 	OrderStatusSortMin          = -10_000
 	OrderStatusSortMax          = 100_000
+	OrderStatusReasonMax        = 200
+	OrderStatusCommentMax       = 4000
 )
 
 var orderStatusCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
@@ -52,3 +54,13 @@ func OrderStatusSortOrder(sortOrder int) string {
 	}
 	return ""
 }
+
+// OrderStatusReason validates the optional short reason of a status change.
+func OrderStatusReason(reason *string) (*string, string) {
+	return optionalSingleLine("reason", reason, OrderStatusReasonMax)
+}
+
+// OrderStatusComment validates the optional free-text comment of a status change.
+func OrderStatusComment(comment *string) (*string, string) {
+	return optionalMultiline("comment", comment, OrderStatusCommentMax)
+}
@@ -1,6 +1,9 @@
 package validate
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestOrderStatusCode_Valid(t *testing.T) {
 	got, msg := OrderStatusCode(" Pending_Payment ")
@@ -21,3 +24,22 @@ func TestOrderStatusSortOrder_Invalid(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestOrderStatusReason(t *testing.T) {
+	in := "  Клиент передумал  "
+	got, msg := OrderStatusReason(&in)
+	if msg != "" || got == nil || *got != "Клиент передумал" {
+		t.Fatalf("got %v msg %q", got, msg)
+	}
+	multi := "a\nb"
+	if _, msg := OrderStatusReason(&multi); msg == "" {
+		t.Fatal("expected single-line error")
+	}
+}
+
+func TestOrderStatusComment_TooLong(t *testing.T) {
+	long := strings.Repeat("x", OrderStatusCommentMax+1)
+	if _, msg := OrderStatusComment(&long); msg == "" {
+		t.Fatal("expected length error")
+	}
+}
@@ -0,0 +1,30 @@
+package validate
+
+import "strings"
+
+const OrganizationNameMax = 200
+
+// OrganizationName validates a household or company name.
+func OrganizationName(name string) (string, string) {
+	return requiredSingleLine("name", name, OrganizationNameMax)
+}
+
+// OrganizationKind validates the organization type; empty means household.
+func OrganizationKind(kind string) (string, string) {
+	switch k := strings.TrimSpace(kind); k {
+	case "":
+		return "household", ""
+	case "household", "company":
+		return k, ""
+	}
+	return "", "kind must be household or company"
+}
+
+// OrganizationRole validates a member role.
+func OrganizationRole(role string) (string, string) {
+	switch r := strings.TrimSpace(role); r {
+	case "owner", "driver", "viewer":
+		return r, ""
+	}
+	return "", "role must be owner, driver or viewer"
+}
@@ -0,0 +1,29 @@
+package validate
+
+import "testing"
+
+func TestOrganizationKind(t *testing.T) {
+	if k, msg := OrganizationKind(""); msg != "" || k != "household" {
+		t.Fatalf("empty kind: got %q %q, want household", k, msg)
+	}
+	if k, msg := OrganizationKind("company"); msg != "" || k != "company" {
+		t.Fatalf("company: got %q %q", k, msg)
+	}
+	if _, msg := OrganizationKind("fleet"); msg == "" {
+		t.Fatal("unknown kind should fail")
+	}
+}
+
+func TestOrganizationRole(t *testing.T) {
+	for _, role := range []string{"owner", "driver", " viewer "} {
+		if _, msg := OrganizationRole(role); msg != "" {
+			t.Fatalf("role %q rejected: %s", role, msg)
+		}
+	}
+	if _, msg := OrganizationRole(""); msg == "" {
+		t.Fatal("empty role should fail")
+	}
+	if _, msg := OrganizationRole("admin"); msg == "" {
+		t.Fatal("unknown role should fail")
+	}
+}
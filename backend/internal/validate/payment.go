@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	RefundReasonMax = 500
+)
+
+// InvoiceKind validates deposit or balance.
+func InvoiceKind(kind string) (string, string) {
+	s := strings.TrimSpace(strings.ToLower(kind))
+	switch s {
+	case "deposit", "balance":
+		return s, ""
+	default:
+		return "", "kind must be deposit or balance"
+	}
+}
+
+// InvoiceDueDate parses an optional YYYY-MM-DD due date.
+func InvoiceDueDate(date *string) (*time.Time, string) {
+	if date == nil || strings.TrimSpace(*date) == "" {
+		return nil, ""
+	}
+	d, err := time.Parse("2006-01-02", strings.TrimSpace(*date))
+	if err != nil {
+		return nil, "due_date must be YYYY-MM-DD"
+	}
+	return &d, ""
+}
+
+// OfflinePaymentMethod validates how staff received money outside the online gateway.
+func OfflinePaymentMethod(method string) (string, string) {
+	s := strings.TrimSpace(strings.ToLower(method))
+	switch s {
+	case "cash", "card_terminal", "bank_transfer":
+		return s, ""
+	default:
+		return "", "method must be cash, card_terminal or bank_transfer"
+	}
+}
+
+// PaymentAmount validates an optional explicit amount (nil means "the remainder").
+func PaymentAmount(amount *float64) string {
+	if amount != nil && (*amount <= 0 || *amount > ServicePriceMax) {
+		return "invalid amount"
+	}
+	return ""
+}
+
+// RefundReason validates the required refund reason.
+func RefundReason(reason string) (string, string) {
+	return requiredSingleLine("reason", reason, RefundReasonMax)
+}
@@ -0,0 +1,36 @@
+package validate
+
+import "testing"
+
+func TestInvoiceDueDate(t *testing.T) {
+	if d, msg := InvoiceDueDate(nil); d != nil || msg != "" {
+		t.Fatalf("nil date: got %v %q", d, msg)
+	}
+	s := " 2026-11-30 "
+	if d, msg := InvoiceDueDate(&s); msg != "" || d.Format("2006-01-02") != "2026-11-30" {
+		t.Fatalf("got %v %q", d, msg)
+	}
+	bad := "30.11.2026"
+	if _, msg := InvoiceDueDate(&bad); msg == "" {
+		t.Fatal("non-ISO date should fail")
+	}
+}
+
+func TestOfflinePaymentMethod(t *testing.T) {
+	if m, msg := OfflinePaymentMethod(" Cash "); msg != "" || m != "cash" {
+		t.Fatalf("got %q msg %q", m, msg)
+	}
+	if _, msg := OfflinePaymentMethod("online"); msg == "" {
+		t.Fatal("online is not an offline method")
+	}
+}
+
+func TestPaymentAmount(t *testing.T) {
+	if msg := PaymentAmount(nil); msg != "" {
+		t.Fatalf("nil amount: %q", msg)
+	}
+	zero := 0.0
+	if msg := PaymentAmount(&zero); msg == "" {
+		t.Fatal("zero amount should fail")
+	}
+}
@@ -0,0 +1,105 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	PromotionNameMax        = 200
+	PromotionDescriptionMax = 4000
+	PromotionSegmentMax     = 100
+	PromoCodeMin            = 4
+	PromoCodeMax            = 40
+	PromotionPriorityMin    = -1000
+	PromotionPriorityMax    = 1000
+)
+
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9-]+$`)
+
+// PromotionName validates required promotion title.
+func PromotionName(name string) (string, string) {
+	return requiredSingleLine("name", name, PromotionNameMax)
+}
+
+// PromotionDescription validates optional promotion description.
+func PromotionDescription(description *string) (*string, string) {
+	return optionalMultiline("description", description, PromotionDescriptionMax)
+}
+
+// PromotionDiscount validates discount type and value (percent in (0, 100], fixed > 0).
+func PromotionDiscount(discountType string, value float64) (string, string) {
+	t := strings.TrimSpace(strings.ToLower(discountType))
+	switch t {
+	case "percent":
+		if value <= 0 || value > 100 {
+			return "", "percent discount_value must be between 0 and 100"
+		}
+	case "fixed":
+		if value <= 0 || value > ServicePriceMax {
+			return "", "invalid discount_value"
+		}
+	default:
+		return "", "discount_type must be percent or fixed"
+	}
+	return t, ""
+}
+
+// PromotionVehicleSegment validates optional vehicle segment scope (matches models.segment).
+func PromotionVehicleSegment(segment *string) (*string, string) {
+	return optionalSingleLine("vehicle_segment", segment, PromotionSegmentMax)
+}
+
+// PromotionCustomerSegment validates optional customer segment scope.
+func PromotionCustomerSegment(segment *string) (*string, string) {
+	if segment == nil {
+		return nil, ""
+	}
+	s := strings.TrimSpace(strings.ToLower(*segment))
+	switch s {
+	case "":
+		return nil, ""
+	case "new_customer", "returning_customer":
+		return &s, ""
+	default:
+		return nil, "customer_segment must be new_customer or returning_customer"
+	}
+}
+
+// PromotionWindow validates optional validity window bounds.
+func PromotionWindow(from, to *time.Time) string {
+	if from != nil && to != nil && !to.After(*from) {
+		return "valid_to must be after valid_from"
+	}
+	return ""
+}
+
+// PromotionLimits validates optional usage limits and priority.
+func PromotionLimits(maxUses, maxUsesPerUser *int, priority int) string {
+	if maxUses != nil && *maxUses <= 0 {
+		return "max_uses must be positive"
+	}
+	if maxUsesPerUser != nil && *maxUsesPerUser <= 0 {
+		return "max_uses_per_user must be positive"
+	}
+	if priority < PromotionPriorityMin || priority > PromotionPriorityMax {
+		return "invalid priority"
+	}
+	return ""
+}
+
+// PromoCode normalizes a promo code to upper case and validates its charset and length.
+func PromoCode(code string) (string, string) {
+	s := strings.ToUpper(strings.TrimSpace(code))
+	if s == "" {
+		return "", "promo code is required"
+	}
+	if len(s) < PromoCodeMin || len(s) > PromoCodeMax {
+		return "", "promo code must be 4 to 40 characters"
+	}
+	if !promoCodePattern.MatchString(s) {
+		return "", "promo code may contain only letters A-Z, digits and hyphens"
+	}
+	return s, ""
+}
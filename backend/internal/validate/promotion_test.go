@@ -0,0 +1,59 @@
+package validate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromoCode_Normalizes(t *testing.T) {
+	got, msg := PromoCode("  spring-25 ")
+	if msg != "" || got != "SPRING-25" {
+		t.Fatalf("got %q msg %q", got, msg)
+	}
+}
+
+func TestPromoCode_Invalid(t *testing.T) {
+	for _, code := range []string{"", "abc", "SALE 10", "СКИДКА10"} {
+		if _, msg := PromoCode(code); msg == "" {
+			t.Fatalf("expected error for %q", code)
+		}
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	if got, msg := PromotionDiscount("Percent", 5); msg != "" || got != "percent" {
+		t.Fatalf("got %q msg %q", got, msg)
+	}
+	if _, msg := PromotionDiscount("percent", 120); msg == "" {
+		t.Fatal("percent above 100 should fail")
+	}
+	if _, msg := PromotionDiscount("fixed", 0); msg == "" {
+		t.Fatal("zero fixed discount should fail")
+	}
+	if _, msg := PromotionDiscount("bogus", 10); msg == "" {
+		t.Fatal("unknown type should fail")
+	}
+}
+
+func TestPromotionWindow(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	if msg := PromotionWindow(&from, &to); msg != "" {
+		t.Fatalf("unexpected %q", msg)
+	}
+	if msg := PromotionWindow(&to, &from); msg == "" {
+		t.Fatal("reversed window should fail")
+	}
+}
+
+func TestPromotionCustomerSegment(t *testing.T) {
+	s := " New_Customer "
+	got, msg := PromotionCustomerSegment(&s)
+	if msg != "" || got == nil || *got != "new_customer" {
+		t.Fatalf("got %v msg %q", got, msg)
+	}
+	bad := "vip"
+	if _, msg := PromotionCustomerSegment(&bad); msg == "" {
+		t.Fatal("unknown segment should fail")
+	}
+}
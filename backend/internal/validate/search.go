@@ -0,0 +1,112 @@
+package validate
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	SearchTextMaxRunes  = 100
+	SearchStatusesMax   = 20
+	SearchDateRangeDays = 366
+)
+
+// appointmentStatuses mirrors the service_appointments status CHECK.
+var appointmentStatuses = map[string]struct{}{
+	"scheduled":   {},
+	"in_progress": {},
+	"completed":   {},
+	"cancelled":   {},
+	"no_show":     {},
+}
+
+// SearchDateRange parses optional inclusive YYYY-MM-DD bounds into a half-open UTC range
+// [from, to); either bound may be omitted.
+func SearchDateRange(fromStr, toStr string) (*time.Time, *time.Time, string) {
+	var from, to *time.Time
+	if s := strings.TrimSpace(fromStr); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, nil, "from must be YYYY-MM-DD"
+		}
+		from = &d
+	}
+	if s := strings.TrimSpace(toStr); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, nil, "to must be YYYY-MM-DD"
+		}
+		d = d.AddDate(0, 0, 1)
+		to = &d
+	}
+	if from != nil && to != nil {
+		if !to.After(*from) {
+			return nil, nil, "to must not be before from"
+		}
+		if to.After(from.AddDate(0, 0, SearchDateRangeDays)) {
+			return nil, nil, "date range cannot exceed " + itoa(SearchDateRangeDays) + " days"
+		}
+	}
+	return from, to, ""
+}
+
+// SearchPriceRange validates optional price bounds.
+func SearchPriceRange(min, max *float64) string {
+	for _, p := range []*float64{min, max} {
+		if p != nil && (math.IsNaN(*p) || math.IsInf(*p, 0) || *p < 0) {
+			return "price bounds must be non-negative numbers"
+		}
+	}
+	if min != nil && max != nil && *min > *max {
+		return "min_price must not exceed max_price"
+	}
+	return ""
+}
+
+// SearchText validates an optional free-text filter.
+func SearchText(field, value string) (string, string) {
+	s, msg := optionalSingleLine(field, &value, SearchTextMaxRunes)
+	if s == nil {
+		return "", msg
+	}
+	return *s, ""
+}
+
+// SearchStatuses splits a comma-separated status filter and drops blanks and duplicates.
+func SearchStatuses(raw string) ([]string, string) {
+	var out []string
+	seen := map[string]struct{}{}
+	for _, part := range strings.Split(raw, ",") {
+		s := strings.TrimSpace(part)
+		if s == "" {
+			continue
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		if runeLen(s) > 32 || hasDisallowedControlRunes(s, false) {
+			return nil, "invalid status filter"
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	if len(out) > SearchStatusesMax {
+		return nil, "too many statuses in filter"
+	}
+	return out, ""
+}
+
+// AppointmentStatuses validates a status filter for service appointments.
+func AppointmentStatuses(raw string) ([]string, string) {
+	statuses, msg := SearchStatuses(raw)
+	if msg != "" {
+		return nil, msg
+	}
+	for _, s := range statuses {
+		if _, ok := appointmentStatuses[s]; !ok {
+			return nil, "invalid appointment status " + s
+		}
+	}
+	return statuses, ""
+}
@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchDateRange(t *testing.T) {
+	from, to, msg := SearchDateRange("2026-05-01", "2026-05-31")
+	if msg != "" || !from.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("got %v %v %q", from, to, msg)
+	}
+	if from, to, msg := SearchDateRange("", ""); msg != "" || from != nil || to != nil {
+		t.Fatalf("empty range: %v %v %q", from, to, msg)
+	}
+	if _, _, msg := SearchDateRange("2026-05-02", "2026-05-01"); msg == "" {
+		t.Error("reversed range should fail")
+	}
+	if _, _, msg := SearchDateRange("2025-01-01", "2026-05-01"); msg == "" {
+		t.Error("too long range should fail")
+	}
+	if _, _, msg := SearchDateRange("01.05.2026", ""); msg == "" {
+		t.Error("bad date should fail")
+	}
+}
+
+func TestSearchPriceRange(t *testing.T) {
+	lo, hi, neg := 100.0, 50.0, -1.0
+	if msg := SearchPriceRange(&hi, &lo); msg != "" {
+		t.Fatalf("valid range: %q", msg)
+	}
+	if msg := SearchPriceRange(&lo, &hi); msg == "" {
+		t.Error("min above max should fail")
+	}
+	if msg := SearchPriceRange(&neg, nil); msg == "" {
+		t.Error("negative bound should fail")
+	}
+}
+
+func TestSearchStatuses(t *testing.T) {
+	got, msg := SearchStatuses(" pending, paid,,pending ")
+	if msg != "" || len(got) != 2 || got[0] != "pending" || got[1] != "paid" {
+		t.Fatalf("got %v %q", got, msg)
+	}
+	if _, msg := AppointmentStatuses("scheduled,completed"); msg != "" {
+		t.Fatalf("valid appointment statuses: %q", msg)
+	}
+	if _, msg := AppointmentStatuses("paid"); msg == "" {
+		t.Error("order status is not an appointment status")
+	}
+}
+
+func TestSearchText(t *testing.T) {
+	if s, msg := SearchText("customer", "  ivan@example.com "); msg != "" || s != "ivan@example.com" {
+		t.Fatalf("got %q %q", s, msg)
+	}
+	if s, msg := SearchText("customer", "   "); msg != "" || s != "" {
+		t.Fatalf("blank: %q %q", s, msg)
+	}
+}
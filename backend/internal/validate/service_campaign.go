@@ -0,0 +1,113 @@
+package validate
+
+import "strings"
+
+const (
+	CampaignCodeMax        = 50
+	CampaignTitleMax       = 200
+	CampaignDescriptionMax = 5000
+	CampaignServicesMax    = 20
+)
+
+// CampaignCode validates the manufacturer campaign number.
+func CampaignCode(code string) (string, string) {
+	return requiredSingleLine("code", code, CampaignCodeMax)
+}
+
+// CampaignKind validates the campaign type; empty means recall.
+func CampaignKind(kind string) (string, string) {
+	switch k := strings.TrimSpace(kind); k {
+	case "":
+		return "recall", ""
+	case "recall", "service":
+		return k, ""
+	}
+	return "", "kind must be recall or service"
+}
+
+// CampaignTitle validates the campaign title shown to customers.
+func CampaignTitle(title string) (string, string) {
+	return requiredSingleLine("title", title, CampaignTitleMax)
+}
+
+// CampaignDescription validates the optional description of the work.
+func CampaignDescription(s *string) (*string, string) {
+	return optionalMultiline("description", s, CampaignDescriptionMax)
+}
+
+// CampaignYears checks the model year range; either bound may be open.
+func CampaignYears(from, to *int) string {
+	if from != nil && (*from < 1900 || *from > 2100) {
+		return "year_from must be between 1900 and 2100"
+	}
+	if to != nil && (*to < 1900 || *to > 2100) {
+		return "year_to must be between 1900 and 2100"
+	}
+	if from != nil && to != nil && *to < *from {
+		return "year_to must not be before year_from"
+	}
+	return ""
+}
+
+// CampaignVINPattern normalizes a VIN pattern: VIN characters, '?' for any one character and an
+// optional trailing '*' for any remainder. Without '*' the pattern covers all 17 characters, and
+// at least one character must be fixed.
+func CampaignVINPattern(p *string) (*string, string) {
+	if p == nil {
+		return nil, ""
+	}
+	s := NormalizeVIN(*p)
+	if s == "" {
+		return nil, ""
+	}
+	body, open := strings.CutSuffix(s, "*")
+	if len(s) > 17 || (!open && len(body) != 17) {
+		return nil, "vin_pattern must have 17 characters or end with *"
+	}
+	fixed := 0
+	for _, r := range body {
+		switch {
+		case r == '?':
+		case strings.ContainsRune("IOQ", r) || !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'):
+			return nil, "vin_pattern may contain only VIN characters, ? and a trailing *"
+		default:
+			fixed++
+		}
+	}
+	if fixed == 0 {
+		return nil, "vin_pattern must fix at least one character"
+	}
+	return &s, ""
+}
+
+// CampaignVINRange normalizes an inclusive VIN range; both ends are set together.
+func CampaignVINRange(from, to *string) (*string, *string, string) {
+	if from == nil && to == nil {
+		return nil, nil, ""
+	}
+	if from == nil || to == nil {
+		return nil, nil, "vin_from and vin_to must be set together"
+	}
+	f, t := NormalizeVIN(*from), NormalizeVIN(*to)
+	if msg := VIN(f); msg != "" {
+		return nil, nil, "vin_from: " + msg
+	}
+	if msg := VIN(t); msg != "" {
+		return nil, nil, "vin_to: " + msg
+	}
+	if t < f {
+		return nil, nil, "vin_to must not be before vin_from"
+	}
+	return &f, &t, ""
+}
+
+// CampaignServiceCount checks how many service types a campaign lists.
+func CampaignServiceCount(n int) string {
+	if n == 0 {
+		return "at least one service type is required"
+	}
+	if n > CampaignServicesMax {
+		return "too many service types"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package validate
+
+import "testing"
+
+func TestCampaignVINPattern(t *testing.T) {
+	for in, want := range map[string]string{
+		" wdd2130421a?????? ": "WDD2130421A??????",
+		"WDD213*":             "WDD213*",
+		"??????????A123456":   "??????????A123456",
+	} {
+		s := in
+		got, msg := CampaignVINPattern(&s)
+		if msg != "" || got == nil || *got != want {
+			t.Fatalf("CampaignVINPattern(%q) = %v, %q; want %q", in, got, msg, want)
+		}
+	}
+	for _, in := range []string{"WDD213", "WDD*213", "*", "?????????????????", "WDI213*", "WDD2130421A1234567"} {
+		s := in
+		if _, msg := CampaignVINPattern(&s); msg == "" {
+			t.Fatalf("CampaignVINPattern(%q) should fail", in)
+		}
+	}
+	if got, msg := CampaignVINPattern(nil); got != nil || msg != "" {
+		t.Fatal("a missing pattern should be allowed")
+	}
+}
+
+func TestCampaignVINRange(t *testing.T) {
+	from, to := "wdd2130421a000100", "WDD2130421A000500"
+	f, tt, msg := CampaignVINRange(&from, &to)
+	if msg != "" || *f != "WDD2130421A000100" || *tt != "WDD2130421A000500" {
+		t.Fatalf("valid range rejected: %q", msg)
+	}
+	if _, _, msg := CampaignVINRange(&to, &from); msg == "" {
+		t.Fatal("a reversed range should fail")
+	}
+	if _, _, msg := CampaignVINRange(&from, nil); msg == "" {
+		t.Fatal("a half-open range should fail")
+	}
+}
+
+func TestCampaignYears(t *testing.T) {
+	from, to := 2019, 2021
+	if msg := CampaignYears(&from, &to); msg != "" {
+		t.Fatalf("valid years rejected: %q", msg)
+	}
+	if msg := CampaignYears(&to, &from); msg == "" {
+		t.Fatal("reversed years should fail")
+	}
+	if msg := CampaignYears(nil, &to); msg != "" {
+		t.Fatalf("open lower bound rejected: %q", msg)
+	}
+}
@@ -0,0 +1,51 @@
+package validate
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	ServiceResourceNameMax = 100
+	ResourceSkillsMax      = 20
+)
+
+var resourceSkillPattern = regexp.MustCompile(`^[a-z0-9_]{2,40}$`)
+
+// ServiceResourceKind validates a workshop resource kind.
+func ServiceResourceKind(kind string) (string, string) {
+	k := strings.ToLower(strings.TrimSpace(kind))
+	if k != "bay" && k != "technician" {
+		return "", "kind must be bay or technician"
+	}
+	return k, ""
+}
+
+// ServiceResourceName validates a bay or technician display name.
+func ServiceResourceName(name string) (string, string) {
+	return requiredSingleLine("name", name, ServiceResourceNameMax)
+}
+
+// ResourceSkills normalises skill codes (lowercase letters, digits, underscore) into a sorted set;
+// the result is never nil so that it stores as an empty array.
+func ResourceSkills(skills []string) ([]string, string) {
+	seen := make(map[string]struct{}, len(skills))
+	out := []string{}
+	for _, raw := range skills {
+		s := strings.ToLower(strings.TrimSpace(raw))
+		if !resourceSkillPattern.MatchString(s) {
+			return nil, "skill codes must be 2-40 characters: a-z, 0-9, _"
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	if len(out) > ResourceSkillsMax {
+		return nil, "too many skills"
+	}
+	sort.Strings(out)
+	return out, ""
+}
@@ -0,0 +1,28 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceResourceKind(t *testing.T) {
+	if k, msg := ServiceResourceKind(" Bay "); msg != "" || k != "bay" {
+		t.Fatalf("got %q %q", k, msg)
+	}
+	if _, msg := ServiceResourceKind("lift"); msg == "" {
+		t.Fatal("unknown kind should fail")
+	}
+}
+
+func TestResourceSkills(t *testing.T) {
+	got, msg := ResourceSkills([]string{" Lift", "alignment", "lift"})
+	if msg != "" || !reflect.DeepEqual(got, []string{"alignment", "lift"}) {
+		t.Fatalf("got %v %q", got, msg)
+	}
+	if got, msg := ResourceSkills(nil); msg != "" || got == nil || len(got) != 0 {
+		t.Fatalf("nil skills should become an empty set: %v %q", got, msg)
+	}
+	if _, msg := ResourceSkills([]string{"wheel alignment"}); msg == "" {
+		t.Fatal("spaces are not allowed")
+	}
+}
@@ -0,0 +1,56 @@
+package validate
+
+const (
+	DepreciationRuleNameMax = 200
+	TradeInNotesMax         = 4000
+	TradeInAdjustmentsMax   = 20
+	TradeInAdjustmentLabel  = 200
+	TradeInMileageMax       = 200_000
+)
+
+// DepreciationRuleName validates required rule name.
+func DepreciationRuleName(name string) (string, string) {
+	return requiredSingleLine("name", name, DepreciationRuleNameMax)
+}
+
+// DepreciationRuleSegment validates optional vehicle segment scope (matches models.segment).
+func DepreciationRuleSegment(segment *string) (*string, string) {
+	return optionalSingleLine("vehicle_segment", segment, PromotionSegmentMax)
+}
+
+// DepreciationRuleRates validates depreciation percentages and expected annual mileage.
+func DepreciationRuleRates(firstYear, annual, mileageAdj, minResidual float64, expectedMileage int) string {
+	if firstYear < 0 || firstYear >= 100 {
+		return "first_year_depreciation_percent must be between 0 and 100"
+	}
+	if annual < 0 || annual >= 100 {
+		return "annual_depreciation_percent must be between 0 and 100"
+	}
+	if mileageAdj < 0 || mileageAdj > 100 {
+		return "mileage_adjustment_percent must be between 0 and 100"
+	}
+	if minResidual < 0 || minResidual >= 100 {
+		return "min_residual_percent must be between 0 and 100"
+	}
+	if expectedMileage <= 0 || expectedMileage > TradeInMileageMax {
+		return "invalid expected_annual_mileage"
+	}
+	return ""
+}
+
+// TradeInNotes validates optional appraiser notes.
+func TradeInNotes(notes *string) (*string, string) {
+	return optionalMultiline("notes", notes, TradeInNotesMax)
+}
+
+// TradeInAdjustment validates one manual correction (label and non-zero amount).
+func TradeInAdjustment(label string, amount float64) (string, string) {
+	l, msg := requiredSingleLine("adjustment label", label, TradeInAdjustmentLabel)
+	if msg != "" {
+		return "", msg
+	}
+	if amount == 0 || amount > ServicePriceMax || amount < -ServicePriceMax {
+		return "", "invalid adjustment amount"
+	}
+	return l, ""
+}
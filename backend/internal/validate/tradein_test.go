@@ -0,0 +1,27 @@
+package validate
+
+import "testing"
+
+func TestDepreciationRuleRates(t *testing.T) {
+	if msg := DepreciationRuleRates(20, 12, 1, 10, 15000); msg != "" {
+		t.Fatalf("unexpected %q", msg)
+	}
+	if msg := DepreciationRuleRates(100, 12, 1, 10, 15000); msg == "" {
+		t.Fatal("100% first-year depreciation should fail")
+	}
+	if msg := DepreciationRuleRates(20, 12, 1, 10, 0); msg == "" {
+		t.Fatal("zero expected mileage should fail")
+	}
+}
+
+func TestTradeInAdjustment(t *testing.T) {
+	if label, msg := TradeInAdjustment("  Bumper repair ", -15000); msg != "" || label != "Bumper repair" {
+		t.Fatalf("got %q msg %q", label, msg)
+	}
+	if _, msg := TradeInAdjustment("x", 0); msg == "" {
+		t.Fatal("zero amount should fail")
+	}
+	if _, msg := TradeInAdjustment(" ", 100); msg == "" {
+		t.Fatal("empty label should fail")
+	}
+}
@@ -0,0 +1,52 @@
+package validate
+
+import "strings"
+
+const (
+	// TransferCodeLength is the number of significant characters of a transfer code.
+	TransferCodeLength = 8
+	// TransferCodeAlphabet leaves out characters that are easy to misread (0/O, 1/I).
+	TransferCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	TransferReasonMax    = 1000
+)
+
+// TransferAppointmentPolicy validates how scheduled appointments are handled on transfer; empty
+// means cancel.
+func TransferAppointmentPolicy(policy string) (string, string) {
+	switch p := strings.TrimSpace(policy); p {
+	case "":
+		return "cancel", ""
+	case "cancel", "transfer":
+		return p, ""
+	}
+	return "", "appointment_policy must be cancel or transfer"
+}
+
+// TransferReason validates the audit reason of a staff transfer.
+func TransferReason(reason string) (string, string) {
+	return requiredSingleLine("reason", reason, TransferReasonMax)
+}
+
+// NormalizeTransferCode uppercases a transfer code and drops the separators customers tend to type.
+func NormalizeTransferCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// TransferCode returns an API error message if the code cannot be a transfer code.
+func TransferCode(code string) string {
+	c := NormalizeTransferCode(code)
+	if len(c) != TransferCodeLength {
+		return "code must be 8 characters"
+	}
+	for _, r := range c {
+		if !strings.ContainsRune(TransferCodeAlphabet, r) {
+			return "code contains invalid characters"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package validate
+
+import "testing"
+
+func TestTransferAppointmentPolicy(t *testing.T) {
+	if p, msg := TransferAppointmentPolicy(""); msg != "" || p != "cancel" {
+		t.Fatalf("empty policy: got %q %q, want cancel", p, msg)
+	}
+	if p, msg := TransferAppointmentPolicy(" transfer "); msg != "" || p != "transfer" {
+		t.Fatalf("transfer policy: got %q %q", p, msg)
+	}
+	if _, msg := TransferAppointmentPolicy("keep"); msg == "" {
+		t.Fatal("unknown policy should fail")
+	}
+}
+
+func TestTransferCode(t *testing.T) {
+	if got := NormalizeTransferCode(" abcd-ef23 "); got != "ABCDEF23" {
+		t.Fatalf("NormalizeTransferCode: got %q", got)
+	}
+	if msg := TransferCode("abcd-ef23"); msg != "" {
+		t.Fatalf("valid code rejected: %q", msg)
+	}
+	if msg := TransferCode("ABCDEF2"); msg == "" {
+		t.Fatal("short code should fail")
+	}
+	if msg := TransferCode("ABCDEF20"); msg == "" {
+		t.Fatal("code with 0 should fail")
+	}
+}
+
+func TestTransferReason(t *testing.T) {
+	if _, msg := TransferReason("  "); msg == "" {
+		t.Fatal("blank reason should fail")
+	}
+	if r, msg := TransferReason(" Договор купли-продажи "); msg != "" || r != "Договор купли-продажи" {
+		t.Fatalf("reason: got %q %q", r, msg)
+	}
+}
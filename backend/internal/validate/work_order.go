@@ -0,0 +1,64 @@
+package validate
+
+import "math"
+
+const (
+	OdometerMaxKm               = 2000000
+	WorkSummaryMaxRunes         = 4000
+	WorkLinesMax                = 100
+	WorkLineDescriptionMaxRunes = 200
+	WorkLinePartNumberMaxRunes  = 60
+	WorkLineQuantityMax         = 10000
+	WorkLineUnitPriceMax        = 10000000
+)
+
+// Odometer validates an odometer reading against the car's recorded mileage; readings cannot go
+// backwards.
+func Odometer(km, recorded int) string {
+	if km < 0 || km > OdometerMaxKm {
+		return "odometer_km must be between 0 and " + itoa(OdometerMaxKm)
+	}
+	if km < recorded {
+		return "odometer_km cannot be lower than the car's recorded mileage (" + itoa(recorded) + " km)"
+	}
+	return ""
+}
+
+// WorkSummary validates the optional free-text description of the work performed.
+func WorkSummary(s *string) (*string, string) {
+	return optionalMultiline("work_summary", s, WorkSummaryMaxRunes)
+}
+
+// WorkLineKind validates a work line kind (labour or part).
+func WorkLineKind(kind string) string {
+	if kind != "labour" && kind != "part" {
+		return "kind must be labour or part"
+	}
+	return ""
+}
+
+func WorkLineDescription(s string) (string, string) {
+	return requiredSingleLine("description", s, WorkLineDescriptionMaxRunes)
+}
+
+func WorkLinePartNumber(s *string) (*string, string) {
+	return optionalSingleLine("part_number", s, WorkLinePartNumberMaxRunes)
+}
+
+// WorkLineQuantity validates a positive quantity (hours or pieces), rounded to hundredths as stored.
+func WorkLineQuantity(q float64) (float64, string) {
+	q = math.Round(q*100) / 100
+	if math.IsNaN(q) || q <= 0 || q > WorkLineQuantityMax {
+		return 0, "quantity must be greater than 0 and at most " + itoa(WorkLineQuantityMax)
+	}
+	return q, ""
+}
+
+// WorkLineUnitPrice validates a non-negative unit price, rounded to kopecks as stored.
+func WorkLineUnitPrice(p float64) (float64, string) {
+	p = math.Round(p*100) / 100
+	if math.IsNaN(p) || p < 0 || p > WorkLineUnitPriceMax {
+		return 0, "unit_price must be between 0 and " + itoa(WorkLineUnitPriceMax)
+	}
+	return p, ""
+}
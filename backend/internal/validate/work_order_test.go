@@ -0,0 +1,51 @@
+package validate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOdometer(t *testing.T) {
+	cases := []struct {
+		km, recorded int
+		ok           bool
+	}{
+		{15000, 12000, true},
+		{12000, 12000, true},
+		{11999, 12000, false},
+		{-1, 0, false},
+		{OdometerMaxKm + 1, 0, false},
+	}
+	for _, c := range cases {
+		if got := Odometer(c.km, c.recorded) == ""; got != c.ok {
+			t.Errorf("Odometer(%d, %d) ok = %v, want %v", c.km, c.recorded, got, c.ok)
+		}
+	}
+}
+
+func TestWorkLineQuantityAndPrice(t *testing.T) {
+	if q, msg := WorkLineQuantity(1.505); msg != "" || q != 1.51 {
+		t.Errorf("quantity rounds to hundredths: %v %q", q, msg)
+	}
+	for _, q := range []float64{0, -1, 0.004, WorkLineQuantityMax + 1, math.NaN()} {
+		if _, msg := WorkLineQuantity(q); msg == "" {
+			t.Errorf("quantity %v should fail", q)
+		}
+	}
+	if p, msg := WorkLineUnitPrice(0); msg != "" || p != 0 {
+		t.Errorf("free line is allowed: %v %q", p, msg)
+	}
+	for _, p := range []float64{-0.01, WorkLineUnitPriceMax + 1, math.NaN()} {
+		if _, msg := WorkLineUnitPrice(p); msg == "" {
+			t.Errorf("price %v should fail", p)
+		}
+	}
+}
+
+func TestWorkLineKind(t *testing.T) {
+	for kind, ok := range map[string]bool{"labour": true, "part": true, "": false, "Labour": false} {
+		if got := WorkLineKind(kind) == ""; got != ok {
+			t.Errorf("WorkLineKind(%q) ok = %v, want %v", kind, got, ok)
+		}
+	}
+}
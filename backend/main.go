@@ -14,6 +14,9 @@ import (
 	"github.com/carkeeper/backend/database"
 	"github.com/carkeeper/backend/internal/app"
 	"github.com/carkeeper/backend/internal/handler"
+	"github.com/carkeeper/backend/internal/jobs"
+	"github.com/carkeeper/backend/internal/model"
+	"github.com/carkeeper/backend/internal/payment"
 	"github.com/carkeeper/backend/internal/repository"
 	"github.com/carkeeper/backend/internal/service"
 	"github.com/carkeeper/backend/internal/storage"
@@ -40,9 +43,12 @@ func main() {
 		log.Fatalf("Document storage: %v", err)
 	}
 
+	// config.validate only accepts the built-in fake gateway for now.
+	gateway := payment.NewFake(cfg.Payment.WebhookSecret, cfg.Payment.FakeAutoConfirm)
+
 	repos := repository.New(db)
 	service.BootstrapAuthz(context.Background(), repos)
-	services := service.New(repos, cfg, fileStore)
+	services := service.New(repos, cfg, fileStore, gateway)
 	handlers := handler.New(services, cfg)
 	router := app.NewRouter(handlers, cfg, db)
 
@@ -54,6 +60,50 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	waitJobs := func() {}
+	if cfg.Lifecycle.JobsEnabled {
+		waitJobs = jobs.Start(jobsCtx, jobs.Task{
+			Name:     "configuration-lifecycle",
+			Interval: cfg.Lifecycle.JobInterval,
+			Run: func(ctx context.Context) error {
+				run, err := services.Configurator.RunLifecycle(ctx, time.Now())
+				if err == nil && run != (model.ConfigurationLifecycleRun{}) {
+					slog.Info("configuration lifecycle", "expired", run.DraftsExpired,
+						"draft_warnings", run.DraftWarnings, "price_lock_warnings", run.PriceLockWarnings)
+				}
+				return err
+			},
+		}, jobs.Task{
+			Name:     "reports-refresh",
+			Interval: cfg.Reports.RefreshInterval,
+			Run: func(ctx context.Context) error {
+				_, err := services.Report.Refresh(ctx)
+				return err
+			},
+		}, jobs.Task{
+			Name:     "maintenance-reminders",
+			Interval: cfg.Maintenance.ReminderInterval,
+			Run: func(ctx context.Context) error {
+				n, err := services.Maintenance.RunReminders(ctx, time.Now())
+				if n > 0 {
+					slog.Info("maintenance reminders", "created", n)
+				}
+				return err
+			},
+		}, jobs.Task{
+			Name:     "service-campaign-matching",
+			Interval: cfg.Campaigns.MatchInterval,
+			Run: func(ctx context.Context) error {
+				n, err := services.ServiceCampaign.RunMatching(ctx)
+				if n > 0 {
+					slog.Info("service campaigns", "matched", n)
+				}
+				return err
+			},
+		})
+	}
+
 	go func() {
 		slog.Info("server starting", "addr", cfg.Server.Address())
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -67,6 +117,9 @@ func main() {
 
 	slog.Info("shutting down server")
 
+	stopJobs()
+	waitJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
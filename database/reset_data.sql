@@ -7,13 +7,44 @@
 BEGIN;
 
 TRUNCATE TABLE
+    report_refreshes,
+    message_reads,
+    message_attachments,
+    messages,
+    notifications,
+    refunds,
+    payments,
+    invoices,
     documents,
+    tradein_offers,
+    tradein_depreciation_rules,
+    order_finance_plans,
+    finance_products,
+    order_promotions,
+    promotion_codes,
+    promotions,
+    delivery_appointments,
+    service_campaign_vehicles,
+    service_campaign_service_types,
+    service_campaigns,
+    vehicle_transfers,
+    vehicle_service_documents,
+    vehicle_service_records,
+    maintenance_reminders,
+    maintenance_plan_service_types,
+    maintenance_plans,
     service_appointment_types,
+    service_work_lines,
     service_appointments,
+    service_resources,
+    configuration_shares,
     configuration_options,
+    order_events,
     orders,
     configurations,
     user_cars,
+    organization_members,
+    organizations,
     news,
     trim_options,
     trims,
@@ -21,6 +52,8 @@ TRUNCATE TABLE
     models,
     brands,
     service_types,
+    branch_calendar_exceptions,
+    branch_weekly_hours,
     branches,
     users,
     colors,
@@ -30,4 +63,9 @@ TRUNCATE TABLE
     drive_types
 RESTART IDENTITY CASCADE;
 
+-- Аналитика пересчитывается по пустым таблицам
+REFRESH MATERIALIZED VIEW mv_order_stats_daily;
+REFRESH MATERIALIZED VIEW mv_sales_funnel_daily;
+REFRESH MATERIALIZED VIEW mv_service_stats_daily;
+
 COMMIT;
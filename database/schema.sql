@@ -63,7 +63,19 @@ INSERT INTO permissions (permission_code, description) VALUES
     ('admin.order_statuses', 'CRUD справочника статусов заказа'),
     ('admin.roles_view', 'Просмотр справочника ролей (admin API)'),
     ('catalog.manage', 'CRUD справочника каталога (бренды и др.)'),
-    ('service.manage', 'Управление услугами ТО и филиалами');
+    ('service.manage', 'Управление услугами ТО и филиалами'),
+    ('promotions.manage', 'Управление акциями и промокодами'),
+    ('finance.manage', 'Управление кредитными и лизинговыми программами'),
+    ('tradein.manage', 'Управление моделью амортизации trade-in'),
+    ('tradein.appraise', 'Оценка автомобилей клиентов в trade-in'),
+    ('assignments.claim', 'Взять заказ или запись на ТО в работу'),
+    ('assignments.manage', 'Назначение ответственных, автоназначение и загрузка менеджеров'),
+    ('payments.manage', 'Выставление счетов по заказам и учёт оплат'),
+    ('payments.refund', 'Возврат платежей клиентам'),
+    ('documents.generate', 'Формирование PDF-документов по шаблонам'),
+    ('reports.view', 'Аналитические отчёты по продажам и сервису'),
+    ('appointments.manage', 'Приёмка автомобиля, заказ-наряд и закрытие записей на ТО'),
+    ('garage.transfer', 'Принудительная передача автомобиля другому клиенту');
 
 INSERT INTO role_permissions (role_code, permission_code) VALUES
     ('manager', 'orders.view_any'),
@@ -75,6 +87,10 @@ INSERT INTO role_permissions (role_code, permission_code) VALUES
     ('manager', 'documents.view_any'),
     ('manager', 'news.manage'),
     ('manager', 'service.manage'),
+    ('manager', 'promotions.manage'),
+    ('manager', 'finance.manage'),
+    ('manager', 'tradein.manage'),
+    ('manager', 'tradein.appraise'),
     ('service_advisor', 'orders.view_any'),
     ('service_advisor', 'orders.manage_status'),
     ('service_advisor', 'configurations.view_any'),
@@ -82,6 +98,7 @@ INSERT INTO role_permissions (role_code, permission_code) VALUES
     ('service_advisor', 'garage.view_any'),
     ('service_advisor', 'documents.view_any'),
     ('service_advisor', 'service.manage'),
+    ('service_advisor', 'tradein.appraise'),
     ('admin', 'orders.view_any'),
     ('admin', 'orders.manage_status'),
     ('admin', 'configurations.view_any'),
@@ -93,7 +110,28 @@ INSERT INTO role_permissions (role_code, permission_code) VALUES
     ('admin', 'admin.order_statuses'),
     ('admin', 'admin.roles_view'),
     ('admin', 'catalog.manage'),
-    ('admin', 'service.manage');
+    ('admin', 'service.manage'),
+    ('admin', 'promotions.manage'),
+    ('admin', 'finance.manage'),
+    ('admin', 'tradein.manage'),
+    ('admin', 'tradein.appraise'),
+    ('manager', 'assignments.claim'),
+    ('manager', 'assignments.manage'),
+    ('service_advisor', 'assignments.claim'),
+    ('admin', 'assignments.claim'),
+    ('admin', 'assignments.manage'),
+    ('manager', 'payments.manage'),
+    ('admin', 'payments.manage'),
+    ('admin', 'payments.refund'),
+    ('manager', 'documents.generate'),
+    ('service_advisor', 'documents.generate'),
+    ('admin', 'documents.generate'),
+    ('admin', 'reports.view'),
+    ('manager', 'appointments.manage'),
+    ('service_advisor', 'appointments.manage'),
+    ('admin', 'appointments.manage'),
+    ('manager', 'garage.transfer'),
+    ('admin', 'garage.transfer');
 
 -- Users table
 CREATE TABLE users (
@@ -129,6 +167,9 @@ CREATE TABLE branches (
     workday_end_minutes   integer NOT NULL DEFAULT 1080 CHECK (workday_end_minutes > 0 AND workday_end_minutes <= 1440),
     slot_step_minutes     integer NOT NULL DEFAULT 30 CHECK (slot_step_minutes > 0 AND slot_step_minutes <= 180),
     concurrent_bays       integer NOT NULL DEFAULT 2 CHECK (concurrent_bays >= 1 AND concurrent_bays <= 32),
+    -- Зоны выдачи новых автомобилей (0 — филиал не выдаёт автомобили) и длительность выдачи
+    handover_bays             integer NOT NULL DEFAULT 1 CHECK (handover_bays >= 0 AND handover_bays <= 16),
+    handover_duration_minutes integer NOT NULL DEFAULT 60 CHECK (handover_duration_minutes >= 15 AND handover_duration_minutes <= 240),
     created_at  timestamptz NOT NULL DEFAULT now(),
     updated_at  timestamptz NOT NULL DEFAULT now(),
     CHECK (workday_start_minutes < workday_end_minutes)
@@ -141,6 +182,67 @@ BEFORE UPDATE ON branches
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- Недельный график филиала: строка на каждый рабочий день недели (0 — воскресенье, как EXTRACT(DOW)).
+-- Дня нет в графике — филиал в этот день закрыт. Время — минуты от полуночи по часовому поясу филиала.
+CREATE TABLE branch_weekly_hours (
+    branch_id           uuid NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    weekday             smallint NOT NULL CHECK (weekday >= 0 AND weekday <= 6),
+    open_minutes        integer NOT NULL CHECK (open_minutes >= 0 AND open_minutes < 1440),
+    close_minutes       integer NOT NULL CHECK (close_minutes > 0 AND close_minutes <= 1440),
+    -- Перерыв (обед): запись на ТО и выдачу не пересекает его
+    break_start_minutes integer,
+    break_end_minutes   integer,
+    PRIMARY KEY (branch_id, weekday),
+    CHECK (open_minutes < close_minutes),
+    CHECK ((break_start_minutes IS NULL) = (break_end_minutes IS NULL)),
+    CHECK (break_start_minutes IS NULL OR
+        (open_minutes < break_start_minutes AND break_start_minutes < break_end_minutes AND break_end_minutes < close_minutes))
+);
+
+-- Новый филиал получает график пн–сб по workday_start_minutes/workday_end_minutes
+CREATE OR REPLACE FUNCTION branch_default_weekly_hours()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO branch_weekly_hours (branch_id, weekday, open_minutes, close_minutes)
+    SELECT NEW.branch_id, d, NEW.workday_start_minutes, NEW.workday_end_minutes
+    FROM generate_series(1, 6) AS d;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_branches_default_weekly_hours
+AFTER INSERT ON branches
+FOR EACH ROW
+EXECUTE FUNCTION branch_default_weekly_hours();
+
+-- Исключения календаря филиала на конкретные даты: праздник (закрыт), сокращённый день,
+-- дополнительный рабочий день. Заменяют недельный график на эту дату целиком.
+CREATE TABLE branch_calendar_exceptions (
+    exception_id        uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    branch_id           uuid NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    day                 date NOT NULL,
+    is_closed           boolean NOT NULL,
+    open_minutes        integer CHECK (open_minutes >= 0 AND open_minutes < 1440),
+    close_minutes       integer CHECK (close_minutes > 0 AND close_minutes <= 1440),
+    break_start_minutes integer,
+    break_end_minutes   integer,
+    note                varchar(200),
+    created_by          uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at          timestamptz NOT NULL DEFAULT now(),
+    updated_at          timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (branch_id, day),
+    CHECK (is_closed OR (open_minutes IS NOT NULL AND close_minutes IS NOT NULL AND open_minutes < close_minutes)),
+    CHECK (NOT is_closed OR (open_minutes IS NULL AND close_minutes IS NULL AND break_start_minutes IS NULL)),
+    CHECK ((break_start_minutes IS NULL) = (break_end_minutes IS NULL)),
+    CHECK (break_start_minutes IS NULL OR
+        (open_minutes < break_start_minutes AND break_start_minutes < break_end_minutes AND break_end_minutes < close_minutes))
+);
+
+CREATE TRIGGER trg_branch_calendar_exceptions_updated_at
+BEFORE UPDATE ON branch_calendar_exceptions
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 -- Brands table
 CREATE TABLE brands (
     brand_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -269,15 +371,25 @@ CREATE TABLE configurations (
     color_id         uuid NOT NULL REFERENCES colors(color_id) ON DELETE RESTRICT,
     status           varchar(30) NOT NULL DEFAULT 'draft',
     total_price      numeric(12,2) NOT NULL CHECK (total_price >= 0),
+    -- Срок жизни черновика; NULL у старых строк = updated_at + CONFIG_DRAFT_EXPIRY_DAYS
+    expires_at         timestamptz,
+    -- Фиксация цены при подтверждении: цены комплектации и цвета на момент confirm
+    price_locked_until timestamptz,
+    locked_trim_price  numeric(12,2) CHECK (locked_trim_price >= 0),
+    locked_color_price numeric(12,2),
+    -- Когда клиенту отправлено предупреждение об истечении черновика или фиксации цены
+    expiry_warned_at   timestamptz,
     created_at       timestamptz NOT NULL DEFAULT now(),
     updated_at       timestamptz NOT NULL DEFAULT now(),
-    CHECK (status IN ('draft','confirmed','ordered','cancelled','purchased'))
+    CHECK (status IN ('draft','confirmed','ordered','cancelled','purchased','expired'))
 );
 
 CREATE INDEX idx_configurations_user_id ON configurations(user_id);
 CREATE INDEX idx_configurations_trim_id ON configurations(trim_id);
 CREATE INDEX idx_configurations_status ON configurations(status);
 CREATE INDEX idx_configurations_created_at ON configurations(created_at);
+CREATE INDEX idx_configurations_expires_at ON configurations(expires_at) WHERE status = 'draft';
+CREATE INDEX idx_configurations_price_locked_until ON configurations(price_locked_until) WHERE status = 'confirmed';
 
 CREATE TRIGGER trg_configurations_updated_at
 BEFORE UPDATE ON configurations
@@ -288,11 +400,44 @@ EXECUTE FUNCTION set_updated_at();
 CREATE TABLE configuration_options (
     configuration_id uuid NOT NULL REFERENCES configurations(configuration_id) ON DELETE CASCADE,
     option_id        uuid NOT NULL REFERENCES options(option_id) ON DELETE RESTRICT,
+    -- Цена опции, зафиксированная при подтверждении конфигурации
+    locked_price     numeric(12,2) CHECK (locked_price >= 0),
     PRIMARY KEY (configuration_id, option_id)
 );
 
 CREATE INDEX idx_configuration_options_option_id ON configuration_options(option_id);
 
+-- Уведомления пользователей (предупреждения об истечении черновиков, фиксации цены и т.п.)
+CREATE TABLE notifications (
+    notification_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id         uuid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    kind            varchar(60) NOT NULL,
+    title           varchar(200) NOT NULL,
+    body            text NOT NULL,
+    entity_type     varchar(40),
+    entity_id       uuid,
+    read_at         timestamptz,
+    created_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Configuration share links (публичная ссылка только для чтения; отзывается и истекает)
+CREATE TABLE configuration_shares (
+    share_id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    configuration_id uuid NOT NULL REFERENCES configurations(configuration_id) ON DELETE CASCADE,
+    token            varchar(64) NOT NULL UNIQUE,
+    created_by       uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    expires_at       timestamptz NOT NULL,
+    revoked_at       timestamptz,
+    view_count       integer NOT NULL DEFAULT 0 CHECK (view_count >= 0),
+    last_viewed_at   timestamptz,
+    created_at       timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_configuration_shares_configuration_id ON configuration_shares(configuration_id);
+
 -- Orders table
 -- Order status dictionary (managed by admin; orders reference stable code)
 CREATE TABLE order_status_definitions (
@@ -322,6 +467,37 @@ INSERT INTO order_status_definitions (code, customer_label_ru, admin_label_ru, s
     ('completed', 'Выполнен', 'Завершён', 40, true),
     ('cancelled', 'Отменён', 'Отменён', 50, true);
 
+-- Order status transitions (граф переходов: кто и куда может перевести заказ)
+CREATE TABLE order_status_transitions (
+    transition_id        uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_status          varchar(32) NOT NULL REFERENCES order_status_definitions(code) ON UPDATE CASCADE ON DELETE CASCADE,
+    to_status            varchar(32) NOT NULL REFERENCES order_status_definitions(code) ON UPDATE CASCADE ON DELETE CASCADE,
+    -- Право сотрудника, достаточное для перехода
+    permission_code      varchar(64) NOT NULL DEFAULT 'orders.manage_status' REFERENCES permissions(permission_code) ON DELETE RESTRICT,
+    -- Владелец заказа может выполнить переход сам
+    customer_may_trigger boolean NOT NULL DEFAULT false,
+    created_at           timestamptz NOT NULL DEFAULT now(),
+    updated_at           timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (from_status, to_status),
+    CHECK (from_status <> to_status)
+);
+
+CREATE INDEX idx_order_status_transitions_to ON order_status_transitions(to_status);
+
+CREATE TRIGGER trg_order_status_transitions_updated_at
+BEFORE UPDATE ON order_status_transitions
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO order_status_transitions (from_status, to_status, customer_may_trigger) VALUES
+    ('pending', 'approved', false),
+    ('pending', 'cancelled', true),
+    ('approved', 'pending', false),
+    ('approved', 'paid', false),
+    ('approved', 'cancelled', true),
+    ('paid', 'completed', false),
+    ('paid', 'cancelled', false);
+
 CREATE TABLE orders (
     order_id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id          uuid NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
@@ -329,6 +505,8 @@ CREATE TABLE orders (
     manager_id       uuid REFERENCES users(user_id) ON DELETE SET NULL,
     status           varchar(32) NOT NULL DEFAULT 'pending' REFERENCES order_status_definitions(code) ON UPDATE CASCADE ON DELETE RESTRICT,
     final_price      numeric(12,2) NOT NULL CHECK (final_price >= 0),
+    discount_total   numeric(12,2) NOT NULL DEFAULT 0 CHECK (discount_total >= 0),
+    tradein_credit   numeric(12,2) NOT NULL DEFAULT 0 CHECK (tradein_credit >= 0 AND tradein_credit <= final_price),
     created_at       timestamptz NOT NULL DEFAULT now(),
     updated_at       timestamptz NOT NULL DEFAULT now()
 );
@@ -344,6 +522,234 @@ BEFORE UPDATE ON orders
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- Order events (история заказа: создание и смены статуса; пишется в той же транзакции)
+CREATE TABLE order_events (
+    event_id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id         uuid NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    actor_id         uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    event_type       varchar(32) NOT NULL CHECK (event_type IN ('created','status_changed')),
+    from_status      varchar(32),
+    to_status        varchar(32) NOT NULL,
+    reason           varchar(200),
+    comment          text,
+    -- Комментарий только для сотрудников: клиент видит событие без него
+    comment_internal boolean NOT NULL DEFAULT false,
+    created_at       timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_events_order_id ON order_events(order_id, created_at);
+
+-- Promotions (акции: скидка по бренду/модели/комплектации/опции/сегменту клиента)
+CREATE TABLE promotions (
+    promotion_id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name              varchar(200) NOT NULL,
+    description       text,
+    discount_type     varchar(16) NOT NULL CHECK (discount_type IN ('percent','fixed')),
+    discount_value    numeric(12,2) NOT NULL CHECK (discount_value > 0),
+    brand_id          uuid REFERENCES brands(brand_id) ON DELETE CASCADE,
+    model_id          uuid REFERENCES models(model_id) ON DELETE CASCADE,
+    trim_id           uuid REFERENCES trims(trim_id) ON DELETE CASCADE,
+    option_id         uuid REFERENCES options(option_id) ON DELETE CASCADE,
+    vehicle_segment   varchar(100),
+    customer_segment  varchar(32) CHECK (customer_segment IS NULL OR customer_segment IN ('new_customer','returning_customer')),
+    valid_from        timestamptz,
+    valid_to          timestamptz,
+    max_uses          integer CHECK (max_uses IS NULL OR max_uses > 0),
+    max_uses_per_user integer CHECK (max_uses_per_user IS NULL OR max_uses_per_user > 0),
+    uses_count        integer NOT NULL DEFAULT 0 CHECK (uses_count >= 0),
+    is_stackable      boolean NOT NULL DEFAULT false,
+    requires_code     boolean NOT NULL DEFAULT false,
+    priority          integer NOT NULL DEFAULT 0,
+    is_active         boolean NOT NULL DEFAULT true,
+    created_at        timestamptz NOT NULL DEFAULT now(),
+    updated_at        timestamptz NOT NULL DEFAULT now(),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CHECK (valid_from IS NULL OR valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX idx_promotions_active_window ON promotions (is_active, valid_from, valid_to);
+
+CREATE TRIGGER trg_promotions_updated_at
+BEFORE UPDATE ON promotions
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE promotion_codes (
+    promotion_code_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id      uuid NOT NULL REFERENCES promotions(promotion_id) ON DELETE CASCADE,
+    code              varchar(40) NOT NULL UNIQUE,
+    is_single_use     boolean NOT NULL DEFAULT true,
+    redeemed_by       uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    redeemed_at       timestamptz,
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_promotion_codes_promotion_id ON promotion_codes(promotion_id);
+
+-- Applied promotions snapshot (значения копируются на момент заказа)
+CREATE TABLE order_promotions (
+    order_id          uuid NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    promotion_id      uuid REFERENCES promotions(promotion_id) ON DELETE SET NULL,
+    promotion_code_id uuid REFERENCES promotion_codes(promotion_code_id) ON DELETE SET NULL,
+    promotion_name    varchar(200) NOT NULL,
+    code              varchar(40),
+    discount_type     varchar(16) NOT NULL,
+    discount_value    numeric(12,2) NOT NULL,
+    discount_amount   numeric(12,2) NOT NULL CHECK (discount_amount >= 0),
+    created_at        timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_promotions_order_id ON order_promotions(order_id);
+CREATE INDEX idx_order_promotions_promotion_id ON order_promotions(promotion_id);
+
+-- Finance products (кредитные и лизинговые программы банков-партнёров)
+CREATE TABLE finance_products (
+    finance_product_id       uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                     varchar(200) NOT NULL,
+    provider                 varchar(200) NOT NULL,
+    product_type             varchar(16) NOT NULL DEFAULT 'loan' CHECK (product_type IN ('loan','lease')),
+    description              text,
+    annual_rate              numeric(5,2) NOT NULL CHECK (annual_rate >= 0 AND annual_rate <= 100),
+    min_term_months          integer NOT NULL CHECK (min_term_months > 0),
+    max_term_months          integer NOT NULL CHECK (max_term_months <= 360),
+    min_down_payment_percent numeric(5,2) NOT NULL DEFAULT 0 CHECK (min_down_payment_percent >= 0 AND min_down_payment_percent < 100),
+    max_down_payment_percent numeric(5,2) NOT NULL DEFAULT 90 CHECK (max_down_payment_percent < 100),
+    balloon_percent          numeric(5,2) NOT NULL DEFAULT 0 CHECK (balloon_percent >= 0 AND balloon_percent < 100),
+    min_amount               numeric(12,2) CHECK (min_amount IS NULL OR min_amount >= 0),
+    max_amount               numeric(12,2) CHECK (max_amount IS NULL OR max_amount > 0),
+    is_active                boolean NOT NULL DEFAULT true,
+    created_at               timestamptz NOT NULL DEFAULT now(),
+    updated_at               timestamptz NOT NULL DEFAULT now(),
+    CHECK (max_term_months >= min_term_months),
+    CHECK (max_down_payment_percent >= min_down_payment_percent),
+    CHECK (min_amount IS NULL OR max_amount IS NULL OR max_amount >= min_amount)
+);
+
+CREATE TRIGGER trg_finance_products_updated_at
+BEFORE UPDATE ON finance_products
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Chosen finance plan snapshot (условия копируются на момент заказа)
+CREATE TABLE order_finance_plans (
+    order_id           uuid PRIMARY KEY REFERENCES orders(order_id) ON DELETE CASCADE,
+    finance_product_id uuid REFERENCES finance_products(finance_product_id) ON DELETE SET NULL,
+    product_name       varchar(200) NOT NULL,
+    provider           varchar(200) NOT NULL,
+    product_type       varchar(16) NOT NULL,
+    schedule_type      varchar(16) NOT NULL CHECK (schedule_type IN ('annuity','differentiated')),
+    annual_rate        numeric(5,2) NOT NULL,
+    term_months        integer NOT NULL CHECK (term_months > 0),
+    vehicle_price      numeric(12,2) NOT NULL CHECK (vehicle_price >= 0),
+    down_payment       numeric(12,2) NOT NULL CHECK (down_payment >= 0),
+    principal          numeric(12,2) NOT NULL CHECK (principal >= 0),
+    balloon_payment    numeric(12,2) NOT NULL DEFAULT 0 CHECK (balloon_payment >= 0),
+    first_payment      numeric(12,2) NOT NULL,
+    last_payment       numeric(12,2) NOT NULL,
+    total_payment      numeric(12,2) NOT NULL,
+    overpayment        numeric(12,2) NOT NULL,
+    created_at         timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_finance_plans_product_id ON order_finance_plans(finance_product_id);
+
+-- Invoices (счета по заказу: предоплата и остаток; оплачиваются частями)
+CREATE TABLE invoices (
+    invoice_id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id        uuid NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    kind            varchar(16) NOT NULL CHECK (kind IN ('deposit','balance')),
+    amount          numeric(12,2) NOT NULL CHECK (amount > 0),
+    -- Суммы успешных платежей и возвратов; пересчитываются в транзакции проведения
+    amount_paid     numeric(12,2) NOT NULL DEFAULT 0 CHECK (amount_paid >= 0),
+    amount_refunded numeric(12,2) NOT NULL DEFAULT 0 CHECK (amount_refunded >= 0 AND amount_refunded <= amount_paid),
+    status          varchar(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open','partially_paid','paid','refunded','cancelled')),
+    due_date        date,
+    created_by      uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_invoices_order_id ON invoices(order_id);
+-- Не более одного действующего счёта каждого вида на заказ
+CREATE UNIQUE INDEX uq_invoices_order_kind_active ON invoices(order_id, kind) WHERE status <> 'cancelled';
+
+CREATE TRIGGER trg_invoices_updated_at
+BEFORE UPDATE ON invoices
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Payments (платёж по счёту; через шлюз подтверждается асинхронно вебхуком)
+CREATE TABLE payments (
+    payment_id     uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    invoice_id     uuid NOT NULL REFERENCES invoices(invoice_id) ON DELETE CASCADE,
+    order_id       uuid NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    amount         numeric(12,2) NOT NULL CHECK (amount > 0),
+    method         varchar(20) NOT NULL CHECK (method IN ('online','cash','card_terminal','bank_transfer')),
+    status         varchar(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','succeeded','failed')),
+    -- Шлюз и его идентификатор платежа; NULL для оплат, внесённых сотрудником
+    gateway        varchar(32),
+    gateway_ref    varchar(128),
+    payment_url    text,
+    failure_reason varchar(500),
+    created_by     uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    paid_at        timestamptz,
+    created_at     timestamptz NOT NULL DEFAULT now(),
+    updated_at     timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (gateway, gateway_ref)
+);
+
+CREATE INDEX idx_payments_invoice_id ON payments(invoice_id);
+CREATE INDEX idx_payments_order_id ON payments(order_id);
+
+CREATE TRIGGER trg_payments_updated_at
+BEFORE UPDATE ON payments
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Refunds (возврат части или всего успешного платежа)
+CREATE TABLE refunds (
+    refund_id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id     uuid NOT NULL REFERENCES payments(payment_id) ON DELETE CASCADE,
+    amount         numeric(12,2) NOT NULL CHECK (amount > 0),
+    reason         varchar(500) NOT NULL,
+    status         varchar(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','succeeded','failed')),
+    gateway_ref    varchar(128),
+    failure_reason varchar(500),
+    created_by     uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    processed_at   timestamptz,
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_refunds_payment_id ON refunds(payment_id);
+CREATE UNIQUE INDEX uq_refunds_gateway_ref ON refunds(gateway_ref) WHERE gateway_ref IS NOT NULL;
+
+-- Организации: семья или компания с общим гаражом
+CREATE TABLE organizations (
+    organization_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name            varchar(200) NOT NULL,
+    kind            varchar(20) NOT NULL DEFAULT 'household' CHECK (kind IN ('household','company')),
+    created_by      uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_organizations_updated_at
+BEFORE UPDATE ON organizations
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Участники организации: owner — управляет составом и автомобилями, driver — записывает общие
+-- автомобили на ТО, viewer — только просмотр и отчёт о расходах
+CREATE TABLE organization_members (
+    organization_id uuid NOT NULL REFERENCES organizations(organization_id) ON DELETE CASCADE,
+    user_id         uuid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    role            varchar(20) NOT NULL CHECK (role IN ('owner','driver','viewer')),
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
 -- User cars table
 CREATE TABLE user_cars (
     user_car_id     uuid PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -354,13 +760,69 @@ CREATE TABLE user_cars (
     year            integer NOT NULL CHECK (year >= 1900 AND year <= EXTRACT(YEAR FROM CURRENT_DATE) + 1),
     current_mileage integer NOT NULL DEFAULT 0 CHECK (current_mileage >= 0),
     purchase_date   date CHECK (purchase_date IS NULL OR purchase_date <= CURRENT_DATE),
+    -- Заказ, по которому автомобиль передан клиенту (NULL — добавлен клиентом вручную)
+    order_id        uuid UNIQUE REFERENCES orders(order_id) ON DELETE SET NULL,
+    -- Организация, с которой автомобиль общий (NULL — только у владельца)
+    organization_id uuid REFERENCES organizations(organization_id) ON DELETE SET NULL,
     created_at      timestamptz NOT NULL DEFAULT now()
 );
 
 CREATE INDEX idx_user_cars_user_id ON user_cars(user_id);
+CREATE INDEX idx_user_cars_organization_id ON user_cars(organization_id) WHERE organization_id IS NOT NULL;
 CREATE INDEX idx_user_cars_trim_id ON user_cars(trim_id);
 CREATE INDEX idx_user_cars_vin ON user_cars(vin);
 
+-- Trade-in depreciation model (правила оценки: бренд и/или сегмент; NULL = любой)
+CREATE TABLE tradein_depreciation_rules (
+    rule_id                         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                            varchar(200) NOT NULL,
+    brand_id                        uuid REFERENCES brands(brand_id) ON DELETE CASCADE,
+    vehicle_segment                 varchar(100),
+    first_year_depreciation_percent numeric(5,2) NOT NULL CHECK (first_year_depreciation_percent >= 0 AND first_year_depreciation_percent < 100),
+    annual_depreciation_percent     numeric(5,2) NOT NULL CHECK (annual_depreciation_percent >= 0 AND annual_depreciation_percent < 100),
+    expected_annual_mileage         integer NOT NULL DEFAULT 15000 CHECK (expected_annual_mileage > 0),
+    mileage_adjustment_percent      numeric(5,2) NOT NULL DEFAULT 0 CHECK (mileage_adjustment_percent >= 0 AND mileage_adjustment_percent <= 100),
+    min_residual_percent            numeric(5,2) NOT NULL DEFAULT 10 CHECK (min_residual_percent >= 0 AND min_residual_percent < 100),
+    is_active                       boolean NOT NULL DEFAULT true,
+    created_at                      timestamptz NOT NULL DEFAULT now(),
+    updated_at                      timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_tradein_depreciation_rules_updated_at
+BEFORE UPDATE ON tradein_depreciation_rules
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Trade-in offers (оценка сотрудником; принятое предложение зачитывается в заказ)
+CREATE TABLE tradein_offers (
+    tradein_offer_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_car_id      uuid NOT NULL REFERENCES user_cars(user_car_id) ON DELETE CASCADE,
+    user_id          uuid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    appraised_by     uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    rule_id          uuid REFERENCES tradein_depreciation_rules(rule_id) ON DELETE SET NULL,
+    reference_price  numeric(12,2) NOT NULL CHECK (reference_price >= 0),
+    age_years        integer NOT NULL CHECK (age_years >= 0),
+    mileage          integer NOT NULL CHECK (mileage >= 0),
+    model_value      numeric(12,2) NOT NULL CHECK (model_value >= 0),
+    adjustments      jsonb NOT NULL DEFAULT '[]'::jsonb,
+    offer_amount     numeric(12,2) NOT NULL CHECK (offer_amount >= 0),
+    status           varchar(16) NOT NULL DEFAULT 'offered' CHECK (status IN ('offered','accepted','rejected','applied','cancelled')),
+    notes            text,
+    valid_until      timestamptz NOT NULL,
+    order_id         uuid UNIQUE REFERENCES orders(order_id) ON DELETE SET NULL,
+    decided_at       timestamptz,
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    updated_at       timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_tradein_offers_user_id ON tradein_offers(user_id);
+CREATE INDEX idx_tradein_offers_user_car_id ON tradein_offers(user_car_id);
+
+CREATE TRIGGER trg_tradein_offers_updated_at
+BEFORE UPDATE ON tradein_offers
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 -- Service types table (типы сервисных услуг)
 CREATE TABLE service_types (
     service_type_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -370,6 +832,9 @@ CREATE TABLE service_types (
     price          numeric(12,2) NOT NULL CHECK (price >= 0),
     duration_minutes integer CHECK (duration_minutes IS NULL OR duration_minutes > 0),
     is_available   boolean NOT NULL DEFAULT true,
+    -- Оборудование поста и навыки мастера, без которых услугу не выполнить (коды из service_resources.skills)
+    required_bay_skills        varchar(40)[] NOT NULL DEFAULT '{}',
+    required_technician_skills varchar(40)[] NOT NULL DEFAULT '{}',
     created_at     timestamptz NOT NULL DEFAULT now(),
     CHECK (category IN ('maintenance','repair','diagnostics','detailing','tires'))
 );
@@ -377,6 +842,31 @@ CREATE TABLE service_types (
 CREATE INDEX idx_service_types_category ON service_types(category);
 CREATE INDEX idx_service_types_is_available ON service_types(is_available);
 
+-- Service resources (посты и мастера филиала). Запись на ТО занимает один пост и, если в филиале
+-- заведены мастера, одного мастера. Филиал без постов считает ёмкость по concurrent_bays.
+CREATE TABLE service_resources (
+    resource_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    branch_id   uuid NOT NULL REFERENCES branches(branch_id) ON DELETE CASCADE,
+    kind        varchar(20) NOT NULL CHECK (kind IN ('bay','technician')),
+    name        varchar(100) NOT NULL,
+    -- Учётная запись сотрудника для мастера
+    user_id     uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    -- Оборудование поста (alignment, lift) или допуски мастера (diagnostics_cert)
+    skills      varchar(40)[] NOT NULL DEFAULT '{}',
+    is_active   boolean NOT NULL DEFAULT true,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (branch_id, kind, name),
+    CHECK (kind = 'technician' OR user_id IS NULL)
+);
+
+CREATE INDEX idx_service_resources_branch ON service_resources(branch_id, kind) WHERE is_active;
+
+CREATE TRIGGER trg_service_resources_updated_at
+BEFORE UPDATE ON service_resources
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 -- Service appointments table
 CREATE TABLE service_appointments (
     service_appointment_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -387,17 +877,54 @@ CREATE TABLE service_appointments (
     duration_minutes       integer NOT NULL CHECK (duration_minutes > 0),
     status                 varchar(30) NOT NULL DEFAULT 'scheduled',
     description            text,
+    -- Назначенные при записи пост и мастер (NULL — филиал без ресурсов или запись до их появления)
+    bay_id                 uuid REFERENCES service_resources(resource_id) ON DELETE SET NULL,
+    technician_id          uuid REFERENCES service_resources(resource_id) ON DELETE SET NULL,
+    -- Заказ-наряд: приёмка автомобиля, пробег при приёмке, фактически выполненные работы, закрытие
+    checked_in_at          timestamptz,
+    odometer_km            integer CHECK (odometer_km >= 0),
+    work_summary           text,
+    completed_at           timestamptz,
+    -- Канал записи: online — клиент сам, staff — сотрудник по звонку, walk_in — клиент приехал без записи
+    source                 varchar(20) NOT NULL DEFAULT 'online' CHECK (source IN ('online','staff','walk_in')),
+    created_by             uuid REFERENCES users(user_id) ON DELETE SET NULL,
     created_at             timestamptz NOT NULL DEFAULT now(),
     updated_at             timestamptz NOT NULL DEFAULT now(),
-    CHECK (status IN ('scheduled','completed','cancelled'))
+    CHECK (status IN ('scheduled','in_progress','completed','cancelled','no_show'))
 );
 
 CREATE INDEX idx_service_user_car_id ON service_appointments(user_car_id);
+CREATE INDEX idx_service_bay_id ON service_appointments(bay_id) WHERE bay_id IS NOT NULL;
+CREATE INDEX idx_service_technician_id ON service_appointments(technician_id) WHERE technician_id IS NOT NULL;
 CREATE INDEX idx_service_branch_id ON service_appointments(branch_id);
 CREATE INDEX idx_service_manager_id ON service_appointments(manager_id);
 CREATE INDEX idx_service_status ON service_appointments(status);
 CREATE INDEX idx_service_appointment_date ON service_appointments(appointment_date);
 
+-- Delivery appointments (запись на выдачу оплаченного автомобиля; календарь филиала, отдельные зоны выдачи)
+CREATE TABLE delivery_appointments (
+    delivery_id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id         uuid NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    branch_id        uuid NOT NULL REFERENCES branches(branch_id) ON DELETE RESTRICT,
+    scheduled_at     timestamptz NOT NULL,
+    duration_minutes integer NOT NULL CHECK (duration_minutes > 0),
+    status           varchar(30) NOT NULL DEFAULT 'scheduled',
+    notes            text,
+    created_by       uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    updated_at       timestamptz NOT NULL DEFAULT now(),
+    CHECK (status IN ('scheduled','completed','cancelled'))
+);
+
+-- Не более одной активной записи на выдачу по заказу
+CREATE UNIQUE INDEX uq_delivery_appointments_order_scheduled ON delivery_appointments(order_id) WHERE status = 'scheduled';
+CREATE INDEX idx_delivery_appointments_branch_time ON delivery_appointments(branch_id, scheduled_at);
+
+CREATE TRIGGER trg_delivery_appointments_updated_at
+BEFORE UPDATE ON delivery_appointments
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 -- Service appointment types junction table (связь записей на ТО с типами услуг)
 CREATE TABLE service_appointment_types (
     service_appointment_id uuid NOT NULL REFERENCES service_appointments(service_appointment_id) ON DELETE CASCADE,
@@ -407,17 +934,264 @@ CREATE TABLE service_appointment_types (
 
 CREATE INDEX idx_service_appointment_types_service_type_id ON service_appointment_types(service_type_id);
 
+-- Service work lines (строки заказа-наряда: работы и запчасти, фактически выполненные по записи)
+CREATE TABLE service_work_lines (
+    work_line_id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    service_appointment_id uuid NOT NULL REFERENCES service_appointments(service_appointment_id) ON DELETE CASCADE,
+    kind                   varchar(20) NOT NULL CHECK (kind IN ('labour','part')),
+    description            varchar(200) NOT NULL,
+    part_number            varchar(60),
+    quantity               numeric(10,2) NOT NULL CHECK (quantity > 0),
+    unit_price             numeric(12,2) NOT NULL CHECK (unit_price >= 0),
+    position               integer NOT NULL,
+    created_by             uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (service_appointment_id, position)
+);
+
 CREATE TRIGGER trg_service_appointments_updated_at
 BEFORE UPDATE ON service_appointments
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- Maintenance plans (регламент ТО модели или поколения: интервал по пробегу и/или по времени)
+CREATE TABLE maintenance_plans (
+    plan_id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Ровно одно из двух: регламент поколения заменяет регламент модели
+    model_id        uuid REFERENCES models(model_id) ON DELETE CASCADE,
+    generation_id   uuid REFERENCES generations(generation_id) ON DELETE CASCADE,
+    name            varchar(200) NOT NULL,
+    interval_km     integer CHECK (interval_km > 0),
+    interval_months integer CHECK (interval_months > 0),
+    is_active       boolean NOT NULL DEFAULT true,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    updated_at      timestamptz NOT NULL DEFAULT now(),
+    CHECK ((model_id IS NULL) <> (generation_id IS NULL)),
+    CHECK (interval_km IS NOT NULL OR interval_months IS NOT NULL)
+);
+
+CREATE INDEX idx_maintenance_plans_model_id ON maintenance_plans(model_id) WHERE model_id IS NOT NULL;
+CREATE INDEX idx_maintenance_plans_generation_id ON maintenance_plans(generation_id) WHERE generation_id IS NOT NULL;
+
+CREATE TRIGGER trg_maintenance_plans_updated_at
+BEFORE UPDATE ON maintenance_plans
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Услуги, входящие в регламент ТО (ими же предзаполняется запись по напоминанию)
+CREATE TABLE maintenance_plan_service_types (
+    plan_id         uuid NOT NULL REFERENCES maintenance_plans(plan_id) ON DELETE CASCADE,
+    service_type_id uuid NOT NULL REFERENCES service_types(service_type_id) ON DELETE CASCADE,
+    PRIMARY KEY (plan_id, service_type_id)
+);
+
+-- Напоминания о плановом ТО: одно на автомобиль, регламент и точку отсчёта (последнее ТО)
+CREATE TABLE maintenance_reminders (
+    reminder_id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_car_id            uuid NOT NULL REFERENCES user_cars(user_car_id) ON DELETE CASCADE,
+    plan_id                uuid NOT NULL REFERENCES maintenance_plans(plan_id) ON DELETE CASCADE,
+    -- Последняя закрытая запись по услугам регламента (NULL — отсчёт от покупки автомобиля)
+    last_appointment_id    uuid REFERENCES service_appointments(service_appointment_id) ON DELETE CASCADE,
+    due_date               date,
+    due_mileage            integer,
+    -- Предзаполненная запись на ТО: user_car_id, branch_id, service_type_ids, description
+    booking                jsonb NOT NULL,
+    status                 varchar(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open','dismissed','done')),
+    -- Запись, которой выполнено ТО по напоминанию
+    service_appointment_id uuid REFERENCES service_appointments(service_appointment_id) ON DELETE SET NULL,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    updated_at             timestamptz NOT NULL DEFAULT now(),
+    UNIQUE NULLS NOT DISTINCT (user_car_id, plan_id, last_appointment_id)
+);
+
+CREATE INDEX idx_maintenance_reminders_open ON maintenance_reminders(user_car_id) WHERE status = 'open';
+
+CREATE TRIGGER trg_maintenance_reminders_updated_at
+BEFORE UPDATE ON maintenance_reminders
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Сервисная книжка: неизменяемые записи о выполненном ТО по VIN (переживают удаление автомобиля из
+-- гаража и смену владельца). Создаётся при закрытии записи на ТО.
+CREATE TABLE vehicle_service_records (
+    record_id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    vin                    varchar(17) NOT NULL CHECK (LENGTH(vin) = 17),
+    -- Исходная запись на ТО и филиал (NULL после их удаления; данные записи сохранены ниже)
+    service_appointment_id uuid UNIQUE REFERENCES service_appointments(service_appointment_id) ON DELETE SET NULL,
+    branch_id              uuid REFERENCES branches(branch_id) ON DELETE SET NULL,
+    branch_name            varchar(200) NOT NULL,
+    vehicle_title          varchar(400) NOT NULL,
+    vehicle_year           integer NOT NULL,
+    performed_at           timestamptz NOT NULL,
+    odometer_km            integer CHECK (odometer_km >= 0),
+    work_summary           text,
+    -- Названия услуг записи и строки заказа-наряда на момент закрытия
+    services               text[] NOT NULL DEFAULT '{}',
+    lines                  jsonb NOT NULL DEFAULT '[]'::jsonb,
+    total                  numeric(12,2) NOT NULL CHECK (total >= 0),
+    -- Организация, с которой автомобиль был общим на момент визита (для отчёта о расходах)
+    organization_id        uuid REFERENCES organizations(organization_id) ON DELETE SET NULL,
+    created_at             timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_vehicle_service_records_vin ON vehicle_service_records(vin, performed_at);
+CREATE INDEX idx_vehicle_service_records_organization_id ON vehicle_service_records(organization_id, performed_at)
+    WHERE organization_id IS NOT NULL;
+
+-- Документы записи сервисной книжки (акт и др.): метаданные копируются, файл в хранилище не удаляется
+CREATE TABLE vehicle_service_documents (
+    record_id     uuid NOT NULL REFERENCES vehicle_service_records(record_id) ON DELETE RESTRICT,
+    -- Строка documents может быть удалена вместе с автомобилем или пользователем
+    document_id   uuid NOT NULL,
+    document_type varchar(50) NOT NULL,
+    file_path     text NOT NULL,
+    file_name     varchar(255),
+    file_size     bigint,
+    mime_type     varchar(100),
+    created_at    timestamptz NOT NULL,
+    PRIMARY KEY (record_id, document_id)
+);
+
+CREATE INDEX idx_vehicle_service_documents_file_path ON vehicle_service_documents(file_path);
+
+-- Записи сервисной книжки не изменяются и не удаляются; допускается только обнуление ссылок при
+-- удалении исходной записи на ТО, филиала или организации
+CREATE OR REPLACE FUNCTION forbid_service_history_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND TG_TABLE_NAME = 'vehicle_service_records'
+        AND (NEW.organization_id IS NULL OR NEW.organization_id = OLD.organization_id)
+        AND to_jsonb(NEW) - 'service_appointment_id' - 'branch_id' - 'organization_id'
+            = to_jsonb(OLD) - 'service_appointment_id' - 'branch_id' - 'organization_id' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'service history is immutable' USING ERRCODE = 'restrict_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_vehicle_service_records_immutable
+BEFORE UPDATE OR DELETE ON vehicle_service_records
+FOR EACH ROW
+EXECUTE FUNCTION forbid_service_history_changes();
+
+CREATE TRIGGER trg_vehicle_service_documents_immutable
+BEFORE UPDATE OR DELETE ON vehicle_service_documents
+FOR EACH ROW
+EXECUTE FUNCTION forbid_service_history_changes();
+
+-- Передача автомобиля другому клиенту: продавец получает одноразовый код, покупатель подтверждает
+-- передачу VIN и кодом. Сотрудник может передать автомобиль принудительно с указанием причины;
+-- строки не удаляются и служат журналом смены владельцев
+CREATE TABLE vehicle_transfers (
+    transfer_id            uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_car_id            uuid NOT NULL REFERENCES user_cars(user_car_id) ON DELETE CASCADE,
+    from_user_id           uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    -- Новый владелец (NULL, пока передача не подтверждена)
+    to_user_id             uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    -- SHA-256 кода подтверждения; сам код показывается продавцу один раз
+    code_hash              bytea,
+    status                 varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','accepted','cancelled','expired','forced')),
+    -- Будущие записи на ТО: cancel — отменяются, transfer — переходят к новому владельцу вместе с автомобилем
+    appointment_policy     varchar(20) NOT NULL DEFAULT 'cancel' CHECK (appointment_policy IN ('cancel','transfer')),
+    failed_attempts        integer NOT NULL DEFAULT 0 CHECK (failed_attempts >= 0),
+    cancelled_appointments integer NOT NULL DEFAULT 0 CHECK (cancelled_appointments >= 0),
+    expires_at             timestamptz,
+    -- Принудительная передача: сотрудник и причина
+    forced_by              uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    reason                 text,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    completed_at           timestamptz,
+    CHECK (status <> 'pending' OR (code_hash IS NOT NULL AND expires_at IS NOT NULL)),
+    CHECK (status <> 'forced' OR reason IS NOT NULL)
+);
+
+-- Не более одной ожидающей передачи на автомобиль
+CREATE UNIQUE INDEX uq_vehicle_transfers_pending ON vehicle_transfers(user_car_id) WHERE status = 'pending';
+CREATE INDEX idx_vehicle_transfers_user_car_id ON vehicle_transfers(user_car_id, created_at);
+
+-- Отзывные и сервисные кампании производителя. Затронутые автомобили задаются поколением,
+-- комплектацией, годами выпуска, шаблоном VIN и/или диапазоном VIN; должны совпасть все заданные
+-- условия (NULL — любое значение)
+CREATE TABLE service_campaigns (
+    campaign_id   uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Номер кампании производителя
+    code          varchar(50) NOT NULL UNIQUE,
+    kind          varchar(20) NOT NULL DEFAULT 'recall' CHECK (kind IN ('recall','service')),
+    title         varchar(200) NOT NULL,
+    description   text,
+    generation_id uuid REFERENCES generations(generation_id) ON DELETE CASCADE,
+    trim_id       uuid REFERENCES trims(trim_id) ON DELETE CASCADE,
+    year_from     integer CHECK (year_from >= 1900 AND year_from <= 2100),
+    year_to       integer CHECK (year_to >= 1900 AND year_to <= 2100),
+    -- Шаблон VIN: «?» — любой символ, «*» в конце — любой остаток
+    vin_pattern   varchar(17),
+    vin_from      varchar(17) CHECK (LENGTH(vin_from) = 17),
+    vin_to        varchar(17) CHECK (LENGTH(vin_to) = 17),
+    is_active     boolean NOT NULL DEFAULT true,
+    created_by    uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    updated_at    timestamptz NOT NULL DEFAULT now(),
+    CHECK (year_from IS NULL OR year_to IS NULL OR year_to >= year_from),
+    CHECK ((vin_from IS NULL) = (vin_to IS NULL) AND (vin_from IS NULL OR vin_from <= vin_to)),
+    CHECK (generation_id IS NOT NULL OR trim_id IS NOT NULL OR year_from IS NOT NULL OR year_to IS NOT NULL
+        OR vin_pattern IS NOT NULL OR vin_from IS NOT NULL)
+);
+
+CREATE TRIGGER trg_service_campaigns_updated_at
+BEFORE UPDATE ON service_campaigns
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Работы по кампании (ими же предзаполняется запись на ТО; закрытие записи с ними выполняет кампанию)
+CREATE TABLE service_campaign_service_types (
+    campaign_id     uuid NOT NULL REFERENCES service_campaigns(campaign_id) ON DELETE CASCADE,
+    service_type_id uuid NOT NULL REFERENCES service_types(service_type_id) ON DELETE CASCADE,
+    PRIMARY KEY (campaign_id, service_type_id)
+);
+
+-- Автомобили, попавшие под кампанию; владелец уведомляется при добавлении строки
+CREATE TABLE service_campaign_vehicles (
+    campaign_id            uuid NOT NULL REFERENCES service_campaigns(campaign_id) ON DELETE CASCADE,
+    user_car_id            uuid NOT NULL REFERENCES user_cars(user_car_id) ON DELETE CASCADE,
+    status                 varchar(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open','done')),
+    -- Запись на ТО, которой выполнены работы по кампании
+    service_appointment_id uuid REFERENCES service_appointments(service_appointment_id) ON DELETE SET NULL,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    completed_at           timestamptz,
+    PRIMARY KEY (campaign_id, user_car_id),
+    CHECK (status <> 'done' OR completed_at IS NOT NULL)
+);
+
+CREATE INDEX idx_service_campaign_vehicles_user_car_id ON service_campaign_vehicles(user_car_id);
+
+-- Assignment rules (автоназначение ответственного для заказов и записей на ТО)
+CREATE TABLE assignment_rules (
+    entity_type      varchar(20) PRIMARY KEY CHECK (entity_type IN ('order','appointment')),
+    strategy         varchar(20) NOT NULL DEFAULT 'manual' CHECK (strategy IN ('manual','round_robin','least_loaded')),
+    -- Из сотрудников с этой ролью выбирается ответственный
+    role_code        varchar(32) NOT NULL REFERENCES role_definitions(code) ON UPDATE CASCADE ON DELETE RESTRICT,
+    -- Последний назначенный (для round_robin)
+    last_assignee_id uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    updated_at       timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_assignment_rules_updated_at
+BEFORE UPDATE ON assignment_rules
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO assignment_rules (entity_type, strategy, role_code) VALUES
+    ('order', 'manual', 'manager'),
+    ('appointment', 'manual', 'service_advisor');
+
 -- Documents table
 CREATE TABLE documents (
     document_id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id             uuid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
     order_id            uuid REFERENCES orders(order_id) ON DELETE CASCADE,
     service_appointment_id uuid REFERENCES service_appointments(service_appointment_id) ON DELETE CASCADE,
+    -- Коммерческое предложение по конфигурации до оформления заказа
+    configuration_id    uuid REFERENCES configurations(configuration_id) ON DELETE CASCADE,
     document_type       varchar(50) NOT NULL,
     file_path           text NOT NULL,
     file_name           varchar(255),
@@ -425,14 +1199,49 @@ CREATE TABLE documents (
     mime_type           varchar(100),
     created_at          timestamptz NOT NULL DEFAULT now(),
     CHECK (document_type IN ('commercial_offer','order_contract','service_order','service_act')),
-    CHECK (order_id IS NOT NULL OR service_appointment_id IS NOT NULL)
+    CONSTRAINT documents_attachment_check
+        CHECK (order_id IS NOT NULL OR service_appointment_id IS NOT NULL OR configuration_id IS NOT NULL)
 );
 
 CREATE INDEX idx_documents_user_id ON documents(user_id);
 CREATE INDEX idx_documents_order_id ON documents(order_id);
 CREATE INDEX idx_documents_service_appointment_id ON documents(service_appointment_id);
+CREATE INDEX idx_documents_configuration_id ON documents(configuration_id) WHERE configuration_id IS NOT NULL;
 CREATE INDEX idx_documents_document_type ON documents(document_type);
 
+-- Messages (переписка по заказу или записи на ТО; внутренние заметки видны только сотрудникам)
+CREATE TABLE messages (
+    message_id             uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id               uuid REFERENCES orders(order_id) ON DELETE CASCADE,
+    service_appointment_id uuid REFERENCES service_appointments(service_appointment_id) ON DELETE CASCADE,
+    author_id              uuid REFERENCES users(user_id) ON DELETE SET NULL,
+    body                   text NOT NULL CHECK (length(btrim(body)) > 0),
+    is_internal            boolean NOT NULL DEFAULT false,
+    created_at             timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT messages_thread_check CHECK ((order_id IS NULL) <> (service_appointment_id IS NULL))
+);
+
+CREATE INDEX idx_messages_order_id ON messages(order_id, created_at) WHERE order_id IS NOT NULL;
+CREATE INDEX idx_messages_service_appointment_id ON messages(service_appointment_id, created_at)
+    WHERE service_appointment_id IS NOT NULL;
+
+-- Ссылки сообщения на документы той же переписки
+CREATE TABLE message_attachments (
+    message_id  uuid NOT NULL REFERENCES messages(message_id) ON DELETE CASCADE,
+    document_id uuid NOT NULL REFERENCES documents(document_id) ON DELETE CASCADE,
+    PRIMARY KEY (message_id, document_id)
+);
+
+-- Отметки о прочтении (собственные сообщения автора не отмечаются)
+CREATE TABLE message_reads (
+    message_id uuid NOT NULL REFERENCES messages(message_id) ON DELETE CASCADE,
+    user_id    uuid NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    read_at    timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX idx_message_reads_user_id ON message_reads(user_id);
+
 -- News table
 CREATE TABLE news (
     news_id      uuid PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -455,5 +1264,73 @@ BEFORE UPDATE ON news
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
-COMMIT;
+-- Аналитика: материализованные представления обновляются фоновой задачей (REFRESH ... CONCURRENTLY,
+-- поэтому у каждого есть уникальный индекс). Дни заказов и конфигураций — по UTC, записей на ТО — по
+-- часовому поясу филиала.
+
+-- Заказы по дням, моделям и менеджерам; оплаченным считается заказ, хоть раз переходивший в paid
+CREATE MATERIALIZED VIEW mv_order_stats_daily AS
+SELECT
+    (o.created_at AT TIME ZONE 'UTC')::date AS day,
+    b.brand_id,
+    m.model_id,
+    o.manager_id,
+    COUNT(*)::int AS orders_count,
+    SUM(o.final_price)::numeric(14,2) AS orders_value,
+    COUNT(*) FILTER (WHERE p.paid)::int AS paid_count,
+    COALESCE(SUM(o.final_price) FILTER (WHERE p.paid), 0)::numeric(14,2) AS paid_value,
+    COUNT(*) FILTER (WHERE o.status = 'cancelled')::int AS cancelled_count
+FROM orders o
+JOIN configurations c ON c.configuration_id = o.configuration_id
+JOIN trims t ON t.trim_id = c.trim_id
+JOIN generations g ON g.generation_id = t.generation_id
+JOIN models m ON m.model_id = g.model_id
+JOIN brands b ON b.brand_id = m.brand_id
+CROSS JOIN LATERAL (
+    SELECT o.status IN ('paid','completed')
+        OR EXISTS (SELECT 1 FROM order_events e WHERE e.order_id = o.order_id AND e.to_status = 'paid') AS paid
+) p
+GROUP BY 1, 2, 3, 4;
+
+CREATE UNIQUE INDEX idx_mv_order_stats_daily_key
+    ON mv_order_stats_daily(day, brand_id, model_id, manager_id) NULLS NOT DISTINCT;
+
+-- Воронка по дате создания конфигурации: конфигурация → подтверждение → заказ → оплата
+CREATE MATERIALIZED VIEW mv_sales_funnel_daily AS
+SELECT
+    (c.created_at AT TIME ZONE 'UTC')::date AS day,
+    COUNT(*)::int AS configurations,
+    COUNT(*) FILTER (WHERE c.price_locked_until IS NOT NULL
+        OR c.status IN ('confirmed','ordered','purchased') OR o.order_id IS NOT NULL)::int AS confirmed,
+    COUNT(o.order_id)::int AS ordered,
+    COUNT(*) FILTER (WHERE o.status IN ('paid','completed')
+        OR EXISTS (SELECT 1 FROM order_events e WHERE e.order_id = o.order_id AND e.to_status = 'paid'))::int AS paid
+FROM configurations c
+LEFT JOIN orders o ON o.configuration_id = c.configuration_id
+GROUP BY 1;
 
+CREATE UNIQUE INDEX idx_mv_sales_funnel_daily_day ON mv_sales_funnel_daily(day);
+
+-- Записи на ТО по дням и филиалам
+CREATE MATERIALIZED VIEW mv_service_stats_daily AS
+SELECT
+    (sa.appointment_date AT TIME ZONE br.timezone)::date AS day,
+    sa.branch_id,
+    COUNT(*)::int AS appointments,
+    COUNT(*) FILTER (WHERE sa.status = 'completed')::int AS completed,
+    COUNT(*) FILTER (WHERE sa.status = 'cancelled')::int AS cancelled,
+    COUNT(*) FILTER (WHERE sa.status = 'no_show')::int AS no_show,
+    COALESCE(SUM(sa.duration_minutes) FILTER (WHERE sa.status <> 'cancelled'), 0)::int AS booked_minutes
+FROM service_appointments sa
+JOIN branches br ON br.branch_id = sa.branch_id
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX idx_mv_service_stats_daily_key ON mv_service_stats_daily(day, branch_id);
+
+-- Время последнего обновления аналитики (показывается в отчётах)
+CREATE TABLE report_refreshes (
+    view_name    varchar(63) PRIMARY KEY,
+    refreshed_at timestamptz NOT NULL
+);
+
+COMMIT;
@@ -142,6 +142,12 @@ INSERT INTO user_cars (user_car_id, user_id, trim_id, color_id, vin, year, curre
 ('d0000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000004', '80000000-0000-0000-0000-000000000004', '90000000-0000-0000-0000-000000000006', 'JTEAAAAH401234567', 2023, 18500, '2023-03-20'),
 ('d0000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000005', '80000000-0000-0000-0000-000000000005', '90000000-0000-0000-0000-000000000002', 'WBA8E9C50NK123456', 2022, 31200, '2022-11-01');
 
+-- ---------------------------------------------------------------------------
+-- Trade-in: базовое правило амортизации
+-- ---------------------------------------------------------------------------
+INSERT INTO tradein_depreciation_rules (rule_id, name, brand_id, vehicle_segment, first_year_depreciation_percent, annual_depreciation_percent, expected_annual_mileage, mileage_adjustment_percent, min_residual_percent) VALUES
+('7d000000-0000-0000-0000-000000000001', 'Базовая модель', NULL, NULL, 20.00, 12.00, 15000, 1.00, 10.00);
+
 -- ---------------------------------------------------------------------------
 -- Конфигурации (черновик / подтверждена / в заказе)
 -- ---------------------------------------------------------------------------